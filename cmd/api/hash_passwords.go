@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/chickey/blog/internal/config"
+	"github.com/chickey/blog/internal/database"
+	"github.com/chickey/blog/internal/password"
+	"github.com/chickey/blog/internal/services"
+)
+
+// newHasher creates the password hasher described by the environment config.
+func newHasher(cfg config.Config) (*password.Hasher, error) {
+	return password.NewHasher(password.Params{
+		Algorithm:         password.Algorithm(cfg.PasswordHashAlgorithm),
+		BcryptCost:        cfg.BcryptCost,
+		Argon2Memory:      cfg.Argon2Memory,
+		Argon2Iterations:  cfg.Argon2Iterations,
+		Argon2Parallelism: cfg.Argon2Parallelism,
+	})
+}
+
+// runHashPasswords handles the `hash-passwords` subcommand, replacing any
+// plaintext passwords left in the users table with hashes. Rows that already
+// hold a hash are left untouched, so it is safe to run more than once.
+func runHashPasswords(ctx context.Context) error {
+	// Load and validate environment config
+	cfg, err := config.New()
+	if err != nil {
+		return fmt.Errorf("[in main.runHashPasswords] failed to load config: %w", err)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+		Level: cfg.LogLevel,
+	}))
+
+	hasher, err := newHasher(cfg)
+	if err != nil {
+		return fmt.Errorf("[in main.runHashPasswords] failed to create password hasher: %w", err)
+	}
+
+	db, err := database.Open(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("[in main.runHashPasswords] failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	updated, err := services.NewUsersService(logger, db, hasher).HashPlaintextPasswords(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "hashed %d plaintext passwords\n", updated)
+	return nil
+}
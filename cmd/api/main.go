@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -13,14 +12,33 @@ import (
 	"time"
 
 	"github.com/chickey/blog/internal/config"
+	"github.com/chickey/blog/internal/database"
 	"github.com/chickey/blog/internal/middleware"
 	"github.com/chickey/blog/internal/routes"
 	"github.com/chickey/blog/internal/services"
-	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 func main() {
 	ctx := context.Background()
+
+	// `api migrate ...` manages the schema instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, os.Args[2:]); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "migrate encountered an error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// `api hash-passwords` hashes any plaintext passwords left in the database
+	if len(os.Args) > 1 && os.Args[1] == "hash-passwords" {
+		if err := runHashPasswords(ctx); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "hash-passwords encountered an error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(ctx); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "server encountered an error: %s\n", err)
 		os.Exit(1)
@@ -40,24 +58,12 @@ func run(ctx context.Context) error {
 		Level: cfg.LogLevel,
 	}))
 
-	// Create a new DB connection using environment config
+	// Create a new DB connection using environment config and ping it to
+	// verify the connection
 	logger.DebugContext(ctx, "Connecting to database")
-	db, err := sql.Open("pgx", fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		cfg.DBHost,
-		cfg.DBUserName,
-		cfg.DBUserPassword,
-		cfg.DBName,
-		cfg.DBPort,
-	))
+	db, err := database.Open(ctx, cfg)
 	if err != nil {
-		return fmt.Errorf("[in main.run] failed to open database: %w", err)
-	}
-
-	// Ping the database to verify connection
-	logger.DebugContext(ctx, "Pinging database")
-	if err = db.PingContext(ctx); err != nil {
-		return fmt.Errorf("[in main.run] failed to ping database: %w", err)
+		return fmt.Errorf("[in main.run] failed to connect to database: %w", err)
 	}
 
 	defer func() {
@@ -69,15 +75,56 @@ func run(ctx context.Context) error {
 
 	logger.InfoContext(ctx, "Connected successfully to the database")
 
+	// Bring the schema up to date before any service touches it
+	migrator, err := database.NewMigrator(logger, db)
+	if err != nil {
+		return fmt.Errorf("[in main.run] failed to create migrator: %w", err)
+	}
+	if err = migrator.Up(ctx); err != nil {
+		return fmt.Errorf("[in main.run] failed to migrate database: %w", err)
+	}
+
+	// Create the password hasher used to store and verify credentials
+	hasher, err := newHasher(cfg)
+	if err != nil {
+		return fmt.Errorf("[in main.run] failed to create password hasher: %w", err)
+	}
+
 	// Create a new users service
-	usersService := services.NewUsersService(logger, db)
+	usersService := services.NewUsersService(logger, db, hasher)
 
-	// Create a new blogs service
-	blogsService := services.NewBlogsService(logger, db)
+	// Create a new auth service, signing tokens with the configured key
+	authService, err := services.NewAuthService(
+		logger,
+		db,
+		usersService,
+		[]byte(cfg.AuthSecret),
+		cfg.AccessTokenTTL,
+		cfg.RefreshTokenTTL,
+	)
+	if err != nil {
+		return fmt.Errorf("[in main.run] failed to create auth service: %w", err)
+	}
+
+	// Create a new tags service
+	tagsService := services.NewTagsService(logger, db)
+
+	// Create a new blogs service, which assigns tags through the tags service
+	blogsService := services.NewBlogsService(logger, db, tagsService)
 
 	// Create a new comments service
 	commentsService := services.NewCommentsService(logger, db)
 
+	// Create a new search service
+	searchService := services.NewSearchService(logger, db)
+
+	// Create a new idempotency service, keeping the responses to retried
+	// requests for the configured time
+	idempotencyService, err := services.NewIdempotencyService(logger, db, cfg.IdempotencyKeyTTL)
+	if err != nil {
+		return fmt.Errorf("[in main.run] failed to create idempotency service: %w", err)
+	}
+
 	// Create a serve mux to act as our route multiplexer
 	mux := http.NewServeMux()
 
@@ -85,18 +132,28 @@ func run(ctx context.Context) error {
 	routes.AddRoutes(
 		mux,
 		logger,
+		authService,
 		usersService,
 		blogsService,
 		commentsService,
+		searchService,
+		tagsService,
+		idempotencyService,
 		fmt.Sprintf("http://%s:%s", cfg.Host, cfg.Port),
 	)
 	// Wrap the mux with middleware
 
-	wrappedMux := middleware.Logger(logger)(mux)
+	// Identify the caller from their access token, if they sent one
+	wrappedMux := middleware.Authenticate(logger, authService)(mux)
+
+	wrappedMux = middleware.Logger(logger)(wrappedMux)
 
 	// IS THIS CORRECT WAY TO CALL RECOver
 	wrappedMux = middleware.Recover(logger)(wrappedMux)
 
+	// Assign request ids outermost so every other layer can see them
+	wrappedMux = middleware.RequestID()(wrappedMux)
+
 	// Create a new http server with our mux as the handler
 	// Create a new http server with our mux as the handler
 	httpServer := &http.Server{
@@ -110,6 +167,15 @@ func run(ctx context.Context) error {
 	ctx, done := context.WithCancel(ctx)
 	defer done()
 
+	// Publish scheduled blogs as they come due, for as long as the server runs
+	go runScheduler(ctx, logger, blogsService, cfg.PublishInterval)
+
+	// Purge deleted records once they can no longer be restored
+	go runPurger(ctx, logger, usersService, cfg.PurgeInterval, cfg.DeletedRetention)
+
+	// Purge idempotency keys once they have expired
+	go runKeyPurger(ctx, logger, idempotencyService, cfg.PurgeInterval)
+
 	// Handle graceful shutdown with go routine on SIGINT
 	go func() {
 		// create a channel to listen for SIGINT and then block until it is received
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chickey/blog/internal/config"
+	"github.com/chickey/blog/internal/database"
+)
+
+const migrateUsage = "usage: api migrate up | down [steps] | status"
+
+// runMigrate handles the `migrate` subcommand, applying, rolling back or
+// printing the status of the embedded schema migrations.
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("[in main.runMigrate] missing command, %s", migrateUsage)
+	}
+
+	// Load and validate environment config
+	cfg, err := config.New()
+	if err != nil {
+		return fmt.Errorf("[in main.runMigrate] failed to load config: %w", err)
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+		Level: cfg.LogLevel,
+	}))
+
+	db, err := database.Open(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("[in main.runMigrate] failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(logger, db)
+	if err != nil {
+		return fmt.Errorf("[in main.runMigrate] failed to create migrator: %w", err)
+	}
+
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("[in main.runMigrate] invalid steps %q, %s", args[1], migrateUsage)
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			_, _ = fmt.Fprintf(tw, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("[in main.runMigrate] unknown command %q, %s", args[0], migrateUsage)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// duePublisher represents a type capable of publishing the scheduled blogs
+// that have come due.
+type duePublisher interface {
+	PublishDue(ctx context.Context) (int64, error)
+}
+
+// runScheduler publishes due blogs once at startup and then every interval,
+// until ctx is cancelled. Failures are logged and retried on the next tick.
+func runScheduler(ctx context.Context, logger *slog.Logger, publisher duePublisher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := publisher.PublishDue(ctx)
+		switch {
+		case err != nil:
+			logger.ErrorContext(ctx, "Failed to publish due blogs", "err", err)
+		case published > 0:
+			logger.InfoContext(ctx, "Published due blogs", "count", published)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deletedPurger represents a type capable of permanently deleting the records
+// that were deleted before a given time.
+type deletedPurger interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// runPurger purges the records deleted more than retention ago once at
+// startup and then every interval, until ctx is cancelled. Failures are logged
+// and retried on the next tick.
+func runPurger(ctx context.Context, logger *slog.Logger, purger deletedPurger, interval time.Duration, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purger.PurgeDeleted(ctx, time.Now().UTC().Add(-retention))
+		switch {
+		case err != nil:
+			logger.ErrorContext(ctx, "Failed to purge deleted records", "err", err)
+		case purged > 0:
+			logger.InfoContext(ctx, "Purged deleted records", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expiredKeyPurger represents a type capable of deleting the idempotency keys
+// that expired before a given time.
+type expiredKeyPurger interface {
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// runKeyPurger purges expired idempotency keys once at startup and then every
+// interval, until ctx is cancelled. Failures are logged and retried on the
+// next tick.
+func runKeyPurger(ctx context.Context, logger *slog.Logger, purger expiredKeyPurger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purger.PurgeExpired(ctx, time.Now().UTC())
+		switch {
+		case err != nil:
+			logger.ErrorContext(ctx, "Failed to purge expired idempotency keys", "err", err)
+		case purged > 0:
+			logger.InfoContext(ctx, "Purged expired idempotency keys", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// runExport handles the `export` subcommand, writing every user, blog and
+// comment that isn't deleted to a file of each in the chosen format. Rows are
+// streamed from the database to the files as they are read.
+func runExport(ctx context.Context, args []string) error {
+	opts, err := parseOptions("export", args)
+	if err != nil {
+		return fmt.Errorf("[in main.runExport] %w", err)
+	}
+
+	a, err := connect(ctx)
+	if err != nil {
+		return fmt.Errorf("[in main.runExport] %w", err)
+	}
+	defer a.db.Close()
+
+	err = exportFile(opts, "users", func(w recordWriter) error {
+		return a.users.ExportUsers(ctx, func(user models.User) error {
+			return w.Write(newUserRecord(user))
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[in main.runExport] %w", err)
+	}
+
+	err = exportFile(opts, "blogs", func(w recordWriter) error {
+		return a.blogs.ExportBlogs(ctx, func(blog models.Blog) error {
+			return w.Write(newBlogRecord(blog))
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[in main.runExport] %w", err)
+	}
+
+	err = exportFile(opts, "comments", func(w recordWriter) error {
+		return a.comments.ExportComments(ctx, func(comment models.Comment) error {
+			return w.Write(newCommentRecord(comment))
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("[in main.runExport] %w", err)
+	}
+
+	return nil
+}
+
+// exportFile creates the file of the records called name in the export
+// directory and calls export to write them to it.
+func exportFile(opts options, name string, export func(w recordWriter) error) error {
+	path := filepath.Join(opts.dir, name+"."+string(opts.format))
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	counter := &countingWriter{w: newRecordWriter(opts.format, file)}
+	if err = export(counter); err != nil {
+		return fmt.Errorf("failed to export %s: %w", name, err)
+	}
+	if err = counter.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "exported %d %s to %s\n", counter.n, name, path)
+	return nil
+}
+
+// countingWriter counts the records written through it.
+type countingWriter struct {
+	w recordWriter
+	n int
+}
+
+func (w *countingWriter) Write(rec record) error {
+	if err := w.w.Write(rec); err != nil {
+		return err
+	}
+	w.n++
+
+	return nil
+}
+
+func (w *countingWriter) Flush() error {
+	return w.w.Flush()
+}
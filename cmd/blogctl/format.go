@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// format is a file format records are exported and imported in.
+type format string
+
+const (
+	// formatNDJSON files hold one JSON object per line.
+	formatNDJSON format = "ndjson"
+
+	// formatCSV files hold a header naming the columns, then one record per
+	// row.
+	formatCSV format = "csv"
+)
+
+// parseFormat returns the format called name.
+func parseFormat(name string) (format, error) {
+	switch f := format(name); f {
+	case formatNDJSON, formatCSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, expected ndjson or csv", name)
+	}
+}
+
+// record is a row of an exported file. It is written to and read from NDJSON
+// by its JSON tags. columns names its CSV columns, and fields and scan convert
+// it to and from their values, in the same order.
+type record interface {
+	columns() []string
+	fields() []string
+	scan(fields []string) error
+}
+
+// recordWriter writes records to a file in one of the formats.
+type recordWriter interface {
+	Write(rec record) error
+	Flush() error
+}
+
+// newRecordWriter returns a recordWriter writing to w in format f.
+func newRecordWriter(f format, w io.Writer) recordWriter {
+	buf := bufio.NewWriter(w)
+	if f == formatCSV {
+		return &csvWriter{w: csv.NewWriter(buf), buf: buf}
+	}
+
+	return &ndjsonWriter{enc: json.NewEncoder(buf), buf: buf}
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+	buf *bufio.Writer
+}
+
+// Write writes rec as a line of JSON.
+func (w *ndjsonWriter) Write(rec record) error {
+	return w.enc.Encode(rec)
+}
+
+// Flush writes any buffered records.
+func (w *ndjsonWriter) Flush() error {
+	return w.buf.Flush()
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	buf    *bufio.Writer
+	header bool
+}
+
+// Write writes rec as a row, after the header if it is the first.
+func (w *csvWriter) Write(rec record) error {
+	if !w.header {
+		if err := w.w.Write(rec.columns()); err != nil {
+			return err
+		}
+		w.header = true
+	}
+
+	return w.w.Write(rec.fields())
+}
+
+// Flush writes any buffered records.
+func (w *csvWriter) Flush() error {
+	w.w.Flush()
+	if err := w.w.Error(); err != nil {
+		return err
+	}
+
+	return w.buf.Flush()
+}
+
+// rowError is an error reading a single row of a file, which only rejects
+// that row.
+type rowError struct {
+	err error
+}
+
+func (e rowError) Error() string {
+	return e.err.Error()
+}
+
+func (e rowError) Unwrap() error {
+	return e.err
+}
+
+// recordReader reads records from a file in one of the formats.
+type recordReader interface {
+	// Read reads the next record into rec, returning the line it starts
+	// on. io.EOF is returned at the end of the file, and a rowError if the
+	// row can't be read into rec.
+	Read(rec record) (int, error)
+}
+
+// newRecordReader returns a recordReader reading from r in format f.
+func newRecordReader(f format, r io.Reader) recordReader {
+	if f == formatCSV {
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		return &csvReader{r: reader}
+	}
+
+	return &ndjsonReader{r: bufio.NewReader(r)}
+}
+
+type ndjsonReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// Read reads the next line that isn't blank into rec.
+func (r *ndjsonReader) Read(rec record) (int, error) {
+	for {
+		data, err := r.r.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return 0, err
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		r.line++
+
+		if data = bytes.TrimSpace(data); len(data) == 0 {
+			continue
+		}
+		if err = json.Unmarshal(data, rec); err != nil {
+			return r.line, rowError{err}
+		}
+
+		return r.line, nil
+	}
+}
+
+type csvReader struct {
+	r *csv.Reader
+
+	// index holds the position of each of the record's columns in the
+	// header, or -1 for columns the file doesn't have.
+	index []int
+}
+
+// Read reads the next row into rec, first reading the header if it hasn't
+// been read yet. Columns are matched by name, so they may come in any order,
+// and columns the file doesn't have are left empty.
+func (r *csvReader) Read(rec record) (int, error) {
+	if r.index == nil {
+		header, err := r.r.Read()
+		if err != nil {
+			return 0, err
+		}
+
+		r.index = make([]int, len(rec.columns()))
+		for i, column := range rec.columns() {
+			r.index[i] = -1
+			for j, name := range header {
+				if name == column {
+					r.index[i] = j
+				}
+			}
+		}
+	}
+
+	row, err := r.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return parseErr.StartLine, rowError{err}
+		}
+		return 0, err
+	}
+	line, _ := r.r.FieldPos(0)
+
+	fields := make([]string, len(r.index))
+	for i, j := range r.index {
+		if j >= 0 && j < len(row) {
+			fields[i] = row[j]
+		}
+	}
+	if err = rec.scan(fields); err != nil {
+		return line, rowError{err}
+	}
+
+	return line, nil
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// runImport handles the `import` subcommand, reading the users, blogs and
+// comments files of an export, in that order, and creating their records.
+// Records keep their IDs when the database is empty. Otherwise they are given
+// new ones, and the references between them are remapped to match. Rows the
+// API would reject, or that can't be created, are reported and skipped, and
+// an error is returned once every file is read if any were.
+func runImport(ctx context.Context, args []string) error {
+	opts, err := parseOptions("import", args)
+	if err != nil {
+		return fmt.Errorf("[in main.runImport] %w", err)
+	}
+
+	a, err := connect(ctx)
+	if err != nil {
+		return fmt.Errorf("[in main.runImport] %w", err)
+	}
+	defer a.db.Close()
+
+	// Deleted users still hold on to their IDs, so they count too
+	existing, err := a.users.ListUsers(ctx, "", models.PageRequest{Limit: 1}, true)
+	if err != nil {
+		return fmt.Errorf("[in main.runImport] %w", err)
+	}
+	keepIDs := len(existing.Items) == 0
+	if !keepIDs {
+		_, _ = fmt.Fprintln(os.Stderr, "database is not empty, imported records are given new IDs")
+	}
+
+	im := &importer{
+		opts:    opts,
+		keepIDs: keepIDs,
+		report:  os.Stderr,
+	}
+
+	if err = im.importUsers(ctx, a); err != nil {
+		return fmt.Errorf("[in main.runImport] %w", err)
+	}
+	if err = im.importBlogs(ctx, a); err != nil {
+		return fmt.Errorf("[in main.runImport] %w", err)
+	}
+	if err = im.importComments(ctx, a); err != nil {
+		return fmt.Errorf("[in main.runImport] %w", err)
+	}
+
+	if im.rejected > 0 {
+		return fmt.Errorf("[in main.runImport] %d rows were rejected", im.rejected)
+	}
+
+	return nil
+}
+
+// importer imports the files of an export, keeping track of the IDs their
+// records were created with.
+type importer struct {
+	opts    options
+	keepIDs bool
+
+	// report is where rejected rows are reported.
+	report   io.Writer
+	rejected int
+
+	users    idMap
+	blogs    idMap
+	comments idMap
+}
+
+// idMap maps the IDs records had in an export to the IDs they were imported
+// with. Until the file of its records is read, IDs are mapped to themselves,
+// so that records can refer to ones already in the database.
+type idMap struct {
+	read bool
+	ids  map[uint]uint
+}
+
+func (m *idMap) set(exported, imported uint) {
+	if m.ids == nil {
+		m.ids = make(map[uint]uint)
+	}
+	m.ids[exported] = imported
+}
+
+// lookup returns the ID the record with the exported id was imported with,
+// and false if it wasn't.
+func (m *idMap) lookup(id uint) (uint, bool) {
+	if !m.read {
+		return id, true
+	}
+
+	imported, ok := m.ids[id]
+	return imported, ok
+}
+
+func (im *importer) importUsers(ctx context.Context, a app) error {
+	return importFile(
+		ctx,
+		im,
+		"users",
+		&im.users,
+		func(rec *userRecord) (models.User, map[string]string) {
+			return models.User{
+				ID:       rec.ID,
+				Name:     rec.Name,
+				Email:    rec.Email,
+				Password: rec.Password,
+				Role:     models.Role(rec.Role),
+			}, nil
+		},
+		nil,
+		func(users []models.User) ([]models.BulkResult[models.User], error) {
+			return a.users.ImportUsers(ctx, users, im.keepIDs)
+		},
+		func(rec *userRecord, user models.User) {
+			im.users.set(rec.ID, user.ID)
+		},
+	)
+}
+
+func (im *importer) importBlogs(ctx context.Context, a app) error {
+	return importFile(
+		ctx,
+		im,
+		"blogs",
+		&im.blogs,
+		func(rec *blogRecord) (models.Blog, map[string]string) {
+			blog := models.Blog{
+				ID:          rec.ID,
+				Title:       rec.Title,
+				Body:        rec.Body,
+				Excerpt:     rec.Excerpt,
+				Category:    rec.Category,
+				Tags:        rec.Tags,
+				Status:      models.BlogStatus(rec.Status),
+				CreatedDate: rec.CreatedDate,
+			}
+			if rec.PublishAt != nil {
+				blog.PublishAt = *rec.PublishAt
+			}
+
+			var ok bool
+			if blog.AuthorID, ok = im.users.lookup(rec.AuthorID); !ok {
+				return blog, map[string]string{"AuthorID": fmt.Sprintf("User %d was not imported", rec.AuthorID)}
+			}
+
+			return blog, nil
+		},
+		nil,
+		func(blogs []models.Blog) ([]models.BulkResult[models.Blog], error) {
+			return a.blogs.ImportBlogs(ctx, blogs, im.keepIDs)
+		},
+		func(rec *blogRecord, blog models.Blog) {
+			im.blogs.set(rec.ID, blog.ID)
+		},
+	)
+}
+
+func (im *importer) importComments(ctx context.Context, a app) error {
+	return importFile(
+		ctx,
+		im,
+		"comments",
+		&im.comments,
+		func(rec *commentRecord) (models.Comment, map[string]string) {
+			comment := models.Comment{
+				ID:          rec.ID,
+				Message:     rec.Message,
+				CreatedDate: rec.CreatedDate,
+			}
+
+			problems := make(map[string]string)
+			var ok bool
+			if comment.UserID, ok = im.users.lookup(rec.UserID); !ok {
+				problems["UserID"] = fmt.Sprintf("User %d was not imported", rec.UserID)
+			}
+			if comment.BlogID, ok = im.blogs.lookup(rec.BlogID); !ok {
+				problems["BlogID"] = fmt.Sprintf("Blog %d was not imported", rec.BlogID)
+			}
+			if rec.ParentID != 0 {
+				if comment.ParentID, ok = im.comments.lookup(rec.ParentID); !ok {
+					problems["ParentID"] = fmt.Sprintf("Comment %d was not imported", rec.ParentID)
+				}
+			}
+
+			return comment, problems
+		},
+		// A reply can only be imported once the comment it replies to is
+		func(rec *commentRecord, batch []*commentRecord) bool {
+			return rec.ParentID != 0 && slices.ContainsFunc(batch, func(pending *commentRecord) bool {
+				return pending.ID == rec.ParentID
+			})
+		},
+		func(comments []models.Comment) ([]models.BulkResult[models.Comment], error) {
+			return a.comments.ImportComments(ctx, comments, im.keepIDs)
+		},
+		func(rec *commentRecord, comment models.Comment) {
+			im.comments.set(rec.ID, comment.ID)
+		},
+	)
+}
+
+// importable is a pointer to a record that can be validated the way the API
+// validates the request to create it.
+type importable[R any] interface {
+	*R
+	record
+	problems(ctx context.Context) map[string]string
+}
+
+// importFile reads the records of the file called name, which is skipped if
+// it doesn't exist, and imports them in batches. convert turns a valid
+// record into the model to import, remapping its references, and write
+// imports a batch of them. A record for which flushFirst reports true is only
+// converted once the batch before it is imported. done is called with each
+// record that was imported and the model it was imported as.
+func importFile[R any, PR importable[R], M any](
+	ctx context.Context,
+	im *importer,
+	name string,
+	ids *idMap,
+	convert func(rec PR) (M, map[string]string),
+	flushFirst func(rec PR, batch []PR) bool,
+	write func(batch []M) ([]models.BulkResult[M], error),
+	done func(rec PR, model M),
+) error {
+	path := filepath.Join(im.opts.dir, name+"."+string(im.opts.format))
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, _ = fmt.Fprintf(im.report, "%s not found, skipping %s\n", path, name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	ids.read = true
+
+	var lines []int
+	var records []PR
+	var batch []M
+	imported := 0
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		results, err := write(batch)
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", name, err)
+		}
+		for i, result := range results {
+			if result.Err != nil {
+				im.reject(path, lines[i], errors.Unwrap(result.Err).Error())
+				continue
+			}
+			done(records[i], result.Value)
+			imported++
+		}
+
+		lines, records, batch = lines[:0], records[:0], batch[:0]
+		return nil
+	}
+
+	reader := newRecordReader(im.opts.format, file)
+	for {
+		rec := PR(new(R))
+		line, err := reader.Read(rec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr rowError
+		if errors.As(err, &rowErr) {
+			im.reject(path, line, rowErr.Error())
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if problems := rec.problems(ctx); len(problems) > 0 {
+			im.reject(path, line, formatProblems(problems))
+			continue
+		}
+
+		if flushFirst != nil && flushFirst(rec, records) {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+
+		model, problems := convert(rec)
+		if len(problems) > 0 {
+			im.reject(path, line, formatProblems(problems))
+			continue
+		}
+
+		lines = append(lines, line)
+		records = append(records, rec)
+		batch = append(batch, model)
+		if len(batch) == im.opts.batchSize {
+			if err = flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err = flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "imported %d %s from %s\n", imported, name, path)
+	return nil
+}
+
+// reject reports the row of the file at path that starts on line as
+// rejected, for the reason given.
+func (im *importer) reject(path string, line int, reason string) {
+	im.rejected++
+	_, _ = fmt.Fprintf(im.report, "%s:%d: rejected: %s\n", path, line, reason)
+}
+
+// formatProblems lists problems on one line, ordered by field.
+func formatProblems(problems map[string]string) string {
+	fields := make([]string, 0, len(problems))
+	for field := range problems {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	list := make([]string, len(fields))
+	for i, field := range fields {
+		list[i] = field + ": " + strings.TrimSpace(problems[field])
+	}
+
+	return strings.Join(list, "; ")
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/chickey/blog/internal/config"
+	"github.com/chickey/blog/internal/database"
+	"github.com/chickey/blog/internal/password"
+	"github.com/chickey/blog/internal/services"
+)
+
+const usage = "usage: blogctl export | import [-format ndjson|csv] [-dir directory]"
+
+func main() {
+	ctx := context.Background()
+
+	if len(os.Args) < 2 {
+		_, _ = fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(ctx, os.Args[2:])
+	case "import":
+		err = runImport(ctx, os.Args[2:])
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "unknown command %q, %s\n", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%s encountered an error: %s\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// options are the flags shared by the export and import commands.
+type options struct {
+	format    format
+	dir       string
+	batchSize int
+}
+
+// parseOptions parses the flags of the command called name. batchSize is only
+// a flag of import.
+func parseOptions(name string, args []string) (options, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	formatName := flags.String("format", string(formatNDJSON), "file format, ndjson or csv")
+	dir := flags.String("dir", ".", "directory holding the users, blogs and comments files")
+	batchSize := flags.Int("batch", 500, "number of rows imported in each transaction")
+
+	if err := flags.Parse(args); err != nil {
+		return options{}, fmt.Errorf("%w, %s", err, usage)
+	}
+	if flags.NArg() > 0 {
+		return options{}, fmt.Errorf("unexpected argument %q, %s", flags.Arg(0), usage)
+	}
+
+	f, err := parseFormat(*formatName)
+	if err != nil {
+		return options{}, err
+	}
+	if *batchSize < 1 {
+		return options{}, fmt.Errorf("invalid batch size %d", *batchSize)
+	}
+
+	return options{format: f, dir: *dir, batchSize: *batchSize}, nil
+}
+
+// app holds the services the commands move data through.
+type app struct {
+	db       *sql.DB
+	users    *services.UsersService
+	blogs    *services.BlogsService
+	comments *services.CommentsService
+}
+
+// connect loads the environment config, connects to the database it names and
+// creates the services. The caller closes app.db.
+func connect(ctx context.Context) (app, error) {
+	// Load and validate environment config
+	cfg, err := config.New()
+	if err != nil {
+		return app{}, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Logs go to stderr, leaving stdout to the command's own output
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
+		Level: cfg.LogLevel,
+	}))
+
+	hasher, err := password.NewHasher(password.Params{
+		Algorithm:         password.Algorithm(cfg.PasswordHashAlgorithm),
+		BcryptCost:        cfg.BcryptCost,
+		Argon2Memory:      cfg.Argon2Memory,
+		Argon2Iterations:  cfg.Argon2Iterations,
+		Argon2Parallelism: cfg.Argon2Parallelism,
+	})
+	if err != nil {
+		return app{}, fmt.Errorf("failed to create password hasher: %w", err)
+	}
+
+	db, err := database.Open(ctx, cfg)
+	if err != nil {
+		return app{}, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return app{
+		db:       db,
+		users:    services.NewUsersService(logger, db, hasher),
+		blogs:    services.NewBlogsService(logger, db, services.NewTagsService(logger, db)),
+		comments: services.NewCommentsService(logger, db),
+	}, nil
+}
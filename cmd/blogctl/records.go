@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers"
+	"github.com/chickey/blog/internal/models"
+)
+
+// userRecord is a user as it is exported. Password holds the password hash,
+// which is imported as it is.
+type userRecord struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+func newUserRecord(user models.User) *userRecord {
+	return &userRecord{
+		ID:       user.ID,
+		Name:     user.Name,
+		Email:    user.Email,
+		Password: user.Password,
+		Role:     string(user.Role),
+	}
+}
+
+func (r *userRecord) columns() []string {
+	return []string{"id", "name", "email", "password", "role"}
+}
+
+func (r *userRecord) fields() []string {
+	return []string{formatID(r.ID), r.Name, r.Email, r.Password, r.Role}
+}
+
+func (r *userRecord) scan(fields []string) error {
+	var err error
+	if r.ID, err = parseID("id", fields[0]); err != nil {
+		return err
+	}
+	r.Name, r.Email, r.Password, r.Role = fields[1], fields[2], fields[3], fields[4]
+
+	return nil
+}
+
+// problems returns the problems the API would find with the user.
+func (r *userRecord) problems(ctx context.Context) map[string]string {
+	request := handlers.UserRequest{Name: r.Name, Email: r.Email, Password: r.Password}
+	return withID(request.Valid(ctx), r.ID)
+}
+
+// blogRecord is a blog as it is exported, with its tags.
+type blogRecord struct {
+	ID          uint       `json:"id"`
+	AuthorID    uint       `json:"author_id"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	Excerpt     string     `json:"excerpt"`
+	Category    string     `json:"category"`
+	Tags        []string   `json:"tags"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+	CreatedDate time.Time  `json:"created_date"`
+}
+
+func newBlogRecord(blog models.Blog) *blogRecord {
+	r := &blogRecord{
+		ID:          blog.ID,
+		AuthorID:    blog.AuthorID,
+		Title:       blog.Title,
+		Body:        blog.Body,
+		Excerpt:     blog.Excerpt,
+		Category:    blog.Category,
+		Tags:        blog.Tags,
+		Status:      string(blog.Status),
+		CreatedDate: blog.CreatedDate,
+	}
+	if !blog.PublishAt.IsZero() {
+		r.PublishAt = &blog.PublishAt
+	}
+
+	return r
+}
+
+func (r *blogRecord) columns() []string {
+	return []string{"id", "author_id", "title", "body", "excerpt", "category", "tags", "status", "publish_at", "created_date"}
+}
+
+// fields returns the values of the columns. Tags are slugs, which never
+// contain commas, so they share a column as a comma separated list.
+func (r *blogRecord) fields() []string {
+	var publishAt time.Time
+	if r.PublishAt != nil {
+		publishAt = *r.PublishAt
+	}
+
+	return []string{
+		formatID(r.ID),
+		formatID(r.AuthorID),
+		r.Title,
+		r.Body,
+		r.Excerpt,
+		r.Category,
+		strings.Join(r.Tags, ","),
+		r.Status,
+		formatTime(publishAt),
+		formatTime(r.CreatedDate),
+	}
+}
+
+func (r *blogRecord) scan(fields []string) error {
+	var err error
+	if r.ID, err = parseID("id", fields[0]); err != nil {
+		return err
+	}
+	if r.AuthorID, err = parseID("author_id", fields[1]); err != nil {
+		return err
+	}
+	r.Title, r.Body, r.Excerpt, r.Category = fields[2], fields[3], fields[4], fields[5]
+	if fields[6] != "" {
+		r.Tags = strings.Split(fields[6], ",")
+	}
+	r.Status = fields[7]
+
+	publishAt, err := parseTime("publish_at", fields[8])
+	if err != nil {
+		return err
+	}
+	if !publishAt.IsZero() {
+		r.PublishAt = &publishAt
+	}
+
+	r.CreatedDate, err = parseTime("created_date", fields[9])
+	return err
+}
+
+// problems returns the problems the API would find with the blog.
+func (r *blogRecord) problems(ctx context.Context) map[string]string {
+	request := handlers.BlogRequest{
+		AuthorID: r.AuthorID,
+		Title:    r.Title,
+		Body:     r.Body,
+		Excerpt:  r.Excerpt,
+		Category: r.Category,
+		Tags:     r.Tags,
+		Status:   r.Status,
+	}
+	if r.PublishAt != nil {
+		request.PublishAt = *r.PublishAt
+	}
+
+	return withID(request.Valid(ctx), r.ID)
+}
+
+// commentRecord is a comment as it is exported. ParentID is zero for
+// comments on the blog itself.
+type commentRecord struct {
+	ID          uint      `json:"id"`
+	ParentID    uint      `json:"parent_id,omitempty"`
+	UserID      uint      `json:"user_id"`
+	BlogID      uint      `json:"blog_id"`
+	Message     string    `json:"message"`
+	CreatedDate time.Time `json:"created_date"`
+}
+
+func newCommentRecord(comment models.Comment) *commentRecord {
+	return &commentRecord{
+		ID:          comment.ID,
+		ParentID:    comment.ParentID,
+		UserID:      comment.UserID,
+		BlogID:      comment.BlogID,
+		Message:     comment.Message,
+		CreatedDate: comment.CreatedDate,
+	}
+}
+
+func (r *commentRecord) columns() []string {
+	return []string{"id", "parent_id", "user_id", "blog_id", "message", "created_date"}
+}
+
+func (r *commentRecord) fields() []string {
+	parentID := ""
+	if r.ParentID != 0 {
+		parentID = formatID(r.ParentID)
+	}
+
+	return []string{
+		formatID(r.ID),
+		parentID,
+		formatID(r.UserID),
+		formatID(r.BlogID),
+		r.Message,
+		formatTime(r.CreatedDate),
+	}
+}
+
+func (r *commentRecord) scan(fields []string) error {
+	var err error
+	if r.ID, err = parseID("id", fields[0]); err != nil {
+		return err
+	}
+	if r.ParentID, err = parseID("parent_id", fields[1]); err != nil {
+		return err
+	}
+	if r.UserID, err = parseID("user_id", fields[2]); err != nil {
+		return err
+	}
+	if r.BlogID, err = parseID("blog_id", fields[3]); err != nil {
+		return err
+	}
+	r.Message = fields[4]
+
+	r.CreatedDate, err = parseTime("created_date", fields[5])
+	return err
+}
+
+// problems returns the problems the API would find with the comment.
+func (r *commentRecord) problems(ctx context.Context) map[string]string {
+	request := handlers.CommentRequest{
+		UserID:   r.UserID,
+		BlogID:   r.BlogID,
+		ParentID: r.ParentID,
+		Message:  r.Message,
+	}
+
+	return withID(request.Valid(ctx), r.ID)
+}
+
+// withID adds a problem to problems if id is missing. Rows need their ID so
+// that the rows referring to them can be remapped.
+func withID(problems map[string]string, id uint) map[string]string {
+	if id == 0 {
+		problems["ID"] = "Invalid ID"
+	}
+
+	return problems
+}
+
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// parseID parses the ID in the column called name, which is zero if empty.
+func parseID(name, s string) (uint, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, s)
+	}
+
+	return uint(id), nil
+}
+
+// formatTime formats t as RFC 3339, or as an empty string if it is zero.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+// parseTime parses the RFC 3339 time in the column called name, which is zero
+// if empty.
+func parseTime(name, s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q", name, s)
+	}
+
+	return t, nil
+}
@@ -1,63 +1,44 @@
-DROP TABLE IF EXISTS "users";
-DROP TABLE IF EXISTS "blogs";
-DROP TABLE IF EXISTS "comments";
+-- Sample data for local development.
+--
+-- The schema itself is owned by the versioned migrations embedded in
+-- internal/database/migrations, which the API applies on startup. Load this
+-- file into a freshly migrated database with `make seed-database`.
 
--- Create user table
-CREATE TABLE "users" (
-    id BIGSERIAL PRIMARY KEY,
-    name TEXT NOT NULL,
-    email TEXT NOT NULL,
-    password TEXT NOT NULL
-);
-
--- Create blog table
-CREATE TABLE "blogs" (
-    id BIGSERIAL PRIMARY KEY,
-    author_id INTEGER NOT NULL,
-    title TEXT NOT NULL,
-    score REAL NOT NULL,
-    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-
--- Create comment table
-CREATE TABLE "comments" (
-    user_id BIGSERIAL NOT NULL,
-    blog_id BIGSERIAL NOT NULL,
-    message TEXT NOT NULL,
-    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    PRIMARY KEY (user_id, blog_id)
-);
-
--- Insert data into the user table
+-- Insert data into the user table. Passwords are bcrypt hashes of
+-- password1 through password10 in the order listed.
 INSERT INTO "users" (name, email, password) VALUES
-    ('John Doe', 'john@example.com', 'password1'),
-    ('Jane Smith', 'jane@example.com', 'password2'),
-    ('Alice Johnson', 'alice@example.com', 'password3'),
-    ('Bob Brown', 'bob@example.com', 'password4'),
-    ('Emma Davis', 'emma@example.com', 'password5'),
-    ('Michael Wilson', 'michael@example.com', 'password6'),
-    ('Sarah Lee', 'sarah@example.com', 'password7'),
-    ('David Garcia', 'david@example.com', 'password8'),
-    ('Olivia Martinez', 'olivia@example.com', 'password9'),
-    ('William Rodriguez', 'william@example.com', 'password10');
+    ('John Doe', 'john@example.com', '$2a$12$1theZuac9dKQ180lUj6.Guu8FH312ZNVuzQHRb7EhzXmDtTGyQWp2'),
+    ('Jane Smith', 'jane@example.com', '$2a$12$PUzYI/0uc4u1.W4d.llNLuBm0Meao19.jvR1NkLufkxxQWL672vn6'),
+    ('Alice Johnson', 'alice@example.com', '$2a$12$2qUBC80rSlfA5VFjZEOMbeyeNfkjrUy1Euy4ibZkwDcQ.IghtQws2'),
+    ('Bob Brown', 'bob@example.com', '$2a$12$tmB02hL1ttsID6kwuOvVuumxoZbWrMVkY077xX0a68RJHeUT/EvuW'),
+    ('Emma Davis', 'emma@example.com', '$2a$12$EbIE7KYSknR6LxJxhXoL4.6/ms0zaCajBcv4RytAS/klSBTx1mDV.'),
+    ('Michael Wilson', 'michael@example.com', '$2a$12$8j3/8HmMgD.TcKOPTIKy2OO6dFbBWt2dB6.7m1vktTXsvNORXim9S'),
+    ('Sarah Lee', 'sarah@example.com', '$2a$12$JXP7S7r6W2qZh20QkhZ69.RLHRTYPRyyul1fVQ0LZWyx/EpEwBcv6'),
+    ('David Garcia', 'david@example.com', '$2a$12$TdwijkJ04PWaa9IFSPvZB.U/hde2velyT63SAF/VuHw2YeCKRjo0S'),
+    ('Olivia Martinez', 'olivia@example.com', '$2a$12$a73mnNomLY.DPKc7pbO4u.D7BhsNDCkssR.Ri5pivkTOJ8wfurxli'),
+    ('William Rodriguez', 'william@example.com', '$2a$12$3qFhs9xpFD0y7OMiP8DCHeAJsrdO26PxRQGMPDxL2JuAnFZWPxy7a');
+
+-- John Doe can manage everyone's blogs and comments
+UPDATE users SET role = 'admin' WHERE email = 'john@example.com';
 
--- Insert data into the blog table
-INSERT INTO blogs (author_id, title, score, created_date) VALUES
-    (1, 'First Blog Post', 8.5, '2024-05-14 09:00:00'),
-    (2, 'Travel Adventures', 7.2, '2024-05-13 14:30:00'),
-    (3, 'Cooking Tips', 9.3, '2024-05-12 11:45:00'),
-    (4, 'Tech Reviews', 6.7, '2024-05-11 16:20:00'),
-    (5, 'Fitness Journey', 8.9, '2024-05-10 08:15:00'),
-    (6, 'Book Recommendations', 7.8, '2024-05-09 10:45:00'),
-    (7, 'Photography Tips', 9.1, '2024-05-08 13:20:00'),
-    (8, 'Financial Advice', 6.4, '2024-05-07 17:30:00'),
-    (9, 'DIY Projects', 8.0, '2024-05-06 09:45:00'),
-    (10, 'Movie Reviews', 7.5, '2024-05-05 14:00:00'),
-    (1, 'Second Blog Post', 8.2, '2024-05-04 11:10:00'),
-    (2, 'Healthy Recipes', 9.0, '2024-05-03 15:25:00'),
-    (3, 'Productivity Hacks', 8.7, '2024-05-02 10:50:00'),
-    (4, 'Gaming News', 7.3, '2024-05-01 12:15:00'),
-    (5, 'Home Decor Ideas', 9.5, '2024-04-30 09:30:00');
+-- Insert data into the blog table. Blogs are drafts unless told otherwise, so
+-- these are published as they were written. Scores are left to the votes.
+INSERT INTO blogs (author_id, title, status, publish_at, created_date) VALUES
+    (1, 'First Blog Post', 'published', '2024-05-14 09:00:00', '2024-05-14 09:00:00'),
+    (2, 'Travel Adventures', 'published', '2024-05-13 14:30:00', '2024-05-13 14:30:00'),
+    (3, 'Cooking Tips', 'published', '2024-05-12 11:45:00', '2024-05-12 11:45:00'),
+    (4, 'Tech Reviews', 'published', '2024-05-11 16:20:00', '2024-05-11 16:20:00'),
+    (5, 'Fitness Journey', 'published', '2024-05-10 08:15:00', '2024-05-10 08:15:00'),
+    (6, 'Book Recommendations', 'published', '2024-05-09 10:45:00', '2024-05-09 10:45:00'),
+    (7, 'Photography Tips', 'published', '2024-05-08 13:20:00', '2024-05-08 13:20:00'),
+    (8, 'Financial Advice', 'published', '2024-05-07 17:30:00', '2024-05-07 17:30:00'),
+    (9, 'DIY Projects', 'published', '2024-05-06 09:45:00', '2024-05-06 09:45:00'),
+    (10, 'Movie Reviews', 'published', '2024-05-05 14:00:00', '2024-05-05 14:00:00'),
+    (1, 'Second Blog Post', 'published', '2024-05-04 11:10:00', '2024-05-04 11:10:00'),
+    (2, 'Healthy Recipes', 'published', '2024-05-03 15:25:00', '2024-05-03 15:25:00'),
+    (3, 'Productivity Hacks', 'published', '2024-05-02 10:50:00', '2024-05-02 10:50:00'),
+    (4, 'Gaming News', 'published', '2024-05-01 12:15:00', '2024-05-01 12:15:00'),
+    (5, 'Home Decor Ideas', 'published', '2024-04-30 09:30:00', '2024-04-30 09:30:00');
 
 -- Insert data into the comment table
 INSERT INTO "comments" (user_id, blog_id, message, created_date) VALUES
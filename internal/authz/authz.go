@@ -0,0 +1,85 @@
+package authz
+
+import "github.com/chickey/blog/internal/models"
+
+// Action is something a caller may try to do to a resource. Admins may do
+// anything; everyone else may only change their own account and the blogs and
+// comments they wrote, and may only read blogs that are published or that
+// they wrote. Deleted users can only be restored by admins.
+type Action string
+
+const (
+	Read    Action = "read"
+	Create  Action = "create"
+	Update  Action = "update"
+	Delete  Action = "delete"
+	Restore Action = "restore"
+)
+
+// CanUser reports whether actor may perform action on the target user.
+func CanUser(actor models.User, action Action, target models.User) bool {
+	if isAdmin(actor) {
+		return true
+	}
+
+	switch action {
+	case Update, Delete:
+		return owns(actor, target.ID)
+	default:
+		return false
+	}
+}
+
+// CanBlog reports whether actor may perform action on blog. For Create and
+// Update, blog is the blog as it would be written, so ordinary users can't
+// create blogs for, or hand blogs over to, someone else.
+func CanBlog(actor models.User, action Action, blog models.Blog) bool {
+	if isAdmin(actor) {
+		return true
+	}
+
+	switch action {
+	case Read:
+		return blog.Status == models.BlogStatusPublished || owns(actor, blog.AuthorID)
+	case Create, Update, Delete, Restore:
+		return owns(actor, blog.AuthorID)
+	default:
+		return false
+	}
+}
+
+// CanComment reports whether actor may perform action on comment.
+func CanComment(actor models.User, action Action, comment models.Comment) bool {
+	if isAdmin(actor) {
+		return true
+	}
+
+	switch action {
+	case Create, Update, Delete, Restore:
+		return owns(actor, comment.UserID)
+	default:
+		return false
+	}
+}
+
+// CanReadUnpublished reports whether actor may read every blog that isn't
+// published, and the comments on it, whoever wrote it.
+func CanReadUnpublished(actor models.User) bool {
+	return isAdmin(actor)
+}
+
+// CanReadDeleted reports whether actor may read users, blogs and comments that
+// have been deleted.
+func CanReadDeleted(actor models.User) bool {
+	return isAdmin(actor)
+}
+
+// isAdmin reports whether actor is an authenticated admin.
+func isAdmin(actor models.User) bool {
+	return actor.ID != 0 && actor.Role == models.RoleAdmin
+}
+
+// owns reports whether actor is the authenticated user with id ownerID.
+func owns(actor models.User, ownerID uint) bool {
+	return actor.ID != 0 && actor.ID == ownerID
+}
@@ -0,0 +1,145 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+var (
+	john  = models.User{ID: 1, Role: models.RoleUser}
+	jane  = models.User{ID: 2, Role: models.RoleUser}
+	admin = models.User{ID: 3, Role: models.RoleAdmin}
+	guest = models.User{}
+)
+
+func TestCanUser(t *testing.T) {
+	testcases := map[string]struct {
+		actor    models.User
+		action   Action
+		target   models.User
+		expected bool
+	}{
+		"update self":         {actor: john, action: Update, target: john, expected: true},
+		"delete self":         {actor: john, action: Delete, target: john, expected: true},
+		"update someone else": {actor: john, action: Update, target: jane, expected: false},
+		"delete someone else": {actor: john, action: Delete, target: jane, expected: false},
+		"admin updates":       {actor: admin, action: Update, target: jane, expected: true},
+		"admin deletes":       {actor: admin, action: Delete, target: jane, expected: true},
+		"restore self":        {actor: john, action: Restore, target: john, expected: false},
+		"admin restores":      {actor: admin, action: Restore, target: jane, expected: true},
+		"anonymous":           {actor: guest, action: Update, target: guest, expected: false},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := CanUser(tc.actor, tc.action, tc.target); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCanBlog(t *testing.T) {
+	johns := models.Blog{ID: 1, AuthorID: john.ID}
+	janes := models.Blog{ID: 2, AuthorID: jane.ID}
+	janesDraft := models.Blog{ID: 3, AuthorID: jane.ID, Status: models.BlogStatusDraft}
+	published := models.Blog{ID: 4, AuthorID: jane.ID, Status: models.BlogStatusPublished}
+
+	testcases := map[string]struct {
+		actor    models.User
+		action   Action
+		blog     models.Blog
+		expected bool
+	}{
+		"read published":          {actor: guest, action: Read, blog: published, expected: true},
+		"read own draft":          {actor: jane, action: Read, blog: janesDraft, expected: true},
+		"read someone's draft":    {actor: john, action: Read, blog: janesDraft, expected: false},
+		"admin reads draft":       {actor: admin, action: Read, blog: janesDraft, expected: true},
+		"create own":              {actor: john, action: Create, blog: johns, expected: true},
+		"create for someone else": {actor: john, action: Create, blog: janes, expected: false},
+		"update own":              {actor: john, action: Update, blog: johns, expected: true},
+		"update someone else's":   {actor: john, action: Update, blog: janes, expected: false},
+		"delete own":              {actor: john, action: Delete, blog: johns, expected: true},
+		"delete someone else's":   {actor: john, action: Delete, blog: janes, expected: false},
+		"admin updates":           {actor: admin, action: Update, blog: janes, expected: true},
+		"admin deletes":           {actor: admin, action: Delete, blog: janes, expected: true},
+		"restore own":             {actor: john, action: Restore, blog: johns, expected: true},
+		"restore someone else's":  {actor: john, action: Restore, blog: janes, expected: false},
+		"anonymous":               {actor: guest, action: Delete, blog: models.Blog{}, expected: false},
+		"unknown action":          {actor: john, action: "publish", blog: johns, expected: false},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := CanBlog(tc.actor, tc.action, tc.blog); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCanComment(t *testing.T) {
+	johns := models.Comment{UserID: john.ID, BlogID: 2}
+	janes := models.Comment{UserID: jane.ID, BlogID: 1}
+
+	testcases := map[string]struct {
+		actor    models.User
+		action   Action
+		comment  models.Comment
+		expected bool
+	}{
+		"create own":              {actor: john, action: Create, comment: johns, expected: true},
+		"create for someone else": {actor: john, action: Create, comment: janes, expected: false},
+		"update own":              {actor: john, action: Update, comment: johns, expected: true},
+		"update someone else's":   {actor: john, action: Update, comment: janes, expected: false},
+		"delete own":              {actor: john, action: Delete, comment: johns, expected: true},
+		"delete someone else's":   {actor: john, action: Delete, comment: janes, expected: false},
+		"admin updates":           {actor: admin, action: Update, comment: janes, expected: true},
+		"admin deletes":           {actor: admin, action: Delete, comment: janes, expected: true},
+		"restore own":             {actor: john, action: Restore, comment: johns, expected: true},
+		"restore someone else's":  {actor: john, action: Restore, comment: janes, expected: false},
+		"anonymous":               {actor: guest, action: Delete, comment: models.Comment{}, expected: false},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := CanComment(tc.actor, tc.action, tc.comment); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCanReadUnpublished(t *testing.T) {
+	testcases := map[string]struct {
+		actor    models.User
+		expected bool
+	}{
+		"user":      {actor: john, expected: false},
+		"admin":     {actor: admin, expected: true},
+		"anonymous": {actor: guest, expected: false},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := CanReadUnpublished(tc.actor); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCanReadDeleted(t *testing.T) {
+	testcases := map[string]struct {
+		actor    models.User
+		expected bool
+	}{
+		"user":      {actor: john, expected: false},
+		"admin":     {actor: admin, expected: true},
+		"anonymous": {actor: guest, expected: false},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := CanReadDeleted(tc.actor); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
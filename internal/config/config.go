@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
@@ -19,6 +20,36 @@ type Config struct {
 	Host           string     `env:"HOST,required"`
 	Port           string     `env:"PORT,required"`
 	LogLevel       slog.Level `env:"LOG_LEVEL,required"`
+
+	// Password hashing. PasswordHashAlgorithm is either "bcrypt" or
+	// "argon2id"; stored hashes that don't match these settings are
+	// upgraded the next time their owner logs in.
+	PasswordHashAlgorithm string `env:"PASSWORD_HASH_ALGORITHM" envDefault:"bcrypt"`
+	BcryptCost            int    `env:"BCRYPT_COST" envDefault:"12"`
+	Argon2Memory          uint32 `env:"ARGON2_MEMORY" envDefault:"65536"`
+	Argon2Iterations      uint32 `env:"ARGON2_ITERATIONS" envDefault:"3"`
+	Argon2Parallelism     uint8  `env:"ARGON2_PARALLELISM" envDefault:"2"`
+
+	// Token authentication. AuthSecret is the HMAC key used to sign access
+	// and refresh tokens.
+	AuthSecret      string        `env:"AUTH_SECRET,required"`
+	AccessTokenTTL  time.Duration `env:"ACCESS_TOKEN_TTL" envDefault:"15m"`
+	RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL" envDefault:"720h"`
+
+	// Blog scheduling. Scheduled blogs are published by a background job
+	// that checks for due blogs every PublishInterval.
+	PublishInterval time.Duration `env:"PUBLISH_INTERVAL" envDefault:"1m"`
+
+	// Soft deletes. Deleted users, blogs and comments can be restored for
+	// DeletedRetention, after which a background job that runs every
+	// PurgeInterval deletes them for good.
+	DeletedRetention time.Duration `env:"DELETED_RETENTION" envDefault:"720h"`
+	PurgeInterval    time.Duration `env:"PURGE_INTERVAL" envDefault:"1h"`
+
+	// Idempotency keys. A POST sent with an Idempotency-Key header is
+	// replayed rather than repeated when it is retried within
+	// IdempotencyKeyTTL. Expired keys are purged every PurgeInterval.
+	IdempotencyKeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"`
 }
 
 // New loads configuration from environment variables and a .env file, and returns a
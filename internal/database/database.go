@@ -1 +1,34 @@
-package database
\ No newline at end of file
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/chickey/blog/internal/config"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Open creates a new DB connection pool using the provided config and pings
+// the database to verify the connection. The caller is responsible for closing
+// the returned *sql.DB.
+func Open(ctx context.Context, cfg config.Config) (*sql.DB, error) {
+	db, err := sql.Open("pgx", fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+		cfg.DBHost,
+		cfg.DBUserName,
+		cfg.DBUserPassword,
+		cfg.DBName,
+		cfg.DBPort,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("[in database.Open] failed to open database: %w", err)
+	}
+
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("[in database.Open] failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,343 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationsFS holds the versioned schema migrations that ship with the
+// binary. Each migration is a pair of files named
+// <version>_<name>.up.sql and <version>_<name>.down.sql.
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationLockID is the key used with pg_advisory_lock so that only one
+// process applies migrations at a time.
+const migrationLockID int64 = 4_862_019_117
+
+// Migration represents a single versioned schema change.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a Migration has been applied to the
+// database, and when.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies and rolls back the embedded schema migrations, recording
+// progress in the schema_migrations table.
+type Migrator struct {
+	logger     *slog.Logger
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a new Migrator for the migrations embedded in the binary
+// and returns a pointer to it.
+func NewMigrator(logger *slog.Logger, db *sql.DB) (*Migrator, error) {
+	sub, err := fs.Sub(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("[in database.NewMigrator] failed to open migrations: %w", err)
+	}
+
+	return newMigrator(logger, db, sub)
+}
+
+// newMigrator creates a Migrator from the migrations found at the root of
+// fsys.
+func newMigrator(logger *slog.Logger, db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("[in database.NewMigrator] failed to load migrations: %w", err)
+	}
+
+	return &Migrator{
+		logger:     logger,
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies every migration that has not yet been applied, in version order.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return fmt.Errorf("[in database.Migrator.Up] %w", err)
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			m.logger.InfoContext(
+				ctx,
+				"Applying migration",
+				slog.Uint64("version", migration.Version),
+				slog.String("name", migration.Name),
+			)
+
+			err = inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(
+					ctx,
+					`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+					migration.Version,
+					migration.Name,
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf(
+					"[in database.Migrator.Up] failed to apply migration %d_%s: %w",
+					migration.Version,
+					migration.Name,
+					err,
+				)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Down rolls back the most recently applied migrations, up to steps of them.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return fmt.Errorf("[in database.Migrator.Down] %w", err)
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			m.logger.InfoContext(
+				ctx,
+				"Rolling back migration",
+				slog.Uint64("version", migration.Version),
+				slog.String("name", migration.Name),
+			)
+
+			err = inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(
+					ctx,
+					`DELETE FROM schema_migrations WHERE version = $1`,
+					migration.Version,
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf(
+					"[in database.Migrator.Down] failed to roll back migration %d_%s: %w",
+					migration.Version,
+					migration.Name,
+					err,
+				)
+			}
+			steps--
+		}
+
+		return nil
+	})
+}
+
+// Status reports every known migration along with whether it has been
+// applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return fmt.Errorf("[in database.Migrator.Status] %w", err)
+		}
+
+		for _, migration := range m.migrations {
+			appliedAt, ok := applied[migration.Version]
+			statuses = append(statuses, MigrationStatus{
+				Migration: migration,
+				Applied:   ok,
+				AppliedAt: appliedAt,
+			})
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock runs fn on a dedicated connection while holding the migration
+// advisory lock, making sure the schema_migrations table exists first.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("[in database.Migrator] failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	m.logger.DebugContext(ctx, "Acquiring migration lock")
+	if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("[in database.Migrator] failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context so the lock is released even if ctx was
+		// cancelled part way through.
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			m.logger.ErrorContext(ctx, "Failed to release migration lock", "err", err)
+		}
+	}()
+
+	_, err = conn.ExecContext(
+		ctx,
+		`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+		`,
+	)
+	if err != nil {
+		return fmt.Errorf("[in database.Migrator] failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// appliedVersions returns the applied migration versions mapped to the time
+// they were applied.
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[uint64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint64]time.Time)
+	for rows.Next() {
+		var (
+			version   uint64
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	return applied, nil
+}
+
+// inTx runs fn inside a transaction on conn, committing if fn succeeds and
+// rolling back otherwise.
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
+
+// loadMigrations reads and pairs the up/down files found at the root of fsys,
+// returning them sorted by version.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		direction := path.Ext(base)
+		if direction != ".up" && direction != ".down" {
+			return nil, fmt.Errorf("migration %q must end in .up.sql or .down.sql", entry.Name())
+		}
+		base = strings.TrimSuffix(base, direction)
+
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %q must be named <version>_<name>", entry.Name())
+		}
+		version, err := strconv.ParseUint(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %q has an invalid version: %w", entry.Name(), err)
+		}
+
+		contents, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by both %q and %q", version, migration.Name, name)
+		}
+
+		if direction == ".up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDate = time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC)
+
+func TestLoadMigrations(t *testing.T) {
+	testcases := map[string]struct {
+		input          fstest.MapFS
+		expectedOutput []Migration
+		expectError    bool
+	}{
+		"sorted by version": {
+			input: fstest.MapFS{
+				"0002_add_index.up.sql":       {Data: []byte("CREATE INDEX")},
+				"0002_add_index.down.sql":     {Data: []byte("DROP INDEX")},
+				"0001_create_tables.up.sql":   {Data: []byte("CREATE TABLE")},
+				"0001_create_tables.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			expectedOutput: []Migration{
+				{Version: 1, Name: "create_tables", Up: "CREATE TABLE", Down: "DROP TABLE"},
+				{Version: 2, Name: "add_index", Up: "CREATE INDEX", Down: "DROP INDEX"},
+			},
+		},
+		"missing down file": {
+			input: fstest.MapFS{
+				"0001_create_tables.up.sql": {Data: []byte("CREATE TABLE")},
+			},
+			expectError: true,
+		},
+		"invalid version": {
+			input: fstest.MapFS{
+				"first_create_tables.up.sql":   {Data: []byte("CREATE TABLE")},
+				"first_create_tables.down.sql": {Data: []byte("DROP TABLE")},
+			},
+			expectError: true,
+		},
+		"missing direction": {
+			input: fstest.MapFS{
+				"0001_create_tables.sql": {Data: []byte("CREATE TABLE")},
+			},
+			expectError: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			output, err := loadMigrations(tc.input)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := NewMigrator(slog.Default(), nil)
+	if err != nil {
+		t.Fatalf("failed to load embedded migrations: %s", err)
+	}
+
+	for i, migration := range migrator.migrations {
+		if migration.Version != uint64(i+1) {
+			t.Errorf("expected migration %d to have version %d, got %d", i, i+1, migration.Version)
+		}
+	}
+}
+
+func TestEmbeddedMigrations_BlogVersionTrigger(t *testing.T) {
+	migrator, err := NewMigrator(slog.Default(), nil)
+	if err != nil {
+		t.Fatalf("failed to load embedded migrations: %s", err)
+	}
+
+	// The last migration to create the trigger decides when versions go up
+	var trigger string
+	for _, migration := range migrator.migrations {
+		for _, statement := range strings.Split(migration.Up, ";") {
+			if strings.Contains(statement, "CREATE TRIGGER blogs_bump_version") {
+				trigger = statement
+			}
+		}
+	}
+
+	assert.Contains(t, trigger, "WHEN")
+	assert.Contains(t, trigger, "OLD.title")
+	assert.NotContains(t, trigger, "score")
+	assert.NotContains(t, trigger, "deleted_at")
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	migrator, err := newMigrator(slog.Default(), db, fstest.MapFS{
+		"0001_create_tables.up.sql":   {Data: []byte("CREATE TABLE one")},
+		"0001_create_tables.down.sql": {Data: []byte("DROP TABLE one")},
+		"0002_add_index.up.sql":       {Data: []byte("CREATE INDEX two")},
+		"0002_add_index.down.sql":     {Data: []byte("DROP INDEX two")},
+	})
+	if err != nil {
+		t.Fatalf("failed to create migrator: %s", err)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).
+		WithArgs(migrationLockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, testDate))
+
+	// Only the second migration is pending
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE INDEX two`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(uint64(2), "add_index").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).
+		WithArgs(migrationLockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err = migrator.Up(context.TODO()); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
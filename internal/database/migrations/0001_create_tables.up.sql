@@ -0,0 +1,28 @@
+-- Tables are created only if missing so that databases bootstrapped by the
+-- old database_setup.sql init script can adopt the migration history.
+
+-- Create user table
+CREATE TABLE IF NOT EXISTS "users" (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    password TEXT NOT NULL
+);
+
+-- Create blog table
+CREATE TABLE IF NOT EXISTS "blogs" (
+    id BIGSERIAL PRIMARY KEY,
+    author_id INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    score REAL NOT NULL,
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create comment table
+CREATE TABLE IF NOT EXISTS "comments" (
+    user_id BIGSERIAL NOT NULL,
+    blog_id BIGSERIAL NOT NULL,
+    message TEXT NOT NULL,
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, blog_id)
+);
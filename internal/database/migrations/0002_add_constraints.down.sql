@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS comments_blog_id_idx;
+DROP INDEX IF EXISTS blogs_author_id_idx;
+
+ALTER TABLE blogs DROP CONSTRAINT IF EXISTS blogs_title_check;
+ALTER TABLE blogs DROP CONSTRAINT IF EXISTS blogs_score_check;
+
+DROP INDEX IF EXISTS users_email_key;
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_blog_id_fkey;
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_user_id_fkey;
+ALTER TABLE blogs DROP CONSTRAINT IF EXISTS blogs_author_id_fkey;
+
+ALTER TABLE blogs ALTER COLUMN author_id TYPE INTEGER;
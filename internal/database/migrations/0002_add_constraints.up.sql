@@ -0,0 +1,41 @@
+-- Remove rows already orphaned by earlier non-transactional deletes so the
+-- foreign keys below can be created.
+DELETE FROM blogs WHERE author_id NOT IN (SELECT id FROM users);
+DELETE FROM comments
+WHERE user_id NOT IN (SELECT id FROM users)
+   OR blog_id NOT IN (SELECT id FROM blogs);
+
+-- Foreign key columns must match the BIGINT primary keys they reference and
+-- must not draw values from their own sequences.
+ALTER TABLE blogs ALTER COLUMN author_id TYPE BIGINT;
+
+ALTER TABLE comments ALTER COLUMN user_id DROP DEFAULT;
+ALTER TABLE comments ALTER COLUMN blog_id DROP DEFAULT;
+DROP SEQUENCE IF EXISTS comments_user_id_seq;
+DROP SEQUENCE IF EXISTS comments_blog_id_seq;
+
+ALTER TABLE blogs
+    ADD CONSTRAINT blogs_author_id_fkey
+    FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE comments
+    ADD CONSTRAINT comments_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE comments
+    ADD CONSTRAINT comments_blog_id_fkey
+    FOREIGN KEY (blog_id) REFERENCES blogs (id) ON DELETE CASCADE;
+
+-- Emails are compared case-insensitively
+CREATE UNIQUE INDEX users_email_key ON users (LOWER(email));
+
+-- Mirror the validation performed by handlers.BlogRequest.Valid
+ALTER TABLE blogs
+    ADD CONSTRAINT blogs_score_check CHECK (score >= 0 AND score <= 10);
+
+ALTER TABLE blogs
+    ADD CONSTRAINT blogs_title_check CHECK (char_length(title) BETWEEN 1 AND 100);
+
+-- Support the author and blog lookups used by the list endpoints and cascades
+CREATE INDEX blogs_author_id_idx ON blogs (author_id);
+CREATE INDEX comments_blog_id_idx ON comments (blog_id);
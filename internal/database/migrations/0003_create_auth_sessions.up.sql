@@ -0,0 +1,13 @@
+-- Every login creates a session. Access and refresh tokens carry the session
+-- id, so revoking the session invalidates both. refresh_token_id holds the id
+-- of the only refresh token that may still be exchanged for new tokens.
+CREATE TABLE auth_sessions (
+    id TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    refresh_token_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+CREATE INDEX auth_sessions_user_id_idx ON auth_sessions (user_id);
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS comments_blog_id_idx ON comments (blog_id);
+
+DROP INDEX IF EXISTS comments_blog_id_user_id_idx;
+DROP INDEX IF EXISTS blogs_title_idx;
+DROP INDEX IF EXISTS users_name_idx;
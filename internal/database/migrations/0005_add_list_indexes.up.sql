@@ -0,0 +1,9 @@
+-- Lists are filtered by these columns and paged in key order. Comments are
+-- paged by (blog_id, user_id), which also serves lookups by blog alone.
+CREATE INDEX IF NOT EXISTS users_name_idx ON users (name);
+
+CREATE INDEX IF NOT EXISTS blogs_title_idx ON blogs (title);
+
+CREATE INDEX IF NOT EXISTS comments_blog_id_user_id_idx ON comments (blog_id, user_id);
+
+DROP INDEX IF EXISTS comments_blog_id_idx;
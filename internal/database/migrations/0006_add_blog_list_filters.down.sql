@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS blogs_created_date_idx;
+DROP INDEX IF EXISTS blogs_score_idx;
+
+CREATE INDEX IF NOT EXISTS blogs_title_idx ON blogs (title);
+
+DROP INDEX IF EXISTS blogs_title_trgm_idx;
+
+ALTER TABLE blogs ALTER COLUMN created_date DROP NOT NULL;
@@ -0,0 +1,17 @@
+-- Blogs can be sorted by created_date, and keyset pagination can't page over
+-- NULLs, so every blog needs a creation time.
+UPDATE blogs SET created_date = CURRENT_TIMESTAMP WHERE created_date IS NULL;
+
+ALTER TABLE blogs ALTER COLUMN created_date SET NOT NULL;
+
+-- Titles are matched by case-insensitive substring, which a trigram index can
+-- serve and a plain btree index can't.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS blogs_title_trgm_idx ON blogs USING GIN (title gin_trgm_ops);
+
+DROP INDEX IF EXISTS blogs_title_idx;
+
+CREATE INDEX IF NOT EXISTS blogs_score_idx ON blogs (score);
+
+CREATE INDEX IF NOT EXISTS blogs_created_date_idx ON blogs (created_date);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS comments_search_idx;
+DROP INDEX IF EXISTS blogs_search_idx;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS search;
+ALTER TABLE blogs DROP COLUMN IF EXISTS search;
@@ -0,0 +1,13 @@
+-- Full-text search over blog titles and comment messages. The vectors are
+-- generated columns so they can never drift from the text they index.
+ALTER TABLE blogs
+    ADD COLUMN IF NOT EXISTS search tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', title)) STORED;
+
+ALTER TABLE comments
+    ADD COLUMN IF NOT EXISTS search tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', message)) STORED;
+
+CREATE INDEX IF NOT EXISTS blogs_search_idx ON blogs USING GIN (search);
+
+CREATE INDEX IF NOT EXISTS comments_search_idx ON comments USING GIN (search);
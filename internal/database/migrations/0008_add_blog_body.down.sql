@@ -0,0 +1,5 @@
+ALTER TABLE blogs DROP CONSTRAINT IF EXISTS blogs_excerpt_check;
+ALTER TABLE blogs DROP CONSTRAINT IF EXISTS blogs_body_check;
+
+ALTER TABLE blogs DROP COLUMN IF EXISTS excerpt;
+ALTER TABLE blogs DROP COLUMN IF EXISTS body;
@@ -0,0 +1,11 @@
+-- Blog posts carry a Markdown body and a short plain text excerpt for lists.
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS body TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS excerpt TEXT NOT NULL DEFAULT '';
+
+-- Mirror the validation performed by handlers.BlogRequest.Valid
+ALTER TABLE blogs
+    ADD CONSTRAINT blogs_body_check CHECK (char_length(body) <= 100000);
+
+ALTER TABLE blogs
+    ADD CONSTRAINT blogs_excerpt_check CHECK (char_length(excerpt) <= 300);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS blogs_scheduled_publish_at_idx;
+
+ALTER TABLE blogs DROP CONSTRAINT IF EXISTS blogs_publish_at_check;
+ALTER TABLE blogs DROP CONSTRAINT IF EXISTS blogs_status_check;
+
+ALTER TABLE blogs DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE blogs DROP COLUMN IF EXISTS status;
@@ -0,0 +1,22 @@
+-- Blogs move through a draft, scheduled, published and archived workflow.
+-- Blogs written before the workflow existed were visible as soon as they were
+-- created, so they start out published.
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published';
+
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP;
+
+UPDATE blogs SET publish_at = created_date WHERE publish_at IS NULL;
+
+ALTER TABLE blogs ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE blogs
+    ADD CONSTRAINT blogs_status_check
+        CHECK (status IN ('draft', 'scheduled', 'published', 'archived'));
+
+-- A scheduled blog needs to know when to be published
+ALTER TABLE blogs
+    ADD CONSTRAINT blogs_publish_at_check
+        CHECK (status <> 'scheduled' OR publish_at IS NOT NULL);
+
+-- The scheduler looks for scheduled blogs that are due
+CREATE INDEX IF NOT EXISTS blogs_scheduled_publish_at_idx ON blogs (publish_at) WHERE status = 'scheduled';
@@ -0,0 +1,17 @@
+-- Only one comment per user and blog fits the composite key, so every reply
+-- and every comment but a user's first on a blog is removed.
+DELETE FROM comments
+WHERE parent_id IS NOT NULL
+   OR id NOT IN (SELECT MIN(id) FROM comments GROUP BY user_id, blog_id);
+
+DROP INDEX IF EXISTS comments_parent_id_idx;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS parent_id;
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_pkey;
+
+ALTER TABLE comments ADD CONSTRAINT comments_pkey PRIMARY KEY (user_id, blog_id);
+
+ALTER TABLE comments DROP COLUMN IF EXISTS id;
+
+DROP SEQUENCE IF EXISTS comments_id_seq;
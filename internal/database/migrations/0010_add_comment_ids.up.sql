@@ -0,0 +1,37 @@
+-- Comments get their own identifiers, so that a user can comment on a blog
+-- more than once and reply to other comments. Existing comments are numbered
+-- in the order they were written.
+CREATE SEQUENCE IF NOT EXISTS comments_id_seq;
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS id BIGINT;
+
+UPDATE comments
+SET id = numbered.id
+FROM (
+    SELECT user_id,
+           blog_id,
+           row_number() OVER (ORDER BY created_date, blog_id, user_id) AS id
+    FROM comments
+) AS numbered
+WHERE comments.user_id = numbered.user_id
+  AND comments.blog_id = numbered.blog_id
+  AND comments.id IS NULL;
+
+SELECT setval('comments_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM comments;
+
+ALTER TABLE comments ALTER COLUMN id SET DEFAULT nextval('comments_id_seq');
+
+ALTER TABLE comments ALTER COLUMN id SET NOT NULL;
+
+ALTER SEQUENCE comments_id_seq OWNED BY comments.id;
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_pkey;
+
+ALTER TABLE comments ADD CONSTRAINT comments_pkey PRIMARY KEY (id);
+
+-- Replies are removed together with the comment they reply to
+ALTER TABLE comments
+    ADD COLUMN IF NOT EXISTS parent_id BIGINT
+        CONSTRAINT comments_parent_id_fkey REFERENCES comments (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
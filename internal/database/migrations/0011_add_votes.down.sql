@@ -0,0 +1,3 @@
+ALTER TABLE blogs ALTER COLUMN score DROP DEFAULT;
+
+DROP TABLE IF EXISTS votes;
@@ -0,0 +1,21 @@
+-- Readers rate blogs from 0 to 10, once each, and a blog's score is the mean
+-- of its ratings. Scores set by authors before voting existed are kept until
+-- the blog's first vote replaces them.
+CREATE TABLE IF NOT EXISTS votes (
+    user_id BIGINT NOT NULL,
+    blog_id BIGINT NOT NULL,
+    rating SMALLINT NOT NULL,
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, blog_id),
+    CONSTRAINT votes_user_id_fkey
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT votes_blog_id_fkey
+        FOREIGN KEY (blog_id) REFERENCES blogs (id) ON DELETE CASCADE,
+    CONSTRAINT votes_rating_check CHECK (rating BETWEEN 0 AND 10)
+);
+
+-- Scores are recomputed from a blog's votes
+CREATE INDEX IF NOT EXISTS votes_blog_id_idx ON votes (blog_id);
+
+-- Blogs are unrated until they are voted on
+ALTER TABLE blogs ALTER COLUMN score SET DEFAULT 0;
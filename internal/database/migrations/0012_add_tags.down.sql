@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS blog_tags;
+
+DROP TABLE IF EXISTS tags;
+
+DROP INDEX IF EXISTS blogs_category_idx;
+
+ALTER TABLE blogs DROP CONSTRAINT IF EXISTS blogs_category_check;
+
+ALTER TABLE blogs DROP COLUMN IF EXISTS category;
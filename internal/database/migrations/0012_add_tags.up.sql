@@ -0,0 +1,29 @@
+-- Every blog belongs to one category and carries any number of tags. Both are
+-- stored as slugs normalized by services.TagsService, so "Go" and " go " are
+-- the same tag.
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS category TEXT NOT NULL DEFAULT 'uncategorized';
+
+ALTER TABLE blogs
+    ADD CONSTRAINT blogs_category_check CHECK (char_length(category) BETWEEN 1 AND 50);
+
+CREATE INDEX IF NOT EXISTS blogs_category_idx ON blogs (category);
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    CONSTRAINT tags_name_key UNIQUE (name),
+    CONSTRAINT tags_name_check CHECK (char_length(name) BETWEEN 1 AND 50)
+);
+
+CREATE TABLE IF NOT EXISTS blog_tags (
+    blog_id BIGINT NOT NULL,
+    tag_id BIGINT NOT NULL,
+    PRIMARY KEY (blog_id, tag_id),
+    CONSTRAINT blog_tags_blog_id_fkey
+        FOREIGN KEY (blog_id) REFERENCES blogs (id) ON DELETE CASCADE,
+    CONSTRAINT blog_tags_tag_id_fkey
+        FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+
+-- Blogs are filtered by tag, and tags are counted by blog
+CREATE INDEX IF NOT EXISTS blog_tags_tag_id_idx ON blog_tags (tag_id);
@@ -0,0 +1,34 @@
+-- Updates to blogs and comments keep the values they replace as revisions,
+-- along with who made the update and when. Revisions outlive their editor,
+-- whose id is cleared when the editor is deleted.
+CREATE TABLE IF NOT EXISTS blog_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    blog_id BIGINT NOT NULL,
+    editor_id BIGINT,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    excerpt TEXT NOT NULL DEFAULT '',
+    category TEXT NOT NULL,
+    tags TEXT NOT NULL DEFAULT '',
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT blog_revisions_blog_id_fkey
+        FOREIGN KEY (blog_id) REFERENCES blogs (id) ON DELETE CASCADE,
+    CONSTRAINT blog_revisions_editor_id_fkey
+        FOREIGN KEY (editor_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS blog_revisions_blog_id_idx ON blog_revisions (blog_id, id);
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    comment_id BIGINT NOT NULL,
+    editor_id BIGINT,
+    message TEXT NOT NULL,
+    created_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT comment_revisions_comment_id_fkey
+        FOREIGN KEY (comment_id) REFERENCES comments (id) ON DELETE CASCADE,
+    CONSTRAINT comment_revisions_editor_id_fkey
+        FOREIGN KEY (editor_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS comment_revisions_comment_id_idx ON comment_revisions (comment_id, id);
@@ -0,0 +1,16 @@
+-- Rows that were only soft deleted are removed, as the hard deletes would
+-- have done.
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+DELETE FROM blogs WHERE deleted_at IS NOT NULL;
+DELETE FROM comments WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS comments_deleted_at_idx;
+DROP INDEX IF EXISTS blogs_deleted_at_idx;
+DROP INDEX IF EXISTS users_deleted_at_idx;
+
+DROP INDEX IF EXISTS users_email_key;
+CREATE UNIQUE INDEX users_email_key ON users (LOWER(email));
+
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE blogs DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,16 @@
+-- Deleted users, blogs and comments are kept, with the time they were deleted,
+-- until the purge job removes them for good. Rows deleted together share the
+-- same deleted_at, which is how a restore finds them again.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- A deleted user's email may be taken by a new account. Restoring the deleted
+-- user then fails until one of them changes it.
+DROP INDEX IF EXISTS users_email_key;
+CREATE UNIQUE INDEX users_email_key ON users (LOWER(email)) WHERE deleted_at IS NULL;
+
+-- The purge job looks for rows deleted before the retention period
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS blogs_deleted_at_idx ON blogs (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS comments_deleted_at_idx ON comments (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS comments_bump_version ON comments;
+DROP TRIGGER IF EXISTS blogs_bump_version ON blogs;
+DROP TRIGGER IF EXISTS users_bump_version ON users;
+
+DROP FUNCTION IF EXISTS bump_version();
+
+ALTER TABLE comments DROP COLUMN IF EXISTS version;
+ALTER TABLE blogs DROP COLUMN IF EXISTS version;
+ALTER TABLE users DROP COLUMN IF EXISTS version;
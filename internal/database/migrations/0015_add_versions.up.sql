@@ -0,0 +1,21 @@
+-- Users, blogs and comments carry a version that goes up by one with every
+-- update to the row. It is served as the ETag of the row so that clients can
+-- make their writes conditional on nobody having changed it in between. A
+-- trigger bumps it so that no update can forget to.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE blogs ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_version() RETURNS trigger AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_bump_version BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER blogs_bump_version BEFORE UPDATE ON blogs
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER comments_bump_version BEFORE UPDATE ON comments
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
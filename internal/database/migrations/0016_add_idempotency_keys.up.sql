@@ -0,0 +1,17 @@
+-- Idempotency keys let clients retry a POST without repeating it. A key is
+-- scoped to the caller that sent it, and holds a fingerprint of the request
+-- together with the response to replay, which is NULL while the first request
+-- is still being handled. Keys expire at expires_at and are purged after that.
+CREATE TABLE idempotency_keys (
+    scope TEXT NOT NULL,
+    key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    status_code INT,
+    header JSONB,
+    body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
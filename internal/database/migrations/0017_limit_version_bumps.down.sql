@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS users_bump_version ON users;
+DROP TRIGGER IF EXISTS blogs_bump_version ON blogs;
+DROP TRIGGER IF EXISTS comments_bump_version ON comments;
+
+CREATE TRIGGER users_bump_version BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER blogs_bump_version BEFORE UPDATE ON blogs
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER comments_bump_version BEFORE UPDATE ON comments
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
@@ -0,0 +1,24 @@
+-- Versions only go up when a row is edited, so that votes, the scheduler and
+-- soft deletes don't change the ETag a client is about to send back. Blog
+-- status, publish time and tags aren't compared here, as the scheduler moves
+-- blogs on by itself. Edits to them set the version explicitly, which the
+-- trigger still counts as a single bump.
+DROP TRIGGER IF EXISTS users_bump_version ON users;
+DROP TRIGGER IF EXISTS blogs_bump_version ON blogs;
+DROP TRIGGER IF EXISTS comments_bump_version ON comments;
+
+CREATE TRIGGER users_bump_version BEFORE UPDATE ON users
+    FOR EACH ROW
+    WHEN ((OLD.name, OLD.email, OLD.password, OLD.role, OLD.version)
+        IS DISTINCT FROM (NEW.name, NEW.email, NEW.password, NEW.role, NEW.version))
+    EXECUTE FUNCTION bump_version();
+CREATE TRIGGER blogs_bump_version BEFORE UPDATE ON blogs
+    FOR EACH ROW
+    WHEN ((OLD.author_id, OLD.title, OLD.body, OLD.excerpt, OLD.category, OLD.version)
+        IS DISTINCT FROM (NEW.author_id, NEW.title, NEW.body, NEW.excerpt, NEW.category, NEW.version))
+    EXECUTE FUNCTION bump_version();
+CREATE TRIGGER comments_bump_version BEFORE UPDATE ON comments
+    FOR EACH ROW
+    WHEN ((OLD.user_id, OLD.blog_id, OLD.parent_id, OLD.message, OLD.version)
+        IS DISTINCT FROM (NEW.user_id, NEW.blog_id, NEW.parent_id, NEW.message, NEW.version))
+    EXECUTE FUNCTION bump_version();
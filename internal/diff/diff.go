@@ -0,0 +1,112 @@
+// Package diff compares texts line by line.
+package diff
+
+import "strings"
+
+// maxCells bounds the table used to find the lines two texts have in common,
+// so that diffing large, very different texts stays cheap. Texts whose
+// differing parts need a larger table are diffed as a whole replacement.
+const maxCells = 4_000_000
+
+// Lines returns a line diff turning a into b, one line per line of either
+// text. Lines only in a are prefixed with "-", lines only in b with "+", and
+// lines in both with a space. An empty string is returned if a and b are
+// equal.
+func Lines(a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	x, y := split(a), split(b)
+
+	// Lines shared at the start and end are kept without searching for them
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix && x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	var out strings.Builder
+	for _, line := range x[:prefix] {
+		write(&out, ' ', line)
+	}
+	middle(&out, x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])
+	for _, line := range x[len(x)-suffix:] {
+		write(&out, ' ', line)
+	}
+
+	return out.String()
+}
+
+// middle writes the diff of x and y, keeping the longest run of lines they
+// have in common.
+func middle(out *strings.Builder, x, y []string) {
+	if len(x)*len(y) > maxCells {
+		for _, line := range x {
+			write(out, '-', line)
+		}
+		for _, line := range y {
+			write(out, '+', line)
+		}
+		return
+	}
+
+	// common[i][j] is the number of lines x[i:] and y[j:] have in common
+	common := make([][]int32, len(x)+1)
+	for i := range common {
+		common[i] = make([]int32, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			write(out, ' ', x[i])
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			write(out, '-', x[i])
+			i++
+		default:
+			write(out, '+', y[j])
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		write(out, '-', x[i])
+	}
+	for ; j < len(y); j++ {
+		write(out, '+', y[j])
+	}
+}
+
+// split returns the lines of s. An empty s has no lines.
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, "\n")
+}
+
+// write writes line to out prefixed with op.
+func write(out *strings.Builder, op byte, line string) {
+	out.WriteByte(op)
+	out.WriteString(line)
+	out.WriteByte('\n')
+}
@@ -0,0 +1,70 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	testcases := map[string]struct {
+		a        string
+		b        string
+		expected string
+	}{
+		"equal": {
+			a:        "one\ntwo",
+			b:        "one\ntwo",
+			expected: "",
+		},
+		"line changed": {
+			a:        "one\ntwo\nthree",
+			b:        "one\n2\nthree",
+			expected: " one\n-two\n+2\n three\n",
+		},
+		"line added": {
+			a:        "one\nthree",
+			b:        "one\ntwo\nthree",
+			expected: " one\n+two\n three\n",
+		},
+		"line removed": {
+			a:        "one\ntwo\nthree",
+			b:        "one\nthree",
+			expected: " one\n-two\n three\n",
+		},
+		"common lines in the middle": {
+			a:        "a\nkeep\nb",
+			b:        "c\nkeep\nd",
+			expected: "-a\n+c\n keep\n-b\n+d\n",
+		},
+		"from empty": {
+			a:        "",
+			b:        "one\ntwo",
+			expected: "+one\n+two\n",
+		},
+		"to empty": {
+			a:        "one",
+			b:        "",
+			expected: "-one\n",
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			if got := Lines(tc.a, tc.b); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLinesLargeReplacement(t *testing.T) {
+	a := strings.Repeat("a\n", 3000)
+	b := strings.Repeat("b\n", 3000)
+
+	got := Lines(a, b)
+	if !strings.HasPrefix(got, strings.Repeat("-a\n", 3000)+"+b\n") {
+		t.Errorf("expected the old lines to be removed before the new ones are added")
+	}
+	if strings.Count(got, "\n") != 6001 {
+		t.Errorf("expected %d lines, got %d", 6001, strings.Count(got, "\n"))
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/middleware"
+	"github.com/chickey/blog/internal/models"
+)
+
+// actorFromRequest returns the authenticated user making the request. If the
+// request is anonymous it responds with a 401 and returns false.
+func actorFromRequest(w http.ResponseWriter, r *http.Request) (models.User, bool) {
+	actor, ok := middleware.UserFromContext(r.Context())
+	if !ok {
+		writeProblem(w, r, http.StatusUnauthorized, "This request requires an access token.", nil)
+		return models.User{}, false
+	}
+
+	return actor, true
+}
+
+// writeForbidden responds with a 403 for an authenticated user who is not
+// allowed to act on the resource.
+func writeForbidden(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusForbidden, "You are not allowed to perform this action on this resource.", nil)
+}
+
+// parseIncludeDeleted reads the include_deleted query parameter, which only
+// admins may set. If the parameter is invalid or the caller may not read
+// deleted records it responds with a problem and returns false.
+func parseIncludeDeleted(w http.ResponseWriter, r *http.Request) (includeDeleted bool, ok bool) {
+	s := r.URL.Query().Get("include_deleted")
+	if s == "" {
+		return false, true
+	}
+
+	includeDeleted, err := strconv.ParseBool(s)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid include_deleted", map[string]string{
+			"include_deleted": "Include deleted must be true or false",
+		})
+		return false, false
+	}
+	if !includeDeleted {
+		return false, true
+	}
+
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return false, false
+	}
+	if !authz.CanReadDeleted(actor) {
+		writeForbidden(w, r)
+		return false, false
+	}
+
+	return true, true
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/middleware"
+	"github.com/chickey/blog/internal/models"
+)
+
+// Callers used by the handler tests. testUser owns the fixtures, which are
+// all written by the user with id 1.
+var (
+	testUser      = models.User{ID: 1, Name: "john", Role: models.RoleUser}
+	testOtherUser = models.User{ID: 2, Name: "jane", Role: models.RoleUser}
+	testAdmin     = models.User{ID: 3, Name: "root", Role: models.RoleAdmin}
+)
+
+// withActor returns req authenticated as actor, or req unchanged if actor is
+// the zero value.
+func withActor(req *http.Request, actor models.User) *http.Request {
+	if actor.ID == 0 {
+		return req
+	}
+
+	session := models.Session{ID: "session", UserID: actor.ID}
+	return req.WithContext(middleware.WithUser(req.Context(), actor, session))
+}
+
+func TestActorFromRequest(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		wantOK     bool
+		wantStatus int
+	}{
+		"authenticated": {
+			actor:      testUser,
+			wantOK:     true,
+			wantStatus: 200,
+		},
+		"anonymous": {
+			wantOK:     false,
+			wantStatus: 401,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := withActor(httptest.NewRequest("GET", "/", nil), tc.actor)
+			rec := httptest.NewRecorder()
+
+			actor, ok := actorFromRequest(rec, req)
+			if ok != tc.wantOK {
+				t.Errorf("want ok %t, got %t", tc.wantOK, ok)
+			}
+			if ok && actor != tc.actor {
+				t.Errorf("want actor %v, got %v", tc.actor, actor)
+			}
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestParseIncludeDeleted(t *testing.T) {
+	tests := map[string]struct {
+		query      string
+		actor      models.User
+		want       bool
+		wantOK     bool
+		wantStatus int
+	}{
+		"not set": {
+			actor:      testUser,
+			wantOK:     true,
+			wantStatus: 200,
+		},
+		"false": {
+			query:      "?include_deleted=false",
+			actor:      testUser,
+			wantOK:     true,
+			wantStatus: 200,
+		},
+		"admin": {
+			query:      "?include_deleted=true",
+			actor:      testAdmin,
+			want:       true,
+			wantOK:     true,
+			wantStatus: 200,
+		},
+		"not an admin": {
+			query:      "?include_deleted=true",
+			actor:      testUser,
+			wantOK:     false,
+			wantStatus: 403,
+		},
+		"anonymous": {
+			query:      "?include_deleted=true",
+			wantOK:     false,
+			wantStatus: 401,
+		},
+		"invalid": {
+			query:      "?include_deleted=maybe",
+			actor:      testAdmin,
+			wantOK:     false,
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := withActor(httptest.NewRequest("GET", "/"+tc.query, nil), tc.actor)
+			rec := httptest.NewRecorder()
+
+			got, ok := parseIncludeDeleted(rec, req)
+			if ok != tc.wantOK {
+				t.Errorf("want ok %t, got %t", tc.wantOK, ok)
+			}
+			if got != tc.want {
+				t.Errorf("want %t, got %t", tc.want, got)
+			}
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
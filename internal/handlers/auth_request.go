@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+)
+
+// LoginRequest represents the request for logging in.
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (r *LoginRequest) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if r.Email == "" {
+		problems["Email"] = "Email cannot be empty"
+	}
+	if r.Password == "" {
+		problems["Password"] = "Password cannot be empty"
+	}
+
+	return problems
+}
+
+// RefreshRequest represents the request for exchanging a refresh token.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (r *RefreshRequest) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if r.RefreshToken == "" {
+		problems["RefreshToken"] = "Refresh token cannot be empty"
+	}
+
+	return problems
+}
@@ -2,14 +2,40 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"time"
 	"unicode/utf8"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// Limits on the length of a blog's Markdown body and excerpt, in characters.
+const (
+	maxBlogBodyLength    = 100000
+	maxBlogExcerptLength = 300
+)
+
+// Limits on the tags and category of a blog. Lengths are in characters.
+const (
+	maxBlogTags      = 10
+	maxBlogTagLength = 50
 )
 
-// BlogRequest represents the request for creating a Blog.
+// BlogRequest represents the request for creating a Blog. Body is Markdown.
+// Excerpt is generated from the body when left empty. Status defaults to
+// draft for new blogs and is kept for existing ones, and PublishAt is
+// required to schedule a blog. The score is set by readers' votes, not here.
+// Category and Tags are normalized to lower case slugs, and the category
+// defaults to uncategorized.
 type BlogRequest struct {
-	AuthorID uint    `json:"authorid"`
-	Title    string  `json:"title"`
-	Score    float32 `json:"score"`
+	AuthorID  uint      `json:"authorid"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Excerpt   string    `json:"excerpt"`
+	Category  string    `json:"category"`
+	Tags      []string  `json:"tags"`
+	Status    string    `json:"status" enums:"draft,scheduled,published,archived"`
+	PublishAt time.Time `json:"publishat"`
 }
 
 func (r *BlogRequest) Valid(ctx context.Context) map[string]string {
@@ -19,15 +45,48 @@ func (r *BlogRequest) Valid(ctx context.Context) map[string]string {
 	if r.AuthorID == 0 {
 		problems["AuthorId"] = "Invalid AuthorId"
 	}
-	if r.Score < 0 || r.Score > 10 {
-		problems["Score"] = "Score cannot be less than 0 or greater than 10"
-	}
 	if r.Title == "" {
 		problems["Title"] = "Title cannot be empty"
 	}
 	if utf8.RuneCountInString(r.Title) > 100 {
 		problems["Title"] = "Title cannot be greater than 100 characters"
 	}
+	if utf8.RuneCountInString(r.Body) > maxBlogBodyLength {
+		problems["Body"] = fmt.Sprintf("Body cannot be greater than %d characters", maxBlogBodyLength)
+	}
+	if utf8.RuneCountInString(r.Excerpt) > maxBlogExcerptLength {
+		problems["Excerpt"] = fmt.Sprintf("Excerpt cannot be greater than %d characters", maxBlogExcerptLength)
+	}
+	if utf8.RuneCountInString(r.Category) > maxBlogTagLength {
+		problems["Category"] = fmt.Sprintf("Category cannot be greater than %d characters", maxBlogTagLength)
+	}
+	if len(r.Tags) > maxBlogTags {
+		problems["Tags"] = fmt.Sprintf("A blog cannot have more than %d tags", maxBlogTags)
+	}
+	for _, tag := range r.Tags {
+		if utf8.RuneCountInString(tag) > maxBlogTagLength {
+			problems["Tags"] = fmt.Sprintf("Tags cannot be greater than %d characters", maxBlogTagLength)
+		}
+	}
+	if r.Status != "" && !validBlogStatus(models.BlogStatus(r.Status)) {
+		problems["Status"] = "Status must be draft, scheduled, published or archived"
+	}
+	if models.BlogStatus(r.Status) == models.BlogStatusScheduled && r.PublishAt.IsZero() {
+		problems["PublishAt"] = "PublishAt is required to schedule a blog"
+	}
 
 	return problems
 }
+
+// validBlogStatus reports whether status is one of the blog workflow statuses.
+func validBlogStatus(status models.BlogStatus) bool {
+	switch status {
+	case models.BlogStatusDraft,
+		models.BlogStatusScheduled,
+		models.BlogStatusPublished,
+		models.BlogStatusArchived:
+		return true
+	default:
+		return false
+	}
+}
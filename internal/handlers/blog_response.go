@@ -2,13 +2,71 @@ package handlers
 
 import (
 	"time"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// Formats a blog body can be returned in.
+const (
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
 )
 
-// BlogResponse represents the response for creating a Blog.
+// BlogResponse represents the response for creating a Blog. Body is in the
+// named Format and is left out of lists, which carry only the Excerpt.
+// PublishAt is left out for blogs that have never been scheduled or
+// published, and Author unless it was expanded. Tags is empty rather than
+// null for untagged blogs. DeletedAt is only set on deleted blogs, which are
+// only returned to admins who ask for them.
 type BlogResponse struct {
-	ID          uint      `json:"id"`
-	AuthorID    uint      `json:"authorid"`
-	Title       string    `json:"title"`
-	Score       float32   `json:"score"`
-	CreatedDate time.Time `json:"createddate"`
+	ID          uint             `json:"id"`
+	AuthorID    uint             `json:"authorid"`
+	Author      *UserRefResponse `json:"author,omitempty"`
+	Title       string           `json:"title"`
+	Body        string           `json:"body,omitempty"`
+	Format      string           `json:"format,omitempty"`
+	Excerpt     string           `json:"excerpt"`
+	Category    string           `json:"category"`
+	Tags        []string         `json:"tags"`
+	Status      string           `json:"status"`
+	PublishAt   *time.Time       `json:"publishat,omitempty"`
+	Score       float32          `json:"score"`
+	CreatedDate time.Time        `json:"createddate"`
+	DeletedAt   *time.Time       `json:"deletedat,omitempty"`
+}
+
+// BlogRefResponse represents a blog embedded in an expanded response.
+type BlogRefResponse struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+// newBlogRefResponse converts ref into a response model, or nil if the blog
+// wasn't expanded.
+func newBlogRefResponse(ref models.BlogRef) *BlogRefResponse {
+	if ref.ID == 0 {
+		return nil
+	}
+
+	return &BlogRefResponse{ID: ref.ID, Title: ref.Title}
+}
+
+// tagList returns tags, or an empty list if there are none, so that untagged
+// blogs have an empty list of tags rather than null.
+func tagList(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+
+	return tags
+}
+
+// optionalTime returns a pointer to t, or nil if t is zero, so that unset
+// times are left out of responses.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
 }
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+// maxBulkItems is the largest number of items a bulk request may carry.
+const maxBulkItems = 500
+
+// errItemForbidden fails an item of a bulk request that the caller may not
+// write.
+var errItemForbidden = errors.New("forbidden")
+
+// BulkRequest represents a bulk write of Items. Mode is all_or_nothing, the
+// default, which writes every item or none of them, or best_effort, which
+// writes every item it can.
+type BulkRequest[T any] struct {
+	Mode  string `json:"mode" enums:"all_or_nothing,best_effort"`
+	Items []T    `json:"items"`
+}
+
+func (r *BulkRequest[T]) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	switch models.BulkMode(r.Mode) {
+	case "", models.BulkAllOrNothing, models.BulkBestEffort:
+	default:
+		problems["Mode"] = "Mode must be all_or_nothing or best_effort"
+	}
+	if len(r.Items) == 0 {
+		problems["Items"] = "Items cannot be empty"
+	}
+	if len(r.Items) > maxBulkItems {
+		problems["Items"] = fmt.Sprintf("A bulk request cannot have more than %d items", maxBulkItems)
+	}
+
+	return problems
+}
+
+// BulkDeleteItem represents a user or blog to delete in bulk. Version, when
+// set, is the version the delete is made against, as an If-Match header would
+// name it.
+type BulkDeleteItem struct {
+	ID      uint `json:"id"`
+	Version uint `json:"version"`
+}
+
+func (r *BulkDeleteItem) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if r.ID == 0 {
+		problems["ID"] = "Invalid ID"
+	}
+
+	return problems
+}
+
+// BulkResponse represents the outcome of a bulk write, with a result for each
+// item in the order they were sent.
+type BulkResponse[T any] struct {
+	Mode      string                `json:"mode"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []BulkItemResponse[T] `json:"results"`
+}
+
+// BulkItemResponse represents the outcome of one item of a bulk write. Result
+// is set if the item was written, along with ETag if the item's new version
+// is known, and Problem if it wasn't written.
+type BulkItemResponse[T any] struct {
+	Index   int              `json:"index"`
+	Status  int              `json:"status"`
+	Result  *T               `json:"result,omitempty"`
+	ETag    string           `json:"etag,omitempty"`
+	Problem *ProblemResponse `json:"problem,omitempty"`
+}
+
+// bulkItem is an item of a bulk request, validated through a pointer to it.
+type bulkItem[T any] interface {
+	*T
+	validator
+}
+
+// handleBulk serves a bulk write of items of type T. Each item is validated
+// and then turned into the model M by prepare, which also fails the items
+// the caller may not write with errItemForbidden. The items that get that far
+// are passed to write together, and respond converts each model written into
+// the result of its item, along with its version if it is known.
+//
+// The response is a 200 if every item was written. Otherwise it is a 207 for
+// a best effort write, and for an all or nothing write the status of the
+// first item that failed on its own account.
+func handleBulk[T any, PT bulkItem[T], M any, R any](
+	logger *slog.Logger,
+	prepare func(ctx context.Context, actor models.User, item T) (M, error),
+	write func(ctx context.Context, actor models.User, items []M, mode models.BulkMode) ([]models.BulkResult[M], error),
+	respond func(m M) (R, uint),
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Request validation
+		request, problems, err := decodeValid[*BulkRequest[T]](r)
+
+		if err != nil && len(problems) == 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to decode request",
+				slog.String("error", err.Error()))
+
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
+		}
+		if len(problems) > 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"Validation error",
+				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
+			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
+		}
+
+		mode := models.BulkMode(request.Mode)
+		if mode == "" {
+			mode = models.BulkAllOrNothing
+		}
+
+		// Every item is checked before any is written, so that all of their
+		// problems are reported at once
+		results := make([]BulkItemResponse[R], len(request.Items))
+		failed := false
+		var indices []int
+		var items []M
+		for i := range request.Items {
+			results[i].Index = i
+
+			if problems := PT(&request.Items[i]).Valid(ctx); len(problems) > 0 {
+				problem := newProblemResponse(r, http.StatusUnprocessableEntity, "Item failed validation", problems)
+				results[i].Status, results[i].Problem = problem.Status, &problem
+				failed = true
+				continue
+			}
+
+			item, err := prepare(ctx, actor, request.Items[i])
+			if err != nil {
+				results[i].Status, results[i].Problem = bulkItemProblem(r, err)
+				failed = true
+				continue
+			}
+
+			indices = append(indices, i)
+			items = append(items, item)
+		}
+
+		var written []models.BulkResult[M]
+		if failed && mode == models.BulkAllOrNothing {
+			written = make([]models.BulkResult[M], len(items))
+			for i := range written {
+				written[i].Err = services.ErrBulkAborted
+			}
+		} else if len(items) > 0 {
+			written, err = write(ctx, actor, items, mode)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to write items",
+					slog.String("error", err.Error()),
+				)
+
+				writeServiceError(w, r, err)
+				return
+			}
+		}
+
+		for k, result := range written {
+			i := indices[k]
+			if result.Err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to write item",
+					slog.Int("index", i),
+					slog.String("error", result.Err.Error()),
+				)
+
+				results[i].Status, results[i].Problem = bulkItemProblem(r, result.Err)
+				continue
+			}
+
+			value, version := respond(result.Value)
+			results[i].Status = http.StatusOK
+			results[i].Result = &value
+			if version > 0 {
+				results[i].ETag = etag(version)
+			}
+		}
+
+		response := BulkResponse[R]{Mode: string(mode), Results: results}
+		status := http.StatusOK
+		for _, result := range results {
+			if result.Status == http.StatusOK {
+				response.Succeeded++
+				continue
+			}
+
+			response.Failed++
+			if status == http.StatusOK && result.Status != http.StatusFailedDependency {
+				status = result.Status
+			}
+		}
+		if response.Failed > 0 && mode == models.BulkBestEffort {
+			status = http.StatusMultiStatus
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
+
+// bulkItemProblem describes why an item of a bulk request failed, as
+// writeServiceError would describe a request that failed the same way.
+func bulkItemProblem(r *http.Request, err error) (int, *ProblemResponse) {
+	problem := newProblemResponse(r, serviceErrorStatus(err), serviceErrorDetail(err), nil)
+	if errors.Is(err, errItemForbidden) {
+		problem = newProblemResponse(r, http.StatusForbidden, "You are not allowed to perform this action on this resource.", nil)
+	}
+
+	return problem.Status, &problem
+}
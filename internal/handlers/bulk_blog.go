@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// BulkBlogUpdate represents a blog to update in bulk. Version, when set, is
+// the version the update is made against, as an If-Match header would name
+// it.
+type BulkBlogUpdate struct {
+	ID      uint `json:"id"`
+	Version uint `json:"version"`
+	BlogRequest
+}
+
+func (r *BulkBlogUpdate) Valid(ctx context.Context) map[string]string {
+	problems := r.BlogRequest.Valid(ctx)
+
+	if r.ID == 0 {
+		problems["ID"] = "Invalid ID"
+	}
+
+	return problems
+}
+
+// blogBulkCreator represents a type capable of creating blogs in bulk.
+type blogBulkCreator interface {
+	CreateBlogs(ctx context.Context, blogs []models.Blog, mode models.BulkMode) ([]models.BulkResult[models.Blog], error)
+}
+
+// blogBulkUpdater represents a type capable of reading blogs and updating
+// them in bulk.
+type blogBulkUpdater interface {
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
+	UpdateBlogs(ctx context.Context, blogs []models.Blog, editorId uint, mode models.BulkMode) ([]models.BulkResult[models.Blog], error)
+}
+
+// blogBulkDeleter represents a type capable of reading blogs and deleting
+// them in bulk.
+type blogBulkDeleter interface {
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
+	DeleteBlogs(ctx context.Context, blogs []models.Blog, mode models.BulkMode) ([]models.BulkResult[models.Blog], error)
+}
+
+// newBulkBlogResult converts a blog written in bulk into the result of its
+// item.
+func newBulkBlogResult(blog models.Blog) (BlogResponse, uint) {
+	return BlogResponse{
+		ID:          blog.ID,
+		AuthorID:    blog.AuthorID,
+		Title:       blog.Title,
+		Body:        blog.Body,
+		Format:      formatMarkdown,
+		Excerpt:     blog.Excerpt,
+		Category:    blog.Category,
+		Tags:        tagList(blog.Tags),
+		Status:      string(blog.Status),
+		PublishAt:   optionalTime(blog.PublishAt),
+		Score:       blog.Score,
+		CreatedDate: blog.CreatedDate,
+	}, blog.Version
+}
+
+// @Summary		Create Blogs
+// @Description	Creates up to 500 Blogs in a single transaction
+// @Tags			blog
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[BlogRequest]	true	"Blogs to Create"
+// @Success		200		{object}	BulkResponse[BlogResponse]
+// @Success		207		{object}	BulkResponse[BlogResponse]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[BlogResponse]
+// @Failure		409		{object}	BulkResponse[BlogResponse]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/blog/bulk  [POST]
+func HandleBulkCreateBlogs(logger *slog.Logger, blogBulkCreator blogBulkCreator) http.Handler {
+	return handleBulk[BlogRequest](
+		logger,
+		func(ctx context.Context, actor models.User, item BlogRequest) (models.Blog, error) {
+			blog := models.Blog{
+				AuthorID:  item.AuthorID,
+				Title:     item.Title,
+				Body:      item.Body,
+				Excerpt:   item.Excerpt,
+				Category:  item.Category,
+				Tags:      item.Tags,
+				Status:    models.BlogStatus(item.Status),
+				PublishAt: item.PublishAt,
+			}
+
+			// Users may only create blogs under their own name
+			if !authz.CanBlog(actor, authz.Create, blog) {
+				return blog, errItemForbidden
+			}
+
+			return blog, nil
+		},
+		func(ctx context.Context, actor models.User, blogs []models.Blog, mode models.BulkMode) ([]models.BulkResult[models.Blog], error) {
+			return blogBulkCreator.CreateBlogs(ctx, blogs, mode)
+		},
+		newBulkBlogResult,
+	)
+}
+
+// @Summary		Update Blogs
+// @Description	Updates up to 500 Blogs by ID in a single transaction
+// @Tags			blog
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[BulkBlogUpdate]	true	"Blogs to Update"
+// @Success		200		{object}	BulkResponse[BlogResponse]
+// @Success		207		{object}	BulkResponse[BlogResponse]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[BlogResponse]
+// @Failure		404		{object}	BulkResponse[BlogResponse]
+// @Failure		409		{object}	BulkResponse[BlogResponse]
+// @Failure		412		{object}	BulkResponse[BlogResponse]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/blog/bulk  [PUT]
+func HandleBulkUpdateBlogs(logger *slog.Logger, blogBulkUpdater blogBulkUpdater) http.Handler {
+	return handleBulk[BulkBlogUpdate](
+		logger,
+		func(ctx context.Context, actor models.User, item BulkBlogUpdate) (models.Blog, error) {
+			blog := models.Blog{
+				ID:        item.ID,
+				AuthorID:  item.AuthorID,
+				Title:     item.Title,
+				Body:      item.Body,
+				Excerpt:   item.Excerpt,
+				Category:  item.Category,
+				Tags:      item.Tags,
+				Status:    models.BlogStatus(item.Status),
+				PublishAt: item.PublishAt,
+				Version:   item.Version,
+			}
+
+			// Only the author or an admin may update a blog, and only an
+			// admin may hand it over to someone else
+			existing, err := blogBulkUpdater.ReadBlog(ctx, uint64(item.ID), models.BlogExpand{}, false)
+			if err != nil {
+				return blog, err
+			}
+			if !authz.CanBlog(actor, authz.Update, existing) || !authz.CanBlog(actor, authz.Update, blog) {
+				return blog, errItemForbidden
+			}
+
+			return blog, nil
+		},
+		func(ctx context.Context, actor models.User, blogs []models.Blog, mode models.BulkMode) ([]models.BulkResult[models.Blog], error) {
+			return blogBulkUpdater.UpdateBlogs(ctx, blogs, actor.ID, mode)
+		},
+		newBulkBlogResult,
+	)
+}
+
+// @Summary		Delete Blogs
+// @Description	Deletes up to 500 Blogs by ID in a single transaction, along with their comments
+// @Tags			blog
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[BulkDeleteItem]	true	"Blogs to Delete"
+// @Success		200		{object}	BulkResponse[BulkDeleteItem]
+// @Success		207		{object}	BulkResponse[BulkDeleteItem]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[BulkDeleteItem]
+// @Failure		404		{object}	BulkResponse[BulkDeleteItem]
+// @Failure		412		{object}	BulkResponse[BulkDeleteItem]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/blog/bulk  [DELETE]
+func HandleBulkDeleteBlogs(logger *slog.Logger, blogBulkDeleter blogBulkDeleter) http.Handler {
+	return handleBulk[BulkDeleteItem](
+		logger,
+		func(ctx context.Context, actor models.User, item BulkDeleteItem) (models.Blog, error) {
+			blog := models.Blog{ID: item.ID, Version: item.Version}
+
+			// Only the author or an admin may delete a blog
+			existing, err := blogBulkDeleter.ReadBlog(ctx, uint64(item.ID), models.BlogExpand{}, false)
+			if err != nil {
+				return blog, err
+			}
+			if !authz.CanBlog(actor, authz.Delete, existing) {
+				return blog, errItemForbidden
+			}
+
+			return blog, nil
+		},
+		func(ctx context.Context, actor models.User, blogs []models.Blog, mode models.BulkMode) ([]models.BulkResult[models.Blog], error) {
+			return blogBulkDeleter.DeleteBlogs(ctx, blogs, mode)
+		},
+		func(blog models.Blog) (BulkDeleteItem, uint) {
+			return BulkDeleteItem{ID: blog.ID, Version: blog.Version}, 0
+		},
+	)
+}
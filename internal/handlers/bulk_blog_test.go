@@ -0,0 +1,334 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleBulkCreateBlogs(t *testing.T) {
+	first := models.Blog{AuthorID: 1, Title: "First", Body: "One"}
+	second := models.Blog{AuthorID: 1, Title: "Second", Body: "Two"}
+	created := func(blogs ...models.Blog) []models.BulkResult[models.Blog] {
+		results := make([]models.BulkResult[models.Blog], len(blogs))
+		for i, blog := range blogs {
+			blog.ID = uint(i + 1)
+			results[i].Value = blog
+		}
+		return results
+	}
+
+	tests := map[string]struct {
+		actor        models.User
+		body         string
+		mockCalled   bool
+		mockInput    []models.Blog
+		mockMode     models.BulkMode
+		mockOutput   []models.BulkResult[models.Blog]
+		wantStatus   int
+		wantStatuses []int
+	}{
+		"all created": {
+			actor:        testUser,
+			body:         `{"items":[{"authorid":1,"title":"First","body":"One"},{"authorid":1,"title":"Second","body":"Two"}]}`,
+			mockCalled:   true,
+			mockInput:    []models.Blog{first, second},
+			mockMode:     models.BulkAllOrNothing,
+			mockOutput:   created(first, second),
+			wantStatus:   200,
+			wantStatuses: []int{200, 200},
+		},
+		"invalid item aborts the rest": {
+			actor:        testUser,
+			body:         `{"items":[{"authorid":1,"title":"First","body":"One"},{"authorid":1,"body":"Two"}]}`,
+			wantStatus:   422,
+			wantStatuses: []int{424, 422},
+		},
+		"best effort skips invalid item": {
+			actor:        testUser,
+			body:         `{"mode":"best_effort","items":[{"authorid":1,"title":"First","body":"One"},{"authorid":1,"body":"Two"}]}`,
+			mockCalled:   true,
+			mockInput:    []models.Blog{first},
+			mockMode:     models.BulkBestEffort,
+			mockOutput:   created(first),
+			wantStatus:   207,
+			wantStatuses: []int{200, 422},
+		},
+		"someone else's blog": {
+			actor:        testUser,
+			body:         `{"items":[{"authorid":1,"title":"First","body":"One"},{"authorid":2,"title":"Second","body":"Two"}]}`,
+			wantStatus:   403,
+			wantStatuses: []int{424, 403},
+		},
+		"item fails to write": {
+			actor:      testUser,
+			body:       `{"items":[{"authorid":1,"title":"First","body":"One"},{"authorid":1,"title":"Second","body":"Two"}]}`,
+			mockCalled: true,
+			mockInput:  []models.Blog{first, second},
+			mockMode:   models.BulkAllOrNothing,
+			mockOutput: []models.BulkResult[models.Blog]{
+				{Err: services.ErrBulkAborted},
+				{Err: fmt.Errorf("author 1: %w", services.ErrInvalidReference)},
+			},
+			wantStatus:   422,
+			wantStatuses: []int{424, 422},
+		},
+		"admin creates for others": {
+			actor:        testAdmin,
+			body:         `{"items":[{"authorid":1,"title":"First","body":"One"}]}`,
+			mockCalled:   true,
+			mockInput:    []models.Blog{first},
+			mockMode:     models.BulkAllOrNothing,
+			mockOutput:   created(first),
+			wantStatus:   200,
+			wantStatuses: []int{200},
+		},
+		"invalid mode": {
+			actor:      testUser,
+			body:       `{"mode":"some","items":[{"authorid":1,"title":"First","body":"One"}]}`,
+			wantStatus: 422,
+		},
+		"no items": {
+			actor:      testUser,
+			body:       `{"items":[]}`,
+			wantStatus: 422,
+		},
+		"too many items": {
+			actor:      testUser,
+			body:       `{"items":[` + strings.Repeat(`{"authorid":1,"title":"t"},`, maxBulkItems) + `{"authorid":1,"title":"t"}]}`,
+			wantStatus: 422,
+		},
+		"anonymous": {
+			body:       `{"items":[{"authorid":1,"title":"First","body":"One"}]}`,
+			wantStatus: 401,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/blog/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			blogBulkCreator := new(mock.BlogBulkCreator)
+			if tc.mockCalled {
+				blogBulkCreator.On("CreateBlogs", req.Context(), tc.mockInput, tc.mockMode).Return(tc.mockOutput, nil)
+			}
+
+			handler := HandleBulkCreateBlogs(logger, blogBulkCreator)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+
+			if tc.mockCalled {
+				blogBulkCreator.AssertExpectations(t)
+			} else {
+				blogBulkCreator.AssertNumberOfCalls(t, "CreateBlogs", 0)
+			}
+
+			if tc.wantStatuses == nil {
+				return
+			}
+
+			var response BulkResponse[BlogResponse]
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(response.Results) != len(tc.wantStatuses) {
+				t.Fatalf("want %d results, got %d", len(tc.wantStatuses), len(response.Results))
+			}
+			for i, result := range response.Results {
+				if result.Index != i || result.Status != tc.wantStatuses[i] {
+					t.Errorf("result %d: want status %d, got index %d status %d", i, tc.wantStatuses[i], result.Index, result.Status)
+				}
+				if (result.Result != nil) != (result.Status == 200) || (result.Problem != nil) == (result.Status == 200) {
+					t.Errorf("result %d: want a result only on success and a problem otherwise, got %+v", i, result)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleBulkUpdateBlogs(t *testing.T) {
+	tests := map[string]struct {
+		actor        models.User
+		existing     models.Blog
+		body         string
+		mockCalled   bool
+		mockInput    []models.Blog
+		wantStatus   int
+		wantETag     string
+		wantStatuses []int
+	}{
+		"happy path": {
+			actor:        testUser,
+			existing:     models.Blog{ID: 1, AuthorID: 1},
+			body:         `{"items":[{"id":1,"version":3,"authorid":1,"title":"New"}]}`,
+			mockCalled:   true,
+			mockInput:    []models.Blog{{ID: 1, AuthorID: 1, Title: "New", Version: 3}},
+			wantStatus:   200,
+			wantETag:     `"4"`,
+			wantStatuses: []int{200},
+		},
+		"not the author": {
+			actor:        testOtherUser,
+			existing:     models.Blog{ID: 1, AuthorID: 1},
+			body:         `{"items":[{"id":1,"authorid":2,"title":"New"}]}`,
+			wantStatus:   403,
+			wantStatuses: []int{403},
+		},
+		"hand over to someone else": {
+			actor:        testUser,
+			existing:     models.Blog{ID: 1, AuthorID: 1},
+			body:         `{"items":[{"id":1,"authorid":2,"title":"New"}]}`,
+			wantStatus:   403,
+			wantStatuses: []int{403},
+		},
+		"missing id": {
+			actor:        testUser,
+			existing:     models.Blog{ID: 1, AuthorID: 1},
+			body:         `{"items":[{"authorid":1,"title":"New"}]}`,
+			wantStatus:   422,
+			wantStatuses: []int{422},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/blog/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			blogBulkUpdater := new(mock.BlogBulkUpdater)
+			blogBulkUpdater.On("ReadBlog", req.Context(), uint64(1), models.BlogExpand{}, false).Return(tc.existing, nil)
+			if tc.mockCalled {
+				updated := tc.mockInput[0]
+				updated.Version++
+				blogBulkUpdater.On("UpdateBlogs", req.Context(), tc.mockInput, tc.actor.ID, models.BulkAllOrNothing).
+					Return([]models.BulkResult[models.Blog]{{Value: updated}}, nil)
+			}
+
+			handler := HandleBulkUpdateBlogs(logger, blogBulkUpdater)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if !tc.mockCalled {
+				blogBulkUpdater.AssertNumberOfCalls(t, "UpdateBlogs", 0)
+			}
+
+			var response BulkResponse[BlogResponse]
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			for i, result := range response.Results {
+				if result.Status != tc.wantStatuses[i] {
+					t.Errorf("result %d: want status %d, got %d", i, tc.wantStatuses[i], result.Status)
+				}
+				if result.ETag != tc.wantETag {
+					t.Errorf("result %d: want ETag %s, got %s", i, tc.wantETag, result.ETag)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleBulkDeleteBlogs(t *testing.T) {
+	tests := map[string]struct {
+		actor        models.User
+		body         string
+		mockCalled   bool
+		mockInput    []models.Blog
+		mockMode     models.BulkMode
+		mockOutput   []models.BulkResult[models.Blog]
+		wantStatus   int
+		wantStatuses []int
+	}{
+		"happy path": {
+			actor:        testUser,
+			body:         `{"items":[{"id":1,"version":2}]}`,
+			mockCalled:   true,
+			mockInput:    []models.Blog{{ID: 1, Version: 2}},
+			mockMode:     models.BulkAllOrNothing,
+			mockOutput:   []models.BulkResult[models.Blog]{{Value: models.Blog{ID: 1, Version: 2}}},
+			wantStatus:   200,
+			wantStatuses: []int{200},
+		},
+		"missing blog": {
+			actor:        testUser,
+			body:         `{"mode":"best_effort","items":[{"id":1},{"id":9}]}`,
+			mockCalled:   true,
+			mockInput:    []models.Blog{{ID: 1}},
+			mockMode:     models.BulkBestEffort,
+			mockOutput:   []models.BulkResult[models.Blog]{{Value: models.Blog{ID: 1}}},
+			wantStatus:   207,
+			wantStatuses: []int{200, 404},
+		},
+		"stale version": {
+			actor:      testUser,
+			body:       `{"items":[{"id":1,"version":2}]}`,
+			mockCalled: true,
+			mockInput:  []models.Blog{{ID: 1, Version: 2}},
+			mockMode:   models.BulkAllOrNothing,
+			mockOutput: []models.BulkResult[models.Blog]{
+				{Err: fmt.Errorf("blog 1: %w", services.ErrPreconditionFailed)},
+			},
+			wantStatus:   412,
+			wantStatuses: []int{412},
+		},
+		"not the author": {
+			actor:        testOtherUser,
+			body:         `{"items":[{"id":1}]}`,
+			wantStatus:   403,
+			wantStatuses: []int{403},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/blog/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			blogBulkDeleter := new(mock.BlogBulkDeleter)
+			blogBulkDeleter.On("ReadBlog", req.Context(), uint64(1), models.BlogExpand{}, false).Return(models.Blog{ID: 1, AuthorID: 1}, nil)
+			blogBulkDeleter.On("ReadBlog", req.Context(), uint64(9), models.BlogExpand{}, false).Return(models.Blog{}, services.ErrNotFound)
+			if tc.mockCalled {
+				blogBulkDeleter.On("DeleteBlogs", req.Context(), tc.mockInput, tc.mockMode).Return(tc.mockOutput, nil)
+			}
+
+			handler := HandleBulkDeleteBlogs(logger, blogBulkDeleter)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if !tc.mockCalled {
+				blogBulkDeleter.AssertNumberOfCalls(t, "DeleteBlogs", 0)
+			}
+
+			var response BulkResponse[BulkDeleteItem]
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(response.Results) != len(tc.wantStatuses) {
+				t.Fatalf("want %d results, got %d", len(tc.wantStatuses), len(response.Results))
+			}
+			for i, result := range response.Results {
+				if result.Status != tc.wantStatuses[i] {
+					t.Errorf("result %d: want status %d, got %d", i, tc.wantStatuses[i], result.Status)
+				}
+			}
+		})
+	}
+}
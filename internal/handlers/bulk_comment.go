@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// BulkCommentUpdate represents a comment to update in bulk. ID picks out one
+// of the user's comments on the blog when they have several, and ParentID is
+// ignored. Version, when set, is the version the update is made against, as
+// an If-Match header would name it.
+type BulkCommentUpdate struct {
+	ID      uint
+	Version uint
+	CommentRequest
+}
+
+// BulkCommentDelete represents a comment to delete in bulk, picked out the way
+// the query parameters of a single delete pick it out. Version, when set, is
+// the version the delete is made against.
+type BulkCommentDelete struct {
+	ID      uint
+	UserID  uint
+	BlogID  uint
+	Version uint
+}
+
+func (r *BulkCommentDelete) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if r.UserID == 0 {
+		problems["UserID"] = "Invalid UserId"
+	}
+	if r.BlogID == 0 {
+		problems["BlogID"] = "Invalid BlogId"
+	}
+
+	return problems
+}
+
+// commentBulkCreator represents a type capable of creating comments in bulk.
+type commentBulkCreator interface {
+	CreateComments(ctx context.Context, comments []models.Comment, viewerId uint, allBlogs bool, mode models.BulkMode) ([]models.BulkResult[models.Comment], error)
+}
+
+// commentBulkUpdater represents a type capable of updating comments in bulk.
+type commentBulkUpdater interface {
+	UpdateComments(ctx context.Context, comments []models.Comment, editorId uint, mode models.BulkMode) ([]models.BulkResult[models.Comment], error)
+}
+
+// commentBulkDeleter represents a type capable of deleting comments in bulk.
+type commentBulkDeleter interface {
+	DeleteComments(ctx context.Context, comments []models.Comment, mode models.BulkMode) ([]models.BulkResult[models.Comment], error)
+}
+
+// newBulkCommentResult converts a comment written in bulk into the result of
+// its item.
+func newBulkCommentResult(comment models.Comment) (CommentResponse, uint) {
+	return CommentResponse{
+		ID:          comment.ID,
+		ParentID:    comment.ParentID,
+		UserID:      comment.UserID,
+		BlogID:      comment.BlogID,
+		Message:     comment.Message,
+		CreatedDate: comment.CreatedDate,
+	}, comment.Version
+}
+
+// @Summary		Create Comments
+// @Description	Creates up to 500 Comments in a single transaction
+// @Tags			comment
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[CommentRequest]	true	"Comments to Create"
+// @Success		200		{object}	BulkResponse[CommentResponse]
+// @Success		207		{object}	BulkResponse[CommentResponse]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[CommentResponse]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/comment/bulk  [POST]
+func HandleBulkCreateComments(logger *slog.Logger, commentBulkCreator commentBulkCreator) http.Handler {
+	return handleBulk[CommentRequest](
+		logger,
+		func(ctx context.Context, actor models.User, item CommentRequest) (models.Comment, error) {
+			comment := models.Comment{
+				ParentID: item.ParentID,
+				UserID:   item.UserID,
+				BlogID:   item.BlogID,
+				Message:  item.Message,
+			}
+
+			// Users may only comment under their own name
+			if !authz.CanComment(actor, authz.Create, comment) {
+				return comment, errItemForbidden
+			}
+
+			return comment, nil
+		},
+		func(ctx context.Context, actor models.User, comments []models.Comment, mode models.BulkMode) ([]models.BulkResult[models.Comment], error) {
+			return commentBulkCreator.CreateComments(ctx, comments, actor.ID, authz.CanReadUnpublished(actor), mode)
+		},
+		newBulkCommentResult,
+	)
+}
+
+// @Summary		Update Comments
+// @Description	Updates up to 500 Comments in a single transaction
+// @Tags			comment
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[BulkCommentUpdate]	true	"Comments to Update"
+// @Success		200		{object}	BulkResponse[CommentResponse]
+// @Success		207		{object}	BulkResponse[CommentResponse]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[CommentResponse]
+// @Failure		404		{object}	BulkResponse[CommentResponse]
+// @Failure		412		{object}	BulkResponse[CommentResponse]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/comment/bulk  [PUT]
+func HandleBulkUpdateComments(logger *slog.Logger, commentBulkUpdater commentBulkUpdater) http.Handler {
+	return handleBulk[BulkCommentUpdate](
+		logger,
+		func(ctx context.Context, actor models.User, item BulkCommentUpdate) (models.Comment, error) {
+			comment := models.Comment{
+				ID:      item.ID,
+				UserID:  item.UserID,
+				BlogID:  item.BlogID,
+				Message: item.Message,
+				Version: item.Version,
+			}
+
+			// Only the author or an admin may update a comment
+			if !authz.CanComment(actor, authz.Update, comment) {
+				return comment, errItemForbidden
+			}
+
+			return comment, nil
+		},
+		func(ctx context.Context, actor models.User, comments []models.Comment, mode models.BulkMode) ([]models.BulkResult[models.Comment], error) {
+			return commentBulkUpdater.UpdateComments(ctx, comments, actor.ID, mode)
+		},
+		newBulkCommentResult,
+	)
+}
+
+// @Summary		Delete Comments
+// @Description	Deletes up to 500 Comments in a single transaction, along with their replies
+// @Tags			comment
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[BulkCommentDelete]	true	"Comments to Delete"
+// @Success		200		{object}	BulkResponse[BulkCommentDelete]
+// @Success		207		{object}	BulkResponse[BulkCommentDelete]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[BulkCommentDelete]
+// @Failure		404		{object}	BulkResponse[BulkCommentDelete]
+// @Failure		412		{object}	BulkResponse[BulkCommentDelete]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/comment/bulk  [DELETE]
+func HandleBulkDeleteComments(logger *slog.Logger, commentBulkDeleter commentBulkDeleter) http.Handler {
+	return handleBulk[BulkCommentDelete](
+		logger,
+		func(ctx context.Context, actor models.User, item BulkCommentDelete) (models.Comment, error) {
+			comment := models.Comment{
+				ID:      item.ID,
+				UserID:  item.UserID,
+				BlogID:  item.BlogID,
+				Version: item.Version,
+			}
+
+			// Only the author or an admin may delete a comment
+			if !authz.CanComment(actor, authz.Delete, comment) {
+				return comment, errItemForbidden
+			}
+
+			return comment, nil
+		},
+		func(ctx context.Context, actor models.User, comments []models.Comment, mode models.BulkMode) ([]models.BulkResult[models.Comment], error) {
+			return commentBulkDeleter.DeleteComments(ctx, comments, mode)
+		},
+		func(comment models.Comment) (BulkCommentDelete, uint) {
+			return BulkCommentDelete{
+				ID:      comment.ID,
+				UserID:  comment.UserID,
+				BlogID:  comment.BlogID,
+				Version: comment.Version,
+			}, 0
+		},
+	)
+}
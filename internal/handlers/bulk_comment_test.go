@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestHandleBulkCreateComments(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		allBlogs   bool
+		body       string
+		mockCalled bool
+		mockInput  []models.Comment
+		mockMode   models.BulkMode
+		mockError  error
+		wantStatus int
+	}{
+		"happy path": {
+			actor:      testUser,
+			body:       `{"items":[{"UserID":1,"BlogID":1,"Message":"First"},{"UserID":1,"BlogID":1,"ParentID":4,"Message":"Reply"}]}`,
+			mockCalled: true,
+			mockInput: []models.Comment{
+				{UserID: 1, BlogID: 1, Message: "First"},
+				{UserID: 1, BlogID: 1, ParentID: 4, Message: "Reply"},
+			},
+			mockMode:   models.BulkAllOrNothing,
+			wantStatus: 200,
+		},
+		"admin": {
+			actor:      testAdmin,
+			allBlogs:   true,
+			body:       `{"items":[{"UserID":3,"BlogID":1,"Message":"First"}]}`,
+			mockCalled: true,
+			mockInput:  []models.Comment{{UserID: 3, BlogID: 1, Message: "First"}},
+			mockMode:   models.BulkAllOrNothing,
+			wantStatus: 200,
+		},
+		"under someone else's name": {
+			actor:      testUser,
+			body:       `{"mode":"best_effort","items":[{"UserID":1,"BlogID":1,"Message":"First"},{"UserID":2,"BlogID":1,"Message":"Second"}]}`,
+			mockCalled: true,
+			mockInput:  []models.Comment{{UserID: 1, BlogID: 1, Message: "First"}},
+			mockMode:   models.BulkBestEffort,
+			wantStatus: 207,
+		},
+		"empty message": {
+			actor:      testUser,
+			body:       `{"items":[{"UserID":1,"BlogID":1}]}`,
+			wantStatus: 422,
+		},
+		"transaction fails": {
+			actor:      testUser,
+			body:       `{"items":[{"UserID":1,"BlogID":1,"Message":"First"}]}`,
+			mockCalled: true,
+			mockInput:  []models.Comment{{UserID: 1, BlogID: 1, Message: "First"}},
+			mockMode:   models.BulkAllOrNothing,
+			mockError:  errors.New("failed to commit transaction"),
+			wantStatus: 500,
+		},
+		"malformed body": {
+			actor:      testUser,
+			body:       `{"items":`,
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/comment/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			commentBulkCreator := new(mock.CommentBulkCreator)
+			if tc.mockCalled {
+				var results []models.BulkResult[models.Comment]
+				if tc.mockError == nil {
+					results = make([]models.BulkResult[models.Comment], len(tc.mockInput))
+					for i, comment := range tc.mockInput {
+						comment.ID = uint(i + 1)
+						results[i].Value = comment
+					}
+				}
+				commentBulkCreator.On("CreateComments", req.Context(), tc.mockInput, tc.actor.ID, tc.allBlogs, tc.mockMode).Return(results, tc.mockError)
+			}
+
+			handler := HandleBulkCreateComments(logger, commentBulkCreator)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if !tc.mockCalled {
+				commentBulkCreator.AssertNumberOfCalls(t, "CreateComments", 0)
+			}
+		})
+	}
+}
+
+func TestHandleBulkUpdateComments(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		body       string
+		mockCalled bool
+		mockInput  []models.Comment
+		wantStatus int
+	}{
+		"happy path": {
+			actor:      testUser,
+			body:       `{"items":[{"ID":5,"Version":2,"UserID":1,"BlogID":1,"ParentID":9,"Message":"Edited"}]}`,
+			mockCalled: true,
+			mockInput:  []models.Comment{{ID: 5, UserID: 1, BlogID: 1, Message: "Edited", Version: 2}},
+			wantStatus: 200,
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			body:       `{"items":[{"UserID":1,"BlogID":1,"Message":"Edited"}]}`,
+			wantStatus: 403,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/comment/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			commentBulkUpdater := new(mock.CommentBulkUpdater)
+			if tc.mockCalled {
+				commentBulkUpdater.On("UpdateComments", req.Context(), tc.mockInput, tc.actor.ID, models.BulkAllOrNothing).
+					Return([]models.BulkResult[models.Comment]{{Value: tc.mockInput[0]}}, nil)
+			}
+
+			handler := HandleBulkUpdateComments(logger, commentBulkUpdater)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if !tc.mockCalled {
+				commentBulkUpdater.AssertNumberOfCalls(t, "UpdateComments", 0)
+			}
+		})
+	}
+}
+
+func TestHandleBulkDeleteComments(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		body       string
+		mockCalled bool
+		mockInput  []models.Comment
+		wantStatus int
+	}{
+		"happy path": {
+			actor:      testUser,
+			body:       `{"items":[{"ID":5,"UserID":1,"BlogID":1},{"UserID":1,"BlogID":2,"Version":3}]}`,
+			mockCalled: true,
+			mockInput:  []models.Comment{{ID: 5, UserID: 1, BlogID: 1}, {UserID: 1, BlogID: 2, Version: 3}},
+			wantStatus: 200,
+		},
+		"missing blog": {
+			actor:      testUser,
+			body:       `{"items":[{"UserID":1}]}`,
+			wantStatus: 422,
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			body:       `{"items":[{"UserID":1,"BlogID":1}]}`,
+			wantStatus: 403,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/comment/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			commentBulkDeleter := new(mock.CommentBulkDeleter)
+			if tc.mockCalled {
+				results := make([]models.BulkResult[models.Comment], len(tc.mockInput))
+				for i, comment := range tc.mockInput {
+					results[i].Value = comment
+				}
+				commentBulkDeleter.On("DeleteComments", req.Context(), tc.mockInput, models.BulkAllOrNothing).Return(results, nil)
+			}
+
+			handler := HandleBulkDeleteComments(logger, commentBulkDeleter)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if !tc.mockCalled {
+				commentBulkDeleter.AssertNumberOfCalls(t, "DeleteComments", 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// BulkUserUpdate represents a user to update in bulk. Version, when set, is
+// the version the update is made against, as an If-Match header would name
+// it.
+type BulkUserUpdate struct {
+	ID      uint `json:"id"`
+	Version uint `json:"version"`
+	UserRequest
+}
+
+func (r *BulkUserUpdate) Valid(ctx context.Context) map[string]string {
+	problems := r.UserRequest.Valid(ctx)
+
+	if r.ID == 0 {
+		problems["ID"] = "Invalid ID"
+	}
+
+	return problems
+}
+
+// userBulkCreator represents a type capable of creating users in bulk.
+type userBulkCreator interface {
+	CreateUsers(ctx context.Context, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error)
+}
+
+// userBulkUpdater represents a type capable of updating users in bulk.
+type userBulkUpdater interface {
+	UpdateUsers(ctx context.Context, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error)
+}
+
+// userBulkDeleter represents a type capable of deleting users in bulk.
+type userBulkDeleter interface {
+	DeleteUsers(ctx context.Context, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error)
+}
+
+// newBulkUserResult converts a user written in bulk into the result of its
+// item.
+func newBulkUserResult(user models.User) (UserResponse, uint) {
+	return UserResponse{ID: user.ID, Name: user.Name, Email: user.Email}, user.Version
+}
+
+// @Summary		Create Users
+// @Description	Creates up to 500 Users in a single transaction. Only admins may create users in bulk.
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[UserRequest]	true	"Users to Create"
+// @Success		200		{object}	BulkResponse[UserResponse]
+// @Success		207		{object}	BulkResponse[UserResponse]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[UserResponse]
+// @Failure		409		{object}	BulkResponse[UserResponse]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/user/bulk  [POST]
+func HandleBulkCreateUsers(logger *slog.Logger, userBulkCreator userBulkCreator) http.Handler {
+	return handleBulk[UserRequest](
+		logger,
+		func(ctx context.Context, actor models.User, item UserRequest) (models.User, error) {
+			user := models.User{
+				Name:     item.Name,
+				Email:    item.Email,
+				Password: item.Password,
+			}
+
+			if !authz.CanUser(actor, authz.Create, user) {
+				return user, errItemForbidden
+			}
+
+			return user, nil
+		},
+		func(ctx context.Context, actor models.User, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error) {
+			return userBulkCreator.CreateUsers(ctx, users, mode)
+		},
+		newBulkUserResult,
+	)
+}
+
+// @Summary		Update Users
+// @Description	Updates up to 500 Users by ID in a single transaction
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[BulkUserUpdate]	true	"Users to Update"
+// @Success		200		{object}	BulkResponse[UserResponse]
+// @Success		207		{object}	BulkResponse[UserResponse]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[UserResponse]
+// @Failure		404		{object}	BulkResponse[UserResponse]
+// @Failure		409		{object}	BulkResponse[UserResponse]
+// @Failure		412		{object}	BulkResponse[UserResponse]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/user/bulk  [PUT]
+func HandleBulkUpdateUsers(logger *slog.Logger, userBulkUpdater userBulkUpdater) http.Handler {
+	return handleBulk[BulkUserUpdate](
+		logger,
+		func(ctx context.Context, actor models.User, item BulkUserUpdate) (models.User, error) {
+			user := models.User{
+				ID:       item.ID,
+				Name:     item.Name,
+				Email:    item.Email,
+				Password: item.Password,
+				Version:  item.Version,
+			}
+
+			// Users may only update their own account
+			if !authz.CanUser(actor, authz.Update, user) {
+				return user, errItemForbidden
+			}
+
+			return user, nil
+		},
+		func(ctx context.Context, actor models.User, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error) {
+			return userBulkUpdater.UpdateUsers(ctx, users, mode)
+		},
+		newBulkUserResult,
+	)
+}
+
+// @Summary		Delete Users
+// @Description	Deletes up to 500 Users by ID in a single transaction, along with their blogs and comments
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			request	body		BulkRequest[BulkDeleteItem]	true	"Users to Delete"
+// @Success		200		{object}	BulkResponse[BulkDeleteItem]
+// @Success		207		{object}	BulkResponse[BulkDeleteItem]	"Some items of a best effort request failed"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	BulkResponse[BulkDeleteItem]
+// @Failure		404		{object}	BulkResponse[BulkDeleteItem]
+// @Failure		412		{object}	BulkResponse[BulkDeleteItem]
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/user/bulk  [DELETE]
+func HandleBulkDeleteUsers(logger *slog.Logger, userBulkDeleter userBulkDeleter) http.Handler {
+	return handleBulk[BulkDeleteItem](
+		logger,
+		func(ctx context.Context, actor models.User, item BulkDeleteItem) (models.User, error) {
+			user := models.User{ID: item.ID, Version: item.Version}
+
+			// Users may only delete their own account
+			if !authz.CanUser(actor, authz.Delete, user) {
+				return user, errItemForbidden
+			}
+
+			return user, nil
+		},
+		func(ctx context.Context, actor models.User, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error) {
+			return userBulkDeleter.DeleteUsers(ctx, users, mode)
+		},
+		func(user models.User) (BulkDeleteItem, uint) {
+			return BulkDeleteItem{ID: user.ID, Version: user.Version}, 0
+		},
+	)
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleBulkCreateUsers(t *testing.T) {
+	john := models.User{Name: "john", Email: "john@example.com", Password: "password123"}
+	jane := models.User{Name: "jane", Email: "jane@example.com", Password: "password456"}
+
+	tests := map[string]struct {
+		actor        models.User
+		body         string
+		mockCalled   bool
+		mockInput    []models.User
+		mockMode     models.BulkMode
+		mockOutput   []models.BulkResult[models.User]
+		wantStatus   int
+		wantStatuses []int
+	}{
+		"admin creates users": {
+			actor:      testAdmin,
+			body:       `{"items":[{"name":"john","email":"john@example.com","password":"password123"},{"name":"jane","email":"jane@example.com","password":"password456"}]}`,
+			mockCalled: true,
+			mockInput:  []models.User{john, jane},
+			mockMode:   models.BulkAllOrNothing,
+			mockOutput: []models.BulkResult[models.User]{
+				{Value: models.User{ID: 1, Name: "john", Email: "john@example.com"}},
+				{Value: models.User{ID: 2, Name: "jane", Email: "jane@example.com"}},
+			},
+			wantStatus:   200,
+			wantStatuses: []int{200, 200},
+		},
+		"duplicate email with best effort": {
+			actor:      testAdmin,
+			body:       `{"mode":"best_effort","items":[{"name":"john","email":"john@example.com","password":"password123"},{"name":"jane","email":"jane@example.com","password":"password456"}]}`,
+			mockCalled: true,
+			mockInput:  []models.User{john, jane},
+			mockMode:   models.BulkBestEffort,
+			mockOutput: []models.BulkResult[models.User]{
+				{Value: models.User{ID: 1, Name: "john", Email: "john@example.com"}},
+				{Err: fmt.Errorf("item 1: %w", services.ErrConflict)},
+			},
+			wantStatus:   207,
+			wantStatuses: []int{200, 409},
+		},
+		"not an admin": {
+			actor:        testUser,
+			body:         `{"items":[{"name":"john","email":"john@example.com","password":"password123"}]}`,
+			wantStatus:   403,
+			wantStatuses: []int{403},
+		},
+		"invalid email": {
+			actor:        testAdmin,
+			body:         `{"items":[{"name":"john","email":"john","password":"password123"}]}`,
+			wantStatus:   422,
+			wantStatuses: []int{422},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/user/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			userBulkCreator := new(mock.UserBulkCreator)
+			if tc.mockCalled {
+				userBulkCreator.On("CreateUsers", req.Context(), tc.mockInput, tc.mockMode).Return(tc.mockOutput, nil)
+			}
+
+			handler := HandleBulkCreateUsers(logger, userBulkCreator)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if !tc.mockCalled {
+				userBulkCreator.AssertNumberOfCalls(t, "CreateUsers", 0)
+			}
+
+			var response BulkResponse[UserResponse]
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(response.Results) != len(tc.wantStatuses) {
+				t.Fatalf("want %d results, got %d", len(tc.wantStatuses), len(response.Results))
+			}
+			for i, result := range response.Results {
+				if result.Status != tc.wantStatuses[i] {
+					t.Errorf("result %d: want status %d, got %d", i, tc.wantStatuses[i], result.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleBulkUpdateUsers(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		body       string
+		mockCalled bool
+		mockInput  []models.User
+		wantStatus int
+	}{
+		"own account": {
+			actor:      testUser,
+			body:       `{"items":[{"id":1,"version":2,"name":"john","email":"john@example.com","password":"password123"}]}`,
+			mockCalled: true,
+			mockInput:  []models.User{{ID: 1, Name: "john", Email: "john@example.com", Password: "password123", Version: 2}},
+			wantStatus: 200,
+		},
+		"someone else's account": {
+			actor:      testUser,
+			body:       `{"items":[{"id":2,"name":"jane","email":"jane@example.com","password":"password123"}]}`,
+			wantStatus: 403,
+		},
+		"missing id": {
+			actor:      testUser,
+			body:       `{"items":[{"name":"john","email":"john@example.com","password":"password123"}]}`,
+			wantStatus: 422,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/user/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			userBulkUpdater := new(mock.UserBulkUpdater)
+			if tc.mockCalled {
+				userBulkUpdater.On("UpdateUsers", req.Context(), tc.mockInput, models.BulkAllOrNothing).
+					Return([]models.BulkResult[models.User]{{Value: tc.mockInput[0]}}, nil)
+			}
+
+			handler := HandleBulkUpdateUsers(logger, userBulkUpdater)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if !tc.mockCalled {
+				userBulkUpdater.AssertNumberOfCalls(t, "UpdateUsers", 0)
+			}
+		})
+	}
+}
+
+func TestHandleBulkDeleteUsers(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		body       string
+		mockCalled bool
+		mockInput  []models.User
+		wantStatus int
+	}{
+		"own account": {
+			actor:      testUser,
+			body:       `{"items":[{"id":1}]}`,
+			mockCalled: true,
+			mockInput:  []models.User{{ID: 1}},
+			wantStatus: 200,
+		},
+		"admin deletes several": {
+			actor:      testAdmin,
+			body:       `{"items":[{"id":1},{"id":2,"version":4}]}`,
+			mockCalled: true,
+			mockInput:  []models.User{{ID: 1}, {ID: 2, Version: 4}},
+			wantStatus: 200,
+		},
+		"someone else's account": {
+			actor:      testUser,
+			body:       `{"items":[{"id":1},{"id":2}]}`,
+			wantStatus: 403,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/user/bulk", strings.NewReader(tc.body))
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			userBulkDeleter := new(mock.UserBulkDeleter)
+			if tc.mockCalled {
+				results := make([]models.BulkResult[models.User], len(tc.mockInput))
+				for i, user := range tc.mockInput {
+					results[i].Value = user
+				}
+				userBulkDeleter.On("DeleteUsers", req.Context(), tc.mockInput, models.BulkAllOrNothing).Return(results, nil)
+			}
+
+			handler := HandleBulkDeleteUsers(logger, userBulkDeleter)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if !tc.mockCalled {
+				userBulkDeleter.AssertNumberOfCalls(t, "DeleteUsers", 0)
+			}
+		})
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"unicode/utf8"
 )
 
+// CommentRequest represents the request for creating a Comment. ParentID is
+// the comment being replied to, if any.
 type CommentRequest struct {
-	UserID  uint
-	BlogID  uint
-	Message string
+	UserID   uint
+	BlogID   uint
+	ParentID uint
+	Message  string
 }
 
 func (r *CommentRequest) Valid(ctx context.Context) map[string]string {
@@ -2,9 +2,23 @@ package handlers
 
 import "time"
 
+// CommentResponse represents a comment. ParentID is the comment it replies
+// to, and is zero for comments on the blog itself. User and Blog are left out
+// unless they were expanded, and DeletedAt unless the comment is deleted.
 type CommentResponse struct {
+	ID          uint
+	ParentID    uint
 	UserID      uint
+	User        *UserRefResponse `json:",omitempty"`
 	BlogID      uint
+	Blog        *BlogRefResponse `json:",omitempty"`
 	Message     string
 	CreatedDate time.Time
+	DeletedAt   *time.Time `json:",omitempty"`
+}
+
+// CommentThreadResponse represents a comment together with the replies to it.
+type CommentThreadResponse struct {
+	CommentResponse
+	Replies []CommentThreadResponse
 }
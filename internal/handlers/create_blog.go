@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/chickey/blog/internal/authz"
 	"github.com/chickey/blog/internal/models"
 )
 
@@ -21,16 +22,28 @@ type blogCreator interface {
 //	@Tags			blog
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
 //	@Param			request	body		BlogRequest	true	"Blog to Create"
+//	@Param			Idempotency-Key	header	string	false	"Key that makes retrying the request replay its response rather than create another blog"
 //	@Success		200		{object}	uint
-//	@Failure		400		{object}	string
-//	@Failure		404		{object}	string
-//	@Failure		500		{object}	string
+//	@Header			200		{string}	Idempotent-Replayed	"Set when the response is replayed for a retried request"
+//	@Failure		400		{object}	ProblemResponse
+//	@Failure		401		{object}	ProblemResponse
+//	@Failure		403		{object}	ProblemResponse
+//	@Failure		404		{object}	ProblemResponse
+//	@Failure		409		{object}	ProblemResponse
+//	@Failure		422		{object}	ProblemResponse
+//	@Failure		500		{object}	ProblemResponse
 //	@Router			/blog  [POST]
 func HandleCreateBlog(logger *slog.Logger, blogCreator blogCreator) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
 		// Request validation
 		request, problems, err := decodeValid[*BlogRequest](r)
 
@@ -40,7 +53,8 @@ func HandleCreateBlog(logger *slog.Logger, blogCreator blogCreator) http.Handler
 				"failed to decode request",
 				slog.String("error", err.Error()))
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
 		}
 		if len(problems) > 0 {
 			logger.ErrorContext(
@@ -48,14 +62,29 @@ func HandleCreateBlog(logger *slog.Logger, blogCreator blogCreator) http.Handler
 				"Validation error",
 				slog.String("Validation failures:", fmt.Sprintf("%v", problems)),
 			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
 		}
 
 		modelRequest := models.Blog{
-			AuthorID: request.AuthorID,
-			Title:    request.Title,
-			Score:    request.Score,
+			AuthorID:  request.AuthorID,
+			Title:     request.Title,
+			Body:      request.Body,
+			Excerpt:   request.Excerpt,
+			Category:  request.Category,
+			Tags:      request.Tags,
+			Status:    models.BlogStatus(request.Status),
+			PublishAt: request.PublishAt,
+		}
+
+		// Users may only create blogs under their own name
+		if !authz.CanBlog(actor, authz.Create, modelRequest) {
+			writeForbidden(w, r)
+			return
 		}
-		// Read the blog
+
+		// Create the blog
 		blog, err := blogCreator.CreateBlog(ctx, modelRequest)
 		if err != nil {
 			logger.ErrorContext(
@@ -64,7 +93,7 @@ func HandleCreateBlog(logger *slog.Logger, blogCreator blogCreator) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
@@ -73,6 +102,13 @@ func HandleCreateBlog(logger *slog.Logger, blogCreator blogCreator) http.Handler
 			ID:          blog.ID,
 			AuthorID:    blog.AuthorID,
 			Title:       blog.Title,
+			Body:        blog.Body,
+			Format:      formatMarkdown,
+			Excerpt:     blog.Excerpt,
+			Category:    blog.Category,
+			Tags:        tagList(blog.Tags),
+			Status:      string(blog.Status),
+			PublishAt:   optionalTime(blog.PublishAt),
 			Score:       blog.Score,
 			CreatedDate: blog.CreatedDate,
 		}
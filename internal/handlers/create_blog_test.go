@@ -2,10 +2,10 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,11 +15,13 @@ import (
 
 func TestHandleCreateBlog(t *testing.T) {
 	tests := map[string]struct {
+		actor      models.User
 		wantStatus int
 		wantBody   models.Blog
 		input      models.Blog
 	}{
 		"happy path": {
+			actor:      testUser,
 			wantStatus: 200,
 			wantBody: models.Blog{
 				ID:          1,
@@ -31,7 +33,82 @@ func TestHandleCreateBlog(t *testing.T) {
 			input: models.Blog{
 				AuthorID: 1,
 				Title:    "Book Title",
-				Score:    8.2,
+			},
+		},
+		"validation failure": {
+			actor:      testUser,
+			wantStatus: 422,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "",
+			},
+		},
+		"body too long": {
+			actor:      testUser,
+			wantStatus: 422,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Body:     strings.Repeat("a", maxBlogBodyLength+1),
+			},
+		},
+		"scheduled without publish time": {
+			actor:      testUser,
+			wantStatus: 422,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Status:   models.BlogStatusScheduled,
+			},
+		},
+		"tagged": {
+			actor:      testUser,
+			wantStatus: 200,
+			wantBody: models.Blog{
+				ID:       1,
+				AuthorID: 1,
+				Title:    "Book Title",
+				Category: "databases",
+				Tags:     []string{"go", "postgres"},
+			},
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Category: "Databases",
+				Tags:     []string{"Go", "Postgres"},
+			},
+		},
+		"too many tags": {
+			actor:      testUser,
+			wantStatus: 422,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Tags:     strings.Split("a,b,c,d,e,f,g,h,i,j,k", ","),
+			},
+		},
+		"tag too long": {
+			actor:      testUser,
+			wantStatus: 422,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Tags:     []string{strings.Repeat("a", maxBlogTagLength+1)},
+			},
+		},
+		"someone else's name": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+			},
+		},
+		"anonymous": {
+			wantStatus: 401,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
 			},
 		},
 	}
@@ -40,6 +117,7 @@ func TestHandleCreateBlog(t *testing.T) {
 			// Create a new request
 			reqBody, _ := json.Marshal(tc.input)
 			req := httptest.NewRequest("POST", "/blogs", bytes.NewBuffer(reqBody))
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -48,7 +126,7 @@ func TestHandleCreateBlog(t *testing.T) {
 			logger := slog.Default()
 
 			userCreator := new(mock.BlogCreator)
-			userCreator.On("CreateBlog", context.Background(), tc.input).Return(tc.wantBody, nil)
+			userCreator.On("CreateBlog", req.Context(), tc.input).Return(tc.wantBody, nil)
 
 			// Call the handler
 			handler := HandleCreateBlog(logger, userCreator)
@@ -7,13 +7,14 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/chickey/blog/internal/authz"
 	"github.com/chickey/blog/internal/models"
 )
 
 // commentCreator represents a type capable of reading a comment from storage and
 // returning it or an error.
 type commentCreator interface {
-	CreateComment(ctx context.Context, comment models.Comment) (models.Comment, error)
+	CreateComment(ctx context.Context, comment models.Comment, viewerId uint, allBlogs bool) (models.Comment, error)
 }
 
 // @Summary		Create Comment
@@ -21,16 +22,28 @@ type commentCreator interface {
 // @Tags			comment
 // @Accept			json
 // @Produce		json
+// @Security		BearerAuth
 // @Param			request	body		CommentRequest	true	"Comment to Create"
-// @Success		200		{object}	uint
-// @Failure		400		{object}	string
-// @Failure		404		{object}	string
-// @Failure		500		{object}	string
+// @Param			Idempotency-Key	header	string	false	"Key that makes retrying the request replay its response rather than create another comment"
+// @Success		200		{object}	CommentResponse
+// @Header			200		{string}	Idempotent-Replayed	"Set when the response is replayed for a retried request"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		409		{object}	ProblemResponse
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
 // @Router			/comment  [POST]
 func HandleCreateComment(logger *slog.Logger, commentCreator commentCreator) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
 		// Request validation
 		request, problems, err := decodeValid[*CommentRequest](r)
 
@@ -40,7 +53,8 @@ func HandleCreateComment(logger *slog.Logger, commentCreator commentCreator) htt
 				"failed to decode request",
 				slog.String("error", err.Error()))
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
 		}
 		if len(problems) > 0 {
 			logger.ErrorContext(
@@ -48,15 +62,26 @@ func HandleCreateComment(logger *slog.Logger, commentCreator commentCreator) htt
 				"Validation error",
 				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
 			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
 		}
 
 		modelRequest := models.Comment{
-			UserID:  request.UserID,
-			BlogID:  request.BlogID,
-			Message: request.Message,
+			UserID:   request.UserID,
+			BlogID:   request.BlogID,
+			ParentID: request.ParentID,
+			Message:  request.Message,
+		}
+
+		// Users may only comment under their own name
+		if !authz.CanComment(actor, authz.Create, modelRequest) {
+			writeForbidden(w, r)
+			return
 		}
-		// Read the comment
-		comment, err := commentCreator.CreateComment(ctx, modelRequest)
+
+		// Create the comment
+		comment, err := commentCreator.CreateComment(ctx, modelRequest, actor.ID, authz.CanReadUnpublished(actor))
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -64,12 +89,14 @@ func HandleCreateComment(logger *slog.Logger, commentCreator commentCreator) htt
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
 		// Convert our models.Comment domain model into a response model.
 		response := CommentResponse{
+			ID:          comment.ID,
+			ParentID:    comment.ParentID,
 			UserID:      comment.UserID,
 			BlogID:      comment.BlogID,
 			Message:     comment.Message,
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http/httptest"
@@ -11,15 +10,20 @@ import (
 
 	"github.com/chickey/blog/internal/handlers/mock"
 	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
 )
 
 func TestHandleCreateComment(t *testing.T) {
 	tests := map[string]struct {
+		actor      models.User
+		allBlogs   bool
+		mockError  error
 		wantStatus int
 		wantBody   models.Comment
 		input      models.Comment
 	}{
 		"happy path": {
+			actor:      testUser,
 			wantStatus: 200,
 			wantBody: models.Comment{
 				BlogID:      1,
@@ -33,12 +37,66 @@ func TestHandleCreateComment(t *testing.T) {
 				Message: "Good blog",
 			},
 		},
+		"reply": {
+			actor:      testUser,
+			wantStatus: 200,
+			wantBody: models.Comment{
+				ID:          3,
+				ParentID:    2,
+				BlogID:      1,
+				UserID:      1,
+				Message:     "Agreed",
+				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+			},
+			input: models.Comment{
+				ParentID: 2,
+				BlogID:   1,
+				UserID:   1,
+				Message:  "Agreed",
+			},
+		},
+		"admin": {
+			actor:      testAdmin,
+			allBlogs:   true,
+			wantStatus: 200,
+			wantBody: models.Comment{
+				BlogID:      1,
+				UserID:      3,
+				Message:     "Good blog",
+				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+			},
+			input: models.Comment{
+				BlogID:  1,
+				UserID:  3,
+				Message: "Good blog",
+			},
+		},
+		"blog the caller can't read": {
+			actor:      testUser,
+			mockError:  services.ErrInvalidReference,
+			wantStatus: 422,
+			input: models.Comment{
+				BlogID:  1,
+				UserID:  1,
+				Message: "Good blog",
+			},
+		},
+		"someone else's name": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+			input: models.Comment{
+				BlogID:  1,
+				UserID:  1,
+				Message: "Good blog",
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// Create a new request
 			reqBody, _ := json.Marshal(tc.input)
 			req := httptest.NewRequest("POST", "/comments", bytes.NewBuffer(reqBody))
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -47,7 +105,7 @@ func TestHandleCreateComment(t *testing.T) {
 			logger := slog.Default()
 
 			userCreator := new(mock.CommentCreator)
-			userCreator.On("CreateComment", context.Background(), tc.input).Return(tc.wantBody, nil)
+			userCreator.On("CreateComment", req.Context(), tc.input, tc.actor.ID, tc.allBlogs).Return(tc.wantBody, tc.mockError)
 
 			// Call the handler
 			handler := HandleCreateComment(logger, userCreator)
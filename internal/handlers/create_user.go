@@ -22,10 +22,14 @@ type userCreator interface {
 // @Accept			json
 // @Produce		json
 // @Param			request	body		UserRequest	true	"User to Create"
-// @Success		200		{object}	uint
-// @Failure		400		{object}	string
-// @Failure		404		{object}	string
-// @Failure		500		{object}	string
+// @Param			Idempotency-Key	header	string	false	"Key that makes retrying the request replay its response rather than create another user. For anonymous callers a key only matches a retry of the same request, and is otherwise ignored"
+// @Success		200		{object}	UserResponse
+// @Header			200		{string}	Idempotent-Replayed	"Set when the response is replayed for a retried request"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		409		{object}	ProblemResponse
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
 // @Router			/user  [POST]
 func HandleCreateUser(logger *slog.Logger, userCreator userCreator) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -40,7 +44,8 @@ func HandleCreateUser(logger *slog.Logger, userCreator userCreator) http.Handler
 				"failed to decode request",
 				slog.String("error", err.Error()))
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
 		}
 		if len(problems) > 0 {
 			logger.ErrorContext(
@@ -48,6 +53,9 @@ func HandleCreateUser(logger *slog.Logger, userCreator userCreator) http.Handler
 				"Validation error",
 				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
 			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
 		}
 
 		modelRequest := models.User{
@@ -65,16 +73,15 @@ func HandleCreateUser(logger *slog.Logger, userCreator userCreator) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
 		// Convert our models.User domain model into a response model.
 		response := UserResponse{
-			ID:       user.ID,
-			Name:     user.Name,
-			Email:    user.Email,
-			Password: user.Password,
+			ID:    user.ID,
+			Name:  user.Name,
+			Email: user.Email,
 		}
 
 		// Encode the response model as JSON
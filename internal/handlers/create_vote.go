@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// blogVoter represents a type capable of recording a vote on a blog and
+// returning the blog's new score or an error.
+type blogVoter interface {
+	Vote(ctx context.Context, vote models.Vote) (float32, error)
+}
+
+// @Summary		Vote on Blog
+// @Description	Rate a published blog from 0 to 10, replacing the caller's earlier rating of it. The blog's score is the mean of its ratings.
+// @Tags			blog
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id		path		string		true	"Blog ID"
+// @Param			request	body		VoteRequest	true	"Rating"
+// @Success		200		{object}	VoteResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/blog/{id}/vote  [POST]
+func HandleCreateVote(logger *slog.Logger, blogVoter blogVoter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// Request validation
+		request, problems, err := decodeValid[*VoteRequest](r)
+
+		if err != nil && len(problems) == 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to decode request",
+				slog.String("error", err.Error()))
+
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
+		}
+		if len(problems) > 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"Validation error",
+				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
+			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
+		}
+
+		// Callers always vote as themselves
+		vote := models.Vote{
+			UserID: actor.ID,
+			BlogID: uint(id),
+			Rating: *request.Rating,
+		}
+
+		// Record the vote
+		score, err := blogVoter.Vote(ctx, vote)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to vote on blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Convert our models.Vote domain model into a response model.
+		response := VoteResponse{
+			BlogID: vote.BlogID,
+			UserID: vote.UserID,
+			Rating: &vote.Rating,
+			Score:  score,
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleCreateVote(t *testing.T) {
+	rating := 8
+
+	tests := map[string]struct {
+		actor      models.User
+		id         string
+		body       string
+		mockCalled bool
+		mockScore  float32
+		mockError  error
+		wantStatus int
+		wantBody   VoteResponse
+	}{
+		"happy path": {
+			actor:      testOtherUser,
+			id:         "1",
+			body:       `{"rating": 8}`,
+			mockCalled: true,
+			mockScore:  7.5,
+			wantStatus: 200,
+			wantBody: VoteResponse{
+				BlogID: 1,
+				UserID: testOtherUser.ID,
+				Rating: &rating,
+				Score:  7.5,
+			},
+		},
+		"missing rating": {
+			actor:      testOtherUser,
+			id:         "1",
+			body:       `{}`,
+			wantStatus: 422,
+		},
+		"rating too high": {
+			actor:      testOtherUser,
+			id:         "1",
+			body:       `{"rating": 11}`,
+			wantStatus: 422,
+		},
+		"invalid id": {
+			actor:      testOtherUser,
+			id:         "abc",
+			body:       `{"rating": 8}`,
+			wantStatus: 400,
+		},
+		"blog not found": {
+			actor:      testOtherUser,
+			id:         "1",
+			body:       `{"rating": 8}`,
+			mockCalled: true,
+			mockError:  fmt.Errorf("blog 1: %w", services.ErrNotFound),
+			wantStatus: 404,
+		},
+		"anonymous": {
+			id:         "1",
+			body:       `{"rating": 8}`,
+			wantStatus: 401,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/blog/"+tc.id+"/vote", strings.NewReader(tc.body))
+			req.SetPathValue("id", tc.id)
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			voter := new(mock.BlogVoter)
+			if tc.mockCalled {
+				vote := models.Vote{UserID: tc.actor.ID, BlogID: 1, Rating: rating}
+				voter.On("Vote", req.Context(), vote).Return(tc.mockScore, tc.mockError)
+			}
+
+			handler := HandleCreateVote(logger, voter)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			voter.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
+
+			var got VoteResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got.BlogID != tc.wantBody.BlogID || got.UserID != tc.wantBody.UserID ||
+				got.Rating == nil || *got.Rating != *tc.wantBody.Rating || got.Score != tc.wantBody.Score {
+				t.Errorf("want body %+v, got %+v", tc.wantBody, got)
+			}
+		})
+	}
+}
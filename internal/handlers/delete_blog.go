@@ -5,11 +5,15 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
 )
 
 // uerDeleter represents a type capable of deleting a blog from storage
 type blogDeleter interface {
-	DeleteBlog(ctx context.Context, id uint64) error
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
+	DeleteBlog(ctx context.Context, id uint64, version uint) error
 }
 
 // @Summary		Delete Blog
@@ -17,16 +21,26 @@ type blogDeleter interface {
 // @Tags			blog
 // @Accept			json
 // @Produce		json
-// @Param			id	path	string	true	"Blog ID"
+// @Security		BearerAuth
+// @Param			id			path	string	true	"Blog ID"
+// @Param			If-Match	header	string	false	"ETag of the version of the blog the delete is made against"
 // @Success		200
-// @Failure		400	{object}	string
-// @Failure		404	{object}	string
-// @Failure		500	{object}	string
+// @Failure		400	{object}	ProblemResponse
+// @Failure		401	{object}	ProblemResponse
+// @Failure		403	{object}	ProblemResponse
+// @Failure		404	{object}	ProblemResponse
+// @Failure		412	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
 // @Router			/blog/{id}  [DELETE]
 func HandleDeleteBlog(logger *slog.Logger, blogDeleter blogDeleter) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
 		// Read id from path parameters
 		idStr := r.PathValue("id")
 
@@ -40,12 +54,34 @@ func HandleDeleteBlog(logger *slog.Logger, blogDeleter blogDeleter) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		version, ok := parseIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Only the author or an admin may delete a blog
+		existing, err := blogDeleter.ReadBlog(ctx, uint64(id), models.BlogExpand{}, false)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+		if !authz.CanBlog(actor, authz.Delete, existing) {
+			writeForbidden(w, r)
 			return
 		}
 
-		// Read the blog
-		err = blogDeleter.DeleteBlog(ctx, uint64(id))
+		// Delete the blog
+		err = blogDeleter.DeleteBlog(ctx, uint64(id), version)
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -53,7 +89,7 @@ func HandleDeleteBlog(logger *slog.Logger, blogDeleter blogDeleter) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 		// Encode the response model as JSON
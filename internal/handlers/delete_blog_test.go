@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http/httptest"
@@ -14,11 +13,21 @@ import (
 
 func TestHandleDeleteBlog(t *testing.T) {
 	tests := map[string]struct {
+		actor      models.User
 		wantStatus int
 		wantBody   models.Blog
 		input      models.Blog
 	}{
 		"happy path": {
+			actor:      testUser,
+			wantStatus: 200,
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+		},
+		"admin": {
+			actor:      testAdmin,
 			wantStatus: 200,
 		},
 	}
@@ -28,6 +37,7 @@ func TestHandleDeleteBlog(t *testing.T) {
 			reqBody, _ := json.Marshal(tc.input)
 			req := httptest.NewRequest("DELETE", "/blogs", bytes.NewBuffer(reqBody))
 			req.SetPathValue("id", "1")
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -36,7 +46,8 @@ func TestHandleDeleteBlog(t *testing.T) {
 			logger := slog.Default()
 
 			userDeleter := new(mock.BlogDeleter)
-			userDeleter.On("DeleteBlog", context.Background(), uint64(1)).Return(nil)
+			userDeleter.On("ReadBlog", req.Context(), uint64(1), models.BlogExpand{}, false).Return(models.Blog{ID: 1, AuthorID: 1}, nil)
+			userDeleter.On("DeleteBlog", req.Context(), uint64(1), uint(0)).Return(nil)
 
 			// Call the handler
 			handler := HandleDeleteBlog(logger, userDeleter)
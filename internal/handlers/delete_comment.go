@@ -5,11 +5,14 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
 )
 
 // uerDeleter represents a type capable of deleting a comment from storage
 type commentDeleter interface {
-	DeleteComment(ctx context.Context, user_id uint, blog_id uint) error
+	DeleteComment(ctx context.Context, id uint, user_id uint, blog_id uint, version uint) error
 }
 
 // @Summary		Delete Comment
@@ -17,17 +20,28 @@ type commentDeleter interface {
 // @Tags			comment
 // @Accept			json
 // @Produce		json
+// @Security		BearerAuth
 // @Param			author_id	query	string	false	"Author Id"
 // @Param			blog_id		query	string	false	"Blog Id"
+// @Param			id			query	string	false	"Comment Id, when the author has several comments on the blog"
+// @Param			If-Match	header	string	false	"ETag of the version of the comment the delete is made against"
 // @Success		200
-// @Failure		400	{object}	string
-// @Failure		404	{object}	string
-// @Failure		500	{object}	string
+// @Failure		400	{object}	ProblemResponse
+// @Failure		401	{object}	ProblemResponse
+// @Failure		403	{object}	ProblemResponse
+// @Failure		404	{object}	ProblemResponse
+// @Failure		412	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
 // @Router			/comment  [DELETE]
 func HandleDeleteComment(logger *slog.Logger, commentDeleter commentDeleter) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
 		// Validate query params
 		userIdStr := r.URL.Query().Get("author_id")
 		blogIdStr := r.URL.Query().Get("blog_id")
@@ -45,7 +59,7 @@ func HandleDeleteComment(logger *slog.Logger, commentDeleter commentDeleter) htt
 					slog.String("error", err.Error()),
 				)
 
-				http.Error(w, "Invalid User ID", http.StatusBadRequest)
+				writeProblem(w, r, http.StatusBadRequest, "Invalid User ID", nil)
 				return
 			}
 		}
@@ -62,13 +76,41 @@ func HandleDeleteComment(logger *slog.Logger, commentDeleter commentDeleter) htt
 					slog.String("error", err.Error()),
 				)
 
-				http.Error(w, "Invalid User ID", http.StatusBadRequest)
+				writeProblem(w, r, http.StatusBadRequest, "Invalid Blog ID", nil)
 				return
 			}
 		}
 
-		// Read the comment
-		err = commentDeleter.DeleteComment(ctx, uint(userId), uint(blogId))
+		var commentId int
+
+		if commentIdStr := r.URL.Query().Get("id"); commentIdStr != "" {
+			commentId, err = strconv.Atoi(commentIdStr)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to get valid Comment Id from query param",
+					slog.String("id", commentIdStr),
+					slog.String("error", err.Error()),
+				)
+
+				writeProblem(w, r, http.StatusBadRequest, "Invalid Comment ID", nil)
+				return
+			}
+		}
+
+		version, ok := parseIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Only the author or an admin may delete a comment
+		if !authz.CanComment(actor, authz.Delete, models.Comment{UserID: uint(userId), BlogID: uint(blogId)}) {
+			writeForbidden(w, r)
+			return
+		}
+
+		// Delete the comment
+		err = commentDeleter.DeleteComment(ctx, uint(commentId), uint(userId), uint(blogId), version)
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -76,7 +118,7 @@ func HandleDeleteComment(logger *slog.Logger, commentDeleter commentDeleter) htt
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 		// Encode the response model as JSON
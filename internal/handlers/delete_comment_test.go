@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http/httptest"
@@ -14,20 +13,47 @@ import (
 
 func TestHandleDeleteComment(t *testing.T) {
 	tests := map[string]struct {
+		actor      models.User
+		target     string
+		commentID  uint
 		wantStatus int
 		wantBody   models.Comment
 		input      models.Comment
 	}{
 		"happy path": {
+			actor:      testUser,
+			wantStatus: 200,
+		},
+		"by id": {
+			actor:      testUser,
+			target:     "/comments?author_id=1&blog_id=1&id=4",
+			commentID:  4,
+			wantStatus: 200,
+		},
+		"invalid id": {
+			actor:      testUser,
+			target:     "/comments?author_id=1&blog_id=1&id=abc",
+			wantStatus: 400,
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+		},
+		"admin": {
+			actor:      testAdmin,
 			wantStatus: 200,
 		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// Create a new request
+			if tc.target == "" {
+				tc.target = "/comments?author_id=1&blog_id=1"
+			}
 			reqBody, _ := json.Marshal(tc.input)
-			req := httptest.NewRequest("DELETE", "/comments", bytes.NewBuffer(reqBody))
+			req := httptest.NewRequest("DELETE", tc.target, bytes.NewBuffer(reqBody))
 			req.SetPathValue("id", "1")
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -36,7 +62,7 @@ func TestHandleDeleteComment(t *testing.T) {
 			logger := slog.Default()
 
 			userDeleter := new(mock.CommentDeleter)
-			userDeleter.On("DeleteComment", context.Background(), uint(0), uint(0)).Return(nil)
+			userDeleter.On("DeleteComment", req.Context(), tc.commentID, uint(1), uint(1), uint(0)).Return(nil)
 
 			// Call the handler
 			handler := HandleDeleteComment(logger, userDeleter)
@@ -5,11 +5,14 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
 )
 
 // uerDeleter represents a type capable of deleting a user from storage
 type userDeleter interface {
-	DeleteUser(ctx context.Context, id uint64) error
+	DeleteUser(ctx context.Context, id uint64, version uint) error
 }
 
 // @Summary		Delete User
@@ -17,16 +20,26 @@ type userDeleter interface {
 // @Tags			user
 // @Accept			json
 // @Produce		json
-// @Param			id	path	string	true	"User ID"
+// @Security		BearerAuth
+// @Param			id			path	string	true	"User ID"
+// @Param			If-Match	header	string	false	"ETag of the version of the user the delete is made against"
 // @Success		200
-// @Failure		400	{object}	string
-// @Failure		404	{object}	string
-// @Failure		500	{object}	string
+// @Failure		400	{object}	ProblemResponse
+// @Failure		401	{object}	ProblemResponse
+// @Failure		403	{object}	ProblemResponse
+// @Failure		404	{object}	ProblemResponse
+// @Failure		412	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
 // @Router			/user/{id}  [DELETE]
 func HandleDeleteUser(logger *slog.Logger, userDeleter userDeleter) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
 		// Read id from path parameters
 		idStr := r.PathValue("id")
 
@@ -40,12 +53,23 @@ func HandleDeleteUser(logger *slog.Logger, userDeleter userDeleter) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// Users may only delete their own account unless they are an admin
+		if !authz.CanUser(actor, authz.Delete, models.User{ID: uint(id)}) {
+			writeForbidden(w, r)
+			return
+		}
+
+		version, ok := parseIfMatch(w, r)
+		if !ok {
 			return
 		}
 
 		// Delete the user
-		err = userDeleter.DeleteUser(ctx, uint64(id))
+		err = userDeleter.DeleteUser(ctx, uint64(id), version)
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -53,7 +77,7 @@ func HandleDeleteUser(logger *slog.Logger, userDeleter userDeleter) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 		// Encode the response model as JSON
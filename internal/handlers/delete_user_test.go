@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http/httptest"
@@ -14,11 +13,21 @@ import (
 
 func TestHandleDeleteUser(t *testing.T) {
 	tests := map[string]struct {
+		actor      models.User
 		wantStatus int
 		wantBody   models.User
 		input      models.User
 	}{
 		"happy path": {
+			actor:      testUser,
+			wantStatus: 200,
+		},
+		"someone else": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+		},
+		"admin": {
+			actor:      testAdmin,
 			wantStatus: 200,
 		},
 	}
@@ -28,6 +37,7 @@ func TestHandleDeleteUser(t *testing.T) {
 			reqBody, _ := json.Marshal(tc.input)
 			req := httptest.NewRequest("DELETE", "/users", bytes.NewBuffer(reqBody))
 			req.SetPathValue("id", "1")
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -36,7 +46,7 @@ func TestHandleDeleteUser(t *testing.T) {
 			logger := slog.Default()
 
 			userDeleter := new(mock.UserDeleter)
-			userDeleter.On("DeleteUser", context.Background(), uint64(1)).Return(nil)
+			userDeleter.On("DeleteUser", req.Context(), uint64(1), uint(0)).Return(nil)
 
 			// Call the handler
 			handler := HandleDeleteUser(logger, userDeleter)
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// voteDeleter represents a type capable of withdrawing a user's vote on a blog
+// and returning the blog's new score or an error.
+type voteDeleter interface {
+	DeleteVote(ctx context.Context, blogId uint, userId uint) (float32, error)
+}
+
+// @Summary		Delete Vote
+// @Description	Withdraw the caller's rating of a blog
+// @Tags			blog
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id	path		string	true	"Blog ID"
+// @Success		200	{object}	VoteResponse
+// @Failure		400	{object}	ProblemResponse
+// @Failure		401	{object}	ProblemResponse
+// @Failure		404	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
+// @Router			/blog/{id}/vote  [DELETE]
+func HandleDeleteVote(logger *slog.Logger, voteDeleter voteDeleter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// Withdraw the caller's vote
+		score, err := voteDeleter.DeleteVote(ctx, uint(id), actor.ID)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to delete vote",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		response := VoteResponse{
+			BlogID: uint(id),
+			UserID: actor.ID,
+			Score:  score,
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleDeleteVote(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		id         string
+		mockCalled bool
+		mockScore  float32
+		mockError  error
+		wantStatus int
+		wantBody   VoteResponse
+	}{
+		"happy path": {
+			actor:      testOtherUser,
+			id:         "1",
+			mockCalled: true,
+			mockScore:  6,
+			wantStatus: 200,
+			wantBody: VoteResponse{
+				BlogID: 1,
+				UserID: testOtherUser.ID,
+				Score:  6,
+			},
+		},
+		"not voted": {
+			actor:      testOtherUser,
+			id:         "1",
+			mockCalled: true,
+			mockError:  fmt.Errorf("vote by user 2 on blog 1: %w", services.ErrNotFound),
+			wantStatus: 404,
+		},
+		"invalid id": {
+			actor:      testOtherUser,
+			id:         "abc",
+			wantStatus: 400,
+		},
+		"anonymous": {
+			id:         "1",
+			wantStatus: 401,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/blog/"+tc.id+"/vote", nil)
+			req.SetPathValue("id", tc.id)
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			deleter := new(mock.VoteDeleter)
+			if tc.mockCalled {
+				deleter.On("DeleteVote", req.Context(), uint(1), tc.actor.ID).Return(tc.mockScore, tc.mockError)
+			}
+
+			handler := HandleDeleteVote(logger, deleter)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			deleter.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
+
+			var got VoteResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got != tc.wantBody {
+				t.Errorf("want body %+v, got %+v", tc.wantBody, got)
+			}
+		})
+	}
+}
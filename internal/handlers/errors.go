@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/chickey/blog/internal/services"
+)
+
+// serviceErrorStatus maps an error returned by one of the services onto the
+// HTTP status code that best describes it. Unrecognised errors are treated as
+// internal server errors.
+func serviceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidCursor),
+		errors.Is(err, services.ErrInvalidSort):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrInvalidCredentials),
+		errors.Is(err, services.ErrInvalidToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, services.ErrConflict),
+		errors.Is(err, services.ErrInvalidTransition):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidReference),
+		errors.Is(err, services.ErrConstraintViolation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, services.ErrBulkAborted):
+		return http.StatusFailedDependency
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// serviceErrorDetail returns a client safe description of an error returned
+// by one of the services. Internal error messages are never exposed.
+func serviceErrorDetail(err error) string {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		return "The requested resource does not exist."
+	case errors.Is(err, services.ErrInvalidCursor):
+		return "The cursor is invalid or was issued for a different order."
+	case errors.Is(err, services.ErrInvalidSort):
+		return "The sort order names a field the list can't be sorted by."
+	case errors.Is(err, services.ErrInvalidCredentials):
+		return "The email or password is incorrect."
+	case errors.Is(err, services.ErrInvalidToken):
+		return "The token is invalid or has expired."
+	case errors.Is(err, services.ErrConflict):
+		return "The request conflicts with an existing resource."
+	case errors.Is(err, services.ErrInvalidTransition):
+		return "The blog can't move from its current status to the requested one."
+	case errors.Is(err, services.ErrInvalidReference):
+		return "The request refers to a resource that does not exist."
+	case errors.Is(err, services.ErrConstraintViolation):
+		return "The request breaks a data constraint."
+	case errors.Is(err, services.ErrPreconditionFailed):
+		return "The resource has changed since the version named by If-Match."
+	case errors.Is(err, services.ErrBulkAborted):
+		return "The item was not written because another item of the bulk request failed."
+	default:
+		return "An unexpected error occurred."
+	}
+}
+
+// writeServiceError responds to the client with a problem details body
+// describing an error returned by one of the services.
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	writeProblem(w, r, serviceErrorStatus(err), serviceErrorDetail(err), nil)
+}
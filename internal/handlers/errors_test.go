@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestServiceErrorStatus(t *testing.T) {
+	tests := map[string]struct {
+		err        error
+		wantStatus int
+	}{
+		"not found": {
+			err:        fmt.Errorf("user 1: %w", services.ErrNotFound),
+			wantStatus: http.StatusNotFound,
+		},
+		"invalid cursor": {
+			err:        fmt.Errorf("list blogs: %w", services.ErrInvalidCursor),
+			wantStatus: http.StatusBadRequest,
+		},
+		"invalid sort": {
+			err:        fmt.Errorf("list blogs: %w", services.ErrInvalidSort),
+			wantStatus: http.StatusBadRequest,
+		},
+		"invalid credentials": {
+			err:        fmt.Errorf("login: %w", services.ErrInvalidCredentials),
+			wantStatus: http.StatusUnauthorized,
+		},
+		"invalid token": {
+			err:        fmt.Errorf("refresh: %w", services.ErrInvalidToken),
+			wantStatus: http.StatusUnauthorized,
+		},
+		"conflict": {
+			err:        fmt.Errorf("create comment: %w", services.ErrConflict),
+			wantStatus: http.StatusConflict,
+		},
+		"invalid transition": {
+			err:        fmt.Errorf("update blog: %w", services.ErrInvalidTransition),
+			wantStatus: http.StatusConflict,
+		},
+		"invalid reference": {
+			err:        fmt.Errorf("author 7: %w", services.ErrInvalidReference),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		"precondition failed": {
+			err:        fmt.Errorf("update blog: %w", services.ErrPreconditionFailed),
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		"bulk aborted": {
+			err:        fmt.Errorf("item 2: %w", services.ErrBulkAborted),
+			wantStatus: http.StatusFailedDependency,
+		},
+		"constraint violation": {
+			err:        fmt.Errorf("update blog: %w", services.ErrConstraintViolation),
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		"unknown error": {
+			err:        sql.ErrConnDone,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := serviceErrorStatus(tc.err); got != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, got)
+			}
+		})
+	}
+}
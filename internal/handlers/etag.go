@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// etag returns the strong entity tag of a record at version.
+func etag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// setETag tells the client which version of a record the response carries.
+func setETag(w http.ResponseWriter, version uint) {
+	w.Header().Set("ETag", etag(version))
+}
+
+// parseIfMatch reads the version a write is conditional on from the If-Match
+// header. A missing header or * returns zero, which makes the write
+// unconditional. Only a single strong entity tag can name a version, so if the
+// header names anything else it responds with a problem and returns false.
+func parseIfMatch(w http.ResponseWriter, r *http.Request) (version uint, ok bool) {
+	s := strings.TrimSpace(r.Header.Get("If-Match"))
+	if s == "" || s == "*" {
+		return 0, true
+	}
+
+	// Weak entity tags never match under the strong comparison If-Match uses
+	if strings.HasPrefix(s, "W/") {
+		writeProblem(w, r, http.StatusPreconditionFailed, "Precondition failed", map[string]string{
+			"If-Match": "If-Match must be a strong entity tag",
+		})
+		return 0, false
+	}
+
+	v, err := strconv.ParseUint(strings.Trim(s, `"`), 10, 0)
+	if err != nil || v == 0 || s != etag(uint(v)) {
+		writeProblem(w, r, http.StatusBadRequest, "Invalid If-Match", map[string]string{
+			"If-Match": "If-Match must be * or a single entity tag from an ETag header",
+		})
+		return 0, false
+	}
+
+	return uint(v), true
+}
+
+// notModified reports whether the If-None-Match header of a read names
+// version, in which case the client already holds it. Entity tags are
+// compared weakly, as If-None-Match requires.
+func notModified(r *http.Request, version uint) bool {
+	s := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if s == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(s, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag(version) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := map[string]struct {
+		header     string
+		want       uint
+		wantOK     bool
+		wantStatus int
+	}{
+		"not set": {
+			wantOK:     true,
+			wantStatus: 200,
+		},
+		"any version": {
+			header:     "*",
+			wantOK:     true,
+			wantStatus: 200,
+		},
+		"entity tag": {
+			header:     `"3"`,
+			want:       3,
+			wantOK:     true,
+			wantStatus: 200,
+		},
+		"weak entity tag": {
+			header:     `W/"3"`,
+			wantOK:     false,
+			wantStatus: 412,
+		},
+		"unquoted": {
+			header:     "3",
+			wantOK:     false,
+			wantStatus: 400,
+		},
+		"several entity tags": {
+			header:     `"3", "4"`,
+			wantOK:     false,
+			wantStatus: 400,
+		},
+		"zero": {
+			header:     `"0"`,
+			wantOK:     false,
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/", nil)
+			req.Header.Set("If-Match", tc.header)
+			rec := httptest.NewRecorder()
+
+			got, ok := parseIfMatch(rec, req)
+			if ok != tc.wantOK {
+				t.Errorf("want ok %t, got %t", tc.wantOK, ok)
+			}
+			if got != tc.want {
+				t.Errorf("want %d, got %d", tc.want, got)
+			}
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	tests := map[string]struct {
+		header string
+		want   bool
+	}{
+		"not set": {
+			want: false,
+		},
+		"any version": {
+			header: "*",
+			want:   true,
+		},
+		"same version": {
+			header: `"3"`,
+			want:   true,
+		},
+		"weak entity tag": {
+			header: `W/"3"`,
+			want:   true,
+		},
+		"one of several": {
+			header: `"2", "3"`,
+			want:   true,
+		},
+		"older version": {
+			header: `"2"`,
+			want:   false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("If-None-Match", tc.header)
+
+			if got := notModified(req, 3); got != tc.want {
+				t.Errorf("want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
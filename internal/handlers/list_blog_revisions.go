@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/middleware"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+// blogRevisionsLister represents a type capable of reading a blog and a page
+// of its revisions, returning them or an error.
+type blogRevisionsLister interface {
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
+	ListBlogRevisions(ctx context.Context, blogId uint, page models.PageRequest) (models.Page[models.BlogRevision], error)
+}
+
+// listBlogRevisionsResponse represents the response for listing the revisions
+// of a blog.
+type listBlogRevisionsResponse struct {
+	Revisions []BlogRevisionResponse
+	PageResponse
+}
+
+// @Summary		List Blog Revisions
+// @Description	List the content a blog had before each of its updates, newest first, with a line diff to the content each update left. Revisions are as visible as the blog.
+// @Tags			blog
+// @Accept			json
+// @Produce		json
+// @Param			id				path		string	true	"Blog ID"
+// @Param			limit			query		int		false	"Page size"
+// @Param			offset			query		int		false	"Number of revisions to skip"
+// @Param			cursor			query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Success		200		{object}	listBlogRevisionsResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/blog/{id}/revisions  [GET]
+func HandleListBlogRevisions(logger *slog.Logger, blogRevisionsLister blogRevisionsLister) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		page, problems := parsePageRequest(r)
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid pagination parameters", problems)
+			return
+		}
+
+		// The revisions of blogs that aren't published are only visible to
+		// their author, like the blogs themselves
+		blog, err := blogRevisionsLister.ReadBlog(ctx, uint64(id), models.BlogExpand{}, false)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+		actor, _ := middleware.UserFromContext(ctx)
+		if !authz.CanBlog(actor, authz.Read, blog) {
+			writeServiceError(w, r, services.ErrNotFound)
+			return
+		}
+
+		// Read the revisions
+		revisions, err := blogRevisionsLister.ListBlogRevisions(ctx, uint(id), page)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to list blog revisions",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Convert our models.BlogRevision domain models into response models.
+		response := listBlogRevisionsResponse{
+			Revisions:    []BlogRevisionResponse{},
+			PageResponse: newPageResponse(r, page, revisions),
+		}
+
+		for _, revision := range revisions.Items {
+			response.Revisions = append(response.Revisions, newBlogRevisionResponse(revision))
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleListBlogRevisions(t *testing.T) {
+	createdDate := time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC)
+	published := models.Blog{ID: 1, AuthorID: 1, Status: models.BlogStatusPublished}
+	draft := models.Blog{ID: 1, AuthorID: 1, Status: models.BlogStatusDraft}
+	revision := models.BlogRevision{
+		ID:          2,
+		BlogID:      1,
+		EditorID:    1,
+		Title:       "Book Title",
+		Body:        "one",
+		Category:    "go",
+		Diff:        "-one\n+two\n",
+		CreatedDate: createdDate,
+	}
+
+	tests := map[string]struct {
+		actor      models.User
+		id         string
+		mockBlog   models.Blog
+		mockError  error
+		mockListed bool
+		wantStatus int
+		wantBody   listBlogRevisionsResponse
+	}{
+		"happy path": {
+			id:         "1",
+			mockBlog:   published,
+			mockListed: true,
+			wantStatus: 200,
+			wantBody: listBlogRevisionsResponse{
+				Revisions: []BlogRevisionResponse{
+					{
+						ID:          2,
+						BlogID:      1,
+						EditorID:    1,
+						Title:       "Book Title",
+						Body:        "one",
+						Category:    "go",
+						Tags:        []string{},
+						Diff:        "-one\n+two\n",
+						CreatedDate: createdDate,
+					},
+				},
+			},
+		},
+		"own draft": {
+			actor:      testUser,
+			id:         "1",
+			mockBlog:   draft,
+			mockListed: true,
+			wantStatus: 200,
+			wantBody: listBlogRevisionsResponse{
+				Revisions: []BlogRevisionResponse{newBlogRevisionResponse(revision)},
+			},
+		},
+		"someone else's draft": {
+			actor:      testOtherUser,
+			id:         "1",
+			mockBlog:   draft,
+			wantStatus: 404,
+		},
+		"blog not found": {
+			id:         "1",
+			mockError:  fmt.Errorf("blog 1: %w", services.ErrNotFound),
+			wantStatus: 404,
+		},
+		"invalid id": {
+			id:         "abc",
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := withActor(httptest.NewRequest("GET", "/api/blog/"+tc.id+"/revisions", nil), tc.actor)
+			req.SetPathValue("id", tc.id)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			lister := new(mock.BlogRevisionsLister)
+			if tc.id == "1" {
+				lister.On("ReadBlog", req.Context(), uint64(1), models.BlogExpand{}, false).Return(tc.mockBlog, tc.mockError)
+			}
+			if tc.mockListed {
+				lister.
+					On("ListBlogRevisions", req.Context(), uint(1), models.PageRequest{}).
+					Return(models.Page[models.BlogRevision]{Items: []models.BlogRevision{revision}}, nil)
+			}
+
+			handler := HandleListBlogRevisions(logger, lister)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			lister.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
+
+			var got listBlogRevisionsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.wantBody) {
+				t.Errorf("want body %+v, got %+v", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -4,41 +4,95 @@ import (
 	"context"
 	"encoding/json"
 	"log/slog"
+	"maps"
 	"net/http"
+	"strconv"
 
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/middleware"
 	"github.com/chickey/blog/internal/models"
 )
 
-// blogReader represents a type capable of reading a blog from storage and
-// returning it or an error.
+// blogsLister represents a type capable of listing blogs from storage and
+// returning a page of them or an error.
 type blogsLister interface {
-	ListBlogs(ctx context.Context, title string) ([]models.Blog, error)
+	ListBlogs(
+		ctx context.Context,
+		filter models.BlogFilter,
+		sort []models.SortField,
+		page models.PageRequest,
+		expand models.BlogExpand,
+	) (models.Page[models.Blog], error)
 }
 
 // listBlogsResponse represents the response for listing blogs.
 type listBlogsResponse struct {
 	Blogs []BlogResponse
+	PageResponse
 }
 
 // @Summary		List Blogs
-// @Description	List All Blogs
+// @Description	List published blogs, and the caller's own blogs whatever their status. Admins are listed every blog
 // @Tags			blog
 // @Accept			json
 // @Produce		json
-// @Param			title	query		string	false	"query param"
-// @Success		200		{array}		models.Blog
-// @Failure		400		{object}	string
-// @Failure		404		{object}	string
-// @Failure		500		{object}	string
+// @Param			author_id		query		int		false	"Author Id"
+// @Param			status			query		string	false	"Status"	Enums(draft, scheduled, published, archived)
+// @Param			title			query		string	false	"Case-insensitive substring of the title"
+// @Param			category		query		string	false	"Category"
+// @Param			tag				query		[]string	false	"Tags, repeated for each tag"	collectionFormat(multi)
+// @Param			tag_match		query		string	false	"Whether blogs need any or all of the tags"	Enums(any, all)	default(any)
+// @Param			min_score		query		number	false	"Lowest score"
+// @Param			max_score		query		number	false	"Highest score"
+// @Param			created_after	query		string	false	"Created at or after, as a date or RFC 3339 time"
+// @Param			created_before	query		string	false	"Created before, as a date or RFC 3339 time"
+// @Param			sort			query		string	false	"Comma separated fields, - for descending, e.g. -score,created_date"
+// @Param			limit			query		int		false	"Page size"
+// @Param			offset			query		int		false	"Number of blogs to skip"
+// @Param			cursor			query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Param			expand			query		string	false	"Related records to embed"	Enums(author)
+// @Param			include_deleted	query		bool	false	"Include deleted blogs, for admins only"
+// @Success		200		{object}	listBlogsResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
 // @Router			/blog  [GET]
 func HandleListBlogs(logger *slog.Logger, blogsLister blogsLister) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		title := r.URL.Query().Get("title")
+		filter, sort, problems := parseBlogFilter(r)
 
-		// Read the blog
-		blogs, err := blogsLister.ListBlogs(ctx, title)
+		page, pageProblems := parsePageRequest(r)
+		maps.Copy(problems, pageProblems)
+
+		expand, ok := parseExpand(r.URL.Query().Get("expand"), "author")
+		if !ok {
+			problems["expand"] = "Expand must be author"
+		}
+
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid query parameters", problems)
+			return
+		}
+
+		filter.IncludeDeleted, ok = parseIncludeDeleted(w, r)
+		if !ok {
+			return
+		}
+
+		// Blogs that aren't published are only listed to those who may read
+		// them
+		if actor, ok := middleware.UserFromContext(ctx); ok {
+			filter.ViewerID = actor.ID
+			filter.AllBlogs = authz.CanReadUnpublished(actor)
+		}
+
+		// Read the blogs
+		blogs, err := blogsLister.ListBlogs(ctx, filter, sort, page, models.BlogExpand{Author: expand["author"]})
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -46,22 +100,30 @@ func HandleListBlogs(logger *slog.Logger, blogsLister blogsLister) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
 		// Convert our models.Blog domain model into a response model.
 		response := listBlogsResponse{
-			Blogs: []BlogResponse{},
+			Blogs:        []BlogResponse{},
+			PageResponse: newPageResponse(r, page, blogs),
 		}
 
-		for _, blog := range blogs {
+		for _, blog := range blogs.Items {
 			newBlog := BlogResponse{
 				ID:          blog.ID,
 				AuthorID:    blog.AuthorID,
+				Author:      newUserRefResponse(blog.Author),
 				Title:       blog.Title,
+				Excerpt:     blog.Excerpt,
+				Category:    blog.Category,
+				Tags:        tagList(blog.Tags),
+				Status:      string(blog.Status),
+				PublishAt:   optionalTime(blog.PublishAt),
 				Score:       blog.Score,
 				CreatedDate: blog.CreatedDate,
+				DeletedAt:   optionalTime(blog.DeletedAt),
 			}
 			response.Blogs = append(response.Blogs, newBlog)
 		}
@@ -79,3 +141,79 @@ func HandleListBlogs(logger *slog.Logger, blogsLister blogsLister) http.Handler
 		}
 	})
 }
+
+// parseBlogFilter reads the filter and sort query parameters of a request to
+// list blogs. Problems are keyed by parameter name.
+func parseBlogFilter(r *http.Request) (models.BlogFilter, []models.SortField, map[string]string) {
+	query := r.URL.Query()
+	problems := make(map[string]string)
+
+	filter := models.BlogFilter{
+		Status:   models.BlogStatus(query.Get("status")),
+		Category: query.Get("category"),
+		Tags:     query["tag"],
+		TagMatch: models.TagMatch(query.Get("tag_match")),
+		Title:    query.Get("title"),
+	}
+
+	if filter.Status != "" && !validBlogStatus(filter.Status) {
+		problems["status"] = "Status must be draft, scheduled, published or archived"
+	}
+
+	if filter.TagMatch != "" && filter.TagMatch != models.TagMatchAny && filter.TagMatch != models.TagMatchAll {
+		problems["tag_match"] = "Tag match must be any or all"
+	}
+
+	if s := query.Get("author_id"); s != "" {
+		authorID, err := strconv.ParseUint(s, 10, 64)
+		if err != nil || authorID == 0 {
+			problems["author_id"] = "Author ID must be a positive number"
+		}
+		filter.AuthorID = uint(authorID)
+	}
+
+	if s := query.Get("min_score"); s != "" {
+		score, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			problems["min_score"] = "Min score must be a number"
+		}
+		minScore := float32(score)
+		filter.MinScore = &minScore
+	}
+
+	if s := query.Get("max_score"); s != "" {
+		score, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			problems["max_score"] = "Max score must be a number"
+		}
+		maxScore := float32(score)
+		filter.MaxScore = &maxScore
+	}
+
+	if filter.MinScore != nil && filter.MaxScore != nil && *filter.MinScore > *filter.MaxScore {
+		problems["min_score"] = "Min score cannot be greater than max score"
+	}
+
+	if s := query.Get("created_after"); s != "" {
+		createdAfter, err := parseTimeParam(s)
+		if err != nil {
+			problems["created_after"] = "Created after must be a date such as 2025-01-21 or an RFC 3339 time"
+		}
+		filter.CreatedAfter = createdAfter
+	}
+
+	if s := query.Get("created_before"); s != "" {
+		createdBefore, err := parseTimeParam(s)
+		if err != nil {
+			problems["created_before"] = "Created before must be a date such as 2025-01-21 or an RFC 3339 time"
+		}
+		filter.CreatedBefore = createdBefore
+	}
+
+	sort, ok := parseSort(query.Get("sort"))
+	if !ok {
+		problems["sort"] = "Sort must be a comma separated list of field names"
+	}
+
+	return filter, sort, problems
+}
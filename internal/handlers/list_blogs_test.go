@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestHandleListBlogs(t *testing.T) {
+	minScore, maxScore := float32(7.5), float32(9)
+
+	tests := map[string]struct {
+		actor      models.User
+		target     string
+		mockCalled bool
+		filter     models.BlogFilter
+		sort       []models.SortField
+		page       models.PageRequest
+		expand     models.BlogExpand
+		wantStatus int
+	}{
+		"no filters": {
+			target:     "/api/blog",
+			mockCalled: true,
+			wantStatus: 200,
+		},
+		"filters and sort": {
+			target: "/api/blog?author_id=1&title=book&min_score=7.5&max_score=9" +
+				"&created_after=2025-01-01&created_before=2025-02-01T10:00:00Z" +
+				"&sort=-score,created_date&limit=5",
+			mockCalled: true,
+			filter: models.BlogFilter{
+				AuthorID:      1,
+				Title:         "book",
+				MinScore:      &minScore,
+				MaxScore:      &maxScore,
+				CreatedAfter:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC),
+			},
+			sort: []models.SortField{
+				{Field: "score", Desc: true},
+				{Field: "created_date"},
+			},
+			page:       models.PageRequest{Limit: 5},
+			wantStatus: 200,
+		},
+		"own drafts": {
+			actor:      testUser,
+			target:     "/api/blog?status=draft",
+			mockCalled: true,
+			filter: models.BlogFilter{
+				ViewerID: testUser.ID,
+				Status:   models.BlogStatusDraft,
+			},
+			wantStatus: 200,
+		},
+		"admin": {
+			actor:      testAdmin,
+			target:     "/api/blog?status=draft",
+			mockCalled: true,
+			filter: models.BlogFilter{
+				ViewerID: testAdmin.ID,
+				AllBlogs: true,
+				Status:   models.BlogStatusDraft,
+			},
+			wantStatus: 200,
+		},
+		"expanded author": {
+			target:     "/api/blog?expand=author",
+			mockCalled: true,
+			expand:     models.BlogExpand{Author: true},
+			wantStatus: 200,
+		},
+		"tags": {
+			target:     "/api/blog?category=Databases&tag=go&tag=postgres&tag_match=all",
+			mockCalled: true,
+			filter: models.BlogFilter{
+				Category: "Databases",
+				Tags:     []string{"go", "postgres"},
+				TagMatch: models.TagMatchAll,
+			},
+			wantStatus: 200,
+		},
+		"invalid tag match": {
+			target:     "/api/blog?tag=go&tag_match=most",
+			wantStatus: 400,
+		},
+		"invalid expand": {
+			target:     "/api/blog?expand=author,comments",
+			wantStatus: 400,
+		},
+		"invalid status": {
+			target:     "/api/blog?status=deleted",
+			wantStatus: 400,
+		},
+		"invalid author": {
+			target:     "/api/blog?author_id=abc",
+			wantStatus: 400,
+		},
+		"min score above max score": {
+			target:     "/api/blog?min_score=9&max_score=7.5",
+			wantStatus: 400,
+		},
+		"invalid date": {
+			target:     "/api/blog?created_after=yesterday",
+			wantStatus: 400,
+		},
+		"empty sort field": {
+			target:     "/api/blog?sort=score,,id",
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := withActor(httptest.NewRequest("GET", tc.target, nil), tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			blogsLister := new(mock.BlogsLister)
+			if tc.mockCalled {
+				blogsLister.
+					On("ListBlogs", req.Context(), tc.filter, tc.sort, tc.page, tc.expand).
+					Return(models.Page[models.Blog]{Items: []models.Blog{}}, nil)
+			}
+
+			handler := HandleListBlogs(logger, blogsLister)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			blogsLister.AssertExpectations(t)
+		})
+	}
+}
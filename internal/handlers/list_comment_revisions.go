@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/middleware"
+	"github.com/chickey/blog/internal/models"
+)
+
+// commentRevisionsLister represents a type capable of reading a page of the
+// revisions of a comment and returning it or an error.
+type commentRevisionsLister interface {
+	ListCommentRevisions(ctx context.Context, commentId uint, viewerId uint, allBlogs bool, page models.PageRequest) (models.Page[models.CommentRevision], error)
+}
+
+// listCommentRevisionsResponse represents the response for listing the
+// revisions of a comment.
+type listCommentRevisionsResponse struct {
+	Revisions []CommentRevisionResponse
+	PageResponse
+}
+
+// @Summary		List Comment Revisions
+// @Description	List the messages a comment had before each of its updates, newest first, with a line diff to the message each update left
+// @Tags			comment
+// @Accept			json
+// @Produce		json
+// @Param			id				query		string	true	"Comment Id"
+// @Param			limit			query		int		false	"Page size"
+// @Param			offset			query		int		false	"Number of revisions to skip"
+// @Param			cursor			query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Success		200		{object}	listCommentRevisionsResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/comment/revisions  [GET]
+func HandleListCommentRevisions(logger *slog.Logger, commentRevisionsLister commentRevisionsLister) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Validate query params
+		idStr := r.URL.Query().Get("id")
+
+		id, err := strconv.Atoi(idStr)
+		if err != nil || id <= 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to get valid Comment Id from query param",
+				slog.String("id", idStr),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid Comment ID", map[string]string{
+				"id": "Comment ID must be a positive number",
+			})
+			return
+		}
+
+		page, problems := parsePageRequest(r)
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid pagination parameters", problems)
+			return
+		}
+
+		// Comments on blogs that aren't published are only visible to the
+		// blog's author, and neither are their revisions
+		actor, _ := middleware.UserFromContext(ctx)
+
+		// Read the revisions
+		revisions, err := commentRevisionsLister.ListCommentRevisions(ctx, uint(id), actor.ID, authz.CanReadUnpublished(actor), page)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to list comment revisions",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Convert our models.CommentRevision domain models into response models.
+		response := listCommentRevisionsResponse{
+			Revisions:    []CommentRevisionResponse{},
+			PageResponse: newPageResponse(r, page, revisions),
+		}
+
+		for _, revision := range revisions.Items {
+			response.Revisions = append(response.Revisions, CommentRevisionResponse{
+				ID:          revision.ID,
+				CommentID:   revision.CommentID,
+				EditorID:    revision.EditorID,
+				Message:     revision.Message,
+				Diff:        revision.Diff,
+				CreatedDate: revision.CreatedDate,
+			})
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
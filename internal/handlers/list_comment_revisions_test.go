@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleListCommentRevisions(t *testing.T) {
+	createdDate := time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC)
+
+	tests := map[string]struct {
+		target     string
+		actor      models.User
+		mockCalled bool
+		allBlogs   bool
+		page       models.PageRequest
+		mockOutput models.Page[models.CommentRevision]
+		mockError  error
+		wantStatus int
+		wantBody   listCommentRevisionsResponse
+	}{
+		"happy path": {
+			target:     "/api/comment/revisions?id=3",
+			mockCalled: true,
+			mockOutput: models.Page[models.CommentRevision]{Items: []models.CommentRevision{
+				{ID: 1, CommentID: 3, UserID: 1, BlogID: 1, Message: "Nice", Diff: "-Nice\n+Good blog\n", CreatedDate: createdDate},
+			}},
+			wantStatus: 200,
+			wantBody: listCommentRevisionsResponse{
+				Revisions: []CommentRevisionResponse{
+					{ID: 1, CommentID: 3, Message: "Nice", Diff: "-Nice\n+Good blog\n", CreatedDate: createdDate},
+				},
+			},
+		},
+		"paged": {
+			target:     "/api/comment/revisions?id=3&limit=1",
+			mockCalled: true,
+			page:       models.PageRequest{Limit: 1},
+			mockOutput: models.Page[models.CommentRevision]{Items: []models.CommentRevision{}, NextCursor: "abc"},
+			wantStatus: 200,
+			wantBody: listCommentRevisionsResponse{
+				Revisions:    []CommentRevisionResponse{},
+				PageResponse: PageResponse{Next: "/api/comment/revisions?cursor=abc&id=3&limit=1"},
+			},
+		},
+		"admin": {
+			target:     "/api/comment/revisions?id=3",
+			actor:      testAdmin,
+			mockCalled: true,
+			allBlogs:   true,
+			mockOutput: models.Page[models.CommentRevision]{Items: []models.CommentRevision{}},
+			wantStatus: 200,
+			wantBody:   listCommentRevisionsResponse{Revisions: []CommentRevisionResponse{}},
+		},
+		"anonymous on a draft": {
+			target:     "/api/comment/revisions?id=3",
+			mockCalled: true,
+			mockError:  fmt.Errorf("comment 3: %w", services.ErrNotFound),
+			wantStatus: 404,
+		},
+		"comment not found": {
+			target:     "/api/comment/revisions?id=3",
+			actor:      testUser,
+			mockCalled: true,
+			mockError:  fmt.Errorf("comment 3: %w", services.ErrNotFound),
+			wantStatus: 404,
+		},
+		"missing id": {
+			target:     "/api/comment/revisions",
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.target, nil)
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			lister := new(mock.CommentRevisionsLister)
+			if tc.mockCalled {
+				lister.On("ListCommentRevisions", req.Context(), uint(3), tc.actor.ID, tc.allBlogs, tc.page).Return(tc.mockOutput, tc.mockError)
+			}
+
+			handler := HandleListCommentRevisions(logger, lister)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			lister.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
+
+			var got listCommentRevisionsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.wantBody) {
+				t.Errorf("want body %+v, got %+v", tc.wantBody, got)
+			}
+		})
+	}
+}
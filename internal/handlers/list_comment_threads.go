@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/middleware"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+// commentThreadsLister represents a type capable of reading a page of the
+// comments on a blog as threads of replies and returning it or an error.
+type commentThreadsLister interface {
+	ListCommentThreads(ctx context.Context, blogId uint, viewerId uint, allBlogs bool, depth int, page models.PageRequest) (models.Page[models.CommentThread], error)
+}
+
+// listCommentThreadsResponse represents the response for listing the comment
+// threads on a blog.
+type listCommentThreadsResponse struct {
+	Comments []CommentThreadResponse
+	PageResponse
+}
+
+// @Summary		List Comment Threads
+// @Description	List the comments on a blog with their commenters' names and their replies nested beneath them, oldest first. Pages count the comments on the blog itself.
+// @Tags			comment
+// @Accept			json
+// @Produce		json
+// @Param			id		path		string	true	"Blog Id"
+// @Param			depth	query		int		false	"Levels of replies to include, counting the comments on the blog"
+// @Param			limit	query		int		false	"Page size"
+// @Param			offset	query		int		false	"Number of comments to skip"
+// @Param			cursor	query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Success		200		{object}	listCommentThreadsResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/blog/{id}/comments  [GET]
+func HandleListCommentThreads(logger *slog.Logger, commentThreadsLister commentThreadsLister) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		var depth int
+
+		if depthStr := r.URL.Query().Get("depth"); depthStr != "" {
+			depth, err = strconv.Atoi(depthStr)
+			if err != nil || depth < 1 || depth > services.MaxThreadDepth {
+				writeProblem(w, r, http.StatusBadRequest, "Invalid query parameters", map[string]string{
+					"depth": fmt.Sprintf("Depth must be a number between 1 and %d", services.MaxThreadDepth),
+				})
+				return
+			}
+		}
+
+		page, problems := parsePageRequest(r)
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid pagination parameters", problems)
+			return
+		}
+
+		// Blogs that aren't published are only visible to their author, and
+		// neither are their comments
+		actor, _ := middleware.UserFromContext(ctx)
+
+		// Read the comment threads
+		threads, err := commentThreadsLister.ListCommentThreads(ctx, uint(id), actor.ID, authz.CanReadUnpublished(actor), depth, page)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to list comment threads",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Convert our models.CommentThread domain models into response models.
+		response := listCommentThreadsResponse{
+			Comments:     newCommentThreadResponses(threads.Items),
+			PageResponse: newPageResponse(r, page, threads),
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
+
+// newCommentThreadResponses converts comment threads, and the replies within
+// them, into response models.
+func newCommentThreadResponses(threads []models.CommentThread) []CommentThreadResponse {
+	responses := make([]CommentThreadResponse, 0, len(threads))
+	for _, thread := range threads {
+		responses = append(responses, CommentThreadResponse{
+			CommentResponse: CommentResponse{
+				ID:          thread.ID,
+				ParentID:    thread.ParentID,
+				UserID:      thread.UserID,
+				User:        newUserRefResponse(thread.User),
+				BlogID:      thread.BlogID,
+				Message:     thread.Message,
+				CreatedDate: thread.CreatedDate,
+			},
+			Replies: newCommentThreadResponses(thread.Replies),
+		})
+	}
+
+	return responses
+}
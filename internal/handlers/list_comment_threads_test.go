@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleListCommentThreads(t *testing.T) {
+	createdDate := time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC)
+	threads := []models.CommentThread{
+		{
+			Comment: models.Comment{ID: 1, UserID: 1, User: models.UserRef{ID: 1, Name: "John"}, BlogID: 1, Message: "First", CreatedDate: createdDate},
+			Replies: []models.CommentThread{
+				{
+					Comment: models.Comment{ID: 2, ParentID: 1, UserID: 2, User: models.UserRef{ID: 2, Name: "Jane"}, BlogID: 1, Message: "Reply", CreatedDate: createdDate},
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		target     string
+		actor      models.User
+		mockCalled bool
+		allBlogs   bool
+		depth      int
+		page       models.PageRequest
+		mockOutput models.Page[models.CommentThread]
+		mockError  error
+		wantStatus int
+		wantBody   listCommentThreadsResponse
+	}{
+		"happy path": {
+			target:     "/api/blog/1/comments",
+			mockCalled: true,
+			mockOutput: models.Page[models.CommentThread]{Items: threads},
+			wantStatus: 200,
+			wantBody: listCommentThreadsResponse{
+				Comments: []CommentThreadResponse{
+					{
+						CommentResponse: CommentResponse{ID: 1, UserID: 1, User: &UserRefResponse{ID: 1, Name: "John"}, BlogID: 1, Message: "First", CreatedDate: createdDate},
+						Replies: []CommentThreadResponse{
+							{
+								CommentResponse: CommentResponse{ID: 2, ParentID: 1, UserID: 2, User: &UserRefResponse{ID: 2, Name: "Jane"}, BlogID: 1, Message: "Reply", CreatedDate: createdDate},
+								Replies:         []CommentThreadResponse{},
+							},
+						},
+					},
+				},
+			},
+		},
+		"depth": {
+			target:     "/api/blog/1/comments?depth=2",
+			mockCalled: true,
+			depth:      2,
+			mockOutput: models.Page[models.CommentThread]{Items: []models.CommentThread{}},
+			wantStatus: 200,
+			wantBody:   listCommentThreadsResponse{Comments: []CommentThreadResponse{}},
+		},
+		"paged": {
+			target:     "/api/blog/1/comments?limit=1",
+			mockCalled: true,
+			page:       models.PageRequest{Limit: 1},
+			mockOutput: models.Page[models.CommentThread]{Items: threads[:1], NextCursor: "abc"},
+			wantStatus: 200,
+			wantBody: listCommentThreadsResponse{
+				Comments: []CommentThreadResponse{
+					{
+						CommentResponse: CommentResponse{ID: 1, UserID: 1, User: &UserRefResponse{ID: 1, Name: "John"}, BlogID: 1, Message: "First", CreatedDate: createdDate},
+						Replies: []CommentThreadResponse{
+							{
+								CommentResponse: CommentResponse{ID: 2, ParentID: 1, UserID: 2, User: &UserRefResponse{ID: 2, Name: "Jane"}, BlogID: 1, Message: "Reply", CreatedDate: createdDate},
+								Replies:         []CommentThreadResponse{},
+							},
+						},
+					},
+				},
+				PageResponse: PageResponse{Next: "/api/blog/1/comments?cursor=abc&limit=1"},
+			},
+		},
+		"invalid limit": {
+			target:     "/api/blog/1/comments?limit=many",
+			wantStatus: 400,
+		},
+		"depth above maximum": {
+			target:     fmt.Sprintf("/api/blog/1/comments?depth=%d", services.MaxThreadDepth+1),
+			wantStatus: 400,
+		},
+		"signed in": {
+			target:     "/api/blog/1/comments",
+			actor:      testUser,
+			mockCalled: true,
+			mockOutput: models.Page[models.CommentThread]{Items: []models.CommentThread{}},
+			wantStatus: 200,
+			wantBody:   listCommentThreadsResponse{Comments: []CommentThreadResponse{}},
+		},
+		"admin": {
+			target:     "/api/blog/1/comments",
+			actor:      testAdmin,
+			mockCalled: true,
+			allBlogs:   true,
+			mockOutput: models.Page[models.CommentThread]{Items: []models.CommentThread{}},
+			wantStatus: 200,
+			wantBody:   listCommentThreadsResponse{Comments: []CommentThreadResponse{}},
+		},
+		"blog not found": {
+			target:     "/api/blog/1/comments",
+			mockCalled: true,
+			mockError:  fmt.Errorf("blog 1: %w", services.ErrNotFound),
+			wantStatus: 404,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.target, nil)
+			req.SetPathValue("id", "1")
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			lister := new(mock.CommentThreadsLister)
+			if tc.mockCalled {
+				lister.On("ListCommentThreads", req.Context(), uint(1), tc.actor.ID, tc.allBlogs, tc.depth, tc.page).Return(tc.mockOutput, tc.mockError)
+			}
+
+			handler := HandleListCommentThreads(logger, lister)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			lister.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
+
+			var got listCommentThreadsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.wantBody) {
+				t.Errorf("want body %+v, got %+v", tc.wantBody, got)
+			}
+		})
+	}
+}
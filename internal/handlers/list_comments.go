@@ -7,31 +7,42 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/middleware"
 	"github.com/chickey/blog/internal/models"
 )
 
 // commentReader represents a type capable of reading a comment from storage and
 // returning it or an error.
 type commentsLister interface {
-	ListComments(ctx context.Context, authorId uint, blogId uint) ([]models.Comment, error)
+	ListComments(ctx context.Context, filter models.CommentFilter, page models.PageRequest, expand models.CommentExpand) (models.Page[models.Comment], error)
 }
 
 // listCommentsResponse represents the response for listing comments.
 type listCommentsResponse struct {
 	Comments []CommentResponse
+	PageResponse
 }
 
 // @Summary		List Comments
-// @Description	List All Comments
+// @Description	List the comments on the blogs the caller may read
 // @Tags			comment
 // @Accept			json
 // @Produce		json
 // @Param			author_id	query		string	false	"Author Id"
 // @Param			blog_id		query		string	false	"Blog Id"
-// @Success		200			{array}		models.Comment
-// @Failure		400			{object}	string
-// @Failure		404			{object}	string
-// @Failure		500			{object}	string
+// @Param			limit		query		int		false	"Page size"
+// @Param			offset		query		int		false	"Number of comments to skip"
+// @Param			cursor		query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Param			expand		query		string	false	"Comma separated related records to embed: user, blog"
+// @Param			include_deleted	query		bool	false	"Include deleted comments, for admins only"
+// @Success		200			{object}	listCommentsResponse
+// @Failure		400			{object}	ProblemResponse
+// @Failure		401			{object}	ProblemResponse
+// @Failure		403			{object}	ProblemResponse
+// @Failure		404			{object}	ProblemResponse
+// @Failure		500			{object}	ProblemResponse
 // @Router			/comment  [GET]
 func HandleListComments(logger *slog.Logger, commentsLister commentsLister) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -53,7 +64,7 @@ func HandleListComments(logger *slog.Logger, commentsLister commentsLister) http
 					slog.String("error", err.Error()),
 				)
 
-				http.Error(w, "Invalid User ID", http.StatusBadRequest)
+				writeProblem(w, r, http.StatusBadRequest, "Invalid User ID", nil)
 				return
 			}
 		}
@@ -70,13 +81,44 @@ func HandleListComments(logger *slog.Logger, commentsLister commentsLister) http
 					slog.String("error", err.Error()),
 				)
 
-				http.Error(w, "Invalid Blog ID", http.StatusBadRequest)
+				writeProblem(w, r, http.StatusBadRequest, "Invalid Blog ID", nil)
 				return
 			}
 		}
 
-		// Read the comment
-		comments, err := commentsLister.ListComments(ctx, uint(userId), uint(blogId))
+		page, problems := parsePageRequest(r)
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid pagination parameters", problems)
+			return
+		}
+
+		expand, ok := parseExpand(r.URL.Query().Get("expand"), "user", "blog")
+		if !ok {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid expand", map[string]string{
+				"expand": "Expand must be a comma separated list of user and blog",
+			})
+			return
+		}
+
+		filter := models.CommentFilter{UserID: uint(userId), BlogID: uint(blogId)}
+
+		filter.IncludeDeleted, ok = parseIncludeDeleted(w, r)
+		if !ok {
+			return
+		}
+
+		// Comments on blogs that aren't published are only listed to those
+		// who may read the blog
+		if actor, ok := middleware.UserFromContext(ctx); ok {
+			filter.ViewerID = actor.ID
+			filter.AllBlogs = authz.CanReadUnpublished(actor)
+		}
+
+		// Read the comments
+		comments, err := commentsLister.ListComments(ctx, filter, page, models.CommentExpand{
+			User: expand["user"],
+			Blog: expand["blog"],
+		})
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -84,21 +126,27 @@ func HandleListComments(logger *slog.Logger, commentsLister commentsLister) http
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
 		// Convert our models.Comment domain model into a response model.
 		response := listCommentsResponse{
-			Comments: []CommentResponse{},
+			Comments:     []CommentResponse{},
+			PageResponse: newPageResponse(r, page, comments),
 		}
 
-		for _, comment := range comments {
+		for _, comment := range comments.Items {
 			newComment := CommentResponse{
+				ID:          comment.ID,
+				ParentID:    comment.ParentID,
 				BlogID:      comment.BlogID,
+				Blog:        newBlogRefResponse(comment.Blog),
 				UserID:      comment.UserID,
+				User:        newUserRefResponse(comment.User),
 				Message:     comment.Message,
 				CreatedDate: comment.CreatedDate,
+				DeletedAt:   optionalTime(comment.DeletedAt),
 			}
 			response.Comments = append(response.Comments, newComment)
 		}
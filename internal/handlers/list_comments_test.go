@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestHandleListComments(t *testing.T) {
+	createdDate := time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC)
+
+	tests := map[string]struct {
+		target     string
+		actor      models.User
+		mockCalled bool
+		filter     models.CommentFilter
+		expand     models.CommentExpand
+		mockOutput []models.Comment
+		wantStatus int
+		wantBody   []CommentResponse
+	}{
+		"happy path": {
+			target:     "/api/comment",
+			mockCalled: true,
+			mockOutput: []models.Comment{
+				{ID: 1, UserID: 1, BlogID: 1, Message: "Good blog", CreatedDate: createdDate},
+			},
+			wantStatus: 200,
+			wantBody: []CommentResponse{
+				{ID: 1, UserID: 1, BlogID: 1, Message: "Good blog", CreatedDate: createdDate},
+			},
+		},
+		"expanded user and blog": {
+			target:     "/api/comment?expand=user,blog",
+			mockCalled: true,
+			expand:     models.CommentExpand{User: true, Blog: true},
+			mockOutput: []models.Comment{
+				{
+					ID:          1,
+					UserID:      1,
+					User:        models.UserRef{ID: 1, Name: "John"},
+					BlogID:      1,
+					Blog:        models.BlogRef{ID: 1, Title: "Book Title"},
+					Message:     "Good blog",
+					CreatedDate: createdDate,
+				},
+			},
+			wantStatus: 200,
+			wantBody: []CommentResponse{
+				{
+					ID:          1,
+					UserID:      1,
+					User:        &UserRefResponse{ID: 1, Name: "John"},
+					BlogID:      1,
+					Blog:        &BlogRefResponse{ID: 1, Title: "Book Title"},
+					Message:     "Good blog",
+					CreatedDate: createdDate,
+				},
+			},
+		},
+		"signed in": {
+			target:     "/api/comment?blog_id=2",
+			actor:      testUser,
+			mockCalled: true,
+			filter:     models.CommentFilter{BlogID: 2, ViewerID: testUser.ID},
+			mockOutput: []models.Comment{},
+			wantStatus: 200,
+			wantBody:   []CommentResponse{},
+		},
+		"admin": {
+			target:     "/api/comment?blog_id=2",
+			actor:      testAdmin,
+			mockCalled: true,
+			filter:     models.CommentFilter{BlogID: 2, ViewerID: testAdmin.ID, AllBlogs: true},
+			mockOutput: []models.Comment{},
+			wantStatus: 200,
+			wantBody:   []CommentResponse{},
+		},
+		"invalid expand": {
+			target:     "/api/comment?expand=author",
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.target, nil)
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			lister := new(mock.CommentsLister)
+			if tc.mockCalled {
+				lister.
+					On("ListComments", req.Context(), tc.filter, models.PageRequest{}, tc.expand).
+					Return(models.Page[models.Comment]{Items: tc.mockOutput}, nil)
+			}
+
+			handler := HandleListComments(logger, lister)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			lister.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
+
+			var got listCommentsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !reflect.DeepEqual(got.Comments, tc.wantBody) {
+				t.Errorf("want comments %+v, got %+v", tc.wantBody, got.Comments)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// tagsLister represents a type capable of reading a page of tags with their
+// blog counts and returning it or an error.
+type tagsLister interface {
+	ListTags(ctx context.Context, sort []models.SortField, page models.PageRequest) (models.Page[models.Tag], error)
+}
+
+// listTagsResponse represents the response for listing tags.
+type listTagsResponse struct {
+	Tags []TagResponse
+	PageResponse
+}
+
+// @Summary		List Tags
+// @Description	List the tags of published blogs with the number of published blogs carrying each, alphabetically unless sorted otherwise
+// @Tags			tag
+// @Accept			json
+// @Produce		json
+// @Param			sort			query		string	false	"Comma separated fields, - for descending, e.g. -blog_count"
+// @Param			limit			query		int		false	"Page size"
+// @Param			offset			query		int		false	"Number of tags to skip"
+// @Param			cursor			query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Success		200		{object}	listTagsResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/tag  [GET]
+func HandleListTags(logger *slog.Logger, tagsLister tagsLister) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		problems := make(map[string]string)
+
+		sort, ok := parseSort(r.URL.Query().Get("sort"))
+		if !ok {
+			problems["sort"] = "Sort must be a comma separated list of field names"
+		}
+
+		page, pageProblems := parsePageRequest(r)
+		maps.Copy(problems, pageProblems)
+
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid query parameters", problems)
+			return
+		}
+
+		// Read the tags
+		tags, err := tagsLister.ListTags(ctx, sort, page)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to list tags",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Convert our models.Tag domain models into response models.
+		response := listTagsResponse{
+			Tags:         []TagResponse{},
+			PageResponse: newPageResponse(r, page, tags),
+		}
+
+		for _, tag := range tags.Items {
+			response.Tags = append(response.Tags, TagResponse{
+				Name:      tag.Name,
+				BlogCount: tag.BlogCount,
+			})
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleListTags(t *testing.T) {
+	tags := []models.Tag{
+		{Name: "go", BlogCount: 3},
+		{Name: "postgres", BlogCount: 1},
+	}
+
+	tests := map[string]struct {
+		target     string
+		mockCalled bool
+		sort       []models.SortField
+		page       models.PageRequest
+		mockOutput models.Page[models.Tag]
+		mockError  error
+		wantStatus int
+		wantBody   listTagsResponse
+	}{
+		"happy path": {
+			target:     "/api/tag",
+			mockCalled: true,
+			mockOutput: models.Page[models.Tag]{Items: tags},
+			wantStatus: 200,
+			wantBody: listTagsResponse{
+				Tags: []TagResponse{
+					{Name: "go", BlogCount: 3},
+					{Name: "postgres", BlogCount: 1},
+				},
+			},
+		},
+		"sorted and paged": {
+			target:     "/api/tag?sort=-blog_count&limit=1",
+			mockCalled: true,
+			sort:       []models.SortField{{Field: "blog_count", Desc: true}},
+			page:       models.PageRequest{Limit: 1},
+			mockOutput: models.Page[models.Tag]{Items: tags[:1], NextCursor: "abc"},
+			wantStatus: 200,
+			wantBody: listTagsResponse{
+				Tags:         []TagResponse{{Name: "go", BlogCount: 3}},
+				PageResponse: PageResponse{Next: "/api/tag?cursor=abc&limit=1&sort=-blog_count"},
+			},
+		},
+		"no tags": {
+			target:     "/api/tag",
+			mockCalled: true,
+			mockOutput: models.Page[models.Tag]{Items: []models.Tag{}},
+			wantStatus: 200,
+			wantBody: listTagsResponse{
+				Tags: []TagResponse{},
+			},
+		},
+		"empty sort field": {
+			target:     "/api/tag?sort=name,,blog_count",
+			wantStatus: 400,
+		},
+		"unknown sort field": {
+			target:     "/api/tag?sort=id",
+			mockCalled: true,
+			sort:       []models.SortField{{Field: "id"}},
+			mockError:  fmt.Errorf("%w: unknown field %q", services.ErrInvalidSort, "id"),
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.target, nil)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			lister := new(mock.TagsLister)
+			if tc.mockCalled {
+				lister.On("ListTags", req.Context(), tc.sort, tc.page).Return(tc.mockOutput, tc.mockError)
+			}
+
+			handler := HandleListTags(logger, lister)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			lister.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
+
+			var got listTagsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.wantBody) {
+				t.Errorf("want body %+v, got %+v", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/middleware"
+	"github.com/chickey/blog/internal/models"
+)
+
+// userBlogsLister represents a type capable of reading a page of the blogs
+// written by a user and returning it or an error.
+type userBlogsLister interface {
+	ListUserBlogs(ctx context.Context, userId uint, viewerId uint, allBlogs bool, page models.PageRequest) (models.Page[models.Blog], error)
+}
+
+// listUserBlogsResponse represents the response for listing the titles of the
+// blogs written by a user.
+type listUserBlogsResponse struct {
+	Titles []string
+	PageResponse
+}
+
+// @Summary		List User Blogs
+// @Description	List the titles of the blogs written by a user, newest first. Blogs that aren't published are only listed to their author and admins.
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Param			id				path		string	true	"User ID"
+// @Param			limit			query		int		false	"Page size"
+// @Param			offset			query		int		false	"Number of blogs to skip"
+// @Param			cursor			query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Success		200		{object}	listUserBlogsResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/user/{id}/blogs  [GET]
+func HandleListUserBlogs(logger *slog.Logger, userBlogsLister userBlogsLister) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		page, problems := parsePageRequest(r)
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid pagination parameters", problems)
+			return
+		}
+
+		// Blogs that aren't published are only listed to those who may read
+		// them
+		actor, _ := middleware.UserFromContext(ctx)
+
+		// Read the blogs
+		blogs, err := userBlogsLister.ListUserBlogs(ctx, uint(id), actor.ID, authz.CanReadUnpublished(actor), page)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to list user blogs",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Convert our models.Blog domain models into a list of titles.
+		response := listUserBlogsResponse{
+			Titles:       make([]string, 0, len(blogs.Items)),
+			PageResponse: newPageResponse(r, page, blogs),
+		}
+
+		for _, blog := range blogs.Items {
+			response.Titles = append(response.Titles, blog.Title)
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleListUserBlogs(t *testing.T) {
+	createdDate := time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC)
+	blogs := []models.Blog{
+		{ID: 2, AuthorID: 1, Title: "New Book", CreatedDate: createdDate},
+		{ID: 1, AuthorID: 1, Title: "Book Title", CreatedDate: createdDate},
+	}
+
+	tests := map[string]struct {
+		target     string
+		id         string
+		actor      models.User
+		mockCalled bool
+		viewerID   uint
+		allBlogs   bool
+		page       models.PageRequest
+		mockOutput models.Page[models.Blog]
+		mockError  error
+		wantStatus int
+		wantBody   listUserBlogsResponse
+	}{
+		"happy path": {
+			target:     "/api/user/1/blogs",
+			id:         "1",
+			mockCalled: true,
+			mockOutput: models.Page[models.Blog]{Items: blogs},
+			wantStatus: 200,
+			wantBody: listUserBlogsResponse{
+				Titles: []string{"New Book", "Book Title"},
+			},
+		},
+		"own blogs": {
+			target:     "/api/user/1/blogs",
+			id:         "1",
+			actor:      testUser,
+			mockCalled: true,
+			viewerID:   1,
+			mockOutput: models.Page[models.Blog]{Items: blogs[:1]},
+			wantStatus: 200,
+			wantBody: listUserBlogsResponse{
+				Titles: []string{"New Book"},
+			},
+		},
+		"admin": {
+			target:     "/api/user/1/blogs",
+			id:         "1",
+			actor:      testAdmin,
+			mockCalled: true,
+			viewerID:   3,
+			allBlogs:   true,
+			mockOutput: models.Page[models.Blog]{Items: blogs[:1]},
+			wantStatus: 200,
+			wantBody: listUserBlogsResponse{
+				Titles: []string{"New Book"},
+			},
+		},
+		"paged": {
+			target:     "/api/user/1/blogs?limit=1",
+			id:         "1",
+			mockCalled: true,
+			page:       models.PageRequest{Limit: 1},
+			mockOutput: models.Page[models.Blog]{Items: blogs[:1], NextCursor: "abc"},
+			wantStatus: 200,
+			wantBody: listUserBlogsResponse{
+				Titles:       []string{"New Book"},
+				PageResponse: PageResponse{Next: "/api/user/1/blogs?cursor=abc&limit=1"},
+			},
+		},
+		"no blogs": {
+			target:     "/api/user/1/blogs",
+			id:         "1",
+			mockCalled: true,
+			mockOutput: models.Page[models.Blog]{Items: []models.Blog{}},
+			wantStatus: 200,
+			wantBody: listUserBlogsResponse{
+				Titles: []string{},
+			},
+		},
+		"invalid id": {
+			target:     "/api/user/abc/blogs",
+			id:         "abc",
+			wantStatus: 400,
+		},
+		"invalid limit": {
+			target:     "/api/user/1/blogs?limit=many",
+			id:         "1",
+			wantStatus: 400,
+		},
+		"user not found": {
+			target:     "/api/user/1/blogs",
+			id:         "1",
+			mockCalled: true,
+			mockError:  fmt.Errorf("user 1: %w", services.ErrNotFound),
+			wantStatus: 404,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := withActor(httptest.NewRequest("GET", tc.target, nil), tc.actor)
+			req.SetPathValue("id", tc.id)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			lister := new(mock.UserBlogsLister)
+			if tc.mockCalled {
+				lister.On("ListUserBlogs", req.Context(), uint(1), tc.viewerID, tc.allBlogs, tc.page).Return(tc.mockOutput, tc.mockError)
+			}
+
+			handler := HandleListUserBlogs(logger, lister)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			lister.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
+
+			var got listUserBlogsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.wantBody) {
+				t.Errorf("want body %+v, got %+v", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -2,35 +2,95 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/chickey/blog/internal/handlers/mock"
 	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
 )
 
 func TestHandleListUser(t *testing.T) {
+	total := 3
+
 	tests := map[string]struct {
+		target     string
+		mockCalled bool
+		name       string
+		page       models.PageRequest
+		mockOutput models.Page[models.User]
+		mockError  error
 		wantStatus int
-		wantBody   []models.User
+		wantNext   string
+		wantPrev   string
+		wantTotal  *int
 	}{
 		"happy path": {
-			wantStatus: 200,
-			wantBody: []models.User{
-				{
-					ID:       1,
-					Name:     "john",
-					Email:    "john@mail.com",
-					Password: "password123!",
+			target:     "/api/user",
+			mockCalled: true,
+			mockOutput: models.Page[models.User]{
+				Items: []models.User{
+					{
+						ID:    1,
+						Name:  "john",
+						Email: "john@mail.com",
+					},
 				},
 			},
+			wantStatus: 200,
+		},
+		"cursor links": {
+			target:     "/api/user?name=john&limit=1&cursor=abc",
+			mockCalled: true,
+			name:       "john",
+			page:       models.PageRequest{Limit: 1, Cursor: "abc"},
+			mockOutput: models.Page[models.User]{
+				Items:      []models.User{{ID: 2, Name: "john", Email: "john@mail.com"}},
+				NextCursor: "next",
+				PrevCursor: "prev",
+			},
+			wantStatus: 200,
+			wantNext:   "/api/user?cursor=next&limit=1&name=john",
+			wantPrev:   "/api/user?cursor=prev&limit=1&name=john",
+		},
+		"offset links with total": {
+			target:     "/api/user?limit=1&offset=1&include_total=true",
+			mockCalled: true,
+			page:       models.PageRequest{Limit: 1, Offset: 1, IncludeTotal: true},
+			mockOutput: models.Page[models.User]{
+				Items:      []models.User{{ID: 2, Name: "jane", Email: "jane@mail.com"}},
+				NextCursor: "next",
+				PrevCursor: "prev",
+				Total:      &total,
+			},
+			wantStatus: 200,
+			wantNext:   "/api/user?include_total=true&limit=1&offset=2",
+			wantPrev:   "/api/user?include_total=true&limit=1&offset=0",
+			wantTotal:  &total,
+		},
+		"invalid limit": {
+			target:     fmt.Sprintf("/api/user?limit=%d", services.MaxPageLimit+1),
+			wantStatus: 400,
+		},
+		"offset and cursor": {
+			target:     "/api/user?offset=10&cursor=abc",
+			wantStatus: 400,
+		},
+		"invalid cursor": {
+			target:     "/api/user?cursor=abc",
+			mockCalled: true,
+			page:       models.PageRequest{Cursor: "abc"},
+			mockError:  fmt.Errorf("list users: %w", services.ErrInvalidCursor),
+			wantStatus: 400,
 		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// Create a new request
-			req := httptest.NewRequest("GET", "/users", nil)
+			req := httptest.NewRequest("GET", tc.target, nil)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -39,7 +99,9 @@ func TestHandleListUser(t *testing.T) {
 			logger := slog.Default()
 
 			userLister := new(mock.UsersLister)
-			userLister.On("ListUsers", context.Background(), "").Return(tc.wantBody, nil)
+			if tc.mockCalled {
+				userLister.On("ListUsers", context.Background(), tc.name, tc.page, false).Return(tc.mockOutput, tc.mockError)
+			}
 
 			// Call the handler
 			handler := HandleListUsers(logger, userLister)
@@ -49,11 +111,29 @@ func TestHandleListUser(t *testing.T) {
 			if rec.Code != tc.wantStatus {
 				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
 			}
+			userLister.AssertExpectations(t)
+
+			if rec.Code != 200 {
+				return
+			}
 
-			// // Check the body
-			// if strings.Trim(rec.Body.String(), "\n") != fmt.Sprintf("%+v", tc.wantBody) {
-			// 	t.Errorf("want body %q, got %q", tc.wantBody, rec.Body.String())
-			// }
+			// Check the body
+			var got listUsersResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(got.Users) != len(tc.mockOutput.Items) {
+				t.Errorf("want %d users, got %d", len(tc.mockOutput.Items), len(got.Users))
+			}
+			if got.Next != tc.wantNext {
+				t.Errorf("want next %q, got %q", tc.wantNext, got.Next)
+			}
+			if got.Prev != tc.wantPrev {
+				t.Errorf("want prev %q, got %q", tc.wantPrev, got.Prev)
+			}
+			if (got.Total == nil) != (tc.wantTotal == nil) || (got.Total != nil && *got.Total != *tc.wantTotal) {
+				t.Errorf("want total %v, got %v", tc.wantTotal, got.Total)
+			}
 		})
 	}
 }
@@ -12,12 +12,13 @@ import (
 // userReader represents a type capable of reading a user from storage and
 // returning it or an error.
 type usersLister interface {
-	ListUsers(ctx context.Context, name string) ([]models.User, error)
+	ListUsers(ctx context.Context, name string, page models.PageRequest, includeDeleted bool) (models.Page[models.User], error)
 }
 
 // listUsersResponse represents the response for listing users.
 type listUsersResponse struct {
 	Users []UserResponse
+	PageResponse
 }
 
 // @Summary		List Users
@@ -25,11 +26,18 @@ type listUsersResponse struct {
 // @Tags			user
 // @Accept			json
 // @Produce		json
-// @Param			name	query		string	false	"query param"
-// @Success		200		{array}		models.User
-// @Failure		400		{object}	string
-// @Failure		404		{object}	string
-// @Failure		500		{object}	string
+// @Param			name			query		string	false	"query param"
+// @Param			limit			query		int		false	"Page size"
+// @Param			offset			query		int		false	"Number of users to skip"
+// @Param			cursor			query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Param			include_deleted	query		bool	false	"Include deleted users, for admins only"
+// @Success		200		{object}	listUsersResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
 // @Router			/user  [GET]
 func HandleListUsers(logger *slog.Logger, usersLister usersLister) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -37,8 +45,19 @@ func HandleListUsers(logger *slog.Logger, usersLister usersLister) http.Handler
 
 		name := r.URL.Query().Get("name")
 
-		// Read the user
-		users, err := usersLister.ListUsers(ctx, name)
+		page, problems := parsePageRequest(r)
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid pagination parameters", problems)
+			return
+		}
+
+		includeDeleted, ok := parseIncludeDeleted(w, r)
+		if !ok {
+			return
+		}
+
+		// Read the users
+		users, err := usersLister.ListUsers(ctx, name, page, includeDeleted)
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -46,21 +65,22 @@ func HandleListUsers(logger *slog.Logger, usersLister usersLister) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
 		// Convert our models.User domain model into a response model.
 		response := listUsersResponse{
-			Users: []UserResponse{},
+			Users:        []UserResponse{},
+			PageResponse: newPageResponse(r, page, users),
 		}
 
-		for _, user := range users {
+		for _, user := range users.Items {
 			newUser := UserResponse{
-				ID:       user.ID,
-				Name:     user.Name,
-				Email:    user.Email,
-				Password: user.Password,
+				ID:        user.ID,
+				Name:      user.Name,
+				Email:     user.Email,
+				DeletedAt: optionalTime(user.DeletedAt),
 			}
 			response.Users = append(response.Users, newUser)
 		}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// sessionCreator represents a type capable of verifying credentials and
+// starting a session, returning its tokens or an error.
+type sessionCreator interface {
+	Login(ctx context.Context, email, password string) (models.TokenPair, error)
+}
+
+// @Summary		Login
+// @Description	Exchanges an email and password for access and refresh tokens
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			request	body		LoginRequest	true	"Credentials"
+// @Success		200		{object}	TokenResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/auth/login  [POST]
+func HandleLogin(logger *slog.Logger, sessionCreator sessionCreator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Request validation
+		request, problems, err := decodeValid[*LoginRequest](r)
+
+		if err != nil && len(problems) == 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to decode request",
+				slog.String("error", err.Error()))
+
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
+		}
+		if len(problems) > 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"Validation error",
+				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
+			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
+		}
+
+		// Start the session
+		tokens, err := sessionCreator.Login(ctx, request.Email, request.Password)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to log in",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(newTokenResponse(tokens)); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleLogin(t *testing.T) {
+	tests := map[string]struct {
+		wantStatus  int
+		wantResults models.TokenPair
+		wantErr     error
+		input       LoginRequest
+	}{
+		"happy path": {
+			wantStatus: 200,
+			wantResults: models.TokenPair{
+				AccessToken:          "access",
+				RefreshToken:         "refresh",
+				AccessTokenExpiresAt: time.Now().Add(15 * time.Minute),
+			},
+			input: LoginRequest{
+				Email:    "john@mail.com",
+				Password: "password123!",
+			},
+		},
+		"invalid credentials": {
+			wantStatus: 401,
+			wantErr:    fmt.Errorf("login: %w", services.ErrInvalidCredentials),
+			input: LoginRequest{
+				Email:    "john@mail.com",
+				Password: "wrong",
+			},
+		},
+		"validation failure": {
+			wantStatus: 422,
+			input: LoginRequest{
+				Email: "john@mail.com",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create a new request
+			reqBody, _ := json.Marshal(tc.input)
+			req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(reqBody))
+
+			// Create a new response recorder
+			rec := httptest.NewRecorder()
+
+			// Create a new logger
+			logger := slog.Default()
+
+			sessionCreator := new(mock.SessionCreator)
+			sessionCreator.On("Login", context.Background(), tc.input.Email, tc.input.Password).Return(tc.wantResults, tc.wantErr)
+
+			// Call the handler
+			handler := HandleLogin(logger, sessionCreator)
+
+			handler.ServeHTTP(rec, req)
+			// Check the status code
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+
+			// Check the body
+			if tc.wantStatus == 200 {
+				var response TokenResponse
+				if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if response.AccessToken != tc.wantResults.AccessToken || response.RefreshToken != tc.wantResults.RefreshToken {
+					t.Errorf("want tokens %+v, got %+v", tc.wantResults, response)
+				}
+				if response.TokenType != "Bearer" || response.ExpiresIn <= 0 {
+					t.Errorf("unexpected token type or lifetime %+v", response)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/chickey/blog/internal/middleware"
+)
+
+// sessionRevoker represents a type capable of revoking a session.
+type sessionRevoker interface {
+	Logout(ctx context.Context, sessionID string) error
+}
+
+// @Summary		Logout
+// @Description	Revokes the session of the access token, invalidating it and its refresh token
+// @Tags			auth
+// @Produce		json
+// @Security		BearerAuth
+// @Success		204
+// @Failure		401	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
+// @Router			/auth/logout  [POST]
+func HandleLogout(logger *slog.Logger, sessionRevoker sessionRevoker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		session, ok := middleware.SessionFromContext(ctx)
+		if !ok {
+			writeProblem(w, r, http.StatusUnauthorized, "This request requires an access token.", nil)
+			return
+		}
+
+		// Revoke the session
+		if err := sessionRevoker.Logout(ctx, session.ID); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to log out",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
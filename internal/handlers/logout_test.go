@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/middleware"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestHandleLogout(t *testing.T) {
+	tests := map[string]struct {
+		wantStatus int
+		session    *models.Session
+	}{
+		"happy path": {
+			wantStatus: 204,
+			session:    &models.Session{ID: "abc", UserID: 1},
+		},
+		"anonymous": {
+			wantStatus: 401,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create a new request, authenticated if the case has a session
+			req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+			if tc.session != nil {
+				ctx := middleware.WithUser(req.Context(), models.User{ID: tc.session.UserID}, *tc.session)
+				req = req.WithContext(ctx)
+			}
+
+			// Create a new response recorder
+			rec := httptest.NewRecorder()
+
+			// Create a new logger
+			logger := slog.Default()
+
+			sessionRevoker := new(mock.SessionRevoker)
+			sessionRevoker.On("Logout", req.Context(), "abc").Return(nil)
+
+			// Call the handler
+			handler := HandleLogout(logger, sessionRevoker)
+
+			handler.ServeHTTP(rec, req)
+			// Check the status code
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if tc.session == nil {
+				sessionRevoker.AssertNotCalled(t, "Logout", req.Context(), "abc")
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/chickey/blog/internal/models"
 )
 
 // BlogDeleter is an autogenerated mock type for the blogDeleter type
@@ -21,17 +23,17 @@ func (_m *BlogDeleter) EXPECT() *BlogDeleter_Expecter {
 	return &BlogDeleter_Expecter{mock: &_m.Mock}
 }
 
-// DeleteBlog provides a mock function with given fields: ctx, id
-func (_m *BlogDeleter) DeleteBlog(ctx context.Context, id uint64) error {
-	ret := _m.Called(ctx, id)
+// DeleteBlog provides a mock function with given fields: ctx, id, version
+func (_m *BlogDeleter) DeleteBlog(ctx context.Context, id uint64, version uint) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteBlog")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -47,13 +49,14 @@ type BlogDeleter_DeleteBlog_Call struct {
 // DeleteBlog is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uint64
-func (_e *BlogDeleter_Expecter) DeleteBlog(ctx interface{}, id interface{}) *BlogDeleter_DeleteBlog_Call {
-	return &BlogDeleter_DeleteBlog_Call{Call: _e.mock.On("DeleteBlog", ctx, id)}
+//   - version uint
+func (_e *BlogDeleter_Expecter) DeleteBlog(ctx interface{}, id interface{}, version interface{}) *BlogDeleter_DeleteBlog_Call {
+	return &BlogDeleter_DeleteBlog_Call{Call: _e.mock.On("DeleteBlog", ctx, id, version)}
 }
 
-func (_c *BlogDeleter_DeleteBlog_Call) Run(run func(ctx context.Context, id uint64)) *BlogDeleter_DeleteBlog_Call {
+func (_c *BlogDeleter_DeleteBlog_Call) Run(run func(ctx context.Context, id uint64, version uint)) *BlogDeleter_DeleteBlog_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64))
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint))
 	})
 	return _c
 }
@@ -63,7 +66,66 @@ func (_c *BlogDeleter_DeleteBlog_Call) Return(_a0 error) *BlogDeleter_DeleteBlog
 	return _c
 }
 
-func (_c *BlogDeleter_DeleteBlog_Call) RunAndReturn(run func(context.Context, uint64) error) *BlogDeleter_DeleteBlog_Call {
+func (_c *BlogDeleter_DeleteBlog_Call) RunAndReturn(run func(context.Context, uint64, uint) error) *BlogDeleter_DeleteBlog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReadBlog provides a mock function with given fields: ctx, id, expand, includeDeleted
+func (_m *BlogDeleter) ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error) {
+	ret := _m.Called(ctx, id, expand, includeDeleted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadBlog")
+	}
+
+	var r0 models.Blog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.BlogExpand, bool) (models.Blog, error)); ok {
+		return rf(ctx, id, expand, includeDeleted)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.BlogExpand, bool) models.Blog); ok {
+		r0 = rf(ctx, id, expand, includeDeleted)
+	} else {
+		r0 = ret.Get(0).(models.Blog)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, models.BlogExpand, bool) error); ok {
+		r1 = rf(ctx, id, expand, includeDeleted)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BlogDeleter_ReadBlog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadBlog'
+type BlogDeleter_ReadBlog_Call struct {
+	*mock.Call
+}
+
+// ReadBlog is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint64
+//   - expand models.BlogExpand
+//   - includeDeleted bool
+func (_e *BlogDeleter_Expecter) ReadBlog(ctx interface{}, id interface{}, expand interface{}, includeDeleted interface{}) *BlogDeleter_ReadBlog_Call {
+	return &BlogDeleter_ReadBlog_Call{Call: _e.mock.On("ReadBlog", ctx, id, expand, includeDeleted)}
+}
+
+func (_c *BlogDeleter_ReadBlog_Call) Run(run func(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool)) *BlogDeleter_ReadBlog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(models.BlogExpand), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *BlogDeleter_ReadBlog_Call) Return(_a0 models.Blog, _a1 error) *BlogDeleter_ReadBlog_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BlogDeleter_ReadBlog_Call) RunAndReturn(run func(context.Context, uint64, models.BlogExpand, bool) (models.Blog, error)) *BlogDeleter_ReadBlog_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -23,9 +23,9 @@ func (_m *BlogReader) EXPECT() *BlogReader_Expecter {
 	return &BlogReader_Expecter{mock: &_m.Mock}
 }
 
-// ReadBlog provides a mock function with given fields: ctx, id
-func (_m *BlogReader) ReadBlog(ctx context.Context, id uint64) (models.Blog, error) {
-	ret := _m.Called(ctx, id)
+// ReadBlog provides a mock function with given fields: ctx, id, expand, includeDeleted
+func (_m *BlogReader) ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error) {
+	ret := _m.Called(ctx, id, expand, includeDeleted)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ReadBlog")
@@ -33,17 +33,17 @@ func (_m *BlogReader) ReadBlog(ctx context.Context, id uint64) (models.Blog, err
 
 	var r0 models.Blog
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) (models.Blog, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.BlogExpand, bool) (models.Blog, error)); ok {
+		return rf(ctx, id, expand, includeDeleted)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) models.Blog); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.BlogExpand, bool) models.Blog); ok {
+		r0 = rf(ctx, id, expand, includeDeleted)
 	} else {
 		r0 = ret.Get(0).(models.Blog)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, models.BlogExpand, bool) error); ok {
+		r1 = rf(ctx, id, expand, includeDeleted)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,13 +59,15 @@ type BlogReader_ReadBlog_Call struct {
 // ReadBlog is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uint64
-func (_e *BlogReader_Expecter) ReadBlog(ctx interface{}, id interface{}) *BlogReader_ReadBlog_Call {
-	return &BlogReader_ReadBlog_Call{Call: _e.mock.On("ReadBlog", ctx, id)}
+//   - expand models.BlogExpand
+//   - includeDeleted bool
+func (_e *BlogReader_Expecter) ReadBlog(ctx interface{}, id interface{}, expand interface{}, includeDeleted interface{}) *BlogReader_ReadBlog_Call {
+	return &BlogReader_ReadBlog_Call{Call: _e.mock.On("ReadBlog", ctx, id, expand, includeDeleted)}
 }
 
-func (_c *BlogReader_ReadBlog_Call) Run(run func(ctx context.Context, id uint64)) *BlogReader_ReadBlog_Call {
+func (_c *BlogReader_ReadBlog_Call) Run(run func(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool)) *BlogReader_ReadBlog_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64))
+		run(args[0].(context.Context), args[1].(uint64), args[2].(models.BlogExpand), args[3].(bool))
 	})
 	return _c
 }
@@ -75,7 +77,7 @@ func (_c *BlogReader_ReadBlog_Call) Return(_a0 models.Blog, _a1 error) *BlogRead
 	return _c
 }
 
-func (_c *BlogReader_ReadBlog_Call) RunAndReturn(run func(context.Context, uint64) (models.Blog, error)) *BlogReader_ReadBlog_Call {
+func (_c *BlogReader_ReadBlog_Call) RunAndReturn(run func(context.Context, uint64, models.BlogExpand, bool) (models.Blog, error)) *BlogReader_ReadBlog_Call {
 	_c.Call.Return(run)
 	return _c
 }
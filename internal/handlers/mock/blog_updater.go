@@ -23,9 +23,68 @@ func (_m *BlogUpdater) EXPECT() *BlogUpdater_Expecter {
 	return &BlogUpdater_Expecter{mock: &_m.Mock}
 }
 
-// UpdateBlog provides a mock function with given fields: ctx, id, patch
-func (_m *BlogUpdater) UpdateBlog(ctx context.Context, id uint64, patch models.Blog) (models.Blog, error) {
-	ret := _m.Called(ctx, id, patch)
+// ReadBlog provides a mock function with given fields: ctx, id, expand, includeDeleted
+func (_m *BlogUpdater) ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error) {
+	ret := _m.Called(ctx, id, expand, includeDeleted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadBlog")
+	}
+
+	var r0 models.Blog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.BlogExpand, bool) (models.Blog, error)); ok {
+		return rf(ctx, id, expand, includeDeleted)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.BlogExpand, bool) models.Blog); ok {
+		r0 = rf(ctx, id, expand, includeDeleted)
+	} else {
+		r0 = ret.Get(0).(models.Blog)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, models.BlogExpand, bool) error); ok {
+		r1 = rf(ctx, id, expand, includeDeleted)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BlogUpdater_ReadBlog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadBlog'
+type BlogUpdater_ReadBlog_Call struct {
+	*mock.Call
+}
+
+// ReadBlog is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint64
+//   - expand models.BlogExpand
+//   - includeDeleted bool
+func (_e *BlogUpdater_Expecter) ReadBlog(ctx interface{}, id interface{}, expand interface{}, includeDeleted interface{}) *BlogUpdater_ReadBlog_Call {
+	return &BlogUpdater_ReadBlog_Call{Call: _e.mock.On("ReadBlog", ctx, id, expand, includeDeleted)}
+}
+
+func (_c *BlogUpdater_ReadBlog_Call) Run(run func(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool)) *BlogUpdater_ReadBlog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64), args[2].(models.BlogExpand), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *BlogUpdater_ReadBlog_Call) Return(_a0 models.Blog, _a1 error) *BlogUpdater_ReadBlog_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BlogUpdater_ReadBlog_Call) RunAndReturn(run func(context.Context, uint64, models.BlogExpand, bool) (models.Blog, error)) *BlogUpdater_ReadBlog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateBlog provides a mock function with given fields: ctx, id, patch, editorId
+func (_m *BlogUpdater) UpdateBlog(ctx context.Context, id uint64, patch models.Blog, editorId uint) (models.Blog, error) {
+	ret := _m.Called(ctx, id, patch, editorId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateBlog")
@@ -33,17 +92,17 @@ func (_m *BlogUpdater) UpdateBlog(ctx context.Context, id uint64, patch models.B
 
 	var r0 models.Blog
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.Blog) (models.Blog, error)); ok {
-		return rf(ctx, id, patch)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.Blog, uint) (models.Blog, error)); ok {
+		return rf(ctx, id, patch, editorId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.Blog) models.Blog); ok {
-		r0 = rf(ctx, id, patch)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, models.Blog, uint) models.Blog); ok {
+		r0 = rf(ctx, id, patch, editorId)
 	} else {
 		r0 = ret.Get(0).(models.Blog)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint64, models.Blog) error); ok {
-		r1 = rf(ctx, id, patch)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, models.Blog, uint) error); ok {
+		r1 = rf(ctx, id, patch, editorId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,13 +119,14 @@ type BlogUpdater_UpdateBlog_Call struct {
 //   - ctx context.Context
 //   - id uint64
 //   - patch models.Blog
-func (_e *BlogUpdater_Expecter) UpdateBlog(ctx interface{}, id interface{}, patch interface{}) *BlogUpdater_UpdateBlog_Call {
-	return &BlogUpdater_UpdateBlog_Call{Call: _e.mock.On("UpdateBlog", ctx, id, patch)}
+//   - editorId uint
+func (_e *BlogUpdater_Expecter) UpdateBlog(ctx interface{}, id interface{}, patch interface{}, editorId interface{}) *BlogUpdater_UpdateBlog_Call {
+	return &BlogUpdater_UpdateBlog_Call{Call: _e.mock.On("UpdateBlog", ctx, id, patch, editorId)}
 }
 
-func (_c *BlogUpdater_UpdateBlog_Call) Run(run func(ctx context.Context, id uint64, patch models.Blog)) *BlogUpdater_UpdateBlog_Call {
+func (_c *BlogUpdater_UpdateBlog_Call) Run(run func(ctx context.Context, id uint64, patch models.Blog, editorId uint)) *BlogUpdater_UpdateBlog_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64), args[2].(models.Blog))
+		run(args[0].(context.Context), args[1].(uint64), args[2].(models.Blog), args[3].(uint))
 	})
 	return _c
 }
@@ -76,7 +136,7 @@ func (_c *BlogUpdater_UpdateBlog_Call) Return(_a0 models.Blog, _a1 error) *BlogU
 	return _c
 }
 
-func (_c *BlogUpdater_UpdateBlog_Call) RunAndReturn(run func(context.Context, uint64, models.Blog) (models.Blog, error)) *BlogUpdater_UpdateBlog_Call {
+func (_c *BlogUpdater_UpdateBlog_Call) RunAndReturn(run func(context.Context, uint64, models.Blog, uint) (models.Blog, error)) *BlogUpdater_UpdateBlog_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -23,29 +23,27 @@ func (_m *BlogsLister) EXPECT() *BlogsLister_Expecter {
 	return &BlogsLister_Expecter{mock: &_m.Mock}
 }
 
-// ListBlogs provides a mock function with given fields: ctx, title
-func (_m *BlogsLister) ListBlogs(ctx context.Context, title string) ([]models.Blog, error) {
-	ret := _m.Called(ctx, title)
+// ListBlogs provides a mock function with given fields: ctx, filter, sort, page, expand
+func (_m *BlogsLister) ListBlogs(ctx context.Context, filter models.BlogFilter, sort []models.SortField, page models.PageRequest, expand models.BlogExpand) (models.Page[models.Blog], error) {
+	ret := _m.Called(ctx, filter, sort, page, expand)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListBlogs")
 	}
 
-	var r0 []models.Blog
+	var r0 models.Page[models.Blog]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.Blog, error)); ok {
-		return rf(ctx, title)
+	if rf, ok := ret.Get(0).(func(context.Context, models.BlogFilter, []models.SortField, models.PageRequest, models.BlogExpand) (models.Page[models.Blog], error)); ok {
+		return rf(ctx, filter, sort, page, expand)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []models.Blog); ok {
-		r0 = rf(ctx, title)
+	if rf, ok := ret.Get(0).(func(context.Context, models.BlogFilter, []models.SortField, models.PageRequest, models.BlogExpand) models.Page[models.Blog]); ok {
+		r0 = rf(ctx, filter, sort, page, expand)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Blog)
-		}
+		r0 = ret.Get(0).(models.Page[models.Blog])
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, title)
+	if rf, ok := ret.Get(1).(func(context.Context, models.BlogFilter, []models.SortField, models.PageRequest, models.BlogExpand) error); ok {
+		r1 = rf(ctx, filter, sort, page, expand)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,24 +58,27 @@ type BlogsLister_ListBlogs_Call struct {
 
 // ListBlogs is a helper method to define mock.On call
 //   - ctx context.Context
-//   - title string
-func (_e *BlogsLister_Expecter) ListBlogs(ctx interface{}, title interface{}) *BlogsLister_ListBlogs_Call {
-	return &BlogsLister_ListBlogs_Call{Call: _e.mock.On("ListBlogs", ctx, title)}
+//   - filter models.BlogFilter
+//   - sort []models.SortField
+//   - page models.PageRequest
+//   - expand models.BlogExpand
+func (_e *BlogsLister_Expecter) ListBlogs(ctx interface{}, filter interface{}, sort interface{}, page interface{}, expand interface{}) *BlogsLister_ListBlogs_Call {
+	return &BlogsLister_ListBlogs_Call{Call: _e.mock.On("ListBlogs", ctx, filter, sort, page, expand)}
 }
 
-func (_c *BlogsLister_ListBlogs_Call) Run(run func(ctx context.Context, title string)) *BlogsLister_ListBlogs_Call {
+func (_c *BlogsLister_ListBlogs_Call) Run(run func(ctx context.Context, filter models.BlogFilter, sort []models.SortField, page models.PageRequest, expand models.BlogExpand)) *BlogsLister_ListBlogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(models.BlogFilter), args[2].([]models.SortField), args[3].(models.PageRequest), args[4].(models.BlogExpand))
 	})
 	return _c
 }
 
-func (_c *BlogsLister_ListBlogs_Call) Return(_a0 []models.Blog, _a1 error) *BlogsLister_ListBlogs_Call {
+func (_c *BlogsLister_ListBlogs_Call) Return(_a0 models.Page[models.Blog], _a1 error) *BlogsLister_ListBlogs_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *BlogsLister_ListBlogs_Call) RunAndReturn(run func(context.Context, string) ([]models.Blog, error)) *BlogsLister_ListBlogs_Call {
+func (_c *BlogsLister_ListBlogs_Call) RunAndReturn(run func(context.Context, models.BlogFilter, []models.SortField, models.PageRequest, models.BlogExpand) (models.Page[models.Blog], error)) *BlogsLister_ListBlogs_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -23,9 +23,9 @@ func (_m *CommentCreator) EXPECT() *CommentCreator_Expecter {
 	return &CommentCreator_Expecter{mock: &_m.Mock}
 }
 
-// CreateComment provides a mock function with given fields: ctx, comment
-func (_m *CommentCreator) CreateComment(ctx context.Context, comment models.Comment) (models.Comment, error) {
-	ret := _m.Called(ctx, comment)
+// CreateComment provides a mock function with given fields: ctx, comment, viewerId, allBlogs
+func (_m *CommentCreator) CreateComment(ctx context.Context, comment models.Comment, viewerId uint, allBlogs bool) (models.Comment, error) {
+	ret := _m.Called(ctx, comment, viewerId, allBlogs)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateComment")
@@ -33,17 +33,17 @@ func (_m *CommentCreator) CreateComment(ctx context.Context, comment models.Comm
 
 	var r0 models.Comment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.Comment) (models.Comment, error)); ok {
-		return rf(ctx, comment)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Comment, uint, bool) (models.Comment, error)); ok {
+		return rf(ctx, comment, viewerId, allBlogs)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, models.Comment) models.Comment); ok {
-		r0 = rf(ctx, comment)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Comment, uint, bool) models.Comment); ok {
+		r0 = rf(ctx, comment, viewerId, allBlogs)
 	} else {
 		r0 = ret.Get(0).(models.Comment)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, models.Comment) error); ok {
-		r1 = rf(ctx, comment)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Comment, uint, bool) error); ok {
+		r1 = rf(ctx, comment, viewerId, allBlogs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,13 +59,15 @@ type CommentCreator_CreateComment_Call struct {
 // CreateComment is a helper method to define mock.On call
 //   - ctx context.Context
 //   - comment models.Comment
-func (_e *CommentCreator_Expecter) CreateComment(ctx interface{}, comment interface{}) *CommentCreator_CreateComment_Call {
-	return &CommentCreator_CreateComment_Call{Call: _e.mock.On("CreateComment", ctx, comment)}
+//   - viewerId uint
+//   - allBlogs bool
+func (_e *CommentCreator_Expecter) CreateComment(ctx interface{}, comment interface{}, viewerId interface{}, allBlogs interface{}) *CommentCreator_CreateComment_Call {
+	return &CommentCreator_CreateComment_Call{Call: _e.mock.On("CreateComment", ctx, comment, viewerId, allBlogs)}
 }
 
-func (_c *CommentCreator_CreateComment_Call) Run(run func(ctx context.Context, comment models.Comment)) *CommentCreator_CreateComment_Call {
+func (_c *CommentCreator_CreateComment_Call) Run(run func(ctx context.Context, comment models.Comment, viewerId uint, allBlogs bool)) *CommentCreator_CreateComment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.Comment))
+		run(args[0].(context.Context), args[1].(models.Comment), args[2].(uint), args[3].(bool))
 	})
 	return _c
 }
@@ -75,7 +77,7 @@ func (_c *CommentCreator_CreateComment_Call) Return(_a0 models.Comment, _a1 erro
 	return _c
 }
 
-func (_c *CommentCreator_CreateComment_Call) RunAndReturn(run func(context.Context, models.Comment) (models.Comment, error)) *CommentCreator_CreateComment_Call {
+func (_c *CommentCreator_CreateComment_Call) RunAndReturn(run func(context.Context, models.Comment, uint, bool) (models.Comment, error)) *CommentCreator_CreateComment_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -21,17 +21,17 @@ func (_m *CommentDeleter) EXPECT() *CommentDeleter_Expecter {
 	return &CommentDeleter_Expecter{mock: &_m.Mock}
 }
 
-// DeleteComment provides a mock function with given fields: ctx, user_id, blog_id
-func (_m *CommentDeleter) DeleteComment(ctx context.Context, user_id uint, blog_id uint) error {
-	ret := _m.Called(ctx, user_id, blog_id)
+// DeleteComment provides a mock function with given fields: ctx, id, user_id, blog_id, version
+func (_m *CommentDeleter) DeleteComment(ctx context.Context, id uint, user_id uint, blog_id uint, version uint) error {
+	ret := _m.Called(ctx, id, user_id, blog_id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteComment")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
-		r0 = rf(ctx, user_id, blog_id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, uint, uint) error); ok {
+		r0 = rf(ctx, id, user_id, blog_id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -46,15 +46,17 @@ type CommentDeleter_DeleteComment_Call struct {
 
 // DeleteComment is a helper method to define mock.On call
 //   - ctx context.Context
+//   - id uint
 //   - user_id uint
 //   - blog_id uint
-func (_e *CommentDeleter_Expecter) DeleteComment(ctx interface{}, user_id interface{}, blog_id interface{}) *CommentDeleter_DeleteComment_Call {
-	return &CommentDeleter_DeleteComment_Call{Call: _e.mock.On("DeleteComment", ctx, user_id, blog_id)}
+//   - version uint
+func (_e *CommentDeleter_Expecter) DeleteComment(ctx interface{}, id interface{}, user_id interface{}, blog_id interface{}, version interface{}) *CommentDeleter_DeleteComment_Call {
+	return &CommentDeleter_DeleteComment_Call{Call: _e.mock.On("DeleteComment", ctx, id, user_id, blog_id, version)}
 }
 
-func (_c *CommentDeleter_DeleteComment_Call) Run(run func(ctx context.Context, user_id uint, blog_id uint)) *CommentDeleter_DeleteComment_Call {
+func (_c *CommentDeleter_DeleteComment_Call) Run(run func(ctx context.Context, id uint, user_id uint, blog_id uint, version uint)) *CommentDeleter_DeleteComment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint), args[2].(uint))
+		run(args[0].(context.Context), args[1].(uint), args[2].(uint), args[3].(uint), args[4].(uint))
 	})
 	return _c
 }
@@ -64,7 +66,7 @@ func (_c *CommentDeleter_DeleteComment_Call) Return(_a0 error) *CommentDeleter_D
 	return _c
 }
 
-func (_c *CommentDeleter_DeleteComment_Call) RunAndReturn(run func(context.Context, uint, uint) error) *CommentDeleter_DeleteComment_Call {
+func (_c *CommentDeleter_DeleteComment_Call) RunAndReturn(run func(context.Context, uint, uint, uint, uint) error) *CommentDeleter_DeleteComment_Call {
 	_c.Call.Return(run)
 	return _c
 }
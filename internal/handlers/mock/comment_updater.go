@@ -23,9 +23,9 @@ func (_m *CommentUpdater) EXPECT() *CommentUpdater_Expecter {
 	return &CommentUpdater_Expecter{mock: &_m.Mock}
 }
 
-// UpdateComment provides a mock function with given fields: ctx, patch
-func (_m *CommentUpdater) UpdateComment(ctx context.Context, patch models.Comment) (models.Comment, error) {
-	ret := _m.Called(ctx, patch)
+// UpdateComment provides a mock function with given fields: ctx, patch, editorId
+func (_m *CommentUpdater) UpdateComment(ctx context.Context, patch models.Comment, editorId uint) (models.Comment, error) {
+	ret := _m.Called(ctx, patch, editorId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateComment")
@@ -33,17 +33,17 @@ func (_m *CommentUpdater) UpdateComment(ctx context.Context, patch models.Commen
 
 	var r0 models.Comment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.Comment) (models.Comment, error)); ok {
-		return rf(ctx, patch)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Comment, uint) (models.Comment, error)); ok {
+		return rf(ctx, patch, editorId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, models.Comment) models.Comment); ok {
-		r0 = rf(ctx, patch)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Comment, uint) models.Comment); ok {
+		r0 = rf(ctx, patch, editorId)
 	} else {
 		r0 = ret.Get(0).(models.Comment)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, models.Comment) error); ok {
-		r1 = rf(ctx, patch)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Comment, uint) error); ok {
+		r1 = rf(ctx, patch, editorId)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,13 +59,14 @@ type CommentUpdater_UpdateComment_Call struct {
 // UpdateComment is a helper method to define mock.On call
 //   - ctx context.Context
 //   - patch models.Comment
-func (_e *CommentUpdater_Expecter) UpdateComment(ctx interface{}, patch interface{}) *CommentUpdater_UpdateComment_Call {
-	return &CommentUpdater_UpdateComment_Call{Call: _e.mock.On("UpdateComment", ctx, patch)}
+//   - editorId uint
+func (_e *CommentUpdater_Expecter) UpdateComment(ctx interface{}, patch interface{}, editorId interface{}) *CommentUpdater_UpdateComment_Call {
+	return &CommentUpdater_UpdateComment_Call{Call: _e.mock.On("UpdateComment", ctx, patch, editorId)}
 }
 
-func (_c *CommentUpdater_UpdateComment_Call) Run(run func(ctx context.Context, patch models.Comment)) *CommentUpdater_UpdateComment_Call {
+func (_c *CommentUpdater_UpdateComment_Call) Run(run func(ctx context.Context, patch models.Comment, editorId uint)) *CommentUpdater_UpdateComment_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.Comment))
+		run(args[0].(context.Context), args[1].(models.Comment), args[2].(uint))
 	})
 	return _c
 }
@@ -75,7 +76,7 @@ func (_c *CommentUpdater_UpdateComment_Call) Return(_a0 models.Comment, _a1 erro
 	return _c
 }
 
-func (_c *CommentUpdater_UpdateComment_Call) RunAndReturn(run func(context.Context, models.Comment) (models.Comment, error)) *CommentUpdater_UpdateComment_Call {
+func (_c *CommentUpdater_UpdateComment_Call) RunAndReturn(run func(context.Context, models.Comment, uint) (models.Comment, error)) *CommentUpdater_UpdateComment_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -23,29 +23,27 @@ func (_m *CommentsLister) EXPECT() *CommentsLister_Expecter {
 	return &CommentsLister_Expecter{mock: &_m.Mock}
 }
 
-// ListComments provides a mock function with given fields: ctx, authorId, blogId
-func (_m *CommentsLister) ListComments(ctx context.Context, authorId uint, blogId uint) ([]models.Comment, error) {
-	ret := _m.Called(ctx, authorId, blogId)
+// ListComments provides a mock function with given fields: ctx, filter, page, expand
+func (_m *CommentsLister) ListComments(ctx context.Context, filter models.CommentFilter, page models.PageRequest, expand models.CommentExpand) (models.Page[models.Comment], error) {
+	ret := _m.Called(ctx, filter, page, expand)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListComments")
 	}
 
-	var r0 []models.Comment
+	var r0 models.Page[models.Comment]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) ([]models.Comment, error)); ok {
-		return rf(ctx, authorId, blogId)
+	if rf, ok := ret.Get(0).(func(context.Context, models.CommentFilter, models.PageRequest, models.CommentExpand) (models.Page[models.Comment], error)); ok {
+		return rf(ctx, filter, page, expand)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) []models.Comment); ok {
-		r0 = rf(ctx, authorId, blogId)
+	if rf, ok := ret.Get(0).(func(context.Context, models.CommentFilter, models.PageRequest, models.CommentExpand) models.Page[models.Comment]); ok {
+		r0 = rf(ctx, filter, page, expand)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Comment)
-		}
+		r0 = ret.Get(0).(models.Page[models.Comment])
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
-		r1 = rf(ctx, authorId, blogId)
+	if rf, ok := ret.Get(1).(func(context.Context, models.CommentFilter, models.PageRequest, models.CommentExpand) error); ok {
+		r1 = rf(ctx, filter, page, expand)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -60,25 +58,26 @@ type CommentsLister_ListComments_Call struct {
 
 // ListComments is a helper method to define mock.On call
 //   - ctx context.Context
-//   - authorId uint
-//   - blogId uint
-func (_e *CommentsLister_Expecter) ListComments(ctx interface{}, authorId interface{}, blogId interface{}) *CommentsLister_ListComments_Call {
-	return &CommentsLister_ListComments_Call{Call: _e.mock.On("ListComments", ctx, authorId, blogId)}
+//   - filter models.CommentFilter
+//   - page models.PageRequest
+//   - expand models.CommentExpand
+func (_e *CommentsLister_Expecter) ListComments(ctx interface{}, filter interface{}, page interface{}, expand interface{}) *CommentsLister_ListComments_Call {
+	return &CommentsLister_ListComments_Call{Call: _e.mock.On("ListComments", ctx, filter, page, expand)}
 }
 
-func (_c *CommentsLister_ListComments_Call) Run(run func(ctx context.Context, authorId uint, blogId uint)) *CommentsLister_ListComments_Call {
+func (_c *CommentsLister_ListComments_Call) Run(run func(ctx context.Context, filter models.CommentFilter, page models.PageRequest, expand models.CommentExpand)) *CommentsLister_ListComments_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint), args[2].(uint))
+		run(args[0].(context.Context), args[1].(models.CommentFilter), args[2].(models.PageRequest), args[3].(models.CommentExpand))
 	})
 	return _c
 }
 
-func (_c *CommentsLister_ListComments_Call) Return(_a0 []models.Comment, _a1 error) *CommentsLister_ListComments_Call {
+func (_c *CommentsLister_ListComments_Call) Return(_a0 models.Page[models.Comment], _a1 error) *CommentsLister_ListComments_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *CommentsLister_ListComments_Call) RunAndReturn(run func(context.Context, uint, uint) ([]models.Comment, error)) *CommentsLister_ListComments_Call {
+func (_c *CommentsLister_ListComments_Call) RunAndReturn(run func(context.Context, models.CommentFilter, models.PageRequest, models.CommentExpand) (models.Page[models.Comment], error)) *CommentsLister_ListComments_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -21,17 +21,17 @@ func (_m *UserDeleter) EXPECT() *UserDeleter_Expecter {
 	return &UserDeleter_Expecter{mock: &_m.Mock}
 }
 
-// DeleteUser provides a mock function with given fields: ctx, id
-func (_m *UserDeleter) DeleteUser(ctx context.Context, id uint64) error {
-	ret := _m.Called(ctx, id)
+// DeleteUser provides a mock function with given fields: ctx, id, version
+func (_m *UserDeleter) DeleteUser(ctx context.Context, id uint64, version uint) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -47,13 +47,14 @@ type UserDeleter_DeleteUser_Call struct {
 // DeleteUser is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uint64
-func (_e *UserDeleter_Expecter) DeleteUser(ctx interface{}, id interface{}) *UserDeleter_DeleteUser_Call {
-	return &UserDeleter_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, id)}
+//   - version uint
+func (_e *UserDeleter_Expecter) DeleteUser(ctx interface{}, id interface{}, version interface{}) *UserDeleter_DeleteUser_Call {
+	return &UserDeleter_DeleteUser_Call{Call: _e.mock.On("DeleteUser", ctx, id, version)}
 }
 
-func (_c *UserDeleter_DeleteUser_Call) Run(run func(ctx context.Context, id uint64)) *UserDeleter_DeleteUser_Call {
+func (_c *UserDeleter_DeleteUser_Call) Run(run func(ctx context.Context, id uint64, version uint)) *UserDeleter_DeleteUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64))
+		run(args[0].(context.Context), args[1].(uint64), args[2].(uint))
 	})
 	return _c
 }
@@ -63,7 +64,7 @@ func (_c *UserDeleter_DeleteUser_Call) Return(_a0 error) *UserDeleter_DeleteUser
 	return _c
 }
 
-func (_c *UserDeleter_DeleteUser_Call) RunAndReturn(run func(context.Context, uint64) error) *UserDeleter_DeleteUser_Call {
+func (_c *UserDeleter_DeleteUser_Call) RunAndReturn(run func(context.Context, uint64, uint) error) *UserDeleter_DeleteUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
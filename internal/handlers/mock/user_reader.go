@@ -23,9 +23,9 @@ func (_m *UserReader) EXPECT() *UserReader_Expecter {
 	return &UserReader_Expecter{mock: &_m.Mock}
 }
 
-// ReadUser provides a mock function with given fields: ctx, id
-func (_m *UserReader) ReadUser(ctx context.Context, id uint64) (models.User, error) {
-	ret := _m.Called(ctx, id)
+// ReadUser provides a mock function with given fields: ctx, id, includeDeleted
+func (_m *UserReader) ReadUser(ctx context.Context, id uint64, includeDeleted bool) (models.User, error) {
+	ret := _m.Called(ctx, id, includeDeleted)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ReadUser")
@@ -33,17 +33,17 @@ func (_m *UserReader) ReadUser(ctx context.Context, id uint64) (models.User, err
 
 	var r0 models.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) (models.User, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, bool) (models.User, error)); ok {
+		return rf(ctx, id, includeDeleted)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uint64) models.User); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, bool) models.User); ok {
+		r0 = rf(ctx, id, includeDeleted)
 	} else {
 		r0 = ret.Get(0).(models.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, bool) error); ok {
+		r1 = rf(ctx, id, includeDeleted)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,13 +59,14 @@ type UserReader_ReadUser_Call struct {
 // ReadUser is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uint64
-func (_e *UserReader_Expecter) ReadUser(ctx interface{}, id interface{}) *UserReader_ReadUser_Call {
-	return &UserReader_ReadUser_Call{Call: _e.mock.On("ReadUser", ctx, id)}
+//   - includeDeleted bool
+func (_e *UserReader_Expecter) ReadUser(ctx interface{}, id interface{}, includeDeleted interface{}) *UserReader_ReadUser_Call {
+	return &UserReader_ReadUser_Call{Call: _e.mock.On("ReadUser", ctx, id, includeDeleted)}
 }
 
-func (_c *UserReader_ReadUser_Call) Run(run func(ctx context.Context, id uint64)) *UserReader_ReadUser_Call {
+func (_c *UserReader_ReadUser_Call) Run(run func(ctx context.Context, id uint64, includeDeleted bool)) *UserReader_ReadUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uint64))
+		run(args[0].(context.Context), args[1].(uint64), args[2].(bool))
 	})
 	return _c
 }
@@ -75,7 +76,7 @@ func (_c *UserReader_ReadUser_Call) Return(_a0 models.User, _a1 error) *UserRead
 	return _c
 }
 
-func (_c *UserReader_ReadUser_Call) RunAndReturn(run func(context.Context, uint64) (models.User, error)) *UserReader_ReadUser_Call {
+func (_c *UserReader_ReadUser_Call) RunAndReturn(run func(context.Context, uint64, bool) (models.User, error)) *UserReader_ReadUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -23,29 +23,27 @@ func (_m *UsersLister) EXPECT() *UsersLister_Expecter {
 	return &UsersLister_Expecter{mock: &_m.Mock}
 }
 
-// ListUsers provides a mock function with given fields: ctx, name
-func (_m *UsersLister) ListUsers(ctx context.Context, name string) ([]models.User, error) {
-	ret := _m.Called(ctx, name)
+// ListUsers provides a mock function with given fields: ctx, name, page, includeDeleted
+func (_m *UsersLister) ListUsers(ctx context.Context, name string, page models.PageRequest, includeDeleted bool) (models.Page[models.User], error) {
+	ret := _m.Called(ctx, name, page, includeDeleted)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListUsers")
 	}
 
-	var r0 []models.User
+	var r0 models.Page[models.User]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]models.User, error)); ok {
-		return rf(ctx, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.PageRequest, bool) (models.Page[models.User], error)); ok {
+		return rf(ctx, name, page, includeDeleted)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []models.User); ok {
-		r0 = rf(ctx, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.PageRequest, bool) models.Page[models.User]); ok {
+		r0 = rf(ctx, name, page, includeDeleted)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.User)
-		}
+		r0 = ret.Get(0).(models.Page[models.User])
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.PageRequest, bool) error); ok {
+		r1 = rf(ctx, name, page, includeDeleted)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,23 +59,25 @@ type UsersLister_ListUsers_Call struct {
 // ListUsers is a helper method to define mock.On call
 //   - ctx context.Context
 //   - name string
-func (_e *UsersLister_Expecter) ListUsers(ctx interface{}, name interface{}) *UsersLister_ListUsers_Call {
-	return &UsersLister_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx, name)}
+//   - page models.PageRequest
+//   - includeDeleted bool
+func (_e *UsersLister_Expecter) ListUsers(ctx interface{}, name interface{}, page interface{}, includeDeleted interface{}) *UsersLister_ListUsers_Call {
+	return &UsersLister_ListUsers_Call{Call: _e.mock.On("ListUsers", ctx, name, page, includeDeleted)}
 }
 
-func (_c *UsersLister_ListUsers_Call) Run(run func(ctx context.Context, name string)) *UsersLister_ListUsers_Call {
+func (_c *UsersLister_ListUsers_Call) Run(run func(ctx context.Context, name string, page models.PageRequest, includeDeleted bool)) *UsersLister_ListUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(models.PageRequest), args[3].(bool))
 	})
 	return _c
 }
 
-func (_c *UsersLister_ListUsers_Call) Return(_a0 []models.User, _a1 error) *UsersLister_ListUsers_Call {
+func (_c *UsersLister_ListUsers_Call) Return(_a0 models.Page[models.User], _a1 error) *UsersLister_ListUsers_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UsersLister_ListUsers_Call) RunAndReturn(run func(context.Context, string) ([]models.User, error)) *UsersLister_ListUsers_Call {
+func (_c *UsersLister_ListUsers_Call) RunAndReturn(run func(context.Context, string, models.PageRequest, bool) (models.Page[models.User], error)) *UsersLister_ListUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
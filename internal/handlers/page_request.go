@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+// parsePageRequest reads the limit, offset, cursor and include_total query
+// parameters of a list request. Problems are keyed by parameter name.
+func parsePageRequest(r *http.Request) (models.PageRequest, map[string]string) {
+	query := r.URL.Query()
+	problems := make(map[string]string)
+
+	page := models.PageRequest{
+		Cursor: query.Get("cursor"),
+	}
+
+	if s := query.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > services.MaxPageLimit {
+			problems["limit"] = fmt.Sprintf("Limit must be a number between 1 and %d", services.MaxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	if s := query.Get("offset"); s != "" {
+		offset, err := strconv.Atoi(s)
+		if err != nil || offset < 0 {
+			problems["offset"] = "Offset must be a number of at least 0"
+		}
+		if page.Cursor != "" {
+			problems["offset"] = "Offset cannot be combined with a cursor"
+		}
+		page.Offset = offset
+	}
+
+	if s := query.Get("include_total"); s != "" {
+		includeTotal, err := strconv.ParseBool(s)
+		if err != nil {
+			problems["include_total"] = "Include total must be true or false"
+		}
+		page.IncludeTotal = includeTotal
+	}
+
+	return page, problems
+}
+
+// parseSort parses a sort query parameter: a comma separated list of field
+// names, each prefixed with - for descending order, such as
+// "-score,created_date". ok is false if a field name is empty.
+func parseSort(s string) (fields []models.SortField, ok bool) {
+	if s == "" {
+		return nil, true
+	}
+
+	for _, name := range strings.Split(s, ",") {
+		field := models.SortField{Field: strings.TrimSpace(name)}
+		if rest, desc := strings.CutPrefix(field.Field, "-"); desc {
+			field = models.SortField{Field: rest, Desc: true}
+		}
+		if field.Field == "" {
+			return nil, false
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, true
+}
+
+// parseExpand parses an expand query parameter: a comma separated list of the
+// related records to embed in a response, such as "user,blog". ok is false if
+// a name is not one of allowed.
+func parseExpand(s string, allowed ...string) (expand map[string]bool, ok bool) {
+	expand = make(map[string]bool)
+	if s == "" {
+		return expand, true
+	}
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(allowed, name) {
+			return nil, false
+		}
+		expand[name] = true
+	}
+
+	return expand, true
+}
+
+// parseTimeParam parses a time query parameter given either as an RFC 3339
+// timestamp or as a date, which is taken as midnight UTC.
+func parseTimeParam(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+// PageResponse holds the links to the next and previous pages of a list, and
+// the number of items in the whole list when it was requested.
+type PageResponse struct {
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Total *int   `json:"total,omitempty"`
+}
+
+// newPageResponse builds the links to the pages around page, keeping the
+// other query parameters of r. Lists requested by offset are linked by
+// offset, and all others by cursor.
+func newPageResponse[T any](r *http.Request, request models.PageRequest, page models.Page[T]) PageResponse {
+	response := PageResponse{Total: page.Total}
+
+	if !r.URL.Query().Has("offset") {
+		if page.NextCursor != "" {
+			response.Next = pageLink(r, "cursor", page.NextCursor)
+		}
+		if page.PrevCursor != "" {
+			response.Prev = pageLink(r, "cursor", page.PrevCursor)
+		}
+		return response
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = services.DefaultPageLimit
+	}
+
+	if page.NextCursor != "" {
+		response.Next = pageLink(r, "offset", strconv.Itoa(request.Offset+limit))
+	}
+	if request.Offset > 0 {
+		response.Prev = pageLink(r, "offset", strconv.Itoa(max(request.Offset-limit, 0)))
+	}
+
+	return response
+}
+
+// pageLink returns the path and query of r with the query parameter key set
+// to value.
+func pageLink(r *http.Request, key, value string) string {
+	query := r.URL.Query()
+	query.Set(key, value)
+
+	return r.URL.Path + "?" + query.Encode()
+}
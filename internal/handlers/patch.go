@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/chickey/blog/internal/patch"
+)
+
+// acceptPatch lists the patch media types the PATCH endpoints understand, as
+// advertised in the Accept-Patch header.
+var acceptPatch = strings.Join([]string{patch.MergePatch, patch.JSONPatch}, ", ")
+
+// applyPatch applies the patch document in the body of r to current, the
+// request model of the record being patched, and returns the patched request
+// model. The patched model is validated, but only the problems with the
+// members the patch touched are reported, so that a patch isn't rejected for
+// what it left alone. If the patch can't be applied it responds with a
+// problem and returns false.
+func applyPatch[T validator](w http.ResponseWriter, r *http.Request, current T) (patched T, ok bool) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != patch.MergePatch && mediaType != patch.JSONPatch) {
+		w.Header().Set("Accept-Patch", acceptPatch)
+		writeProblem(w, r, http.StatusUnsupportedMediaType, "Unsupported patch media type", map[string]string{
+			"Content-Type": "Content-Type must be " + patch.MergePatch + " or " + patch.JSONPatch,
+		})
+		return patched, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, "Request body could not be read", nil)
+		return patched, false
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, "Internal Server Error", nil)
+		return patched, false
+	}
+
+	out, touched, err := patch.Apply(mediaType, doc, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, patch.ErrMalformed):
+			writeProblem(w, r, http.StatusBadRequest, "Patch document could not be decoded", map[string]string{
+				"patch": err.Error(),
+			})
+		case errors.Is(err, patch.ErrTestFailed):
+			writeProblem(w, r, http.StatusConflict, "Patch test failed", map[string]string{
+				"patch": err.Error(),
+			})
+		case errors.Is(err, patch.ErrInapplicable):
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Patch cannot be applied", map[string]string{
+				"patch": err.Error(),
+			})
+		default:
+			writeProblem(w, r, http.StatusInternalServerError, "Internal Server Error", nil)
+		}
+		return patched, false
+	}
+
+	// The patched document has to still be a valid request, without members
+	// the request model doesn't have
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&patched); err != nil {
+		writeProblem(w, r, http.StatusUnprocessableEntity, "Patch cannot be applied", map[string]string{
+			"patch": fmt.Sprintf("patched document is not a valid request: %s", err),
+		})
+		return patched, false
+	}
+
+	problems := make(map[string]string)
+	for field, problem := range patched.Valid(r.Context()) {
+		for _, member := range touched {
+			if strings.EqualFold(field, member) {
+				problems[field] = problem
+			}
+		}
+	}
+	if len(problems) > 0 {
+		writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+		return patched, false
+	}
+
+	return patched, true
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// blogPatcher represents a type capable of reading a blog and applying a
+// partial update to it.
+type blogPatcher interface {
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
+	PatchBlog(ctx context.Context, id uint64, patch models.BlogPatch, editorId uint) (models.Blog, error)
+}
+
+// @Summary		Patch Blog
+// @Description	Patch Blog by ID with a JSON Merge Patch or a JSON Patch of a BlogRequest
+// @Tags			blog
+// @Accept			application/merge-patch+json
+// @Accept			application/json-patch+json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id			path		string	true	"Blog ID"
+// @Param			request		body		object	true	"Patch document"
+// @Param			If-Match	header		string	false	"ETag of the version of the blog the patch is made against"
+// @Success		200			{object}	BlogResponse
+// @Header			200			{string}	ETag	"Version of the patched blog"
+// @Failure		400			{object}	ProblemResponse
+// @Failure		401			{object}	ProblemResponse
+// @Failure		403			{object}	ProblemResponse
+// @Failure		404			{object}	ProblemResponse
+// @Failure		409			{object}	ProblemResponse
+// @Failure		412			{object}	ProblemResponse
+// @Failure		415			{object}	ProblemResponse
+// @Failure		422			{object}	ProblemResponse
+// @Failure		500			{object}	ProblemResponse
+// @Router			/blog/{id}  [PATCH]
+func HandlePatchBlog(logger *slog.Logger, blogPatcher blogPatcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		version, ok := parseIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		existing, err := blogPatcher.ReadBlog(ctx, uint64(id), models.BlogExpand{}, false)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+		if !authz.CanBlog(actor, authz.Update, existing) {
+			writeForbidden(w, r)
+			return
+		}
+
+		// The patch is applied to the blog as read here, so it may only be
+		// written if nobody has changed the blog since
+		if version == 0 {
+			version = existing.Version
+		}
+
+		request, ok := applyPatch(w, r, &BlogRequest{
+			AuthorID:  existing.AuthorID,
+			Title:     existing.Title,
+			Body:      existing.Body,
+			Excerpt:   existing.Excerpt,
+			Category:  existing.Category,
+			Tags:      tagList(existing.Tags),
+			Status:    string(existing.Status),
+			PublishAt: existing.PublishAt,
+		})
+		if !ok {
+			return
+		}
+
+		// Only an admin may hand a blog over to someone else
+		if !authz.CanBlog(actor, authz.Update, models.Blog{AuthorID: request.AuthorID}) {
+			writeForbidden(w, r)
+			return
+		}
+
+		modelRequest := models.BlogPatch{Version: version}
+		if request.AuthorID != existing.AuthorID {
+			modelRequest.AuthorID = &request.AuthorID
+		}
+		if request.Title != existing.Title {
+			modelRequest.Title = &request.Title
+		}
+		if request.Body != existing.Body {
+			modelRequest.Body = &request.Body
+		}
+		if request.Excerpt != existing.Excerpt {
+			modelRequest.Excerpt = &request.Excerpt
+		}
+		if request.Category != existing.Category {
+			modelRequest.Category = &request.Category
+		}
+		if !slices.Equal(request.Tags, existing.Tags) {
+			modelRequest.Tags = &request.Tags
+		}
+		if status := models.BlogStatus(request.Status); status != existing.Status {
+			modelRequest.Status = &status
+		}
+		if !request.PublishAt.Equal(existing.PublishAt) {
+			modelRequest.PublishAt = &request.PublishAt
+		}
+
+		// Patch the blog
+		blog, err := blogPatcher.PatchBlog(ctx, uint64(id), modelRequest, actor.ID)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to patch blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		setETag(w, blog.Version)
+
+		// Convert our models.Blog domain model into a response model.
+		response := BlogResponse{
+			ID:          blog.ID,
+			AuthorID:    blog.AuthorID,
+			Title:       blog.Title,
+			Body:        blog.Body,
+			Format:      formatMarkdown,
+			Excerpt:     blog.Excerpt,
+			Category:    blog.Category,
+			Tags:        tagList(blog.Tags),
+			Status:      string(blog.Status),
+			PublishAt:   optionalTime(blog.PublishAt),
+			Score:       blog.Score,
+			CreatedDate: blog.CreatedDate,
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
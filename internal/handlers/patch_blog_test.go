@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestHandlePatchBlog(t *testing.T) {
+	title := "New Title"
+	tags := []string{"go", "sql"}
+	published := models.BlogStatusPublished
+	jane := uint(2)
+
+	existing := models.Blog{
+		ID:       1,
+		AuthorID: 1,
+		Title:    "Book Title",
+		Body:     "Body text",
+		Tags:     []string{"go"},
+		Status:   models.BlogStatusDraft,
+		Version:  3,
+	}
+
+	tests := map[string]struct {
+		actor      models.User
+		mediaType  string
+		body       string
+		mockCalled bool
+		mockInput  models.BlogPatch
+		wantStatus int
+	}{
+		"merge patch": {
+			actor:      testUser,
+			body:       `{"title":"New Title","status":"published"}`,
+			mockCalled: true,
+			mockInput:  models.BlogPatch{Title: &title, Status: &published, Version: 3},
+			wantStatus: 200,
+		},
+		"add a tag": {
+			actor:      testUser,
+			body:       `{"tags":["go","sql"]}`,
+			mockCalled: true,
+			mockInput:  models.BlogPatch{Tags: &tags, Version: 3},
+			wantStatus: 200,
+		},
+		"json patch": {
+			actor:      testUser,
+			mediaType:  "application/json-patch+json",
+			body:       `[{"op":"add","path":"/tags/-","value":"sql"}]`,
+			mockCalled: true,
+			mockInput:  models.BlogPatch{Tags: &tags, Version: 3},
+			wantStatus: 200,
+		},
+		"invalid status": {
+			actor:      testUser,
+			body:       `{"status":"hidden"}`,
+			wantStatus: 422,
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			body:       `{"title":"New Title"}`,
+			wantStatus: 403,
+		},
+		"hand over to someone else": {
+			actor:      testUser,
+			body:       `{"authorid":2}`,
+			wantStatus: 403,
+		},
+		"admin hands over": {
+			actor:      testAdmin,
+			body:       `{"authorid":2}`,
+			mockCalled: true,
+			mockInput:  models.BlogPatch{AuthorID: &jane, Version: 3},
+			wantStatus: 200,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create a new request
+			req := httptest.NewRequest("PATCH", "/blogs", bytes.NewBufferString(tc.body))
+			req.SetPathValue("id", "1")
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			if tc.mediaType != "" {
+				req.Header.Set("Content-Type", tc.mediaType)
+			}
+			req = withActor(req, tc.actor)
+
+			// Create a new response recorder
+			rec := httptest.NewRecorder()
+
+			// Create a new logger
+			logger := slog.Default()
+
+			blogPatcher := new(mock.BlogPatcher)
+			blogPatcher.On("ReadBlog", req.Context(), uint64(1), models.BlogExpand{}, false).Return(existing, nil)
+			blogPatcher.On("PatchBlog", req.Context(), uint64(1), tc.mockInput, tc.actor.ID).
+				Return(models.Blog{ID: 1, AuthorID: 1, Title: "New Title", Version: 4}, nil)
+
+			// Call the handler
+			handler := HandlePatchBlog(logger, blogPatcher)
+
+			handler.ServeHTTP(rec, req)
+			// Check the status code
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body)
+			}
+			if rec.Code == 200 && rec.Header().Get("ETag") != etag(4) {
+				t.Errorf("want ETag %s, got %s", etag(4), rec.Header().Get("ETag"))
+			}
+			if !tc.mockCalled {
+				blogPatcher.AssertNumberOfCalls(t, "PatchBlog", 0)
+			}
+		})
+	}
+}
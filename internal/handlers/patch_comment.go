@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+// commentPatcher represents a type capable of reading a comment and updating
+// it.
+type commentPatcher interface {
+	ReadComment(ctx context.Context, id uint, userId uint, blogId uint) (models.Comment, error)
+	UpdateComment(ctx context.Context, patch models.Comment, editorId uint) (models.Comment, error)
+}
+
+// @Summary		Patch Comment
+// @Description	Patch Comment with a JSON Merge Patch or a JSON Patch of a CommentRequest. Only the message can be changed.
+// @Tags			comment
+// @Accept			application/merge-patch+json
+// @Accept			application/json-patch+json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			author_id	query		string	true	"Author Id"
+// @Param			blog_id		query		string	true	"Blog Id"
+// @Param			id			query		string	false	"Comment Id, when the author has several comments on the blog"
+// @Param			request		body		object	true	"Patch document"
+// @Param			If-Match	header		string	false	"ETag of the version of the comment the patch is made against"
+// @Success		200			{object}	CommentResponse
+// @Header			200			{string}	ETag	"Version of the patched comment"
+// @Failure		400			{object}	ProblemResponse
+// @Failure		401			{object}	ProblemResponse
+// @Failure		403			{object}	ProblemResponse
+// @Failure		404			{object}	ProblemResponse
+// @Failure		409			{object}	ProblemResponse
+// @Failure		412			{object}	ProblemResponse
+// @Failure		415			{object}	ProblemResponse
+// @Failure		422			{object}	ProblemResponse
+// @Failure		500			{object}	ProblemResponse
+// @Router			/comment  [PATCH]
+func HandlePatchComment(logger *slog.Logger, commentPatcher commentPatcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Validate query params
+		var ids [3]int
+		for i, param := range []struct{ name, detail string }{
+			{"author_id", "Invalid User ID"},
+			{"blog_id", "Invalid Blog ID"},
+			{"id", "Invalid Comment ID"},
+		} {
+			s := r.URL.Query().Get(param.name)
+			if s == "" {
+				continue
+			}
+
+			id, err := strconv.Atoi(s)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to get valid id from query param",
+					slog.String(param.name, s),
+					slog.String("error", err.Error()),
+				)
+
+				writeProblem(w, r, http.StatusBadRequest, param.detail, nil)
+				return
+			}
+			ids[i] = id
+		}
+		userId, blogId, commentId := ids[0], ids[1], ids[2]
+
+		version, ok := parseIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		current, err := commentPatcher.ReadComment(ctx, uint(commentId), uint(userId), uint(blogId))
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read comment",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Only the author or an admin may update a comment
+		if !authz.CanComment(actor, authz.Update, current) {
+			writeForbidden(w, r)
+			return
+		}
+
+		// The patch is applied to the comment as read here, so it may only be
+		// written if nobody has changed the comment since
+		if version == 0 {
+			version = current.Version
+		}
+
+		request, ok := applyPatch(w, r, &CommentRequest{
+			UserID:   current.UserID,
+			BlogID:   current.BlogID,
+			ParentID: current.ParentID,
+			Message:  current.Message,
+		})
+		if !ok {
+			return
+		}
+
+		// A comment stays where it was written
+		problems := make(map[string]string)
+		if request.UserID != current.UserID {
+			problems["UserID"] = "UserID cannot be changed"
+		}
+		if request.BlogID != current.BlogID {
+			problems["BlogID"] = "BlogID cannot be changed"
+		}
+		if request.ParentID != current.ParentID {
+			problems["ParentID"] = "ParentID cannot be changed"
+		}
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
+		}
+
+		comment := current
+		if request.Message != current.Message {
+			comment, err = commentPatcher.UpdateComment(ctx, models.Comment{
+				ID:      current.ID,
+				UserID:  current.UserID,
+				BlogID:  current.BlogID,
+				Message: request.Message,
+				Version: version,
+			}, actor.ID)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to patch comment",
+					slog.String("error", err.Error()),
+				)
+
+				writeServiceError(w, r, err)
+				return
+			}
+		} else if version != current.Version {
+			writeServiceError(w, r, services.ErrPreconditionFailed)
+			return
+		}
+
+		setETag(w, comment.Version)
+
+		// Convert our models.Comment domain model into a response model.
+		response := CommentResponse{
+			ID:          comment.ID,
+			ParentID:    comment.ParentID,
+			BlogID:      comment.BlogID,
+			UserID:      comment.UserID,
+			Message:     comment.Message,
+			CreatedDate: comment.CreatedDate,
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
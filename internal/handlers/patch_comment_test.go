@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestHandlePatchComment(t *testing.T) {
+	current := models.Comment{
+		ID:          4,
+		ParentID:    3,
+		UserID:      1,
+		BlogID:      2,
+		Message:     "Good blog",
+		CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+		Version:     5,
+	}
+
+	tests := map[string]struct {
+		actor      models.User
+		ifMatch    string
+		body       string
+		mockCalled bool
+		wantStatus int
+		wantETag   string
+	}{
+		"new message": {
+			actor:      testUser,
+			body:       `{"Message":"Great blog"}`,
+			mockCalled: true,
+			wantStatus: 200,
+			wantETag:   `"6"`,
+		},
+		"nothing changed": {
+			actor:      testUser,
+			body:       `{"Message":"Good blog"}`,
+			wantStatus: 200,
+			wantETag:   `"5"`,
+		},
+		"nothing changed at a stale version": {
+			actor:      testUser,
+			ifMatch:    `"4"`,
+			body:       `{}`,
+			wantStatus: 412,
+		},
+		"move to another blog": {
+			actor:      testUser,
+			body:       `{"BlogID":3}`,
+			wantStatus: 422,
+		},
+		"empty message": {
+			actor:      testUser,
+			body:       `{"Message":""}`,
+			wantStatus: 422,
+		},
+		"someone else's": {
+			actor:      testOtherUser,
+			body:       `{"Message":"Great blog"}`,
+			wantStatus: 403,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create a new request
+			req := httptest.NewRequest("PATCH", "/comment?author_id=1&blog_id=2&id=4", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/merge-patch+json")
+			req.Header.Set("If-Match", tc.ifMatch)
+			req = withActor(req, tc.actor)
+
+			// Create a new response recorder
+			rec := httptest.NewRecorder()
+
+			// Create a new logger
+			logger := slog.Default()
+
+			updated := current
+			updated.Message = "Great blog"
+			updated.Version = 6
+
+			commentPatcher := new(mock.CommentPatcher)
+			commentPatcher.On("ReadComment", req.Context(), uint(4), uint(1), uint(2)).Return(current, nil)
+			commentPatcher.On("UpdateComment", req.Context(), models.Comment{
+				ID:      4,
+				UserID:  1,
+				BlogID:  2,
+				Message: "Great blog",
+				Version: 5,
+			}, tc.actor.ID).Return(updated, nil)
+
+			// Call the handler
+			handler := HandlePatchComment(logger, commentPatcher)
+
+			handler.ServeHTTP(rec, req)
+			// Check the status code
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body)
+			}
+			if tc.wantETag != "" && rec.Header().Get("ETag") != tc.wantETag {
+				t.Errorf("want ETag %s, got %s", tc.wantETag, rec.Header().Get("ETag"))
+			}
+			if !tc.mockCalled {
+				commentPatcher.AssertNumberOfCalls(t, "UpdateComment", 0)
+			}
+		})
+	}
+}
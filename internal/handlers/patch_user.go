@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// userPatcher represents a type capable of reading a user and applying a
+// partial update to it.
+type userPatcher interface {
+	ReadUser(ctx context.Context, id uint64, includeDeleted bool) (models.User, error)
+	PatchUser(ctx context.Context, id uint64, patch models.UserPatch) (models.User, error)
+}
+
+// @Summary		Patch User
+// @Description	Patch User by ID with a JSON Merge Patch or a JSON Patch of a UserRequest. The password reads as empty.
+// @Tags			user
+// @Accept			application/merge-patch+json
+// @Accept			application/json-patch+json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id			path		string	true	"User ID"
+// @Param			request		body		object	true	"Patch document"
+// @Param			If-Match	header		string	false	"ETag of the version of the user the patch is made against"
+// @Success		200			{object}	UserResponse
+// @Header			200			{string}	ETag	"Version of the patched user"
+// @Failure		400			{object}	ProblemResponse
+// @Failure		401			{object}	ProblemResponse
+// @Failure		403			{object}	ProblemResponse
+// @Failure		404			{object}	ProblemResponse
+// @Failure		409			{object}	ProblemResponse
+// @Failure		412			{object}	ProblemResponse
+// @Failure		415			{object}	ProblemResponse
+// @Failure		422			{object}	ProblemResponse
+// @Failure		500			{object}	ProblemResponse
+// @Router			/user/{id}  [PATCH]
+func HandlePatchUser(logger *slog.Logger, userPatcher userPatcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// Users may only update their own account unless they are an admin
+		if !authz.CanUser(actor, authz.Update, models.User{ID: uint(id)}) {
+			writeForbidden(w, r)
+			return
+		}
+
+		version, ok := parseIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		current, err := userPatcher.ReadUser(ctx, uint64(id), false)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read user",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// The patch is applied to the user as read here, so it may only be
+		// written if nobody has changed the user since
+		if version == 0 {
+			version = current.Version
+		}
+
+		// The stored password is a hash, so it is patched from empty
+		request, ok := applyPatch(w, r, &UserRequest{
+			Name:  current.Name,
+			Email: current.Email,
+		})
+		if !ok {
+			return
+		}
+
+		modelRequest := models.UserPatch{Version: version}
+		if request.Name != current.Name {
+			modelRequest.Name = &request.Name
+		}
+		if request.Email != current.Email {
+			modelRequest.Email = &request.Email
+		}
+		if request.Password != "" {
+			modelRequest.Password = &request.Password
+		}
+
+		// Patch the user
+		user, err := userPatcher.PatchUser(ctx, uint64(id), modelRequest)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to patch user",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		setETag(w, user.Version)
+
+		// Convert our models.User domain model into a response model.
+		response := UserResponse{
+			ID:    user.ID,
+			Name:  user.Name,
+			Email: user.Email,
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
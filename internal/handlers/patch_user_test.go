@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandlePatchUser(t *testing.T) {
+	name := "johnny"
+	password := "password456!"
+
+	tests := map[string]struct {
+		actor       models.User
+		contentType string
+		ifMatch     string
+		body        string
+		mockCalled  bool
+		mockInput   models.UserPatch
+		mockError   error
+		wantStatus  int
+		wantHeader  string
+	}{
+		"merge patch": {
+			actor:       testUser,
+			contentType: "application/merge-patch+json",
+			body:        `{"name":"johnny"}`,
+			mockCalled:  true,
+			mockInput:   models.UserPatch{Name: &name, Version: 3},
+			wantStatus:  200,
+		},
+		"json patch": {
+			actor:       testUser,
+			contentType: "application/json-patch+json; charset=utf-8",
+			ifMatch:     `"2"`,
+			body:        `[{"op":"replace","path":"/password","value":"password456!"}]`,
+			mockCalled:  true,
+			mockInput:   models.UserPatch{Password: &password, Version: 2},
+			wantStatus:  200,
+		},
+		"stale version": {
+			actor:       testUser,
+			contentType: "application/merge-patch+json",
+			ifMatch:     `"2"`,
+			body:        `{"name":"johnny"}`,
+			mockCalled:  true,
+			mockInput:   models.UserPatch{Name: &name, Version: 2},
+			mockError:   fmt.Errorf("user 1: %w", services.ErrPreconditionFailed),
+			wantStatus:  412,
+		},
+		"only touched fields are validated": {
+			actor:       testUser,
+			contentType: "application/merge-patch+json",
+			body:        `{"email":"not an email"}`,
+			wantStatus:  422,
+		},
+		"unknown member": {
+			actor:       testUser,
+			contentType: "application/merge-patch+json",
+			body:        `{"role":"admin"}`,
+			wantStatus:  422,
+		},
+		"failed test": {
+			actor:       testUser,
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"test","path":"/name","value":"jane"}]`,
+			wantStatus:  409,
+		},
+		"malformed patch": {
+			actor:       testUser,
+			contentType: "application/json-patch+json",
+			body:        `{"name":"johnny"}`,
+			wantStatus:  400,
+		},
+		"unsupported media type": {
+			actor:       testUser,
+			contentType: "application/json",
+			body:        `{"name":"johnny"}`,
+			wantStatus:  415,
+			wantHeader:  acceptPatch,
+		},
+		"someone else": {
+			actor:       testOtherUser,
+			contentType: "application/merge-patch+json",
+			body:        `{"name":"johnny"}`,
+			wantStatus:  403,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create a new request
+			req := httptest.NewRequest("PATCH", "/users", bytes.NewBufferString(tc.body))
+			req.SetPathValue("id", "1")
+			req.Header.Set("Content-Type", tc.contentType)
+			req.Header.Set("If-Match", tc.ifMatch)
+			req = withActor(req, tc.actor)
+
+			// Create a new response recorder
+			rec := httptest.NewRecorder()
+
+			// Create a new logger
+			logger := slog.Default()
+
+			userPatcher := new(mock.UserPatcher)
+			userPatcher.On("ReadUser", req.Context(), uint64(1), false).
+				Return(models.User{ID: 1, Name: "john", Email: "john@mail.com", Version: 3}, nil)
+			userPatcher.On("PatchUser", req.Context(), uint64(1), tc.mockInput).
+				Return(models.User{ID: 1, Name: "johnny", Email: "john@mail.com", Version: 4}, tc.mockError)
+
+			// Call the handler
+			handler := HandlePatchUser(logger, userPatcher)
+
+			handler.ServeHTTP(rec, req)
+			// Check the status code
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body)
+			}
+			if rec.Code == 200 && rec.Header().Get("ETag") != etag(4) {
+				t.Errorf("want ETag %s, got %s", etag(4), rec.Header().Get("ETag"))
+			}
+			if got := rec.Header().Get("Accept-Patch"); got != tc.wantHeader {
+				t.Errorf("want Accept-Patch %q, got %q", tc.wantHeader, got)
+			}
+			if !tc.mockCalled {
+				userPatcher.AssertNumberOfCalls(t, "PatchUser", 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/chickey/blog/internal/middleware"
+)
+
+// problemContentType is the media type for RFC 7807 problem details.
+const problemContentType = "application/problem+json"
+
+// writeProblem responds to the client with an RFC 7807 problem details body.
+// problems holds field level validation failures, keyed by field name, and
+// may be nil.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string, problems map[string]string) {
+	response := newProblemResponse(r, status, detail, problems)
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// newProblemResponse builds the problem details describing a failure of r.
+func newProblemResponse(r *http.Request, status int, detail string, problems map[string]string) ProblemResponse {
+	return ProblemResponse{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.RequestURI(),
+		RequestID: middleware.RequestIDFromContext(r.Context()),
+		Errors:    problems,
+	}
+}
@@ -0,0 +1,12 @@
+package handlers
+
+// ProblemResponse represents an RFC 7807 problem details error response.
+type ProblemResponse struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance"`
+	RequestID string            `json:"request_id,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteProblem(t *testing.T) {
+	tests := map[string]struct {
+		status   int
+		detail   string
+		problems map[string]string
+		want     ProblemResponse
+	}{
+		"validation failure": {
+			status:   http.StatusUnprocessableEntity,
+			detail:   "Request failed validation",
+			problems: map[string]string{"Title": "Title cannot be empty"},
+			want: ProblemResponse{
+				Type:     "about:blank",
+				Title:    "Unprocessable Entity",
+				Status:   http.StatusUnprocessableEntity,
+				Detail:   "Request failed validation",
+				Instance: "/api/blog?title=x",
+				Errors:   map[string]string{"Title": "Title cannot be empty"},
+			},
+		},
+		"no field errors": {
+			status: http.StatusNotFound,
+			detail: "The requested resource does not exist.",
+			want: ProblemResponse{
+				Type:     "about:blank",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "The requested resource does not exist.",
+				Instance: "/api/blog?title=x",
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/blog?title=x", nil)
+			rec := httptest.NewRecorder()
+
+			writeProblem(rec, req, tc.status, tc.detail, tc.problems)
+
+			if rec.Code != tc.status {
+				t.Errorf("want status %d, got %d", tc.status, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != problemContentType {
+				t.Errorf("want content type %q, got %q", problemContentType, got)
+			}
+
+			var got ProblemResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode body: %s", err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -7,13 +7,17 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/markdown"
+	"github.com/chickey/blog/internal/middleware"
 	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
 )
 
 // blogReader represents a type capable of reading a blog from storage and
 // returning it or an error.
 type blogReader interface {
-	ReadBlog(ctx context.Context, id uint64) (models.Blog, error)
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
 }
 
 // @Summary		Read Blog
@@ -21,11 +25,19 @@ type blogReader interface {
 // @Tags			blog
 // @Accept			json
 // @Produce		json
-// @Param			id	path		string	true	"Blog Id"
-// @Success		200	{object}	models.Blog
-// @Failure		400	{object}	string
-// @Failure		404	{object}	string
-// @Failure		500	{object}	string
+// @Param			id		path		string	true	"Blog Id"
+// @Param			format	query		string	false	"Body format, markdown (default) or sanitized html"	Enums(markdown, html)
+// @Param			expand	query		string	false	"Related records to embed"	Enums(author)
+// @Param			include_deleted	query	bool	false	"Read the blog even if it is deleted, for admins only"
+// @Param			If-None-Match	header	string	false	"ETag of the version of the blog the client already holds"
+// @Success		200		{object}	BlogResponse
+// @Header			200		{string}	ETag	"Version of the blog"
+// @Success		304		"The blog is still at the version named by If-None-Match"
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		403		{object}	ProblemResponse
+// @Failure		404		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
 // @Router			/blog/{id}  [GET]
 func HandleReadBlog(logger *slog.Logger, blogReader blogReader) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -44,12 +56,37 @@ func HandleReadBlog(logger *slog.Logger, blogReader blogReader) http.Handler {
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		switch format {
+		case "":
+			format = formatMarkdown
+		case formatMarkdown, formatHTML:
+		default:
+			writeProblem(w, r, http.StatusBadRequest, "Invalid format", map[string]string{
+				"format": "Format must be markdown or html",
+			})
+			return
+		}
+
+		expand, ok := parseExpand(r.URL.Query().Get("expand"), "author")
+		if !ok {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid expand", map[string]string{
+				"expand": "Expand must be author",
+			})
+			return
+		}
+
+		includeDeleted, ok := parseIncludeDeleted(w, r)
+		if !ok {
 			return
 		}
 
 		// Read the blog
-		blog, err := blogReader.ReadBlog(ctx, uint64(id))
+		blog, err := blogReader.ReadBlog(ctx, uint64(id), models.BlogExpand{Author: expand["author"]}, includeDeleted)
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -57,17 +94,57 @@ func HandleReadBlog(logger *slog.Logger, blogReader blogReader) http.Handler {
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
+		// Blogs that aren't published are only visible to their author, and
+		// don't exist as far as anyone else can tell
+		actor, _ := middleware.UserFromContext(ctx)
+		if !authz.CanBlog(actor, authz.Read, blog) {
+			writeServiceError(w, r, services.ErrNotFound)
+			return
+		}
+
+		// Save sending the blog again to a client that already holds it
+		setETag(w, blog.Version)
+		if notModified(r, blog.Version) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		// Render the Markdown body if HTML was asked for
+		body := blog.Body
+		if format == formatHTML {
+			body, err = markdown.ToHTML(blog.Body)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to render blog body",
+					slog.String("error", err.Error()),
+				)
+
+				writeServiceError(w, r, err)
+				return
+			}
+		}
+
 		// Convert our models.Blog domain model into a response model.
 		response := BlogResponse{
 			ID:          blog.ID,
 			AuthorID:    blog.AuthorID,
+			Author:      newUserRefResponse(blog.Author),
 			Title:       blog.Title,
+			Body:        body,
+			Format:      format,
+			Excerpt:     blog.Excerpt,
+			Category:    blog.Category,
+			Tags:        tagList(blog.Tags),
+			Status:      string(blog.Status),
+			PublishAt:   optionalTime(blog.PublishAt),
 			Score:       blog.Score,
 			CreatedDate: blog.CreatedDate,
+			DeletedAt:   optionalTime(blog.DeletedAt),
 		}
 
 		// Encode the response model as JSON
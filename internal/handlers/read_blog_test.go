@@ -1,7 +1,8 @@
 package handlers
 
 import (
-	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -10,13 +11,21 @@ import (
 
 	"github.com/chickey/blog/internal/handlers/mock"
 	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
 )
 
 func TestHandleReadBlog(t *testing.T) {
 	tests := map[string]struct {
+		actor       models.User
+		format      string
+		expand      string
+		ifNoneMatch string
 		wantStatus  int
+		wantETag    string
 		wantBody    string
+		wantAuthor  *UserRefResponse
 		wantResults models.Blog
+		wantErr     error
 	}{
 		"happy path": {
 			wantStatus: 200,
@@ -24,16 +33,119 @@ func TestHandleReadBlog(t *testing.T) {
 				ID:          1,
 				AuthorID:    1,
 				Title:       "Book Title",
+				Status:      models.BlogStatusPublished,
 				Score:       8.2,
 				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
 			},
 		},
+		"html format": {
+			format:     "html",
+			wantStatus: 200,
+			wantBody:   "<h1>Chapter</h1>\n<p>Textalert(1)</p>\n",
+			wantResults: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Status:      models.BlogStatusPublished,
+				Body:        "# Chapter\n\nText<script>alert(1)</script>",
+				Score:       8.2,
+				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+			},
+		},
+		"markdown format": {
+			format:     "markdown",
+			wantStatus: 200,
+			wantBody:   "# Chapter",
+			wantResults: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Status:      models.BlogStatusPublished,
+				Body:        "# Chapter",
+				Score:       8.2,
+				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+			},
+		},
+		"invalid format": {
+			format:     "pdf",
+			wantStatus: 400,
+		},
+		"expanded author": {
+			expand:     "author",
+			wantStatus: 200,
+			wantAuthor: &UserRefResponse{ID: 1, Name: "John"},
+			wantResults: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Author:      models.UserRef{ID: 1, Name: "John"},
+				Title:       "Book Title",
+				Status:      models.BlogStatusPublished,
+				Score:       8.2,
+				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+			},
+		},
+		"invalid expand": {
+			expand:     "comments",
+			wantStatus: 400,
+		},
+		"own draft": {
+			actor:      testUser,
+			wantStatus: 200,
+			wantResults: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Status:      models.BlogStatusDraft,
+				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+			},
+		},
+		"someone else's draft": {
+			actor:      testOtherUser,
+			wantStatus: 404,
+			wantResults: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Status:      models.BlogStatusDraft,
+				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+			},
+		},
+		"not modified": {
+			ifNoneMatch: `"1", "2"`,
+			wantStatus:  304,
+			wantETag:    `"2"`,
+			wantResults: models.Blog{
+				ID:       1,
+				AuthorID: 1,
+				Title:    "Book Title",
+				Status:   models.BlogStatusPublished,
+				Version:  2,
+			},
+		},
+		"modified": {
+			ifNoneMatch: `"1"`,
+			wantStatus:  200,
+			wantETag:    `"2"`,
+			wantResults: models.Blog{
+				ID:       1,
+				AuthorID: 1,
+				Title:    "Book Title",
+				Status:   models.BlogStatusPublished,
+				Version:  2,
+			},
+		},
+		"not found": {
+			wantStatus: 404,
+			wantErr:    fmt.Errorf("blog 1: %w", services.ErrNotFound),
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// Create a new request
-			req := httptest.NewRequest(http.MethodGet, "/blogs/1", nil)
+			req := httptest.NewRequest(http.MethodGet, "/blogs/1?format="+tc.format+"&expand="+tc.expand, nil)
 			req.SetPathValue("id", "1")
+			req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -42,7 +154,8 @@ func TestHandleReadBlog(t *testing.T) {
 			logger := slog.Default()
 
 			userReader := new(mock.BlogReader)
-			userReader.On("ReadBlog", context.Background(), uint64(1)).Return(tc.wantResults, nil)
+			expand := models.BlogExpand{Author: tc.expand == "author"}
+			userReader.On("ReadBlog", req.Context(), uint64(1), expand, false).Return(tc.wantResults, tc.wantErr)
 			// Call the handler
 			handler := HandleReadBlog(logger, userReader)
 
@@ -51,12 +164,31 @@ func TestHandleReadBlog(t *testing.T) {
 			if rec.Code != tc.wantStatus {
 				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
 			}
+			if tc.wantETag != "" && rec.Header().Get("ETag") != tc.wantETag {
+				t.Errorf("want ETag %s, got %s", tc.wantETag, rec.Header().Get("ETag"))
+			}
 
-			// // Check the body
-			// json, _ := json.Marshal(tc.wantResults)
-			// if strings.Trim(rec.Body.String(), "\n") != string(json) {
-			// 	t.Errorf("want body %q, got %q", string(json), rec.Body.String())
-			// }
+			// Check the body
+			if tc.wantBody != "" {
+				var got BlogResponse
+				if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if got.Body != tc.wantBody || got.Format != tc.format {
+					t.Errorf("want %s body %q, got %s body %q", tc.format, tc.wantBody, got.Format, got.Body)
+				}
+			}
+
+			// Check the embedded author
+			if tc.wantAuthor != nil {
+				var got BlogResponse
+				if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if got.Author == nil || *got.Author != *tc.wantAuthor {
+					t.Errorf("want author %+v, got %+v", tc.wantAuthor, got.Author)
+				}
+			}
 		})
 	}
 }
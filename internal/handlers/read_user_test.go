@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -21,17 +20,12 @@ func TestHandleReadUser(t *testing.T) {
 	}{
 		"happy path": {
 			wantStatus: 200,
-			wantBody: `
-				"id":       1,
-				"name":     "john",
-				"email":    "john@mail.com",
-				"password": "password123!",
-			`,
+			wantBody:   `{"id":1,"name":"john","email":"john@mail.com"}`,
 			wantResults: models.User{
 				ID:       1,
 				Name:     "john",
 				Email:    "john@mail.com",
-				Password: "password123!",
+				Password: "$2a$12$R9h/cIPz0gi.URNNX3kh2OPST9/PgBkqquzi.Ss7KIUgO2t0jWMUW",
 			},
 		},
 	}
@@ -48,7 +42,7 @@ func TestHandleReadUser(t *testing.T) {
 			logger := slog.Default()
 
 			userReader := new(mock.UserReader)
-			userReader.On("ReadUser", context.Background(), uint64(1)).Return(tc.wantResults, nil)
+			userReader.On("ReadUser", context.Background(), uint64(1), false).Return(tc.wantResults, nil)
 			// Call the handler
 			handler := HandleReadUser(logger, userReader)
 
@@ -58,10 +52,9 @@ func TestHandleReadUser(t *testing.T) {
 				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
 			}
 
-			// Check the body
-			json, _ := json.Marshal(tc.wantResults)
-			if strings.Trim(rec.Body.String(), "\n") != string(json) {
-				t.Errorf("want body %q, got %q", string(json), rec.Body.String())
+			// Check the body never includes the password hash
+			if strings.Trim(rec.Body.String(), "\n") != tc.wantBody {
+				t.Errorf("want body %q, got %q", tc.wantBody, rec.Body.String())
 			}
 		})
 	}
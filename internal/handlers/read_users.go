@@ -13,7 +13,7 @@ import (
 // userReader represents a type capable of reading a user from storage and
 // returning it or an error.
 type userReader interface {
-	ReadUser(ctx context.Context, id uint64) (models.User, error)
+	ReadUser(ctx context.Context, id uint64, includeDeleted bool) (models.User, error)
 }
 
 // @Summary		Read User
@@ -21,11 +21,17 @@ type userReader interface {
 // @Tags			user
 // @Accept			json
 // @Produce		json
-// @Param			id	path		string	true	"User ID"
-// @Success		200	{object}	models.User
-// @Failure		400	{object}	string
-// @Failure		404	{object}	string
-// @Failure		500	{object}	string
+// @Param			id				path		string	true	"User ID"
+// @Param			include_deleted	query		bool	false	"Read the user even if they are deleted, for admins only"
+// @Param			If-None-Match	header		string	false	"ETag of the version of the user the client already holds"
+// @Success		200	{object}	UserResponse
+// @Header			200	{string}	ETag	"Version of the user"
+// @Success		304	"The user is still at the version named by If-None-Match"
+// @Failure		400	{object}	ProblemResponse
+// @Failure		401	{object}	ProblemResponse
+// @Failure		403	{object}	ProblemResponse
+// @Failure		404	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
 // @Router			/user/{id}  [GET]
 func HandleReadUser(logger *slog.Logger, userReader userReader) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -44,12 +50,17 @@ func HandleReadUser(logger *slog.Logger, userReader userReader) http.Handler {
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		includeDeleted, ok := parseIncludeDeleted(w, r)
+		if !ok {
 			return
 		}
 
 		// Read the user
-		user, err := userReader.ReadUser(ctx, uint64(id))
+		user, err := userReader.ReadUser(ctx, uint64(id), includeDeleted)
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -57,16 +68,22 @@ func HandleReadUser(logger *slog.Logger, userReader userReader) http.Handler {
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
+			return
+		}
+
+		setETag(w, user.Version)
+		if notModified(r, user.Version) {
+			w.WriteHeader(http.StatusNotModified)
 			return
 		}
 
 		// Convert our models.User domain model into a response model.
 		response := UserResponse{
-			ID:       user.ID,
-			Name:     user.Name,
-			Email:    user.Email,
-			Password: user.Password,
+			ID:        user.ID,
+			Name:      user.Name,
+			Email:     user.Email,
+			DeletedAt: optionalTime(user.DeletedAt),
 		}
 
 		// Encode the response model as JSON
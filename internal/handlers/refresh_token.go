@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// sessionRefresher represents a type capable of exchanging a refresh token for
+// a new token pair.
+type sessionRefresher interface {
+	Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error)
+}
+
+// @Summary		Refresh Tokens
+// @Description	Exchanges a refresh token for a new access and refresh token. Each refresh token can only be used once.
+// @Tags			auth
+// @Accept			json
+// @Produce		json
+// @Param			request	body		RefreshRequest	true	"Refresh token"
+// @Success		200		{object}	TokenResponse
+// @Failure		400		{object}	ProblemResponse
+// @Failure		401		{object}	ProblemResponse
+// @Failure		422		{object}	ProblemResponse
+// @Failure		500		{object}	ProblemResponse
+// @Router			/auth/refresh  [POST]
+func HandleRefreshToken(logger *slog.Logger, sessionRefresher sessionRefresher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Request validation
+		request, problems, err := decodeValid[*RefreshRequest](r)
+
+		if err != nil && len(problems) == 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to decode request",
+				slog.String("error", err.Error()))
+
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
+		}
+		if len(problems) > 0 {
+			logger.ErrorContext(
+				r.Context(),
+				"Validation error",
+				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
+			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
+		}
+
+		// Rotate the tokens
+		tokens, err := sessionRefresher.Refresh(ctx, request.RefreshToken)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to refresh tokens",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(newTokenResponse(tokens)); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
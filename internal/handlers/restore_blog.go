@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// deletedBlogRestorer represents a type capable of restoring a deleted blog
+// in storage
+type deletedBlogRestorer interface {
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
+	RestoreBlog(ctx context.Context, id uint64) error
+}
+
+// @Summary		Restore Blog
+// @Description	Restore a deleted Blog by ID, along with the comments deleted with it
+// @Tags			blog
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id	path	string	true	"Blog ID"
+// @Success		200
+// @Failure		400	{object}	ProblemResponse
+// @Failure		401	{object}	ProblemResponse
+// @Failure		403	{object}	ProblemResponse
+// @Failure		404	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
+// @Router			/blog/{id}/restore  [POST]
+func HandleRestoreBlog(logger *slog.Logger, deletedBlogRestorer deletedBlogRestorer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// Only the author or an admin may restore a blog
+		existing, err := deletedBlogRestorer.ReadBlog(ctx, uint64(id), models.BlogExpand{}, true)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+		if !authz.CanBlog(actor, authz.Restore, existing) {
+			writeForbidden(w, r)
+			return
+		}
+
+		// Restore the blog
+		err = deletedBlogRestorer.RestoreBlog(ctx, uint64(id))
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to restore blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+	})
+}
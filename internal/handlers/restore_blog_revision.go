@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// blogRestorer represents a type capable of reading a blog and restoring one
+// of its revisions, returning the restored blog or an error.
+type blogRestorer interface {
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
+	RestoreBlogRevision(ctx context.Context, blogId uint, revisionId uint, editorId uint, version uint) (models.Blog, error)
+}
+
+// @Summary		Restore Blog Revision
+// @Description	Put the content of one of a blog's revisions back on the blog. The content it replaces is kept as a new revision, and the blog's status is unchanged.
+// @Tags			blog
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id			path		string	true	"Blog ID"
+// @Param			revision	path		string	true	"Revision ID"
+// @Param			If-Match	header		string	false	"ETag of the version of the blog the restore is made against"
+// @Success		200			{object}	BlogResponse
+// @Header			200			{string}	ETag	"Version of the restored blog"
+// @Failure		400			{object}	ProblemResponse
+// @Failure		401			{object}	ProblemResponse
+// @Failure		403			{object}	ProblemResponse
+// @Failure		404			{object}	ProblemResponse
+// @Failure		412			{object}	ProblemResponse
+// @Failure		500			{object}	ProblemResponse
+// @Router			/blog/{id}/revisions/{revision}/restore  [POST]
+func HandleRestoreBlogRevision(logger *slog.Logger, blogRestorer blogRestorer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Read ids from path parameters
+		idStr := r.PathValue("id")
+		revisionStr := r.PathValue("revision")
+
+		// Convert the IDs from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		revisionId, err := strconv.Atoi(revisionStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse revision id from url",
+				slog.String("revision", revisionStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid revision ID", nil)
+			return
+		}
+
+		// A client that read the blog earlier can make sure nobody has
+		// changed it since
+		version, ok := parseIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Only those who may update the blog may restore its revisions
+		existing, err := blogRestorer.ReadBlog(ctx, uint64(id), models.BlogExpand{}, false)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+		if !authz.CanBlog(actor, authz.Update, existing) {
+			writeForbidden(w, r)
+			return
+		}
+
+		// Restore the revision
+		blog, err := blogRestorer.RestoreBlogRevision(ctx, uint(id), uint(revisionId), actor.ID, version)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to restore blog revision",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		setETag(w, blog.Version)
+
+		// Convert our models.Blog domain model into a response model.
+		response := BlogResponse{
+			ID:          blog.ID,
+			AuthorID:    blog.AuthorID,
+			Title:       blog.Title,
+			Body:        blog.Body,
+			Format:      formatMarkdown,
+			Excerpt:     blog.Excerpt,
+			Category:    blog.Category,
+			Tags:        tagList(blog.Tags),
+			Status:      string(blog.Status),
+			PublishAt:   optionalTime(blog.PublishAt),
+			Score:       blog.Score,
+			CreatedDate: blog.CreatedDate,
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleRestoreBlogRevision(t *testing.T) {
+	tests := map[string]struct {
+		actor        models.User
+		revision     string
+		ifMatch      string
+		mockVersion  uint
+		mockRestored bool
+		mockError    error
+		wantStatus   int
+		wantETag     string
+	}{
+		"author": {
+			actor:        testUser,
+			revision:     "5",
+			mockRestored: true,
+			wantStatus:   200,
+			wantETag:     `"4"`,
+		},
+		"matching version": {
+			actor:        testUser,
+			revision:     "5",
+			ifMatch:      `"3"`,
+			mockVersion:  3,
+			mockRestored: true,
+			wantStatus:   200,
+			wantETag:     `"4"`,
+		},
+		"stale version": {
+			actor:        testUser,
+			revision:     "5",
+			ifMatch:      `"2"`,
+			mockVersion:  2,
+			mockRestored: true,
+			mockError:    fmt.Errorf("blog 1: %w", services.ErrPreconditionFailed),
+			wantStatus:   412,
+		},
+		"invalid If-Match": {
+			actor:      testUser,
+			revision:   "5",
+			ifMatch:    "3",
+			wantStatus: 400,
+		},
+		"admin": {
+			actor:        testAdmin,
+			revision:     "5",
+			mockRestored: true,
+			wantStatus:   200,
+			wantETag:     `"4"`,
+		},
+		"revision not found": {
+			actor:        testUser,
+			revision:     "5",
+			mockRestored: true,
+			mockError:    fmt.Errorf("revision 5: %w", services.ErrNotFound),
+			wantStatus:   404,
+		},
+		"someone else's blog": {
+			actor:      testOtherUser,
+			revision:   "5",
+			wantStatus: 403,
+		},
+		"invalid revision": {
+			actor:      testUser,
+			revision:   "abc",
+			wantStatus: 400,
+		},
+		"anonymous": {
+			revision:   "5",
+			wantStatus: 401,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/blog/1/revisions/"+tc.revision+"/restore", nil)
+			req.SetPathValue("id", "1")
+			req.SetPathValue("revision", tc.revision)
+			req.Header.Set("If-Match", tc.ifMatch)
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			restorer := new(mock.BlogRestorer)
+			restorer.On("ReadBlog", req.Context(), uint64(1), models.BlogExpand{}, false).Return(models.Blog{ID: 1, AuthorID: 1}, nil).Maybe()
+			if tc.mockRestored {
+				restorer.
+					On("RestoreBlogRevision", req.Context(), uint(1), uint(5), tc.actor.ID, tc.mockVersion).
+					Return(models.Blog{ID: 1, AuthorID: 1, Title: "Book Title", Version: 4}, tc.mockError)
+			}
+
+			handler := HandleRestoreBlogRevision(logger, restorer)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("ETag"); got != tc.wantETag {
+				t.Errorf("want ETag %q, got %q", tc.wantETag, got)
+			}
+			restorer.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleRestoreBlog(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		readError  error
+		mockError  error
+		wantStatus int
+	}{
+		"happy path": {
+			actor:      testUser,
+			wantStatus: 200,
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+		},
+		"admin": {
+			actor:      testAdmin,
+			wantStatus: 200,
+		},
+		"missing": {
+			actor:      testUser,
+			readError:  services.ErrNotFound,
+			wantStatus: 404,
+		},
+		"not deleted": {
+			actor:      testUser,
+			mockError:  services.ErrNotFound,
+			wantStatus: 404,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create a new request
+			req := httptest.NewRequest("POST", "/blogs/1/restore", nil)
+			req.SetPathValue("id", "1")
+			req = withActor(req, tc.actor)
+
+			// Create a new response recorder
+			rec := httptest.NewRecorder()
+
+			// Create a new logger
+			logger := slog.Default()
+
+			blogRestorer := new(mock.DeletedBlogRestorer)
+			blogRestorer.On("ReadBlog", req.Context(), uint64(1), models.BlogExpand{}, true).Return(models.Blog{ID: 1, AuthorID: 1}, tc.readError)
+			blogRestorer.On("RestoreBlog", req.Context(), uint64(1)).Return(tc.mockError)
+
+			// Call the handler
+			handler := HandleRestoreBlog(logger, blogRestorer)
+
+			handler.ServeHTTP(rec, req)
+			// Check the status code
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
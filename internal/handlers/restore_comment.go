@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// deletedCommentRestorer represents a type capable of restoring a deleted
+// comment in storage
+type deletedCommentRestorer interface {
+	RestoreComment(ctx context.Context, id uint, userId uint, blogId uint) error
+}
+
+// @Summary		Restore Comment
+// @Description	Restore a deleted Comment, along with the replies deleted with it
+// @Tags			comment
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			author_id	query	string	false	"Author Id"
+// @Param			blog_id		query	string	false	"Blog Id"
+// @Param			id			query	string	false	"Comment Id, when the author has several deleted comments on the blog"
+// @Success		200
+// @Failure		400	{object}	ProblemResponse
+// @Failure		401	{object}	ProblemResponse
+// @Failure		403	{object}	ProblemResponse
+// @Failure		404	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
+// @Router			/comment/restore  [POST]
+func HandleRestoreComment(logger *slog.Logger, deletedCommentRestorer deletedCommentRestorer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Validate query params
+		userIdStr := r.URL.Query().Get("author_id")
+		blogIdStr := r.URL.Query().Get("blog_id")
+
+		var userId int
+		var err error
+
+		if userIdStr != "" {
+			userId, err = strconv.Atoi(userIdStr)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to get valid User id from query param",
+					slog.String("id", userIdStr),
+					slog.String("error", err.Error()),
+				)
+
+				writeProblem(w, r, http.StatusBadRequest, "Invalid User ID", nil)
+				return
+			}
+		}
+
+		var blogId int
+
+		if blogIdStr != "" {
+			blogId, err = strconv.Atoi(blogIdStr)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to get valid Blog Id from query param",
+					slog.String("id", blogIdStr),
+					slog.String("error", err.Error()),
+				)
+
+				writeProblem(w, r, http.StatusBadRequest, "Invalid Blog ID", nil)
+				return
+			}
+		}
+
+		var commentId int
+
+		if commentIdStr := r.URL.Query().Get("id"); commentIdStr != "" {
+			commentId, err = strconv.Atoi(commentIdStr)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to get valid Comment Id from query param",
+					slog.String("id", commentIdStr),
+					slog.String("error", err.Error()),
+				)
+
+				writeProblem(w, r, http.StatusBadRequest, "Invalid Comment ID", nil)
+				return
+			}
+		}
+
+		// Only the author or an admin may restore a comment
+		if !authz.CanComment(actor, authz.Restore, models.Comment{UserID: uint(userId), BlogID: uint(blogId)}) {
+			writeForbidden(w, r)
+			return
+		}
+
+		// Restore the comment
+		err = deletedCommentRestorer.RestoreComment(ctx, uint(commentId), uint(userId), uint(blogId))
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to restore comment",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+	})
+}
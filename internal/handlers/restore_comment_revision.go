@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// commentRestorer represents a type capable of reading a comment revision and
+// restoring it, returning the restored comment or an error.
+type commentRestorer interface {
+	ReadCommentRevision(ctx context.Context, id uint) (models.CommentRevision, error)
+	RestoreCommentRevision(ctx context.Context, id uint, editorId uint, version uint) (models.Comment, error)
+}
+
+// @Summary		Restore Comment Revision
+// @Description	Put the message of a comment revision back on its comment. The message it replaces is kept as a new revision.
+// @Tags			comment
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id			path		string	true	"Revision ID"
+// @Param			If-Match	header		string	false	"ETag of the version of the comment the restore is made against"
+// @Success		200			{object}	CommentResponse
+// @Header			200			{string}	ETag	"Version of the restored comment"
+// @Failure		400			{object}	ProblemResponse
+// @Failure		401			{object}	ProblemResponse
+// @Failure		403			{object}	ProblemResponse
+// @Failure		404			{object}	ProblemResponse
+// @Failure		412			{object}	ProblemResponse
+// @Failure		500			{object}	ProblemResponse
+// @Router			/comment/revisions/{id}/restore  [POST]
+func HandleRestoreCommentRevision(logger *slog.Logger, commentRestorer commentRestorer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// A client that read the comment earlier can make sure nobody has
+		// changed it since
+		version, ok := parseIfMatch(w, r)
+		if !ok {
+			return
+		}
+
+		// Only those who may update the comment may restore its revisions
+		revision, err := commentRestorer.ReadCommentRevision(ctx, uint(id))
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read comment revision",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+		existing := models.Comment{
+			ID:     revision.CommentID,
+			UserID: revision.UserID,
+			BlogID: revision.BlogID,
+		}
+		if !authz.CanComment(actor, authz.Update, existing) {
+			writeForbidden(w, r)
+			return
+		}
+
+		// Restore the revision
+		comment, err := commentRestorer.RestoreCommentRevision(ctx, uint(id), actor.ID, version)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to restore comment revision",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		setETag(w, comment.Version)
+
+		// Convert our models.Comment domain model into a response model.
+		response := CommentResponse{
+			ID:          comment.ID,
+			ParentID:    comment.ParentID,
+			BlogID:      comment.BlogID,
+			UserID:      comment.UserID,
+			Message:     comment.Message,
+			CreatedDate: comment.CreatedDate,
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
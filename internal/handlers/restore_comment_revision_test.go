@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleRestoreCommentRevision(t *testing.T) {
+	tests := map[string]struct {
+		actor            models.User
+		id               string
+		ifMatch          string
+		mockRead         bool
+		mockError        error
+		mockVersion      uint
+		mockRestored     bool
+		mockRestoreError error
+		wantStatus       int
+		wantETag         string
+	}{
+		"author": {
+			actor:        testUser,
+			id:           "5",
+			mockRead:     true,
+			mockRestored: true,
+			wantStatus:   200,
+			wantETag:     `"2"`,
+		},
+		"matching version": {
+			actor:        testUser,
+			id:           "5",
+			ifMatch:      `"1"`,
+			mockRead:     true,
+			mockVersion:  1,
+			mockRestored: true,
+			wantStatus:   200,
+			wantETag:     `"2"`,
+		},
+		"stale version": {
+			actor:            testUser,
+			id:               "5",
+			ifMatch:          `"1"`,
+			mockRead:         true,
+			mockVersion:      1,
+			mockRestored:     true,
+			mockRestoreError: fmt.Errorf("comment 3: %w", services.ErrPreconditionFailed),
+			wantStatus:       412,
+		},
+		"invalid If-Match": {
+			actor:      testUser,
+			id:         "5",
+			ifMatch:    "1",
+			wantStatus: 400,
+		},
+		"admin": {
+			actor:        testAdmin,
+			id:           "5",
+			mockRead:     true,
+			mockRestored: true,
+			wantStatus:   200,
+			wantETag:     `"2"`,
+		},
+		"someone else's comment": {
+			actor:      testOtherUser,
+			id:         "5",
+			mockRead:   true,
+			wantStatus: 403,
+		},
+		"revision not found": {
+			actor:      testUser,
+			id:         "5",
+			mockRead:   true,
+			mockError:  fmt.Errorf("revision 5: %w", services.ErrNotFound),
+			wantStatus: 404,
+		},
+		"invalid id": {
+			actor:      testUser,
+			id:         "abc",
+			wantStatus: 400,
+		},
+		"anonymous": {
+			id:         "5",
+			wantStatus: 401,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/comment/revisions/"+tc.id+"/restore", nil)
+			req.SetPathValue("id", tc.id)
+			req.Header.Set("If-Match", tc.ifMatch)
+			req = withActor(req, tc.actor)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			restorer := new(mock.CommentRestorer)
+			if tc.mockRead {
+				restorer.
+					On("ReadCommentRevision", req.Context(), uint(5)).
+					Return(models.CommentRevision{ID: 5, CommentID: 3, UserID: 1, BlogID: 1}, tc.mockError)
+			}
+			if tc.mockRestored {
+				restorer.
+					On("RestoreCommentRevision", req.Context(), uint(5), tc.actor.ID, tc.mockVersion).
+					Return(models.Comment{ID: 3, UserID: 1, BlogID: 1, Message: "Nice", Version: 2}, tc.mockRestoreError)
+			}
+
+			handler := HandleRestoreCommentRevision(logger, restorer)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("ETag"); got != tc.wantETag {
+				t.Errorf("want ETag %q, got %q", tc.wantETag, got)
+			}
+			restorer.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleRestoreComment(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		target     string
+		commentID  uint
+		mockError  error
+		wantStatus int
+	}{
+		"happy path": {
+			actor:      testUser,
+			wantStatus: 200,
+		},
+		"by id": {
+			actor:      testUser,
+			target:     "/comments/restore?author_id=1&blog_id=1&id=4",
+			commentID:  4,
+			wantStatus: 200,
+		},
+		"invalid id": {
+			actor:      testUser,
+			target:     "/comments/restore?author_id=1&blog_id=1&id=abc",
+			wantStatus: 400,
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+		},
+		"admin": {
+			actor:      testAdmin,
+			wantStatus: 200,
+		},
+		"not deleted": {
+			actor:      testUser,
+			mockError:  services.ErrNotFound,
+			wantStatus: 404,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create a new request
+			if tc.target == "" {
+				tc.target = "/comments/restore?author_id=1&blog_id=1"
+			}
+			req := httptest.NewRequest("POST", tc.target, nil)
+			req = withActor(req, tc.actor)
+
+			// Create a new response recorder
+			rec := httptest.NewRecorder()
+
+			// Create a new logger
+			logger := slog.Default()
+
+			commentRestorer := new(mock.DeletedCommentRestorer)
+			commentRestorer.On("RestoreComment", req.Context(), tc.commentID, uint(1), uint(1)).Return(tc.mockError)
+
+			// Call the handler
+			handler := HandleRestoreComment(logger, commentRestorer)
+
+			handler.ServeHTTP(rec, req)
+			// Check the status code
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/chickey/blog/internal/authz"
+	"github.com/chickey/blog/internal/models"
+)
+
+// deletedUserRestorer represents a type capable of restoring a deleted user
+// in storage
+type deletedUserRestorer interface {
+	RestoreUser(ctx context.Context, id uint64) error
+}
+
+// @Summary		Restore User
+// @Description	Restore a deleted User by ID, along with the blogs and comments deleted with them
+// @Tags			user
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			id	path	string	true	"User ID"
+// @Success		200
+// @Failure		400	{object}	ProblemResponse
+// @Failure		401	{object}	ProblemResponse
+// @Failure		403	{object}	ProblemResponse
+// @Failure		404	{object}	ProblemResponse
+// @Failure		409	{object}	ProblemResponse
+// @Failure		500	{object}	ProblemResponse
+// @Router			/user/{id}/restore  [POST]
+func HandleRestoreUser(logger *slog.Logger, deletedUserRestorer deletedUserRestorer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
+		// Read id from path parameters
+		idStr := r.PathValue("id")
+
+		// Convert the ID from string to int
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to parse id from url",
+				slog.String("id", idStr),
+				slog.String("error", err.Error()),
+			)
+
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// Only admins may restore a user
+		if !authz.CanUser(actor, authz.Restore, models.User{ID: uint(id)}) {
+			writeForbidden(w, r)
+			return
+		}
+
+		// Restore the user
+		err = deletedUserRestorer.RestoreUser(ctx, uint64(id))
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to restore user",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
+)
+
+func TestHandleRestoreUser(t *testing.T) {
+	tests := map[string]struct {
+		actor      models.User
+		mockError  error
+		wantStatus int
+	}{
+		"admin": {
+			actor:      testAdmin,
+			wantStatus: 200,
+		},
+		"not deleted": {
+			actor:      testAdmin,
+			mockError:  services.ErrNotFound,
+			wantStatus: 404,
+		},
+		"the user themselves": {
+			actor:      testUser,
+			wantStatus: 403,
+		},
+		"someone else": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create a new request
+			req := httptest.NewRequest("POST", "/users/1/restore", nil)
+			req.SetPathValue("id", "1")
+			req = withActor(req, tc.actor)
+
+			// Create a new response recorder
+			rec := httptest.NewRecorder()
+
+			// Create a new logger
+			logger := slog.Default()
+
+			userRestorer := new(mock.DeletedUserRestorer)
+			userRestorer.On("RestoreUser", req.Context(), uint64(1)).Return(tc.mockError)
+
+			// Call the handler
+			handler := HandleRestoreUser(logger, userRestorer)
+
+			handler.ServeHTTP(rec, req)
+			// Check the status code
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// BlogRevisionResponse represents the content of a blog before one of its
+// updates. Diff is a line diff from the revision to the content the update
+// left, with lines prefixed by "-" for removed, "+" for added and a space for
+// unchanged. EditorID is left out once the editor has been deleted.
+type BlogRevisionResponse struct {
+	ID          uint      `json:"id"`
+	BlogID      uint      `json:"blogid"`
+	EditorID    uint      `json:"editorid,omitempty"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	Excerpt     string    `json:"excerpt"`
+	Category    string    `json:"category"`
+	Tags        []string  `json:"tags"`
+	Diff        string    `json:"diff"`
+	CreatedDate time.Time `json:"createddate"`
+}
+
+// newBlogRevisionResponse converts revision into a response model.
+func newBlogRevisionResponse(revision models.BlogRevision) BlogRevisionResponse {
+	return BlogRevisionResponse{
+		ID:          revision.ID,
+		BlogID:      revision.BlogID,
+		EditorID:    revision.EditorID,
+		Title:       revision.Title,
+		Body:        revision.Body,
+		Excerpt:     revision.Excerpt,
+		Category:    revision.Category,
+		Tags:        tagList(revision.Tags),
+		Diff:        revision.Diff,
+		CreatedDate: revision.CreatedDate,
+	}
+}
+
+// CommentRevisionResponse represents the message of a comment before one of
+// its updates, with a line diff to the message the update left. EditorID is
+// left out once the editor has been deleted.
+type CommentRevisionResponse struct {
+	ID          uint
+	CommentID   uint
+	EditorID    uint `json:",omitempty"`
+	Message     string
+	Diff        string
+	CreatedDate time.Time
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// maxSearchQueryLength is the longest search query accepted, in characters.
+const maxSearchQueryLength = 200
+
+// searcher represents a type capable of searching content and returning a
+// page of results or an error.
+type searcher interface {
+	Search(ctx context.Context, query string, page models.PageRequest) (models.Page[models.SearchResult], error)
+}
+
+// @Summary		Search
+// @Description	Search blog titles and comment messages, best matches first
+// @Tags			search
+// @Accept			json
+// @Produce		json
+// @Param			q				query		string	true	"Search terms, supporting \"quoted phrases\", or and -excluded words"
+// @Param			limit			query		int		false	"Page size"
+// @Param			offset			query		int		false	"Number of results to skip"
+// @Param			cursor			query		string	false	"Cursor from a next or prev link"
+// @Param			include_total	query		bool	false	"Include the total count"
+// @Success		200				{object}	searchResponse
+// @Failure		400				{object}	ProblemResponse
+// @Failure		500				{object}	ProblemResponse
+// @Router			/search  [GET]
+func HandleSearch(logger *slog.Logger, searcher searcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+
+		page, problems := parsePageRequest(r)
+		switch {
+		case query == "":
+			problems["q"] = "Search query cannot be empty"
+		case utf8.RuneCountInString(query) > maxSearchQueryLength:
+			problems["q"] = fmt.Sprintf("Search query cannot be longer than %d characters", maxSearchQueryLength)
+		}
+
+		if len(problems) > 0 {
+			writeProblem(w, r, http.StatusBadRequest, "Invalid query parameters", problems)
+			return
+		}
+
+		// Search the content
+		results, err := searcher.Search(ctx, query, page)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to search",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+
+		// Convert our models.SearchResult domain model into a response model.
+		response := searchResponse{
+			Results:      []SearchResultResponse{},
+			PageResponse: newPageResponse(r, page, results),
+		}
+
+		for _, result := range results.Items {
+			response.Results = append(response.Results, SearchResultResponse{
+				Type:      string(result.Type),
+				BlogID:    result.BlogID,
+				UserID:    result.UserID,
+				CommentID: result.CommentID,
+				Headline:  result.Headline,
+				Rank:      result.Rank,
+			})
+		}
+
+		// Encode the response model as JSON
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to encode response",
+				slog.String("error", err.Error()))
+
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package handlers
+
+// SearchResultResponse represents a blog or comment matching a search.
+// CommentID is only set for comments. Headline is escaped for HTML, with the
+// matched words wrapped in <mark> tags.
+type SearchResultResponse struct {
+	Type      string  `json:"type"`
+	BlogID    uint    `json:"blog_id"`
+	UserID    uint    `json:"user_id"`
+	CommentID uint    `json:"comment_id,omitempty"`
+	Headline  string  `json:"headline"`
+	Rank      float32 `json:"rank"`
+}
+
+// searchResponse represents the response for searching.
+type searchResponse struct {
+	Results []SearchResultResponse `json:"results"`
+	PageResponse
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chickey/blog/internal/handlers/mock"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestHandleSearch(t *testing.T) {
+	tests := map[string]struct {
+		target     string
+		mockCalled bool
+		query      string
+		page       models.PageRequest
+		mockOutput models.Page[models.SearchResult]
+		wantStatus int
+		wantBody   searchResponse
+	}{
+		"happy path": {
+			target:     "/api/search?q=go+tips&limit=1",
+			mockCalled: true,
+			query:      "go tips",
+			page:       models.PageRequest{Limit: 1},
+			mockOutput: models.Page[models.SearchResult]{
+				Items: []models.SearchResult{
+					{
+						Type:     models.SearchResultBlog,
+						BlogID:   1,
+						UserID:   1,
+						Headline: "<mark>Go</mark> <mark>tips</mark>",
+						Rank:     0.5,
+					},
+				},
+				NextCursor: "next",
+			},
+			wantStatus: 200,
+			wantBody: searchResponse{
+				Results: []SearchResultResponse{
+					{
+						Type:     "blog",
+						BlogID:   1,
+						UserID:   1,
+						Headline: "<mark>Go</mark> <mark>tips</mark>",
+						Rank:     0.5,
+					},
+				},
+				PageResponse: PageResponse{Next: "/api/search?cursor=next&limit=1&q=go+tips"},
+			},
+		},
+		"missing query": {
+			target:     "/api/search?q=+",
+			wantStatus: 400,
+		},
+		"query too long": {
+			target:     "/api/search?q=" + strings.Repeat("a", maxSearchQueryLength+1),
+			wantStatus: 400,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.target, nil)
+			rec := httptest.NewRecorder()
+			logger := slog.Default()
+
+			searcher := new(mock.Searcher)
+			if tc.mockCalled {
+				searcher.On("Search", context.Background(), tc.query, tc.page).Return(tc.mockOutput, nil)
+			}
+
+			handler := HandleSearch(logger, searcher)
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
+			}
+			if rec.Code == 200 {
+				var got searchResponse
+				if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if !reflect.DeepEqual(got, tc.wantBody) {
+					t.Errorf("want body %+v, got %+v", tc.wantBody, got)
+				}
+			}
+			searcher.AssertExpectations(t)
+		})
+	}
+}
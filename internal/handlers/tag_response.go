@@ -0,0 +1,7 @@
+package handlers
+
+// TagResponse represents a tag and the number of published blogs carrying it.
+type TagResponse struct {
+	Name      string `json:"name"`
+	BlogCount int    `json:"blogcount"`
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// TokenResponse represents the response for logging in or refreshing tokens.
+// ExpiresIn is the lifetime of the access token in seconds.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// newTokenResponse converts a models.TokenPair into a response model.
+func newTokenResponse(tokens models.TokenPair) TokenResponse {
+	return TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(time.Until(tokens.AccessTokenExpiresAt).Seconds()),
+	}
+}
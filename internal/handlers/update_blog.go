@@ -8,13 +8,15 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/chickey/blog/internal/authz"
 	"github.com/chickey/blog/internal/models"
 )
 
 // blogUpdater represents a type capable of updating a blog and
 // returning it or an error.
 type blogUpdater interface {
-	UpdateBlog(ctx context.Context, id uint64, patch models.Blog) (models.Blog, error)
+	ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error)
+	UpdateBlog(ctx context.Context, id uint64, patch models.Blog, editorId uint) (models.Blog, error)
 }
 
 //	@Summary		Update Blog
@@ -22,17 +24,30 @@ type blogUpdater interface {
 //	@Tags			blog
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
 //	@Param			id		path		string		true	"Blog ID"
 //	@Param			request	body		BlogRequest	true	"Blog to Create"
+//	@Param			If-Match	header	string	false	"ETag of the version of the blog the update is made against"
 //	@Success		200		{object}	models.Blog
-//	@Failure		400		{object}	string
-//	@Failure		404		{object}	string
-//	@Failure		500		{object}	string
+//	@Header			200		{string}	ETag	"Version of the updated blog"
+//	@Failure		400		{object}	ProblemResponse
+//	@Failure		401		{object}	ProblemResponse
+//	@Failure		403		{object}	ProblemResponse
+//	@Failure		404		{object}	ProblemResponse
+//	@Failure		409		{object}	ProblemResponse
+//	@Failure		412		{object}	ProblemResponse
+//	@Failure		422		{object}	ProblemResponse
+//	@Failure		500		{object}	ProblemResponse
 //	@Router			/blog/{id}  [PUT]
 func HandleUpdateBlog(logger *slog.Logger, blogUpdater blogUpdater) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
 		// Read id from path parameters
 		idStr := r.PathValue("id")
 
@@ -46,7 +61,14 @@ func HandleUpdateBlog(logger *slog.Logger, blogUpdater blogUpdater) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// A client that read the blog earlier can make sure nobody has
+		// changed it since
+		version, ok := parseIfMatch(w, r)
+		if !ok {
 			return
 		}
 
@@ -59,7 +81,8 @@ func HandleUpdateBlog(logger *slog.Logger, blogUpdater blogUpdater) http.Handler
 				"failed to decode request",
 				slog.String("error", err.Error()))
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
 		}
 		if len(problems) > 0 {
 			logger.ErrorContext(
@@ -67,16 +90,43 @@ func HandleUpdateBlog(logger *slog.Logger, blogUpdater blogUpdater) http.Handler
 				"Validation error",
 				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
 			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
 		}
 
 		modelRequest := models.Blog{
-			AuthorID: request.AuthorID,
-			Title:    request.Title,
-			Score:    request.Score,
+			AuthorID:  request.AuthorID,
+			Title:     request.Title,
+			Body:      request.Body,
+			Excerpt:   request.Excerpt,
+			Category:  request.Category,
+			Tags:      request.Tags,
+			Status:    models.BlogStatus(request.Status),
+			PublishAt: request.PublishAt,
+			Version:   version,
+		}
+
+		// Only the author or an admin may update a blog, and only an admin may
+		// hand it over to someone else
+		existing, err := blogUpdater.ReadBlog(ctx, uint64(id), models.BlogExpand{}, false)
+		if err != nil {
+			logger.ErrorContext(
+				r.Context(),
+				"failed to read blog",
+				slog.String("error", err.Error()),
+			)
+
+			writeServiceError(w, r, err)
+			return
+		}
+		if !authz.CanBlog(actor, authz.Update, existing) || !authz.CanBlog(actor, authz.Update, modelRequest) {
+			writeForbidden(w, r)
+			return
 		}
 
 		// Update the blog
-		blog, err := blogUpdater.UpdateBlog(ctx, uint64(id), modelRequest)
+		blog, err := blogUpdater.UpdateBlog(ctx, uint64(id), modelRequest, actor.ID)
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -84,15 +134,24 @@ func HandleUpdateBlog(logger *slog.Logger, blogUpdater blogUpdater) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
+		setETag(w, blog.Version)
+
 		// Convert our models.Blog domain model into a response model.
 		response := BlogResponse{
 			ID:          blog.ID,
 			AuthorID:    blog.AuthorID,
 			Title:       blog.Title,
+			Body:        blog.Body,
+			Format:      formatMarkdown,
+			Excerpt:     blog.Excerpt,
+			Category:    blog.Category,
+			Tags:        tagList(blog.Tags),
+			Status:      string(blog.Status),
+			PublishAt:   optionalTime(blog.PublishAt),
 			Score:       blog.Score,
 			CreatedDate: blog.CreatedDate,
 		}
@@ -2,8 +2,8 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http/httptest"
 	"testing"
@@ -11,15 +11,20 @@ import (
 
 	"github.com/chickey/blog/internal/handlers/mock"
 	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/services"
 )
 
 func TestHandleUpdateBlog(t *testing.T) {
 	tests := map[string]struct {
+		actor      models.User
+		ifMatch    string
+		mockError  error
 		wantStatus int
 		wantBody   models.Blog
 		input      models.Blog
 	}{
 		"happy path": {
+			actor:      testUser,
 			wantStatus: 200,
 			wantBody: models.Blog{
 				ID:          1,
@@ -31,7 +36,66 @@ func TestHandleUpdateBlog(t *testing.T) {
 			input: models.Blog{
 				AuthorID: 1,
 				Title:    "Book Title",
-				Score:    8.2,
+			},
+		},
+		"current version": {
+			actor:      testUser,
+			ifMatch:    `"3"`,
+			wantStatus: 200,
+			wantBody: models.Blog{
+				ID:       1,
+				AuthorID: 1,
+				Title:    "Book Title",
+				Version:  4,
+			},
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Version:  3,
+			},
+		},
+		"stale version": {
+			actor:      testUser,
+			ifMatch:    `"3"`,
+			mockError:  fmt.Errorf("blog 1: %w", services.ErrPreconditionFailed),
+			wantStatus: 412,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Version:  3,
+			},
+		},
+		"invalid If-Match": {
+			actor:      testUser,
+			ifMatch:    "3",
+			wantStatus: 400,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+			},
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+			},
+		},
+		"hand over to someone else": {
+			actor:      testUser,
+			wantStatus: 403,
+			input: models.Blog{
+				AuthorID: 2,
+				Title:    "Book Title",
+			},
+		},
+		"admin": {
+			actor:      testAdmin,
+			wantStatus: 200,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
 			},
 		},
 	}
@@ -41,6 +105,8 @@ func TestHandleUpdateBlog(t *testing.T) {
 			reqBody, _ := json.Marshal(tc.input)
 			req := httptest.NewRequest("PUT", "/blogs", bytes.NewBuffer(reqBody))
 			req.SetPathValue("id", "1")
+			req.Header.Set("If-Match", tc.ifMatch)
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -49,7 +115,8 @@ func TestHandleUpdateBlog(t *testing.T) {
 			logger := slog.Default()
 
 			userUpdater := new(mock.BlogUpdater)
-			userUpdater.On("UpdateBlog", context.Background(), uint64(1), tc.input).Return(tc.wantBody, nil)
+			userUpdater.On("ReadBlog", req.Context(), uint64(1), models.BlogExpand{}, false).Return(models.Blog{ID: 1, AuthorID: 1}, nil)
+			userUpdater.On("UpdateBlog", req.Context(), uint64(1), tc.input, tc.actor.ID).Return(tc.wantBody, tc.mockError)
 
 			// Call the handler
 			handler := HandleUpdateBlog(logger, userUpdater)
@@ -59,6 +126,9 @@ func TestHandleUpdateBlog(t *testing.T) {
 			if rec.Code != tc.wantStatus {
 				t.Errorf("want status %d, got %d", tc.wantStatus, rec.Code)
 			}
+			if tc.wantBody.Version != 0 && rec.Header().Get("ETag") != etag(tc.wantBody.Version) {
+				t.Errorf("want ETag %s, got %s", etag(tc.wantBody.Version), rec.Header().Get("ETag"))
+			}
 
 			// // Check the body
 			// if strings.Trim(rec.Body.String(), "\n") != fmt.Sprintf("%+v", tc.wantBody) {
@@ -8,13 +8,14 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/chickey/blog/internal/authz"
 	"github.com/chickey/blog/internal/models"
 )
 
 // commentUpdater represents a type capable of updating a comment and
 // returning it or an error.
 type commentUpdater interface {
-	UpdateComment(ctx context.Context, patch models.Comment) (models.Comment, error)
+	UpdateComment(ctx context.Context, patch models.Comment, editorId uint) (models.Comment, error)
 }
 
 // @Summary		Update Comment
@@ -22,18 +23,31 @@ type commentUpdater interface {
 // @Tags			comment
 // @Accept			json
 // @Produce		json
+// @Security		BearerAuth
 // @Param			author_id	query		string			false	"Author Id"
 // @Param			blog_id		query		string			false	"Blog Id"
+// @Param			id			query		string			false	"Comment Id, when the author has several comments on the blog"
 // @Param			request		body		CommentRequest	true	"Blog to Create"
-// @Success		200			{object}	models.Comment
-// @Failure		400			{object}	string
-// @Failure		404			{object}	string
-// @Failure		500			{object}	string
+// @Param			If-Match	header		string			false	"ETag of the version of the comment the update is made against"
+// @Success		200			{object}	CommentResponse
+// @Header			200			{string}	ETag	"Version of the updated comment"
+// @Failure		400			{object}	ProblemResponse
+// @Failure		401			{object}	ProblemResponse
+// @Failure		403			{object}	ProblemResponse
+// @Failure		404			{object}	ProblemResponse
+// @Failure		412			{object}	ProblemResponse
+// @Failure		422			{object}	ProblemResponse
+// @Failure		500			{object}	ProblemResponse
 // @Router			/comment  [PUT]
 func HandleUpdateComment(logger *slog.Logger, commentUpdater commentUpdater) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
 		// Validate query params
 		userIdStr := r.URL.Query().Get("author_id")
 		blogIdStr := r.URL.Query().Get("blog_id")
@@ -51,7 +65,7 @@ func HandleUpdateComment(logger *slog.Logger, commentUpdater commentUpdater) htt
 					slog.String("error", err.Error()),
 				)
 
-				http.Error(w, "Invalid User ID", http.StatusBadRequest)
+				writeProblem(w, r, http.StatusBadRequest, "Invalid User ID", nil)
 				return
 			}
 		}
@@ -68,11 +82,35 @@ func HandleUpdateComment(logger *slog.Logger, commentUpdater commentUpdater) htt
 					slog.String("error", err.Error()),
 				)
 
-				http.Error(w, "Invalid Blog ID", http.StatusBadRequest)
+				writeProblem(w, r, http.StatusBadRequest, "Invalid Blog ID", nil)
+				return
+			}
+		}
+
+		var commentId int
+
+		if commentIdStr := r.URL.Query().Get("id"); commentIdStr != "" {
+			commentId, err = strconv.Atoi(commentIdStr)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to get valid Comment Id from query param",
+					slog.String("id", commentIdStr),
+					slog.String("error", err.Error()),
+				)
+
+				writeProblem(w, r, http.StatusBadRequest, "Invalid Comment ID", nil)
 				return
 			}
 		}
 
+		// The ETag of a comment names the comment the query picks out, so it
+		// only matches if that is still the one being updated
+		version, ok := parseIfMatch(w, r)
+		if !ok {
+			return
+		}
+
 		// Read request body
 		request, problems, err := decodeValid[*CommentRequest](r)
 
@@ -82,7 +120,8 @@ func HandleUpdateComment(logger *slog.Logger, commentUpdater commentUpdater) htt
 				"failed to decode request",
 				slog.String("error", err.Error()))
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
 		}
 		if len(problems) > 0 {
 			logger.ErrorContext(
@@ -90,7 +129,9 @@ func HandleUpdateComment(logger *slog.Logger, commentUpdater commentUpdater) htt
 				"Validation error",
 				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
 			)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
 		}
 
 		// Validae query params and body matches
@@ -99,17 +140,29 @@ func HandleUpdateComment(logger *slog.Logger, commentUpdater commentUpdater) htt
 				r.Context(),
 				"Validation error: Query Param does not match Request Body",
 			)
-			http.Error(w, "Invalid Request", http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, "Query parameters do not match the request body", map[string]string{
+				"author_id": "must match UserID in the request body",
+				"blog_id":   "must match BlogID in the request body",
+			})
+			return
 		}
 
 		modelRequest := models.Comment{
+			ID:      uint(commentId),
 			UserID:  request.UserID,
 			BlogID:  request.BlogID,
 			Message: request.Message,
+			Version: version,
+		}
+
+		// Only the author or an admin may update a comment
+		if !authz.CanComment(actor, authz.Update, modelRequest) {
+			writeForbidden(w, r)
+			return
 		}
 
 		// Update the comment
-		comment, err := commentUpdater.UpdateComment(ctx, modelRequest)
+		comment, err := commentUpdater.UpdateComment(ctx, modelRequest, actor.ID)
 		if err != nil {
 			logger.ErrorContext(
 				r.Context(),
@@ -117,12 +170,16 @@ func HandleUpdateComment(logger *slog.Logger, commentUpdater commentUpdater) htt
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
+		setETag(w, comment.Version)
+
 		// Convert our models.Comment domain model into a response model.
 		response := CommentResponse{
+			ID:          comment.ID,
+			ParentID:    comment.ParentID,
 			BlogID:      comment.BlogID,
 			UserID:      comment.UserID,
 			Message:     comment.Message,
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http/httptest"
@@ -15,11 +14,14 @@ import (
 
 func TestHandleUpdateComment(t *testing.T) {
 	tests := map[string]struct {
+		actor      models.User
+		target     string
 		wantStatus int
 		wantBody   models.Comment
 		input      models.Comment
 	}{
 		"happy path": {
+			actor:      testUser,
 			wantStatus: 200,
 			wantBody: models.Comment{
 				BlogID:      1,
@@ -33,12 +35,53 @@ func TestHandleUpdateComment(t *testing.T) {
 				Message: "Good blog",
 			},
 		},
+		"by id": {
+			actor:      testUser,
+			target:     "/comments?author_id=1&blog_id=1&id=4",
+			wantStatus: 200,
+			wantBody: models.Comment{
+				ID:          4,
+				ParentID:    3,
+				BlogID:      1,
+				UserID:      1,
+				Message:     "Agreed",
+				CreatedDate: time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC),
+			},
+			input: models.Comment{
+				ID:      4,
+				BlogID:  1,
+				UserID:  1,
+				Message: "Agreed",
+			},
+		},
+		"not the author": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+			input: models.Comment{
+				BlogID:  1,
+				UserID:  1,
+				Message: "Good blog",
+			},
+		},
+		"admin": {
+			actor:      testAdmin,
+			wantStatus: 200,
+			input: models.Comment{
+				BlogID:  1,
+				UserID:  1,
+				Message: "Good blog",
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			// Create a new request
+			if tc.target == "" {
+				tc.target = "/comments?author_id=1&blog_id=1"
+			}
 			reqBody, _ := json.Marshal(tc.input)
-			req := httptest.NewRequest("PUT", "/comments?author_id=1&blog_id=1", bytes.NewBuffer(reqBody))
+			req := httptest.NewRequest("PUT", tc.target, bytes.NewBuffer(reqBody))
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -47,7 +90,7 @@ func TestHandleUpdateComment(t *testing.T) {
 			logger := slog.Default()
 
 			userUpdater := new(mock.CommentUpdater)
-			userUpdater.On("UpdateComment", context.Background(), tc.input).Return(tc.wantBody, nil)
+			userUpdater.On("UpdateComment", req.Context(), tc.input, tc.actor.ID).Return(tc.wantBody, nil)
 
 			// Call the handler
 			handler := HandleUpdateComment(logger, userUpdater)
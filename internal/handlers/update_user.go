@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/chickey/blog/internal/authz"
 	"github.com/chickey/blog/internal/models"
 )
 
@@ -22,17 +23,29 @@ type userUpdater interface {
 //	@Tags			user
 //	@Accept			json
 //	@Produce		json
+//	@Security		BearerAuth
 //	@Param			id		path		string		true	"User ID"
 //	@Param			request	body		UserRequest	true	"User to Create"
-//	@Success		200		{object}	models.User
-//	@Failure		400		{object}	string
-//	@Failure		404		{object}	string
-//	@Failure		500		{object}	string
+//	@Param			If-Match	header	string	false	"ETag of the version of the user the update is made against"
+//	@Success		200		{object}	UserResponse
+//	@Header			200		{string}	ETag	"Version of the updated user"
+//	@Failure		400		{object}	ProblemResponse
+//	@Failure		401		{object}	ProblemResponse
+//	@Failure		403		{object}	ProblemResponse
+//	@Failure		404		{object}	ProblemResponse
+//	@Failure		409		{object}	ProblemResponse
+//	@Failure		412		{object}	ProblemResponse
+//	@Failure		500		{object}	ProblemResponse
 //	@Router			/user/{id}  [PUT]
 func HandleUpdateUser(logger *slog.Logger, userUpdater userUpdater) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		actor, ok := actorFromRequest(w, r)
+		if !ok {
+			return
+		}
+
 		// Read id from path parameters
 		idStr := r.PathValue("id")
 
@@ -46,7 +59,18 @@ func HandleUpdateUser(logger *slog.Logger, userUpdater userUpdater) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			writeProblem(w, r, http.StatusBadRequest, "Invalid ID", nil)
+			return
+		}
+
+		// Users may only update their own account unless they are an admin
+		if !authz.CanUser(actor, authz.Update, models.User{ID: uint(id)}) {
+			writeForbidden(w, r)
+			return
+		}
+
+		version, ok := parseIfMatch(w, r)
+		if !ok {
 			return
 		}
 
@@ -59,7 +83,8 @@ func HandleUpdateUser(logger *slog.Logger, userUpdater userUpdater) http.Handler
 				"failed to decode request",
 				slog.String("error", err.Error()))
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeProblem(w, r, http.StatusBadRequest, "Request body could not be decoded", nil)
+			return
 		}
 		if len(problems) > 0 {
 			logger.ErrorContext(
@@ -67,12 +92,16 @@ func HandleUpdateUser(logger *slog.Logger, userUpdater userUpdater) http.Handler
 				"Validation error",
 				slog.String("Validation errors: ", fmt.Sprintf("%#v", problems)),
 			)
+
+			writeProblem(w, r, http.StatusUnprocessableEntity, "Request failed validation", problems)
+			return
 		}
 
 		modelRequest := models.User{
 			Name:     request.Name,
 			Email:    request.Email,
 			Password: request.Password,
+			Version:  version,
 		}
 
 		// Update the user
@@ -84,16 +113,17 @@ func HandleUpdateUser(logger *slog.Logger, userUpdater userUpdater) http.Handler
 				slog.String("error", err.Error()),
 			)
 
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			writeServiceError(w, r, err)
 			return
 		}
 
+		setETag(w, user.Version)
+
 		// Convert our models.User domain model into a response model.
 		response := UserResponse{
-			ID:       user.ID,
-			Name:     user.Name,
-			Email:    user.Email,
-			Password: user.Password,
+			ID:    user.ID,
+			Name:  user.Name,
+			Email: user.Email,
 		}
 
 		// Encode the response model as JSON
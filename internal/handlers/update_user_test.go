@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"log/slog"
 	"net/http/httptest"
@@ -14,11 +13,13 @@ import (
 
 func TestHandleUpdateUser(t *testing.T) {
 	tests := map[string]struct {
+		actor      models.User
 		wantStatus int
 		wantBody   models.User
 		input      models.User
 	}{
 		"happy path": {
+			actor:      testUser,
 			wantStatus: 200,
 			wantBody: models.User{
 				ID:       1,
@@ -32,6 +33,24 @@ func TestHandleUpdateUser(t *testing.T) {
 				Password: "password123!",
 			},
 		},
+		"someone else": {
+			actor:      testOtherUser,
+			wantStatus: 403,
+			input: models.User{
+				Name:     "john",
+				Email:    "john@mail.com",
+				Password: "password123!",
+			},
+		},
+		"admin": {
+			actor:      testAdmin,
+			wantStatus: 200,
+			input: models.User{
+				Name:     "john",
+				Email:    "john@mail.com",
+				Password: "password123!",
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -39,6 +58,7 @@ func TestHandleUpdateUser(t *testing.T) {
 			reqBody, _ := json.Marshal(tc.input)
 			req := httptest.NewRequest("PUT", "/users", bytes.NewBuffer(reqBody))
 			req.SetPathValue("id", "1")
+			req = withActor(req, tc.actor)
 
 			// Create a new response recorder
 			rec := httptest.NewRecorder()
@@ -47,7 +67,7 @@ func TestHandleUpdateUser(t *testing.T) {
 			logger := slog.Default()
 
 			userUpdater := new(mock.UserUpdater)
-			userUpdater.On("UpdateUser", context.Background(), uint64(1), tc.input).Return(tc.wantBody, nil)
+			userUpdater.On("UpdateUser", req.Context(), uint64(1), tc.input).Return(tc.wantBody, nil)
 
 			// Call the handler
 			handler := HandleUpdateUser(logger, userUpdater)
@@ -1,9 +1,33 @@
 package handlers
 
-// createUserResponse represents the response for creating a user.
+import (
+	"time"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// createUserResponse represents the response for creating a user. DeletedAt
+// is only set on deleted users, which are only returned to admins who ask for
+// them.
 type UserResponse struct {
-	ID       uint   `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	DeletedAt *time.Time `json:"deletedat,omitempty"`
+}
+
+// UserRefResponse represents a user embedded in an expanded response.
+type UserRefResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// newUserRefResponse converts ref into a response model, or nil if the user
+// wasn't expanded.
+func newUserRefResponse(ref models.UserRef) *UserRefResponse {
+	if ref.ID == 0 {
+		return nil
+	}
+
+	return &UserRefResponse{ID: ref.ID, Name: ref.Name}
 }
@@ -0,0 +1,22 @@
+package handlers
+
+import "context"
+
+// VoteRequest represents the request for voting on a Blog. Rating is from 0
+// to 10 and is required.
+type VoteRequest struct {
+	Rating *int `json:"rating"`
+}
+
+func (r *VoteRequest) Valid(ctx context.Context) map[string]string {
+
+	problems := make(map[string]string)
+
+	if r.Rating == nil {
+		problems["Rating"] = "Rating is required"
+	} else if *r.Rating < 0 || *r.Rating > 10 {
+		problems["Rating"] = "Rating cannot be less than 0 or greater than 10"
+	}
+
+	return problems
+}
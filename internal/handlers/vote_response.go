@@ -0,0 +1,10 @@
+package handlers
+
+// VoteResponse represents a user's vote on a blog and the blog's resulting
+// score. Rating is left out once the vote has been withdrawn.
+type VoteResponse struct {
+	BlogID uint    `json:"blogid"`
+	UserID uint    `json:"userid"`
+	Rating *int    `json:"rating,omitempty"`
+	Score  float32 `json:"score"`
+}
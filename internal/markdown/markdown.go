@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	// converter renders GitHub flavoured Markdown. Raw HTML in the source is
+	// omitted rather than passed through.
+	converter = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy allows the formatting, links and images Markdown produces and
+	// strips anything that could run script.
+	policy = bluemonday.UGCPolicy()
+
+	// textPolicy strips every tag, leaving plain text.
+	textPolicy = bluemonday.StrictPolicy()
+)
+
+// ToHTML renders Markdown source as sanitized HTML that is safe to embed in a
+// page.
+func ToHTML(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(source), &buf); err != nil {
+		return "", fmt.Errorf("[in markdown.ToHTML] failed to render: %w", err)
+	}
+
+	return policy.Sanitize(buf.String()), nil
+}
+
+// Excerpt returns the plain text of Markdown source, cut at a word boundary
+// to at most maxLength characters. An ellipsis is appended when the text is
+// cut.
+func Excerpt(source string, maxLength int) (string, error) {
+	rendered, err := ToHTML(source)
+	if err != nil {
+		return "", fmt.Errorf("[in markdown.Excerpt] %w", err)
+	}
+
+	text := strings.Join(strings.Fields(html.UnescapeString(textPolicy.Sanitize(rendered))), " ")
+	if utf8.RuneCountInString(text) <= maxLength {
+		return text, nil
+	}
+
+	// Cut at the last space that leaves room for the ellipsis, or mid-word if
+	// there is none.
+	runes := []rune(text)
+	if i := strings.LastIndex(string(runes[:maxLength]), " "); i > 0 {
+		return string(runes[:maxLength])[:i] + "…", nil
+	}
+
+	return string(runes[:maxLength-1]) + "…", nil
+}
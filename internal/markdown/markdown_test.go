@@ -0,0 +1,76 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToHTML(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"formatting": {
+			input:    "# Title\n\nSome *emphasis* and `code`.",
+			expected: "<h1>Title</h1>\n<p>Some <em>emphasis</em> and <code>code</code>.</p>\n",
+		},
+		"raw html is omitted": {
+			input:    "<script>alert(1)</script>\n\nText",
+			expected: "\n<p>Text</p>\n",
+		},
+		"script links are removed": {
+			input:    "[click](javascript:alert(1))",
+			expected: "<p>click</p>\n",
+		},
+		"links are marked nofollow": {
+			input:    "[site](https://example.com)",
+			expected: `<p><a href="https://example.com" rel="nofollow">site</a></p>` + "\n",
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			output, err := ToHTML(tc.input)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if output != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, output)
+			}
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	testcases := map[string]struct {
+		input     string
+		maxLength int
+		expected  string
+	}{
+		"short text is kept": {
+			input:     "# Title\n\nFish & *chips*.",
+			maxLength: 50,
+			expected:  "Title Fish & chips.",
+		},
+		"cut at a word": {
+			input:     "The quick brown fox jumps over the lazy dog",
+			maxLength: 20,
+			expected:  "The quick brown fox…",
+		},
+		"cut inside a long word": {
+			input:     strings.Repeat("a", 30),
+			maxLength: 10,
+			expected:  strings.Repeat("a", 9) + "…",
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			output, err := Excerpt(tc.input, tc.maxLength)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if output != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, output)
+			}
+		})
+	}
+}
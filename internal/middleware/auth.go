@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// authenticator represents a type capable of validating an access token and
+// returning the user and session it was issued for.
+type authenticator interface {
+	Authenticate(ctx context.Context, token string) (models.User, models.Session, error)
+}
+
+type userKey struct{}
+
+type sessionKey struct{}
+
+// Authenticate is a middleware that validates the bearer token in the
+// Authorization header and stores the authenticated user and session in the
+// request context. Requests without an Authorization header pass through
+// anonymously; requests with a token that is not accepted are rejected with a
+// 401. Use RequireAuth on routes that must not be anonymous.
+func Authenticate(logger *slog.Logger, auth authenticator) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				writeUnauthorized(w, r, "The Authorization header must hold a bearer token.")
+				return
+			}
+
+			user, session, err := auth.Authenticate(r.Context(), token)
+			if err != nil {
+				logger.InfoContext(
+					r.Context(),
+					"rejected access token",
+					slog.String("error", err.Error()),
+				)
+
+				writeUnauthorized(w, r, "The access token is invalid or has expired.")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user, session)))
+		})
+	}
+}
+
+// RequireAuth is a middleware that rejects requests that were not
+// authenticated by the Authenticate middleware with a 401.
+func RequireAuth() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := UserFromContext(r.Context()); !ok {
+				writeUnauthorized(w, r, "This request requires an access token.")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithUser returns a copy of ctx carrying the authenticated user and session.
+func WithUser(ctx context.Context, user models.User, session models.Session) context.Context {
+	ctx = context.WithValue(ctx, userKey{}, user)
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// UserFromContext returns the user stored by the Authenticate middleware and
+// whether there is one.
+func UserFromContext(ctx context.Context) (models.User, bool) {
+	user, ok := ctx.Value(userKey{}).(models.User)
+	return user, ok
+}
+
+// SessionFromContext returns the session stored by the Authenticate
+// middleware and whether there is one.
+func SessionFromContext(ctx context.Context) (models.Session, bool) {
+	session, ok := ctx.Value(sessionKey{}).(models.Session)
+	return session, ok
+}
+
+// writeUnauthorized responds with a 401 problem details body, asking for a
+// bearer token.
+func writeUnauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	writeProblem(w, r, http.StatusUnauthorized, detail)
+}
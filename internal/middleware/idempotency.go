@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// IdempotencyKeyHeader is the header a client sends a key in to make a POST
+// safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses that were replayed for a
+// retried request rather than produced by handling it again.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength caps the length of client supplied keys.
+const maxIdempotencyKeyLength = 255
+
+// replayedHeaders are the response headers stored along with a response and
+// replayed with it. The rest, such as the request id, belong to each request.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// idempotencyStore represents a type capable of reserving idempotency keys
+// and storing the responses to replay for them.
+type idempotencyStore interface {
+	Reserve(ctx context.Context, scope string, key string, fingerprint string) (models.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, key models.IdempotencyKey) error
+	Release(ctx context.Context, scope string, key string) error
+}
+
+// Idempotency is a middleware that makes POST requests carrying an
+// Idempotency-Key header safe to retry. The first request with a key is
+// handled and its response stored. Retries with the same key and the same
+// request get the stored response back, a retry made while the first request
+// is still being handled is rejected with a 409, and reusing a key for a
+// different request is rejected with a 422. Keys are scoped to the caller, so
+// it must run after Authenticate. Anonymous callers can't be told apart, so
+// their keys are scoped to the request itself: a retry with the same key and
+// request is replayed, while a different request with the same key is handled
+// as a new one. Server errors aren't stored, so a request that failed with one
+// can be retried with the same key.
+func Idempotency(logger *slog.Logger, store idempotencyStore) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !validIdempotencyKey(key) {
+				writeProblem(w, r, http.StatusBadRequest, fmt.Sprintf(
+					"The Idempotency-Key header must be at most %d printable characters.",
+					maxIdempotencyKeyLength,
+				))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeProblem(w, r, http.StatusBadRequest, "The request body could not be read.")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := fingerprintRequest(r, body)
+			scope := idempotencyScope(r, fingerprint)
+
+			stored, reserved, err := store.Reserve(r.Context(), scope, key, fingerprint)
+			if err != nil {
+				logger.ErrorContext(
+					r.Context(),
+					"failed to reserve idempotency key",
+					slog.String("error", err.Error()),
+				)
+
+				writeProblem(w, r, http.StatusInternalServerError, "The Idempotency-Key could not be checked.")
+				return
+			}
+
+			if !reserved {
+				switch {
+				case stored.Fingerprint != fingerprint:
+					writeProblem(w, r, http.StatusUnprocessableEntity, "The Idempotency-Key was already used for a different request.")
+				case stored.StatusCode == 0:
+					w.Header().Set("Retry-After", "1")
+					writeProblem(w, r, http.StatusConflict, "A request with this Idempotency-Key is still being handled.")
+				default:
+					for name, values := range stored.Header {
+						w.Header()[name] = values
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(stored.StatusCode)
+					_, _ = w.Write(stored.Body)
+				}
+				return
+			}
+
+			// The response is stored even if the client has gone away, so
+			// that its retry is answered
+			ctx := context.WithoutCancel(r.Context())
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				if err := store.Release(ctx, scope, key); err != nil {
+					logger.ErrorContext(
+						ctx,
+						"failed to release idempotency key",
+						slog.String("error", err.Error()),
+					)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.statusCode >= http.StatusInternalServerError {
+				return
+			}
+
+			header := make(map[string][]string)
+			for _, name := range replayedHeaders {
+				if values := recorder.Header().Values(name); len(values) > 0 {
+					header[name] = values
+				}
+			}
+
+			err = store.Complete(ctx, models.IdempotencyKey{
+				Scope:      scope,
+				Key:        key,
+				StatusCode: recorder.statusCode,
+				Header:     header,
+				Body:       recorder.body.Bytes(),
+			})
+			if err != nil {
+				logger.ErrorContext(
+					ctx,
+					"failed to store idempotent response",
+					slog.String("error", err.Error()),
+				)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// responseRecorder passes a response through to the client while keeping a
+// copy of its status code and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.statusCode = statusCode
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// idempotencyScope returns the scope of the keys sent by the caller of r, so
+// that one caller's keys never match another's. Anonymous callers have no
+// scope of their own, so theirs is the request's fingerprint, and a key only
+// matches a retry of the same request.
+func idempotencyScope(r *http.Request, fingerprint string) string {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		return "anonymous:" + fingerprint
+	}
+
+	return fmt.Sprintf("user:%d", user.ID)
+}
+
+// fingerprintRequest returns a hash of the method, target and body of r, which
+// tells a retry apart from a different request sent with the same key.
+func fingerprintRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.RequestURI())
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// validIdempotencyKey reports whether a client supplied key is safe to store
+// and log.
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for _, c := range key {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// memoryStore is an idempotencyStore that keeps its keys in memory.
+type memoryStore struct {
+	mu   sync.Mutex
+	keys map[string]models.IdempotencyKey
+}
+
+func (s *memoryStore) Reserve(ctx context.Context, scope string, key string, fingerprint string) (models.IdempotencyKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.keys[scope+" "+key]; ok {
+		return existing, false, nil
+	}
+
+	reserved := models.IdempotencyKey{Scope: scope, Key: key, Fingerprint: fingerprint}
+	s.keys[scope+" "+key] = reserved
+	return reserved, true, nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, key models.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key.Fingerprint = s.keys[key.Scope+" "+key.Key].Fingerprint
+	s.keys[key.Scope+" "+key.Key] = key
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, scope string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keys[scope+" "+key].StatusCode == 0 {
+		delete(s.keys, scope+" "+key)
+	}
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	type request struct {
+		actor      *models.User
+		key        string
+		body       string
+		wantStatus int
+		wantBody   string
+		replayed   bool
+	}
+
+	john := &models.User{ID: 1}
+	jane := &models.User{ID: 2}
+
+	testcases := map[string]struct {
+		failFirst bool
+		requests  []request
+	}{
+		"retry is replayed": {
+			requests: []request{
+				{actor: john, key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 1"},
+				{actor: john, key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 1", replayed: true},
+			},
+		},
+		"key reused for a different request": {
+			requests: []request{
+				{actor: john, key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 1"},
+				{actor: john, key: "abc", body: `{"title":"b"}`, wantStatus: 422},
+			},
+		},
+		"keys are scoped to the caller": {
+			requests: []request{
+				{actor: john, key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 1"},
+				{actor: jane, key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 2"},
+			},
+		},
+		"anonymous retry is replayed": {
+			requests: []request{
+				{key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 1"},
+				{key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 1", replayed: true},
+			},
+		},
+		"anonymous key reused for a different request": {
+			requests: []request{
+				{key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 1"},
+				{key: "abc", body: `{"title":"b"}`, wantStatus: 201, wantBody: "created 2"},
+			},
+		},
+		"requests without a key are repeated": {
+			requests: []request{
+				{actor: john, body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 1"},
+				{actor: john, body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 2"},
+			},
+		},
+		"server errors can be retried": {
+			failFirst: true,
+			requests: []request{
+				{actor: john, key: "abc", body: `{"title":"a"}`, wantStatus: 500},
+				{actor: john, key: "abc", body: `{"title":"a"}`, wantStatus: 201, wantBody: "created 2"},
+			},
+		},
+		"invalid key": {
+			requests: []request{
+				{actor: john, key: "a b", body: `{"title":"a"}`, wantStatus: 400},
+			},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			store := &memoryStore{keys: make(map[string]models.IdempotencyKey)}
+
+			created := 0
+			handler := Idempotency(slog.Default(), store)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				created++
+				if tc.failFirst && created == 1 {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.Header().Set("Location", fmt.Sprintf("/api/blog/%d", created))
+				w.WriteHeader(http.StatusCreated)
+				_, _ = fmt.Fprintf(w, "created %d", created)
+			}))
+
+			for i, req := range tc.requests {
+				r := httptest.NewRequest("POST", "/api/blog", strings.NewReader(req.body))
+				if req.key != "" {
+					r.Header.Set(IdempotencyKeyHeader, req.key)
+				}
+				if req.actor != nil {
+					r = r.WithContext(WithUser(r.Context(), *req.actor, models.Session{}))
+				}
+				rec := httptest.NewRecorder()
+
+				handler.ServeHTTP(rec, r)
+
+				if rec.Code != req.wantStatus {
+					t.Errorf("request %d: want status %d, got %d", i, req.wantStatus, rec.Code)
+				}
+				if req.wantBody != "" && rec.Body.String() != req.wantBody {
+					t.Errorf("request %d: want body %q, got %q", i, req.wantBody, rec.Body.String())
+				}
+				if replayed := rec.Header().Get(IdempotentReplayedHeader) == "true"; replayed != req.replayed {
+					t.Errorf("request %d: want replayed %v, got %v", i, req.replayed, replayed)
+				}
+				if req.replayed && rec.Header().Get("Location") != "/api/blog/1" {
+					t.Errorf("request %d: want the stored Location, got %q", i, rec.Header().Get("Location"))
+				}
+			}
+		})
+	}
+}
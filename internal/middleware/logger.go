@@ -37,6 +37,7 @@ func Logger(logger *slog.Logger) Middleware {
 				slog.String("path", r.URL.Path),
 				slog.String("duration", time.Since(start).String()),
 				slog.Int("status", wrapped.statusCode),
+				slog.String("request_id", RequestIDFromContext(r.Context())),
 			)
 		})
 	}
@@ -1,6 +1,25 @@
 package middleware
 
-import "net/http"
+import (
+	"encoding/json"
+	"net/http"
+)
 
 // Middleware is a function that wraps an http.Handler.
 type Middleware func(next http.Handler) http.Handler
+
+// writeProblem responds with a problem details body. It mirrors the handlers
+// package so clients see one error format for every failure.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"type":       "about:blank",
+		"title":      http.StatusText(status),
+		"status":     status,
+		"detail":     detail,
+		"instance":   r.URL.RequestURI(),
+		"request_id": RequestIDFromContext(r.Context()),
+	})
+}
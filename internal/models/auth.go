@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Session is a login session. Access and refresh tokens are issued for a
+// session, and revoking it signs the user out.
+type Session struct {
+	ID        string
+	UserID    uint
+	ExpiresAt time.Time
+}
+
+// TokenPair holds the signed tokens issued on login or refresh.
+// AccessTokenExpiresAt is when the access token stops being accepted.
+type TokenPair struct {
+	AccessToken          string
+	RefreshToken         string
+	AccessTokenExpiresAt time.Time
+}
@@ -2,10 +2,99 @@ package models
 
 import "time"
 
+// BlogStatus is the stage of the publishing workflow a blog is at. Only
+// published blogs are listed to readers other than their author.
+type BlogStatus string
+
+const (
+	// BlogStatusDraft blogs are being written and are seen only by their
+	// author.
+	BlogStatusDraft BlogStatus = "draft"
+
+	// BlogStatusScheduled blogs are published automatically at PublishAt.
+	BlogStatusScheduled BlogStatus = "scheduled"
+
+	// BlogStatusPublished blogs are visible to everyone.
+	BlogStatusPublished BlogStatus = "published"
+
+	// BlogStatusArchived blogs were published and have since been withdrawn.
+	BlogStatusArchived BlogStatus = "archived"
+)
+
+// Blog is a blog post. Body is Markdown, and Excerpt is a short plain text
+// summary shown in lists. PublishAt is when a scheduled blog will be
+// published, or when a published or archived one was, and is zero otherwise.
+// Category and Tags are slugs, with tags in alphabetical order. Author is only
+// set when blogs are read with BlogExpand.Author. DeletedAt is when the blog
+// was deleted, and is only set when deleted blogs are read on purpose.
+// Version goes up with every update to the blog, and is zero when it wasn't
+// read.
 type Blog struct {
 	ID          uint
 	AuthorID    uint
+	Author      UserRef
 	Title       string
+	Body        string
+	Excerpt     string
+	Category    string
+	Tags        []string
+	Status      BlogStatus
+	PublishAt   time.Time
 	Score       float32
 	CreatedDate time.Time
+	DeletedAt   time.Time
+	Version     uint
+}
+
+// BlogPatch is a partial update of a blog. Nil fields are left as they are,
+// and the rest are applied the way a full update applies them. A set Version
+// makes the update conditional on the blog still being at it.
+type BlogPatch struct {
+	AuthorID  *uint
+	Title     *string
+	Body      *string
+	Excerpt   *string
+	Category  *string
+	Tags      *[]string
+	Status    *BlogStatus
+	PublishAt *time.Time
+	Version   uint
+}
+
+// BlogRef is the identifying part of a blog, embedded in comments on it when
+// those are expanded.
+type BlogRef struct {
+	ID    uint
+	Title string
+}
+
+// BlogExpand lists the related records read together with blogs.
+type BlogExpand struct {
+	Author bool
+}
+
+// BlogFilter restricts which blogs are listed. Zero values don't restrict the
+// list. Title matches blogs whose title contains it, ignoring case, and the
+// created range includes CreatedAfter but excludes CreatedBefore.
+//
+// Tags matches blogs carrying any of them, or all of them when TagMatch is
+// TagMatchAll.
+//
+// Blogs that aren't published are only ever listed to their author, the
+// viewer with ViewerID, unless AllBlogs is set. A zero ViewerID is an
+// anonymous viewer. Deleted blogs are left out unless IncludeDeleted is set.
+type BlogFilter struct {
+	ViewerID       uint
+	AllBlogs       bool
+	Status         BlogStatus
+	AuthorID       uint
+	Category       string
+	Tags           []string
+	TagMatch       TagMatch
+	Title          string
+	MinScore       *float32
+	MaxScore       *float32
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	IncludeDeleted bool
 }
@@ -0,0 +1,22 @@
+package models
+
+// BulkMode decides what happens to the rest of a bulk write when some of its
+// items fail.
+type BulkMode string
+
+const (
+	// BulkAllOrNothing writes every item or none of them. If any item fails,
+	// the items that would have succeeded are rolled back with it.
+	BulkAllOrNothing BulkMode = "all_or_nothing"
+
+	// BulkBestEffort writes every item that can be written, and leaves out
+	// the items that fail.
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+// BulkResult is the outcome of one item of a bulk write. Err is nil if the
+// item was written, in which case Value is the item as it was stored.
+type BulkResult[T any] struct {
+	Value T
+	Err   error
+}
@@ -2,9 +2,48 @@ package models
 
 import "time"
 
+// Comment is a comment on a blog. ParentID is the comment it replies to, and
+// is zero for comments on the blog itself. User and Blog are only set when
+// comments are read with the matching CommentExpand fields. DeletedAt is when
+// the comment was deleted, and is only set when deleted comments are read on
+// purpose. Version goes up with every update to the comment, and is zero when
+// it wasn't read.
 type Comment struct {
+	ID          uint
+	ParentID    uint
 	UserID      uint
+	User        UserRef
 	BlogID      uint
+	Blog        BlogRef
 	Message     string
 	CreatedDate time.Time
+	DeletedAt   time.Time
+	Version     uint
+}
+
+// CommentThread is a comment together with the replies to it, oldest first.
+type CommentThread struct {
+	Comment
+	Replies []CommentThread
+}
+
+// CommentExpand lists the related records read together with comments.
+type CommentExpand struct {
+	User bool
+	Blog bool
+}
+
+// CommentFilter picks out the comments to list. UserID and BlogID restrict
+// them to the comments by a user or on a blog when set.
+//
+// Comments on blogs that aren't published are only ever listed to the blog's
+// author, the viewer with ViewerID, and comments on deleted blogs aren't
+// listed at all, unless AllBlogs is set. A zero ViewerID is an anonymous
+// viewer. Deleted comments are left out unless IncludeDeleted is set.
+type CommentFilter struct {
+	UserID         uint
+	BlogID         uint
+	ViewerID       uint
+	AllBlogs       bool
+	IncludeDeleted bool
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// IdempotencyKey is a key a client sent with a request so that retrying the
+// request replays its response rather than repeating it. Keys are scoped to
+// the caller, and Fingerprint identifies the request the key was first used
+// for. StatusCode, Header and Body are the response to replay, and are zero
+// while the first request is still being handled.
+type IdempotencyKey struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	Header      map[string][]string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
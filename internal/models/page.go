@@ -0,0 +1,25 @@
+package models
+
+// PageRequest describes which part of a list to return. Lists are paged by
+// Offset, or by keyset when Cursor is set, in which case Offset is ignored.
+type PageRequest struct {
+	Limit        int
+	Offset       int
+	Cursor       string
+	IncludeTotal bool
+}
+
+// Page is one page of a list. NextCursor and PrevCursor are empty when there
+// is no page in that direction, and Total is only set when it was requested.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	PrevCursor string
+	Total      *int
+}
+
+// SortField is one field of the order a list is sorted in.
+type SortField struct {
+	Field string
+	Desc  bool
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// BlogRevision is the content of a blog as it was before one of its updates.
+// EditorID is the user who made the update, and is zero once they have been
+// deleted. CreatedDate is when the update was made. Diff is a line diff from
+// the revision to the content the update left, covering the title, excerpt,
+// category and tags as header lines above the body.
+type BlogRevision struct {
+	ID          uint
+	BlogID      uint
+	EditorID    uint
+	Title       string
+	Body        string
+	Excerpt     string
+	Category    string
+	Tags        []string
+	Diff        string
+	CreatedDate time.Time
+}
+
+// CommentRevision is the message of a comment as it was before one of its
+// updates. UserID is the comment's author, and EditorID the user who made the
+// update, which is zero once they have been deleted. Diff is a line diff from
+// the revision's message to the one the update left.
+type CommentRevision struct {
+	ID          uint
+	CommentID   uint
+	UserID      uint
+	BlogID      uint
+	EditorID    uint
+	Message     string
+	Diff        string
+	CreatedDate time.Time
+}
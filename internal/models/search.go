@@ -0,0 +1,23 @@
+package models
+
+// SearchResultType names the kind of content a search result was found in.
+type SearchResultType string
+
+const (
+	SearchResultBlog    SearchResultType = "blog"
+	SearchResultComment SearchResultType = "comment"
+)
+
+// SearchResult is a blog or comment matching a search. For a blog, UserID is
+// its author and CommentID is zero; for a comment, CommentID identifies it and
+// BlogID and UserID are the blog it is on and its author. Headline is the
+// matching text, escaped for HTML, with the matched words wrapped in <mark>
+// tags.
+type SearchResult struct {
+	Type      SearchResultType
+	BlogID    uint
+	UserID    uint
+	CommentID uint
+	Headline  string
+	Rank      float32
+}
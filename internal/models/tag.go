@@ -0,0 +1,19 @@
+package models
+
+// Tag is a label blogs are grouped by. BlogCount is the number of published
+// blogs carrying it.
+type Tag struct {
+	Name      string
+	BlogCount int
+}
+
+// TagMatch is how a list of tags filters blogs.
+type TagMatch string
+
+const (
+	// TagMatchAny matches blogs carrying at least one of the tags.
+	TagMatchAny TagMatch = "any"
+
+	// TagMatchAll matches blogs carrying every one of the tags.
+	TagMatchAll TagMatch = "all"
+)
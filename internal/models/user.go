@@ -1,8 +1,44 @@
 package models
 
+import "time"
+
+// Role is the role of a user, deciding what they may do to resources they
+// don't own.
+type Role string
+
+const (
+	// RoleUser may only change their own account, blogs and comments.
+	RoleUser Role = "user"
+
+	// RoleAdmin may change any account, blog or comment.
+	RoleAdmin Role = "admin"
+)
+
+// User is an account. DeletedAt is when the user was deleted, and is only set
+// when deleted users are read on purpose. Version goes up with every update
+// to the user, and is zero when it wasn't read.
 type User struct {
-	ID       uint
-	Name     string
-	Email    string
-	Password string
+	ID        uint
+	Name      string
+	Email     string
+	Password  string
+	Role      Role
+	DeletedAt time.Time
+	Version   uint
+}
+
+// UserPatch is a partial update of a user. Nil fields are left as they are.
+// A set Version makes the update conditional on the user still being at it.
+type UserPatch struct {
+	Name     *string
+	Email    *string
+	Password *string
+	Version  uint
+}
+
+// UserRef is the public part of a user, embedded in the records they wrote
+// when those are expanded.
+type UserRef struct {
+	ID   uint
+	Name string
 }
@@ -0,0 +1,9 @@
+package models
+
+// Vote is the rating from 0 to 10 a user gives a blog. Each user has at most
+// one vote per blog, and a blog's score is the mean of its votes.
+type Vote struct {
+	UserID uint
+	BlogID uint
+	Rating int
+}
@@ -0,0 +1,207 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algorithm names a supported password hashing algorithm.
+type Algorithm string
+
+const (
+	// Bcrypt hashes passwords with bcrypt.
+	Bcrypt Algorithm = "bcrypt"
+
+	// Argon2id hashes passwords with argon2id and encodes them in the PHC
+	// string format.
+	Argon2id Algorithm = "argon2id"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+	argon2Prefix     = "$argon2id$"
+)
+
+// ErrUnknownHash is returned when a stored value is not a hash produced by any
+// supported algorithm.
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Params configures how new hashes are produced. Hashes produced with other
+// parameters still verify, but are reported as needing a rehash.
+type Params struct {
+	Algorithm         Algorithm
+	BcryptCost        int
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+}
+
+// Hasher hashes and verifies passwords.
+type Hasher struct {
+	params Params
+}
+
+// NewHasher validates params and returns a Hasher using them.
+func NewHasher(params Params) (*Hasher, error) {
+	switch params.Algorithm {
+	case Bcrypt:
+		if params.BcryptCost < bcrypt.MinCost || params.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf(
+				"[in password.NewHasher] bcrypt cost must be between %d and %d",
+				bcrypt.MinCost,
+				bcrypt.MaxCost,
+			)
+		}
+	case Argon2id:
+		if params.Argon2Memory == 0 || params.Argon2Iterations == 0 || params.Argon2Parallelism == 0 {
+			return nil, fmt.Errorf("[in password.NewHasher] argon2id memory, iterations and parallelism must be positive")
+		}
+	default:
+		return nil, fmt.Errorf("[in password.NewHasher] unsupported algorithm %q", params.Algorithm)
+	}
+
+	return &Hasher{params: params}, nil
+}
+
+// Hash returns an encoded hash of plain using the configured algorithm.
+func (h *Hasher) Hash(plain string) (string, error) {
+	switch h.params.Algorithm {
+	case Argon2id:
+		salt := make([]byte, argon2SaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return "", fmt.Errorf("[in password.Hasher.Hash] failed to generate salt: %w", err)
+		}
+		key := argon2.IDKey(
+			[]byte(plain),
+			salt,
+			h.params.Argon2Iterations,
+			h.params.Argon2Memory,
+			h.params.Argon2Parallelism,
+			argon2KeyLength,
+		)
+		return encodeArgon2id(h.params, salt, key), nil
+	default:
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), h.params.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("[in password.Hasher.Hash] failed to hash password: %w", err)
+		}
+		return string(hash), nil
+	}
+}
+
+// Verify reports whether plain matches the encoded hash. needsRehash is true
+// when the hash matches but was produced with a different algorithm or
+// parameters than the ones currently configured.
+func (h *Hasher) Verify(hash, plain string) (match bool, needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(hash, argon2Prefix):
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false, false, fmt.Errorf("[in password.Hasher.Verify] %w", err)
+		}
+		other := argon2.IDKey(
+			[]byte(plain),
+			salt,
+			params.Argon2Iterations,
+			params.Argon2Memory,
+			params.Argon2Parallelism,
+			uint32(len(key)),
+		)
+		if subtle.ConstantTimeCompare(key, other) != 1 {
+			return false, false, nil
+		}
+		return true, h.params.Algorithm != Argon2id ||
+			params.Argon2Memory != h.params.Argon2Memory ||
+			params.Argon2Iterations != h.params.Argon2Iterations ||
+			params.Argon2Parallelism != h.params.Argon2Parallelism ||
+			len(key) != argon2KeyLength, nil
+	case isBcrypt(hash):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, fmt.Errorf("[in password.Hasher.Verify] %w", err)
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return false, false, fmt.Errorf("[in password.Hasher.Verify] %w", err)
+		}
+		return true, h.params.Algorithm != Bcrypt || cost != h.params.BcryptCost, nil
+	default:
+		return false, false, fmt.Errorf("[in password.Hasher.Verify] %w", ErrUnknownHash)
+	}
+}
+
+// IsHash reports whether s looks like a hash produced by a supported
+// algorithm. It is used to find rows that still hold plaintext passwords.
+func IsHash(s string) bool {
+	return strings.HasPrefix(s, argon2Prefix) || isBcrypt(s)
+}
+
+// isBcrypt reports whether s carries one of the bcrypt version prefixes.
+func isBcrypt(s string) bool {
+	return strings.HasPrefix(s, "$2a$") || strings.HasPrefix(s, "$2b$") || strings.HasPrefix(s, "$2y$")
+}
+
+// encodeArgon2id encodes an argon2id key in the PHC string format, for example
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>.
+func encodeArgon2id(params Params, salt, key []byte) string {
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		params.Argon2Memory,
+		params.Argon2Iterations,
+		params.Argon2Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+// decodeArgon2id parses a PHC encoded argon2id hash.
+func decodeArgon2id(hash string) (Params, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return Params{}, nil, nil, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Params{}, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return Params{}, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	params := Params{Algorithm: Argon2id}
+	_, err := fmt.Sscanf(
+		parts[3],
+		"m=%d,t=%d,p=%d",
+		&params.Argon2Memory,
+		&params.Argon2Iterations,
+		&params.Argon2Parallelism,
+	)
+	if err != nil {
+		return Params{}, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Params{}, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Params{}, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,164 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	testBcrypt = Params{
+		Algorithm:  Bcrypt,
+		BcryptCost: 4,
+	}
+	testArgon2id = Params{
+		Algorithm:         Argon2id,
+		Argon2Memory:      1024,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+	}
+)
+
+func TestHasher_Verify(t *testing.T) {
+	testcases := map[string]struct {
+		hashWith          Params
+		verifyWith        Params
+		input             string
+		expectedMatch     bool
+		expectedNeedsHash bool
+	}{
+		"bcrypt match": {
+			hashWith:      testBcrypt,
+			verifyWith:    testBcrypt,
+			input:         "password123!",
+			expectedMatch: true,
+		},
+		"bcrypt mismatch": {
+			hashWith:   testBcrypt,
+			verifyWith: testBcrypt,
+			input:      "wrong",
+		},
+		"bcrypt cost changed": {
+			hashWith:          testBcrypt,
+			verifyWith:        Params{Algorithm: Bcrypt, BcryptCost: 5},
+			input:             "password123!",
+			expectedMatch:     true,
+			expectedNeedsHash: true,
+		},
+		"argon2id match": {
+			hashWith:      testArgon2id,
+			verifyWith:    testArgon2id,
+			input:         "password123!",
+			expectedMatch: true,
+		},
+		"argon2id mismatch": {
+			hashWith:   testArgon2id,
+			verifyWith: testArgon2id,
+			input:      "wrong",
+		},
+		"argon2id memory changed": {
+			hashWith: testArgon2id,
+			verifyWith: Params{
+				Algorithm:         Argon2id,
+				Argon2Memory:      2048,
+				Argon2Iterations:  1,
+				Argon2Parallelism: 1,
+			},
+			input:             "password123!",
+			expectedMatch:     true,
+			expectedNeedsHash: true,
+		},
+		"bcrypt to argon2id": {
+			hashWith:          testBcrypt,
+			verifyWith:        testArgon2id,
+			input:             "password123!",
+			expectedMatch:     true,
+			expectedNeedsHash: true,
+		},
+		"argon2id to bcrypt": {
+			hashWith:          testArgon2id,
+			verifyWith:        testBcrypt,
+			input:             "password123!",
+			expectedMatch:     true,
+			expectedNeedsHash: true,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			hasher, err := NewHasher(tc.hashWith)
+			if err != nil {
+				t.Fatalf("failed to create hasher: %v", err)
+			}
+			hash, err := hasher.Hash("password123!")
+			if err != nil {
+				t.Fatalf("failed to hash: %v", err)
+			}
+			if !IsHash(hash) {
+				t.Errorf("expected %q to be recognised as a hash", hash)
+			}
+
+			verifier, err := NewHasher(tc.verifyWith)
+			if err != nil {
+				t.Fatalf("failed to create hasher: %v", err)
+			}
+			match, needsRehash, err := verifier.Verify(hash, tc.input)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if match != tc.expectedMatch {
+				t.Errorf("expected match %t, got %t", tc.expectedMatch, match)
+			}
+			if needsRehash != tc.expectedNeedsHash {
+				t.Errorf("expected needsRehash %t, got %t", tc.expectedNeedsHash, needsRehash)
+			}
+		})
+	}
+}
+
+func TestHasher_VerifyUnknownHash(t *testing.T) {
+	hasher, err := NewHasher(testBcrypt)
+	if err != nil {
+		t.Fatalf("failed to create hasher: %v", err)
+	}
+
+	_, _, err = hasher.Verify("password123!", "password123!")
+	if !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("expected %v, got %v", ErrUnknownHash, err)
+	}
+}
+
+func TestHasher_HashArgon2idFormat(t *testing.T) {
+	hasher, err := NewHasher(testArgon2id)
+	if err != nil {
+		t.Fatalf("failed to create hasher: %v", err)
+	}
+
+	hash, err := hasher.Hash("password123!")
+	if err != nil {
+		t.Fatalf("failed to hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("unexpected argon2id encoding %q", hash)
+	}
+}
+
+func TestNewHasher(t *testing.T) {
+	testcases := map[string]struct {
+		input   Params
+		wantErr bool
+	}{
+		"bcrypt":                {input: testBcrypt},
+		"argon2id":              {input: testArgon2id},
+		"bcrypt cost too low":   {input: Params{Algorithm: Bcrypt, BcryptCost: 1}, wantErr: true},
+		"argon2id missing cost": {input: Params{Algorithm: Argon2id}, wantErr: true},
+		"unknown algorithm":     {input: Params{Algorithm: "md5"}, wantErr: true},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewHasher(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("expected error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
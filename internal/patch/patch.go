@@ -0,0 +1,446 @@
+// Package patch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902)
+// documents to JSON objects.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Media types of the patch documents Apply understands.
+const (
+	MergePatch = "application/merge-patch+json"
+	JSONPatch  = "application/json-patch+json"
+)
+
+var (
+	// ErrUnsupportedType is returned for a patch document of a media type
+	// other than MergePatch or JSONPatch.
+	ErrUnsupportedType = errors.New("unsupported patch media type")
+
+	// ErrMalformed is returned when a patch document is not valid JSON of the
+	// shape its media type calls for.
+	ErrMalformed = errors.New("malformed patch")
+
+	// ErrInapplicable is returned when a patch document is well formed but
+	// can't be applied to the document, such as when it removes a member
+	// that isn't there.
+	ErrInapplicable = errors.New("patch cannot be applied")
+
+	// ErrTestFailed is returned when a JSON Patch test operation finds a
+	// value other than the one it expects.
+	ErrTestFailed = errors.New("patch test failed")
+)
+
+// Apply applies patch, a document of mediaType, to doc, which must be a JSON
+// object. The patched object is returned together with the names of its top
+// level members that the patch touched, in alphabetical order. A JSON Patch
+// is applied as a whole or not at all.
+func Apply(mediaType string, doc []byte, patch []byte) ([]byte, []string, error) {
+	var target any
+	if err := decode(doc, &target); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if _, ok := target.(map[string]any); !ok {
+		return nil, nil, fmt.Errorf("%w: document is not an object", ErrInapplicable)
+	}
+
+	var result any
+	var touched []string
+	var err error
+
+	switch mediaType {
+	case MergePatch:
+		result, touched, err = applyMergePatch(target, patch)
+	case JSONPatch:
+		result, touched, err = applyJSONPatch(target, patch)
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnsupportedType, mediaType)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := result.(map[string]any); !ok {
+		return nil, nil, fmt.Errorf("%w: result is not an object", ErrInapplicable)
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+
+	slices.Sort(touched)
+	return out, slices.Compact(touched), nil
+}
+
+// applyMergePatch merges patch, which must be an object, into target. Members
+// set to null are removed and objects are merged member by member, while any
+// other value replaces the one in target.
+func applyMergePatch(target any, patch []byte) (any, []string, error) {
+	var p any
+	if err := decode(patch, &p); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	members, ok := p.(map[string]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: merge patch is not an object", ErrMalformed)
+	}
+
+	touched := make([]string, 0, len(members))
+	for name := range members {
+		touched = append(touched, name)
+	}
+
+	return merge(target, p), touched, nil
+}
+
+// merge returns target with patch merged into it, as RFC 7396 describes.
+func merge(target any, patch any) any {
+	members, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	object, ok := target.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+	for name, value := range members {
+		if value == nil {
+			delete(object, name)
+			continue
+		}
+		object[name] = merge(object[name], value)
+	}
+
+	return object
+}
+
+// operation is a single JSON Patch operation. Value is left raw so that a
+// missing value can be told apart from null.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyJSONPatch applies the operations of patch, which must be an array, to
+// target in order.
+func applyJSONPatch(target any, patch []byte) (any, []string, error) {
+	var ops []operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+
+	var touched []string
+	for i, op := range ops {
+		if op.Path == nil {
+			return nil, nil, fmt.Errorf("%w: operation %d has no path", ErrMalformed, i)
+		}
+		path, err := parsePointer(*op.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: operation %d: %w", ErrMalformed, i, err)
+		}
+
+		var from []string
+		if op.Op == "move" || op.Op == "copy" {
+			if op.From == nil {
+				return nil, nil, fmt.Errorf("%w: operation %d has no from", ErrMalformed, i)
+			}
+			if from, err = parsePointer(*op.From); err != nil {
+				return nil, nil, fmt.Errorf("%w: operation %d: %w", ErrMalformed, i, err)
+			}
+		}
+
+		var value any
+		if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+			if len(op.Value) == 0 {
+				return nil, nil, fmt.Errorf("%w: operation %d has no value", ErrMalformed, i)
+			}
+			if err = decode(op.Value, &value); err != nil {
+				return nil, nil, fmt.Errorf("%w: operation %d: %w", ErrMalformed, i, err)
+			}
+		}
+
+		before := target
+		switch op.Op {
+		case "add":
+			target, err = add(target, path, value)
+		case "remove":
+			target, err = remove(target, path)
+		case "replace":
+			if target, err = remove(target, path); err == nil {
+				target, err = add(target, path, value)
+			}
+		case "move":
+			if isPrefix(from, path) && len(from) < len(path) {
+				return nil, nil, fmt.Errorf("%w: operation %d moves %q into itself", ErrInapplicable, i, *op.From)
+			}
+			if value, err = get(target, from); err == nil {
+				if target, err = remove(target, from); err == nil {
+					target, err = add(target, path, value)
+				}
+			}
+			touched = append(touched, rootMembers(before, target, from)...)
+		case "copy":
+			if value, err = get(target, from); err == nil {
+				target, err = add(target, path, clone(value))
+			}
+		case "test":
+			current, err := get(target, path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("operation %d: %w", i, err)
+			}
+			if !equal(current, value) {
+				return nil, nil, fmt.Errorf("%w: operation %d: %q", ErrTestFailed, i, *op.Path)
+			}
+			continue
+		default:
+			return nil, nil, fmt.Errorf("%w: operation %d has unknown op %q", ErrMalformed, i, op.Op)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+
+		touched = append(touched, rootMembers(before, target, path)...)
+	}
+
+	return target, touched, nil
+}
+
+// rootMembers returns the top level member that path leads into. A path to
+// the whole document touches every member of the document before and after.
+func rootMembers(before any, after any, path []string) []string {
+	if len(path) > 0 {
+		return path[:1]
+	}
+
+	var names []string
+	for _, doc := range []any{before, after} {
+		if object, ok := doc.(map[string]any); ok {
+			for name := range object {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference
+// tokens. The empty pointer refers to the whole document.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer %q does not start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// get returns the value path refers to in doc.
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrInapplicable, token)
+			}
+			doc = value
+		case []any:
+			i, err := index(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %q is not in an object or array", ErrInapplicable, token)
+		}
+	}
+
+	return doc, nil
+}
+
+// add returns doc with value added at path. Adding to an object member
+// replaces it, while adding to an array inserts before the index, or appends
+// for "-".
+func add(doc any, path []string, value any) (any, error) {
+	return update(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			if token == "-" {
+				return append(node, value), nil
+			}
+			i, err := index(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			return slices.Insert(node, i, value), nil
+		default:
+			return nil, fmt.Errorf("%w: %q is not in an object or array", ErrInapplicable, token)
+		}
+	}, value)
+}
+
+// remove returns doc without the value at path, which must exist.
+func remove(doc any, path []string) (any, error) {
+	return update(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			if _, ok := node[token]; !ok {
+				return nil, fmt.Errorf("%w: member %q does not exist", ErrInapplicable, token)
+			}
+			delete(node, token)
+			return node, nil
+		case []any:
+			i, err := index(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			return slices.Delete(node, i, i+1), nil
+		default:
+			return nil, fmt.Errorf("%w: %q is not in an object or array", ErrInapplicable, token)
+		}
+	}, nil)
+}
+
+// update walks doc to the container holding the last token of path and
+// replaces it with what change makes of it. An empty path replaces the whole
+// document with root.
+func update(doc any, path []string, change func(parent any, token string) (any, error), root any) (any, error) {
+	if len(path) == 0 {
+		return root, nil
+	}
+	if len(path) == 1 {
+		return change(doc, path[0])
+	}
+
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = update(child, path[1:], change, root); err != nil {
+		return nil, err
+	}
+
+	switch node := doc.(type) {
+	case map[string]any:
+		node[path[0]] = child
+	case []any:
+		i, _ := strconv.Atoi(path[0])
+		node[i] = child
+	}
+
+	return doc, nil
+}
+
+// index parses an array index token, which may be at most max.
+func index(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInapplicable, token)
+	}
+
+	return i, nil
+}
+
+// isPrefix reports whether prefix is the start of path.
+func isPrefix(prefix []string, path []string) bool {
+	return len(prefix) <= len(path) && slices.Equal(prefix, path[:len(prefix)])
+}
+
+// equal reports whether two decoded JSON values are the same. Numbers are
+// compared by value, so 1 and 1.0 are equal.
+func equal(a any, b any) bool {
+	x, xok := a.(json.Number)
+	y, yok := b.(json.Number)
+	if xok && yok {
+		fx, errx := x.Float64()
+		fy, erry := y.Float64()
+		return errx == nil && erry == nil && fx == fy
+	}
+
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for name, value := range x {
+			other, ok := y[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// clone returns a deep copy of a decoded JSON value, so that a copied value
+// doesn't share its objects and arrays with the original.
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for name, member := range v {
+			object[name] = clone(member)
+		}
+		return object
+	case []any:
+		array := make([]any, len(v))
+		for i, element := range v {
+			array[i] = clone(element)
+		}
+		return array
+	default:
+		return v
+	}
+}
+
+// decode decodes a JSON value, keeping numbers as written so that large
+// integers survive the round trip.
+func decode(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after the JSON value")
+	}
+
+	return nil
+}
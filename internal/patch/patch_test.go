@@ -0,0 +1,131 @@
+package patch
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	doc := `{"name":"john","email":"john@me.com","tags":["go","sql"],"meta":{"a":1,"b":2}}`
+
+	testcases := map[string]struct {
+		mediaType       string
+		patch           string
+		expected        string
+		expectedTouched []string
+		expectedError   error
+	}{
+		"merge replaces a member": {
+			mediaType:       MergePatch,
+			patch:           `{"name":"johnny"}`,
+			expected:        `{"email":"john@me.com","meta":{"a":1,"b":2},"name":"johnny","tags":["go","sql"]}`,
+			expectedTouched: []string{"name"},
+		},
+		"merge removes null members and merges objects": {
+			mediaType:       MergePatch,
+			patch:           `{"email":null,"meta":{"a":null,"c":3},"tags":["rust"]}`,
+			expected:        `{"meta":{"b":2,"c":3},"name":"john","tags":["rust"]}`,
+			expectedTouched: []string{"email", "meta", "tags"},
+		},
+		"merge patch that isn't an object": {
+			mediaType:     MergePatch,
+			patch:         `["name"]`,
+			expectedError: ErrMalformed,
+		},
+		"merge patch that isn't json": {
+			mediaType:     MergePatch,
+			patch:         `{"name":`,
+			expectedError: ErrMalformed,
+		},
+		"json patch replace and add": {
+			mediaType:       JSONPatch,
+			patch:           `[{"op":"test","path":"/name","value":"john"},{"op":"replace","path":"/name","value":"johnny"},{"op":"add","path":"/tags/-","value":"rust"},{"op":"add","path":"/tags/0","value":"c"}]`,
+			expected:        `{"email":"john@me.com","meta":{"a":1,"b":2},"name":"johnny","tags":["c","go","sql","rust"]}`,
+			expectedTouched: []string{"name", "tags"},
+		},
+		"json patch remove, move and copy": {
+			mediaType:       JSONPatch,
+			patch:           `[{"op":"remove","path":"/tags/1"},{"op":"move","from":"/meta/a","path":"/count"},{"op":"copy","from":"/name","path":"/alias"}]`,
+			expected:        `{"alias":"john","count":1,"email":"john@me.com","meta":{"b":2},"name":"john","tags":["go"]}`,
+			expectedTouched: []string{"alias", "count", "meta", "tags"},
+		},
+		"json patch escaped pointer": {
+			mediaType:       JSONPatch,
+			patch:           `[{"op":"add","path":"/a~1b~0c","value":true}]`,
+			expected:        `{"a/b~c":true,"email":"john@me.com","meta":{"a":1,"b":2},"name":"john","tags":["go","sql"]}`,
+			expectedTouched: []string{"a/b~c"},
+		},
+		"json patch test compares numbers by value": {
+			mediaType:       JSONPatch,
+			patch:           `[{"op":"test","path":"/meta","value":{"b":2.0,"a":1}}]`,
+			expected:        `{"email":"john@me.com","meta":{"a":1,"b":2},"name":"john","tags":["go","sql"]}`,
+			expectedTouched: nil,
+		},
+		"json patch failed test": {
+			mediaType:     JSONPatch,
+			patch:         `[{"op":"replace","path":"/name","value":"johnny"},{"op":"test","path":"/email","value":"jane@me.com"}]`,
+			expectedError: ErrTestFailed,
+		},
+		"json patch removes a missing member": {
+			mediaType:     JSONPatch,
+			patch:         `[{"op":"remove","path":"/password"}]`,
+			expectedError: ErrInapplicable,
+		},
+		"json patch index out of range": {
+			mediaType:     JSONPatch,
+			patch:         `[{"op":"add","path":"/tags/3","value":"rust"}]`,
+			expectedError: ErrInapplicable,
+		},
+		"json patch moves into itself": {
+			mediaType:     JSONPatch,
+			patch:         `[{"op":"move","from":"/meta","path":"/meta/inner"}]`,
+			expectedError: ErrInapplicable,
+		},
+		"json patch removes the document": {
+			mediaType:     JSONPatch,
+			patch:         `[{"op":"remove","path":""}]`,
+			expectedError: ErrInapplicable,
+		},
+		"json patch unknown op": {
+			mediaType:     JSONPatch,
+			patch:         `[{"op":"delete","path":"/name"}]`,
+			expectedError: ErrMalformed,
+		},
+		"json patch missing value": {
+			mediaType:     JSONPatch,
+			patch:         `[{"op":"add","path":"/name"}]`,
+			expectedError: ErrMalformed,
+		},
+		"json patch null value": {
+			mediaType:       JSONPatch,
+			patch:           `[{"op":"replace","path":"/email","value":null}]`,
+			expected:        `{"email":null,"meta":{"a":1,"b":2},"name":"john","tags":["go","sql"]}`,
+			expectedTouched: []string{"email"},
+		},
+		"json patch pointer without slash": {
+			mediaType:     JSONPatch,
+			patch:         `[{"op":"remove","path":"name"}]`,
+			expectedError: ErrMalformed,
+		},
+		"unsupported media type": {
+			mediaType:     "application/json",
+			patch:         `{"name":"johnny"}`,
+			expectedError: ErrUnsupportedType,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			output, touched, err := Apply(tc.mediaType, []byte(doc), []byte(tc.patch))
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected %v, got %v", tc.expectedError, err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, output)
+			}
+			if !slices.Equal(touched, tc.expectedTouched) {
+				t.Errorf("expected touched %v, got %v", tc.expectedTouched, touched)
+			}
+		})
+	}
+}
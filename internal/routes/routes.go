@@ -6,6 +6,7 @@ import (
 
 	_ "github.com/chickey/blog/cmd/api/docs"
 	"github.com/chickey/blog/internal/handlers"
+	"github.com/chickey/blog/internal/middleware"
 	"github.com/chickey/blog/internal/services"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
@@ -23,26 +24,71 @@ import (
 // @BasePath					/api
 // @externalDocs.description	OpenAPI
 // @externalDocs.url			https://swagger.io/resources/open-api/
-func AddRoutes(mux *http.ServeMux, logger *slog.Logger, usersService *services.UsersService, blogsService *services.BlogsService, commentsService *services.CommentsService, baseURL string) {
+//
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				Access token from /auth/login, sent as "Bearer <token>"
+func AddRoutes(mux *http.ServeMux, logger *slog.Logger, authService *services.AuthService, usersService *services.UsersService, blogsService *services.BlogsService, commentsService *services.CommentsService, searchService *services.SearchService, tagsService *services.TagsService, idempotencyService *services.IdempotencyService, baseURL string) {
+	// Routes that change data need an authenticated caller
+	requireAuth := middleware.RequireAuth()
+
+	// Creating a resource is made safe to retry with an Idempotency-Key
+	idempotent := middleware.Idempotency(logger, idempotencyService)
+
+	// Auth endpoints
+	mux.Handle("POST /api/auth/login", handlers.HandleLogin(logger, authService))
+	mux.Handle("POST /api/auth/refresh", handlers.HandleRefreshToken(logger, authService))
+	mux.Handle("POST /api/auth/logout", requireAuth(handlers.HandleLogout(logger, authService)))
+
 	// User endpoints
 	mux.Handle("GET /api/user/{id}", handlers.HandleReadUser(logger, usersService))
 	mux.Handle("GET /api/user", handlers.HandleListUsers(logger, usersService))
-	mux.Handle("POST /api/user", handlers.HandleCreateUser(logger, usersService))
-	mux.Handle("PUT /api/user/{id}", handlers.HandleUpdateUser(logger, usersService))
-	mux.Handle("DELETE /api/user/{id}", handlers.HandleDeleteUser(logger, usersService))
+	mux.Handle("POST /api/user", idempotent(handlers.HandleCreateUser(logger, usersService)))
+	mux.Handle("PUT /api/user/{id}", requireAuth(handlers.HandleUpdateUser(logger, usersService)))
+	mux.Handle("PATCH /api/user/{id}", requireAuth(handlers.HandlePatchUser(logger, usersService)))
+	mux.Handle("DELETE /api/user/{id}", requireAuth(handlers.HandleDeleteUser(logger, usersService)))
+	mux.Handle("GET /api/user/{id}/blogs", handlers.HandleListUserBlogs(logger, blogsService))
+	mux.Handle("POST /api/user/{id}/restore", requireAuth(handlers.HandleRestoreUser(logger, usersService)))
+	mux.Handle("POST /api/user/bulk", requireAuth(idempotent(handlers.HandleBulkCreateUsers(logger, usersService))))
+	mux.Handle("PUT /api/user/bulk", requireAuth(handlers.HandleBulkUpdateUsers(logger, usersService)))
+	mux.Handle("DELETE /api/user/bulk", requireAuth(handlers.HandleBulkDeleteUsers(logger, usersService)))
 
 	// Blog endpoints
 	mux.Handle("GET /api/blog/{id}", handlers.HandleReadBlog(logger, blogsService))
 	mux.Handle("GET /api/blog", handlers.HandleListBlogs(logger, blogsService))
-	mux.Handle("POST /api/blog", handlers.HandleCreateBlog(logger, blogsService))
-	mux.Handle("PUT /api/blog/{id}", handlers.HandleUpdateBlog(logger, blogsService))
-	mux.Handle("DELETE /api/blog/{id}", handlers.HandleDeleteBlog(logger, blogsService))
+	mux.Handle("POST /api/blog", requireAuth(idempotent(handlers.HandleCreateBlog(logger, blogsService))))
+	mux.Handle("PUT /api/blog/{id}", requireAuth(handlers.HandleUpdateBlog(logger, blogsService)))
+	mux.Handle("PATCH /api/blog/{id}", requireAuth(handlers.HandlePatchBlog(logger, blogsService)))
+	mux.Handle("DELETE /api/blog/{id}", requireAuth(handlers.HandleDeleteBlog(logger, blogsService)))
+	mux.Handle("POST /api/blog/{id}/restore", requireAuth(handlers.HandleRestoreBlog(logger, blogsService)))
+	mux.Handle("GET /api/blog/{id}/comments", handlers.HandleListCommentThreads(logger, commentsService))
+	mux.Handle("POST /api/blog/{id}/vote", requireAuth(handlers.HandleCreateVote(logger, blogsService)))
+	mux.Handle("DELETE /api/blog/{id}/vote", requireAuth(handlers.HandleDeleteVote(logger, blogsService)))
+	mux.Handle("GET /api/blog/{id}/revisions", handlers.HandleListBlogRevisions(logger, blogsService))
+	mux.Handle("POST /api/blog/{id}/revisions/{revision}/restore", requireAuth(handlers.HandleRestoreBlogRevision(logger, blogsService)))
+	mux.Handle("POST /api/blog/bulk", requireAuth(idempotent(handlers.HandleBulkCreateBlogs(logger, blogsService))))
+	mux.Handle("PUT /api/blog/bulk", requireAuth(handlers.HandleBulkUpdateBlogs(logger, blogsService)))
+	mux.Handle("DELETE /api/blog/bulk", requireAuth(handlers.HandleBulkDeleteBlogs(logger, blogsService)))
 
 	// Comment endpoints
 	mux.Handle("GET /api/comment", handlers.HandleListComments(logger, commentsService))
-	mux.Handle("POST /api/comment", handlers.HandleCreateComment(logger, commentsService))
-	mux.Handle("PUT /api/comment", handlers.HandleUpdateComment(logger, commentsService))
-	mux.Handle("DELETE /api/comment", handlers.HandleDeleteComment(logger, commentsService))
+	mux.Handle("POST /api/comment", requireAuth(idempotent(handlers.HandleCreateComment(logger, commentsService))))
+	mux.Handle("PUT /api/comment", requireAuth(handlers.HandleUpdateComment(logger, commentsService)))
+	mux.Handle("PATCH /api/comment", requireAuth(handlers.HandlePatchComment(logger, commentsService)))
+	mux.Handle("DELETE /api/comment", requireAuth(handlers.HandleDeleteComment(logger, commentsService)))
+	mux.Handle("POST /api/comment/restore", requireAuth(handlers.HandleRestoreComment(logger, commentsService)))
+	mux.Handle("GET /api/comment/revisions", handlers.HandleListCommentRevisions(logger, commentsService))
+	mux.Handle("POST /api/comment/revisions/{id}/restore", requireAuth(handlers.HandleRestoreCommentRevision(logger, commentsService)))
+	mux.Handle("POST /api/comment/bulk", requireAuth(idempotent(handlers.HandleBulkCreateComments(logger, commentsService))))
+	mux.Handle("PUT /api/comment/bulk", requireAuth(handlers.HandleBulkUpdateComments(logger, commentsService)))
+	mux.Handle("DELETE /api/comment/bulk", requireAuth(handlers.HandleBulkDeleteComments(logger, commentsService)))
+
+	// Tag endpoints
+	mux.Handle("GET /api/tag", handlers.HandleListTags(logger, tagsService))
+
+	// Search endpoints
+	mux.Handle("GET /api/search", handlers.HandleSearch(logger, searchService))
 
 	// health check
 	mux.Handle("GET /api/health", handlers.HandleHealthCheck(logger))
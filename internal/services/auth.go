@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/chickey/blog/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minAuthSecretLength is the shortest HMAC key accepted for signing tokens.
+const minAuthSecretLength = 32
+
+const (
+	tokenIssuer      = "blog"
+	accessTokenType  = "access"
+	refreshTokenType = "refresh"
+)
+
+// tokenClaims are the claims carried by access and refresh tokens.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Type      string `json:"typ"`
+	SessionID string `json:"sid"`
+}
+
+// AuthService is a service capable of logging users in and out and of
+// issuing, refreshing and validating their tokens.
+type AuthService struct {
+	logger     *slog.Logger
+	db         *sql.DB
+	users      *UsersService
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// NewAuthService creates a new AuthService and returns a pointer to it.
+// Tokens are signed with HMAC-SHA256 using secret.
+func NewAuthService(
+	logger *slog.Logger,
+	db *sql.DB,
+	users *UsersService,
+	secret []byte,
+	accessTTL time.Duration,
+	refreshTTL time.Duration,
+) (*AuthService, error) {
+	if len(secret) < minAuthSecretLength {
+		return nil, fmt.Errorf(
+			"[in services.NewAuthService] secret must be at least %d bytes",
+			minAuthSecretLength,
+		)
+	}
+	if accessTTL <= 0 || refreshTTL <= 0 {
+		return nil, fmt.Errorf("[in services.NewAuthService] token lifetimes must be positive")
+	}
+
+	return &AuthService{
+		logger:     logger,
+		db:         db,
+		users:      users,
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}, nil
+}
+
+// Login verifies the provided credentials and starts a new session, returning
+// its tokens. ErrInvalidCredentials is returned if the credentials don't
+// match a user.
+func (s *AuthService) Login(ctx context.Context, email, password string) (models.TokenPair, error) {
+	s.logger.DebugContext(ctx, "Logging in", "email", email)
+
+	user, err := s.users.VerifyPassword(ctx, email, password)
+	if err != nil {
+		return models.TokenPair{}, fmt.Errorf("[in services.AuthService.Login] %w", err)
+	}
+
+	now := s.now()
+	sessionID := newTokenID()
+	refreshID := newTokenID()
+
+	_, err = s.db.ExecContext(
+		ctx,
+		`
+		INSERT INTO auth_sessions (id, user_id, refresh_token_id, expires_at) VALUES ($1, $2, $3, $4)
+		`,
+		sessionID,
+		user.ID,
+		refreshID,
+		now.Add(s.refreshTTL),
+	)
+	if err != nil {
+		return models.TokenPair{}, fmt.Errorf(
+			"[in services.AuthService.Login] failed to create session: %w",
+			err,
+		)
+	}
+
+	tokens, err := s.issue(user.ID, sessionID, refreshID, now)
+	if err != nil {
+		return models.TokenPair{}, fmt.Errorf("[in services.AuthService.Login] %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. Each refresh token
+// can be used once; presenting one that has already been exchanged revokes
+// the whole session, since it means the token has leaked. ErrInvalidToken is
+// returned if the token is not accepted.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	claims, err := s.parse(refreshToken, refreshTokenType)
+	if err != nil {
+		return models.TokenPair{}, fmt.Errorf("[in services.AuthService.Refresh] %w", err)
+	}
+
+	s.logger.DebugContext(ctx, "Refreshing tokens", "session", claims.SessionID)
+
+	now := s.now()
+	refreshID := newTokenID()
+
+	row := s.db.QueryRowContext(
+		ctx,
+		`
+		UPDATE auth_sessions
+		SET refresh_token_id = $1, expires_at = $2
+		WHERE id = $3
+		  AND refresh_token_id = $4
+		  AND revoked_at IS NULL
+		  AND expires_at > $5
+		RETURNING user_id
+		`,
+		refreshID,
+		now.Add(s.refreshTTL),
+		claims.SessionID,
+		claims.ID,
+		now,
+	)
+
+	var userID uint
+	err = row.Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The session is gone, or the token was already exchanged. Revoke in
+		// case it is the latter.
+		if err = s.revoke(ctx, claims.SessionID); err != nil {
+			s.logger.WarnContext(
+				ctx,
+				"failed to revoke session",
+				slog.String("session", claims.SessionID),
+				slog.String("error", err.Error()),
+			)
+		}
+		return models.TokenPair{}, fmt.Errorf(
+			"[in services.AuthService.Refresh] session %s: %w",
+			claims.SessionID,
+			ErrInvalidToken,
+		)
+	}
+	if err != nil {
+		return models.TokenPair{}, fmt.Errorf(
+			"[in services.AuthService.Refresh] failed to rotate refresh token: %w",
+			err,
+		)
+	}
+
+	tokens, err := s.issue(userID, claims.SessionID, refreshID, now)
+	if err != nil {
+		return models.TokenPair{}, fmt.Errorf("[in services.AuthService.Refresh] %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Authenticate validates an access token and returns the user and session it
+// was issued for. ErrInvalidToken is returned if the token is not accepted or
+// its session has been revoked or its user deleted.
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (models.User, models.Session, error) {
+	claims, err := s.parse(accessToken, accessTokenType)
+	if err != nil {
+		return models.User{}, models.Session{}, fmt.Errorf("[in services.AuthService.Authenticate] %w", err)
+	}
+
+	row := s.db.QueryRowContext(
+		ctx,
+		`
+		SELECT users.id,
+		       users.name,
+		       users.email,
+		       users.role,
+		       auth_sessions.expires_at
+		FROM auth_sessions
+		JOIN users ON users.id = auth_sessions.user_id AND users.deleted_at IS NULL
+		WHERE auth_sessions.id = $1
+		  AND auth_sessions.revoked_at IS NULL
+		  AND auth_sessions.expires_at > $2
+		`,
+		claims.SessionID,
+		s.now(),
+	)
+
+	var (
+		user    models.User
+		session = models.Session{ID: claims.SessionID}
+	)
+
+	err = row.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &session.ExpiresAt)
+	if err != nil {
+		return models.User{}, models.Session{}, fmt.Errorf(
+			"[in services.AuthService.Authenticate] session %s: %w",
+			claims.SessionID,
+			replaceNoRows(err, ErrInvalidToken),
+		)
+	}
+	session.UserID = user.ID
+
+	return user, session, nil
+}
+
+// Logout revokes the session with the provided id, invalidating its access
+// and refresh tokens.
+func (s *AuthService) Logout(ctx context.Context, sessionID string) error {
+	s.logger.DebugContext(ctx, "Logging out", "session", sessionID)
+
+	if err := s.revoke(ctx, sessionID); err != nil {
+		return fmt.Errorf("[in services.AuthService.Logout] %w", err)
+	}
+
+	return nil
+}
+
+// revoke marks the session with the provided id as revoked.
+func (s *AuthService) revoke(ctx context.Context, sessionID string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`
+		UPDATE auth_sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL
+		`,
+		s.now(),
+		sessionID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session %s: %w", sessionID, err)
+	}
+
+	return nil
+}
+
+// issue signs a new access token and a refresh token with id refreshID for
+// the provided session.
+func (s *AuthService) issue(userID uint, sessionID, refreshID string, now time.Time) (models.TokenPair, error) {
+	accessExpiresAt := now.Add(s.accessTTL)
+
+	accessToken, err := s.sign(userID, sessionID, newTokenID(), accessTokenType, now, accessExpiresAt)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	refreshToken, err := s.sign(userID, sessionID, refreshID, refreshTokenType, now, now.Add(s.refreshTTL))
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return models.TokenPair{
+		AccessToken:          accessToken,
+		RefreshToken:         refreshToken,
+		AccessTokenExpiresAt: accessExpiresAt,
+	}, nil
+}
+
+// sign returns a signed token with the provided claims.
+func (s *AuthService) sign(userID uint, sessionID, id, kind string, now, expiresAt time.Time) (string, error) {
+	claims := tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			ID:        id,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Type:      kind,
+		SessionID: sessionID,
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s token: %w", kind, err)
+	}
+
+	return token, nil
+}
+
+// parse verifies the signature and expiry of a token and that it is of the
+// expected kind, returning its claims.
+func (s *AuthService) parse(token, kind string) (tokenClaims, error) {
+	var claims tokenClaims
+
+	_, err := jwt.ParseWithClaims(
+		token,
+		&claims,
+		func(*jwt.Token) (any, error) {
+			return s.secret, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(s.now),
+	)
+	if err != nil {
+		return tokenClaims{}, errors.Join(ErrInvalidToken, err)
+	}
+	if claims.Type != kind || claims.SessionID == "" {
+		return tokenClaims{}, fmt.Errorf("expected %s token: %w", kind, ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// newTokenID returns a random 128 bit id encoded as hex.
+func newTokenID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
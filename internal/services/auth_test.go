@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/chickey/blog/internal/models"
+)
+
+var testAuthSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestAuthService_Login(t *testing.T) {
+	hasher := newTestHasher(t)
+	hash, err := hasher.Hash("password123!")
+	if err != nil {
+		t.Fatalf("failed to hash: %v", err)
+	}
+
+	testcases := map[string]struct {
+		mockOutput    *sqlmock.Rows
+		expectSession bool
+		input         string
+		expectedError error
+	}{
+		"happy path": {
+			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
+				AddRow(1, "john", "john@me.com", hash),
+			expectSession: true,
+			input:         "password123!",
+			expectedError: nil,
+		},
+		"wrong password": {
+			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
+				AddRow(1, "john", "john@me.com", hash),
+			input:         "password456!",
+			expectedError: ErrInvalidCredentials,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+			now := time.Date(2025, 1, 21, 11, 12, 11, 0, time.UTC)
+
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`FROM users WHERE LOWER(email) = LOWER($1)`)).
+				WithArgs("john@me.com").
+				WillReturnRows(tc.mockOutput)
+			if tc.expectSession {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`
+						INSERT INTO auth_sessions (id, user_id, refresh_token_id, expires_at) VALUES ($1, $2, $3, $4)
+					`)).
+					WithArgs(sqlmock.AnyArg(), 1, sqlmock.AnyArg(), now.Add(time.Hour)).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+
+			authService, err := NewAuthService(logger, db, NewUsersService(logger, db, hasher), testAuthSecret, time.Minute, time.Hour)
+			if err != nil {
+				t.Fatalf("failed to create auth service: %v", err)
+			}
+			authService.now = func() time.Time { return now }
+
+			tokens, err := authService.Login(context.TODO(), "john@me.com", tc.input)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+
+			if tc.expectedError == nil {
+				if !tokens.AccessTokenExpiresAt.Equal(now.Add(time.Minute)) {
+					t.Errorf("expected access token to expire at %v, got %v", now.Add(time.Minute), tokens.AccessTokenExpiresAt)
+				}
+
+				access, err := authService.parse(tokens.AccessToken, accessTokenType)
+				if err != nil {
+					t.Fatalf("expected a valid access token, got %v", err)
+				}
+				refresh, err := authService.parse(tokens.RefreshToken, refreshTokenType)
+				if err != nil {
+					t.Fatalf("expected a valid refresh token, got %v", err)
+				}
+				if access.Subject != "1" || access.SessionID == "" || access.SessionID != refresh.SessionID {
+					t.Errorf("unexpected claims %+v and %+v", access, refresh)
+				}
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	now := time.Date(2025, 1, 21, 11, 12, 11, 0, time.UTC)
+
+	testcases := map[string]struct {
+		mockOutput    *sqlmock.Rows
+		expectRevoke  bool
+		expectedError error
+	}{
+		"happy path": {
+			mockOutput:    sqlmock.NewRows([]string{"user_id"}).AddRow(1),
+			expectedError: nil,
+		},
+		"reused refresh token revokes the session": {
+			mockOutput:    sqlmock.NewRows([]string{"user_id"}),
+			expectRevoke:  true,
+			expectedError: ErrInvalidToken,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			authService, err := NewAuthService(logger, db, nil, testAuthSecret, time.Minute, time.Hour)
+			if err != nil {
+				t.Fatalf("failed to create auth service: %v", err)
+			}
+			authService.now = func() time.Time { return now }
+
+			issued, err := authService.issue(1, "session", "refresh-id", now.Add(-time.Minute))
+			if err != nil {
+				t.Fatalf("failed to issue tokens: %v", err)
+			}
+
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+					UPDATE auth_sessions
+					SET refresh_token_id = $1, expires_at = $2
+					WHERE id = $3
+					  AND refresh_token_id = $4
+					  AND revoked_at IS NULL
+					  AND expires_at > $5
+					RETURNING user_id
+				`)).
+				WithArgs(sqlmock.AnyArg(), now.Add(time.Hour), "session", "refresh-id", now).
+				WillReturnRows(tc.mockOutput)
+			if tc.expectRevoke {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`UPDATE auth_sessions SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`)).
+					WithArgs(now, "session").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			tokens, err := authService.Refresh(context.TODO(), issued.RefreshToken)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+
+			if tc.expectedError == nil {
+				refresh, err := authService.parse(tokens.RefreshToken, refreshTokenType)
+				if err != nil {
+					t.Fatalf("expected a valid refresh token, got %v", err)
+				}
+				if refresh.ID == "refresh-id" {
+					t.Errorf("expected the refresh token to be rotated")
+				}
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	now := time.Date(2025, 1, 21, 11, 12, 11, 0, time.UTC)
+
+	issuer, err := NewAuthService(slog.Default(), nil, nil, testAuthSecret, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create auth service: %v", err)
+	}
+	valid, err := issuer.issue(1, "session", "refresh-id", now)
+	if err != nil {
+		t.Fatalf("failed to issue tokens: %v", err)
+	}
+	expired, err := issuer.issue(1, "session", "refresh-id", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to issue tokens: %v", err)
+	}
+	other, err := NewAuthService(slog.Default(), nil, nil, []byte("fedcba9876543210fedcba9876543210"), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create auth service: %v", err)
+	}
+	forged, err := other.issue(1, "session", "refresh-id", now)
+	if err != nil {
+		t.Fatalf("failed to issue tokens: %v", err)
+	}
+
+	testcases := map[string]struct {
+		token          string
+		mockCalled     bool
+		mockOutput     *sqlmock.Rows
+		expectedOutput models.User
+		expectedError  error
+	}{
+		"happy path": {
+			token:      valid.AccessToken,
+			mockCalled: true,
+			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "role", "expires_at"}).
+				AddRow(1, "john", "john@me.com", "admin", now.Add(time.Hour)),
+			expectedOutput: models.User{ID: 1, Name: "john", Email: "john@me.com", Role: models.RoleAdmin},
+			expectedError:  nil,
+		},
+		"revoked session": {
+			token:          valid.AccessToken,
+			mockCalled:     true,
+			mockOutput:     sqlmock.NewRows([]string{"id", "name", "email", "role", "expires_at"}),
+			expectedOutput: models.User{},
+			expectedError:  ErrInvalidToken,
+		},
+		"expired token": {
+			token:          expired.AccessToken,
+			expectedOutput: models.User{},
+			expectedError:  ErrInvalidToken,
+		},
+		"refresh token": {
+			token:          valid.RefreshToken,
+			expectedOutput: models.User{},
+			expectedError:  ErrInvalidToken,
+		},
+		"wrong signing key": {
+			token:          forged.AccessToken,
+			expectedOutput: models.User{},
+			expectedError:  ErrInvalidToken,
+		},
+		"malformed": {
+			token:          "not-a-token",
+			expectedOutput: models.User{},
+			expectedError:  ErrInvalidToken,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			if tc.mockCalled {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`FROM auth_sessions JOIN users ON users.id = auth_sessions.user_id`)).
+					WithArgs("session", now).
+					WillReturnRows(tc.mockOutput)
+			}
+
+			authService, err := NewAuthService(slog.Default(), db, nil, testAuthSecret, time.Minute, time.Hour)
+			if err != nil {
+				t.Fatalf("failed to create auth service: %v", err)
+			}
+			authService.now = func() time.Time { return now }
+
+			user, session, err := authService.Authenticate(context.TODO(), tc.token)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if user != tc.expectedOutput {
+				t.Errorf("expected %v, got %v", tc.expectedOutput, user)
+			}
+			if tc.expectedError == nil && (session.ID != "session" || session.UserID != 1) {
+				t.Errorf("unexpected session %+v", session)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestNewAuthService(t *testing.T) {
+	_, err := NewAuthService(slog.Default(), nil, nil, []byte("short"), time.Minute, time.Hour)
+	if err == nil {
+		t.Errorf("expected a short secret to be rejected")
+	}
+}
@@ -6,27 +6,65 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/chickey/blog/internal/diff"
+	"github.com/chickey/blog/internal/markdown"
 	"github.com/chickey/blog/internal/models"
 )
 
+// generatedExcerptLength is the length of the excerpts generated for blogs
+// created without one.
+const generatedExcerptLength = 200
+
 // BlogsService is a service capable of performing CRUD operations for
 // models.Blog models.
 type BlogsService struct {
 	logger *slog.Logger
 	db     *sql.DB
+	tags   *TagsService
+	now    func() time.Time
 }
 
-// NewBlogsService creates a new BlogsService and returns a pointer to it.
-func NewBlogsService(logger *slog.Logger, db *sql.DB) *BlogsService {
+// NewBlogsService creates a new BlogsService and returns a pointer to it. tags
+// normalizes and assigns the tags and categories of the blogs it writes.
+func NewBlogsService(logger *slog.Logger, db *sql.DB, tags *TagsService) *BlogsService {
 	return &BlogsService{
 		logger: logger,
 		db:     db,
+		tags:   tags,
+		now:    time.Now,
+	}
+}
+
+// blogTagsColumn selects the tags of each blog as a comma separated list in
+// alphabetical order, so that they are read in the same query as the blog.
+// Slugs never contain commas.
+const blogTagsColumn = `COALESCE((
+		SELECT string_agg(t.name, ',' ORDER BY t.name)
+		FROM blog_tags bt
+		JOIN tags t ON t.id = bt.tag_id
+		WHERE bt.blog_id = blogs.id
+	), '') AS tags`
+
+// splitTags splits a list of tags read with blogTagsColumn, returning nil if
+// it is empty.
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
 	}
+
+	return strings.Split(tags, ",")
 }
 
 // CreateBlog attempts to create the provided blog, returning a fully hydrated
-// models.Blog or an error.
+// models.Blog or an error. Blogs are created as drafts unless another status
+// is given, and are unrated until they are voted on. The category and tags are
+// normalized, and the tags are created with the blog in a single transaction.
+// ErrInvalidTransition is returned if the blog can't be created with its
+// status.
 func (s *BlogsService) CreateBlog(ctx context.Context, blog models.Blog) (models.Blog, error) {
 	s.logger.DebugContext(ctx, "Creating blog", "name", blog.Title)
 
@@ -39,7 +77,7 @@ func (s *BlogsService) CreateBlog(ctx context.Context, blog models.Blog) (models
 				   email,
 				   password
 			FROM users
-			WHERE id = $1::int
+			WHERE id = $1::int AND deleted_at IS NULL
 			`,
 		blog.AuthorID,
 	)
@@ -50,27 +88,49 @@ func (s *BlogsService) CreateBlog(ctx context.Context, blog models.Blog) (models
 
 	if err != nil {
 		return models.Blog{}, fmt.Errorf(
-			"[in services.BlogsService.UpdateBlog] failed to update blog: %w",
-			err,
+			"[in services.BlogsService.CreateBlog] author %d: %w",
+			blog.AuthorID,
+			replaceNoRows(err, ErrInvalidReference),
 		)
 	}
 
-	// Create new blog entry in blog table
-	result := s.db.QueryRowContext(
-		ctx,
-		`
-		INSERT INTO blogs (author_id, title, score) VALUES ($1, $2, $3) RETURNING id, created_date
-		`,
-		blog.AuthorID,
-		blog.Title,
-		blog.Score,
-	)
+	if blog.Excerpt, err = excerptOf(blog); err != nil {
+		return models.Blog{}, fmt.Errorf("[in services.BlogsService.CreateBlog] %w", err)
+	}
+
+	if err = applyStatus(&blog, models.Blog{}, s.now()); err != nil {
+		return models.Blog{}, fmt.Errorf("[in services.BlogsService.CreateBlog] %w", err)
+	}
 
-	err = result.Scan(&blog.ID, &blog.CreatedDate)
+	blog.Category = s.tags.NormalizeCategory(blog.Category)
+	blog.Tags = s.tags.NormalizeAll(blog.Tags)
+
+	err = withTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Create new blog entry in blog table
+		result := tx.QueryRowContext(
+			ctx,
+			`
+			INSERT INTO blogs (author_id, title, body, excerpt, category, status, publish_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, score, created_date
+			`,
+			blog.AuthorID,
+			blog.Title,
+			blog.Body,
+			blog.Excerpt,
+			blog.Category,
+			blog.Status,
+			nullTime(blog.PublishAt),
+		)
+
+		if err := result.Scan(&blog.ID, &blog.Score, &blog.CreatedDate); err != nil {
+			return fmt.Errorf("failed to create blog: %w", translateError(err))
+		}
+
+		return s.tags.SetBlogTags(ctx, tx, blog.ID, blog.Tags)
+	})
 
 	if err != nil {
 		return models.Blog{}, fmt.Errorf(
-			"[in services.BlogsService.CreateBlog] failed to create blog: %w",
+			"[in services.BlogsService.CreateBlog] %w",
 			err,
 		)
 	}
@@ -78,32 +138,70 @@ func (s *BlogsService) CreateBlog(ctx context.Context, blog models.Blog) (models
 	return blog, nil
 }
 
-// ReadBlog attempts to read a blog from the database using the provided id. A
-// fully hydrated models.Blog or error is returned.
-func (s *BlogsService) ReadBlog(ctx context.Context, id uint64) (models.Blog, error) {
+// ReadBlog attempts to read a blog from the database using the provided id,
+// along with the related records named by expand. Deleted blogs are only read
+// if includeDeleted is set. A fully hydrated models.Blog or error is returned.
+func (s *BlogsService) ReadBlog(ctx context.Context, id uint64, expand models.BlogExpand, includeDeleted bool) (models.Blog, error) {
 	s.logger.DebugContext(ctx, "Reading blog", "id", id)
 
+	columns, joins := blogExpansion(expand)
+	deletedColumn, condition := deletedColumns(includeDeleted)
+
 	row := s.db.QueryRowContext(
 		ctx,
-		`
+		fmt.Sprintf(`
 		SELECT id,
 		       author_id,
 		       title,
+		       body,
+		       excerpt,
+		       status,
+		       publish_at,
 		       score,
-			   created_date
-		FROM blogs
-		WHERE id = $1::int
-        `,
+			   created_date,
+			   category,
+			   version,
+			   %s%s%s
+		FROM blogs%s
+		WHERE id = $1::int%s
+        `, blogTagsColumn, columns, deletedColumn, joins, condition),
 		id,
 	)
 
 	var blog models.Blog
+	var publishAt, deletedAt sql.NullTime
+	var tags string
+
+	dest := []any{
+		&blog.ID,
+		&blog.AuthorID,
+		&blog.Title,
+		&blog.Body,
+		&blog.Excerpt,
+		&blog.Status,
+		&publishAt,
+		&blog.Score,
+		&blog.CreatedDate,
+		&blog.Category,
+		&blog.Version,
+		&tags,
+	}
+	if expand.Author {
+		dest = append(dest, &blog.Author.Name)
+	}
+	if includeDeleted {
+		dest = append(dest, &deletedAt)
+	}
 
-	err := row.Scan(&blog.ID, &blog.AuthorID, &blog.Title, &blog.Score, &blog.CreatedDate)
+	err := row.Scan(dest...)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return models.Blog{}, nil
+			return models.Blog{}, fmt.Errorf(
+				"[in services.BlogsService.ReadBlog] blog %d: %w",
+				id,
+				ErrNotFound,
+			)
 		default:
 			return models.Blog{}, fmt.Errorf(
 				"[in services.BlogsService.ReadBlog] failed to read blog: %w",
@@ -111,14 +209,27 @@ func (s *BlogsService) ReadBlog(ctx context.Context, id uint64) (models.Blog, er
 			)
 		}
 	}
+	blog.PublishAt = publishAt.Time
+	blog.DeletedAt = deletedAt.Time
+	blog.Tags = splitTags(tags)
+	if expand.Author {
+		blog.Author.ID = blog.AuthorID
+	}
 
 	return blog, nil
 }
 
 // UpdateBlog attempts to perform an update of the blog with the provided id,
 // updating, it to reflect the properties on the provided patch object. A
-// models.Blog or an error.
-func (s *BlogsService) UpdateBlog(ctx context.Context, id uint64, patch models.Blog) (models.Blog, error) {
+// models.Blog or an error. A patch without a status keeps the current one,
+// and the score is left to votes. The patch's category and tags are
+// normalized, and its tags replace the blog's in the same transaction. The
+// blog's previous content is kept as a revision made by the editor with
+// editorId. ErrInvalidTransition is returned if the blog can't move from its
+// current status to the patch's. If patch.Version is set the update only goes
+// ahead if it is still the blog's version, and ErrPreconditionFailed is
+// returned otherwise. The returned blog carries its new version.
+func (s *BlogsService) UpdateBlog(ctx context.Context, id uint64, patch models.Blog, editorId uint) (models.Blog, error) {
 	s.logger.DebugContext(ctx, "Updating blog", "id", id)
 
 	//validate authod_id exists in user table
@@ -127,9 +238,9 @@ func (s *BlogsService) UpdateBlog(ctx context.Context, id uint64, patch models.B
 		`
 		SELECT 1
 		FROM users
-		WHERE id = $1::int
+		WHERE id = $1::int AND deleted_at IS NULL
         `,
-		id,
+		patch.AuthorID,
 	)
 
 	var exists int
@@ -138,72 +249,605 @@ func (s *BlogsService) UpdateBlog(ctx context.Context, id uint64, patch models.B
 
 	if err != nil {
 		return models.Blog{}, fmt.Errorf(
-			"[in services.BlogsService.UpdateBlog] failed to update blog: %w",
+			"[in services.BlogsService.UpdateBlog] author %d: %w",
+			patch.AuthorID,
+			replaceNoRows(err, ErrInvalidReference),
+		)
+	}
+
+	if patch.Excerpt, err = excerptOf(patch); err != nil {
+		return models.Blog{}, fmt.Errorf("[in services.BlogsService.UpdateBlog] %w", err)
+	}
+
+	patch.Category = s.tags.NormalizeCategory(patch.Category)
+	patch.Tags = s.tags.NormalizeAll(patch.Tags)
+
+	err = withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		patch, err = s.updateBlog(ctx, tx, id, patch, editorId)
+		return err
+	})
+
+	if err != nil {
+		return models.Blog{}, fmt.Errorf(
+			"[in services.BlogsService.UpdateBlog] %w",
 			err,
 		)
 	}
+	return patch, nil
+}
+
+// updateBlog performs the writes of UpdateBlog within tx. The patch must
+// already be normalized and carry its excerpt.
+func (s *BlogsService) updateBlog(ctx context.Context, tx *sql.Tx, id uint64, patch models.Blog, editorId uint) (models.Blog, error) {
+	// Lock the blog so that the scheduler can't publish it between
+	// checking the transition and writing it
+	var current models.Blog
+	var publishAt sql.NullTime
+	var tags string
+
+	err := tx.QueryRowContext(
+		ctx,
+		fmt.Sprintf(`
+		SELECT status, publish_at, version, %s FROM blogs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		`, blogTagsColumn),
+		id,
+	).Scan(&current.Status, &publishAt, &current.Version, &tags)
+	if err != nil {
+		return models.Blog{}, fmt.Errorf("failed to read blog %d: %w", id, replaceNoRows(err, ErrNotFound))
+	}
+	current.PublishAt = publishAt.Time
+	current.Tags = splitTags(tags)
+
+	if err = checkVersion(current.Version, patch.Version); err != nil {
+		return models.Blog{}, fmt.Errorf("blog %d: %w", id, err)
+	}
+
+	if err = applyStatus(&patch, current, s.now()); err != nil {
+		return models.Blog{}, fmt.Errorf("blog %d: %w", id, err)
+	}
+
+	if err = recordBlogRevision(ctx, tx, uint(id), editorId); err != nil {
+		return models.Blog{}, err
+	}
+
+	// The version trigger only compares the content columns, so a change of
+	// status, publish time or tags moves the blog on to a new version here
+	edited := patch.Status != current.Status ||
+		!patch.PublishAt.Equal(current.PublishAt) ||
+		!slices.Equal(patch.Tags, current.Tags)
 
 	// should we be able to update created date?
-	row := s.db.QueryRowContext(
+	row := tx.QueryRowContext(
 		ctx,
 		`
 		UPDATE blogs
-		SET author_id = $1, title = $2, score = $3
-		WHERE id = $4
-		RETURNING created_date
+		SET author_id = $1, title = $2, body = $3, excerpt = $4, category = $5, status = $6, publish_at = $7,
+		    version = CASE WHEN $8 THEN version + 1 ELSE version END
+		WHERE id = $9
+		RETURNING score, created_date, version
 		`,
 		patch.AuthorID,
 		patch.Title,
-		patch.Score,
+		patch.Body,
+		patch.Excerpt,
+		patch.Category,
+		patch.Status,
+		nullTime(patch.PublishAt),
+		edited,
 		id,
 	)
-	err = row.Scan(&patch.CreatedDate)
+	if err = row.Scan(&patch.Score, &patch.CreatedDate, &patch.Version); err != nil {
+		return models.Blog{}, fmt.Errorf("failed to update blog %d: %w", id, translateError(err))
+	}
+
+	if err = s.tags.SetBlogTags(ctx, tx, uint(id), patch.Tags); err != nil {
+		return models.Blog{}, err
+	}
+
+	patch.ID = uint(id)
+	return patch, nil
+}
+
+// PatchBlog attempts to apply patch to the blog with the provided id, writing
+// only the columns it changes. The fields that are set are normalized and
+// checked the way UpdateBlog does, a revision is only recorded when the
+// content changes, and the tags are only replaced when they change. A patch
+// that changes nothing leaves the blog as it is. If patch.Version is set the
+// patch only goes ahead if it is still the blog's version, and
+// ErrPreconditionFailed is returned otherwise. The patched models.Blog or an
+// error is returned.
+func (s *BlogsService) PatchBlog(ctx context.Context, id uint64, patch models.BlogPatch, editorId uint) (models.Blog, error) {
+	s.logger.DebugContext(ctx, "Patching blog", "id", id)
+
+	if patch.AuthorID != nil {
+		var exists int
+		err := s.db.QueryRowContext(
+			ctx,
+			`
+			SELECT 1
+			FROM users
+			WHERE id = $1::int AND deleted_at IS NULL
+			`,
+			*patch.AuthorID,
+		).Scan(&exists)
+		if err != nil {
+			return models.Blog{}, fmt.Errorf(
+				"[in services.BlogsService.PatchBlog] author %d: %w",
+				*patch.AuthorID,
+				replaceNoRows(err, ErrInvalidReference),
+			)
+		}
+	}
+
+	var blog models.Blog
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var publishAt sql.NullTime
+		var tags string
+
+		err := tx.QueryRowContext(
+			ctx,
+			fmt.Sprintf(`
+			SELECT id, author_id, title, body, excerpt, status, publish_at, score, created_date, category, version, %s
+			FROM blogs
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+			`, blogTagsColumn),
+			id,
+		).Scan(
+			&blog.ID,
+			&blog.AuthorID,
+			&blog.Title,
+			&blog.Body,
+			&blog.Excerpt,
+			&blog.Status,
+			&publishAt,
+			&blog.Score,
+			&blog.CreatedDate,
+			&blog.Category,
+			&blog.Version,
+			&tags,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to read blog %d: %w", id, replaceNoRows(err, ErrNotFound))
+		}
+		blog.PublishAt = publishAt.Time
+		blog.Tags = splitTags(tags)
+
+		if err = checkVersion(blog.Version, patch.Version); err != nil {
+			return fmt.Errorf("blog %d: %w", id, err)
+		}
+
+		current := blog
+		var changes changeSet
+
+		if patch.AuthorID != nil && *patch.AuthorID != current.AuthorID {
+			blog.AuthorID = *patch.AuthorID
+			changes.set("author_id", blog.AuthorID)
+		}
+
+		if patch.Status != nil || patch.PublishAt != nil {
+			var next models.Blog
+			if patch.Status != nil {
+				next.Status = *patch.Status
+			}
+			if patch.PublishAt != nil {
+				next.PublishAt = *patch.PublishAt
+			}
+			if err = applyStatus(&next, current, s.now()); err != nil {
+				return fmt.Errorf("blog %d: %w", id, err)
+			}
+
+			if next.Status != current.Status {
+				blog.Status = next.Status
+				changes.set("status", blog.Status)
+			}
+			if !next.PublishAt.Equal(current.PublishAt) {
+				blog.PublishAt = next.PublishAt
+				changes.set("publish_at", nullTime(blog.PublishAt))
+			}
+		}
+
+		// The content is compared once it is all patched, as a new body may
+		// need a new excerpt
+		if patch.Title != nil {
+			blog.Title = *patch.Title
+		}
+		if patch.Body != nil {
+			blog.Body = *patch.Body
+		}
+		if patch.Excerpt != nil {
+			blog.Excerpt = *patch.Excerpt
+		}
+		if patch.Category != nil {
+			blog.Category = s.tags.NormalizeCategory(*patch.Category)
+		}
+		if patch.Tags != nil {
+			blog.Tags = s.tags.NormalizeAll(*patch.Tags)
+		}
+		if blog.Excerpt, err = excerptOf(blog); err != nil {
+			return err
+		}
+
+		contentChanged := false
+		for _, column := range []struct {
+			name           string
+			current, value string
+		}{
+			{"title", current.Title, blog.Title},
+			{"body", current.Body, blog.Body},
+			{"excerpt", current.Excerpt, blog.Excerpt},
+			{"category", current.Category, blog.Category},
+		} {
+			if column.value != column.current {
+				changes.set(column.name, column.value)
+				contentChanged = true
+			}
+		}
+
+		tagsChanged := !slices.Equal(blog.Tags, current.Tags)
+		if tagsChanged || blog.Status != current.Status || !blog.PublishAt.Equal(current.PublishAt) {
+			// The version trigger only compares the content columns, and the
+			// tags live in their own table, so the blog is moved on to a new
+			// version here
+			changes.set("version", current.Version+1)
+		}
+
+		if changes.empty() {
+			return nil
+		}
+
+		if contentChanged || tagsChanged {
+			if err = recordBlogRevision(ctx, tx, uint(id), editorId); err != nil {
+				return err
+			}
+		}
+
+		err = tx.QueryRowContext(ctx, changes.update("blogs", "version"), append(changes.args, id)...).Scan(&blog.Version)
+		if err != nil {
+			return fmt.Errorf("failed to update blog %d: %w", id, translateError(err))
+		}
+
+		if tagsChanged {
+			return s.tags.SetBlogTags(ctx, tx, uint(id), blog.Tags)
+		}
+
+		return nil
+	})
 
 	if err != nil {
 		return models.Blog{}, fmt.Errorf(
-			"[in services.BlogsService.UpdateBlog] failed to update blog: %w",
+			"[in services.BlogsService.PatchBlog] %w",
 			err,
 		)
 	}
-	patch.ID = uint(id)
-	return patch, nil
+
+	return blog, nil
 }
 
-// DeleteBlog attempts to delete the blog with the provided id. An error is
-// returned if the delete fails.
-func (s *BlogsService) DeleteBlog(ctx context.Context, id uint64) error {
-	s.logger.DebugContext(ctx, "Deleting blog", "id", id)
+// ListBlogRevisions attempts to list a page of the revisions of the blog with
+// blogId, newest first. Each revision carries a diff to the content the
+// update it records left, which for the newest revision is the blog's current
+// content. A page of models.BlogRevision or an error is returned.
+// ErrNotFound is returned if the blog does not exist.
+func (s *BlogsService) ListBlogRevisions(ctx context.Context, blogId uint, page models.PageRequest) (models.Page[models.BlogRevision], error) {
+	s.logger.DebugContext(ctx, "Listing blog revisions", "Blog Id", blogId)
 
-	//DB transaction
+	current := models.BlogRevision{BlogID: blogId}
+	var tags string
 
-	//DELETE from blog from blogs
-	_, err := s.db.ExecContext(
+	err := s.db.QueryRowContext(
 		ctx,
-		`
-		DELETE FROM blogs WHERE id = $1::int
-		`,
-		id,
+		fmt.Sprintf(`
+		SELECT title, body, excerpt, category, %s
+		FROM blogs
+		WHERE id = $1 AND deleted_at IS NULL
+		`, blogTagsColumn),
+		blogId,
+	).Scan(&current.Title, &current.Body, &current.Excerpt, &current.Category, &tags)
+	if err != nil {
+		return models.Page[models.BlogRevision]{}, fmt.Errorf(
+			"[in services.BlogsService.ListBlogRevisions] blog %d: %w",
+			blogId,
+			replaceNoRows(err, ErrNotFound),
+		)
+	}
+	current.Tags = splitTags(tags)
+
+	// Each revision is diffed against the next one, read alongside it
+	q := listQuery{
+		columns: "id, blog_id, editor_id, title, body, excerpt, category, tags, created_date, " +
+			"next_title, next_body, next_excerpt, next_category, next_tags",
+		from: `(
+			SELECT r.*,
+			       LEAD(title) OVER next AS next_title,
+			       LEAD(body) OVER next AS next_body,
+			       LEAD(excerpt) OVER next AS next_excerpt,
+			       LEAD(category) OVER next AS next_category,
+			       LEAD(tags) OVER next AS next_tags
+			FROM blog_revisions r
+			WINDOW next AS (PARTITION BY blog_id ORDER BY id)
+		) revisions`,
+		sort: []sortColumn{
+			{column: "id", cast: "bigint", desc: true},
+		},
+	}
+	q.where("blog_id = $%d", blogId)
+
+	revisions, err := listPage(
+		ctx,
+		s.db,
+		q,
+		page,
+		func(rows *sql.Rows) (models.BlogRevision, error) {
+			var revision models.BlogRevision
+			var editorID sql.NullInt64
+			var tags string
+			var nextTitle, nextBody, nextExcerpt, nextCategory, nextTags sql.NullString
+
+			err := rows.Scan(
+				&revision.ID,
+				&revision.BlogID,
+				&editorID,
+				&revision.Title,
+				&revision.Body,
+				&revision.Excerpt,
+				&revision.Category,
+				&tags,
+				&revision.CreatedDate,
+				&nextTitle,
+				&nextBody,
+				&nextExcerpt,
+				&nextCategory,
+				&nextTags,
+			)
+			if err != nil {
+				return models.BlogRevision{}, err
+			}
+			revision.EditorID = uint(editorID.Int64)
+			revision.Tags = splitTags(tags)
+
+			next := current
+			if nextTitle.Valid {
+				next = models.BlogRevision{
+					Title:    nextTitle.String,
+					Body:     nextBody.String,
+					Excerpt:  nextExcerpt.String,
+					Category: nextCategory.String,
+					Tags:     splitTags(nextTags.String),
+				}
+			}
+			revision.Diff = diff.Lines(blogDocument(revision), blogDocument(next))
+
+			return revision, nil
+		},
+		func(revision models.BlogRevision) []any {
+			return []any{revision.ID}
+		},
 	)
+	if err != nil {
+		return models.Page[models.BlogRevision]{}, fmt.Errorf(
+			"[in services.BlogsService.ListBlogRevisions] failed to list revisions: %w",
+			err,
+		)
+	}
+
+	return revisions, nil
+}
+
+// RestoreBlogRevision attempts to put the content of the revision with
+// revisionId back on the blog with blogId. Like any other update, the content
+// it replaces is kept as a new revision made by the editor with editorId. The
+// blog's status is left as it is. If version is not zero the revision is only
+// restored if it is still the blog's version, and ErrPreconditionFailed is
+// returned otherwise. The restored models.Blog or an error is returned.
+// ErrNotFound is returned if the blog does not exist or has no such revision.
+func (s *BlogsService) RestoreBlogRevision(ctx context.Context, blogId uint, revisionId uint, editorId uint, version uint) (models.Blog, error) {
+	s.logger.DebugContext(ctx, "Restoring blog revision", "Blog Id", blogId, "Revision Id", revisionId)
+
+	var blog models.Blog
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Lock the blog so that no other update slips in between keeping its
+		// content and replacing it
+		var exists int
+
+		err := tx.QueryRowContext(
+			ctx,
+			`
+			SELECT 1 FROM blogs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+			`,
+			blogId,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to read blog %d: %w", blogId, replaceNoRows(err, ErrNotFound))
+		}
+
+		var tags string
+
+		err = tx.QueryRowContext(
+			ctx,
+			`
+			SELECT title, body, excerpt, category, tags FROM blog_revisions WHERE id = $1 AND blog_id = $2
+			`,
+			revisionId,
+			blogId,
+		).Scan(&blog.Title, &blog.Body, &blog.Excerpt, &blog.Category, &tags)
+		if err != nil {
+			return fmt.Errorf("failed to read revision %d: %w", revisionId, replaceNoRows(err, ErrNotFound))
+		}
+		blog.Tags = splitTags(tags)
+
+		if err = recordBlogRevision(ctx, tx, blogId, editorId); err != nil {
+			return err
+		}
+
+		var publishAt sql.NullTime
+
+		// The blog is locked, so if it isn't updated it has moved on from the
+		// version the restore was made against. Restoring records a revision
+		// even when the content is unchanged, so the version always goes up.
+		err = tx.QueryRowContext(
+			ctx,
+			`
+			UPDATE blogs
+			SET title = $1, body = $2, excerpt = $3, category = $4, version = version + 1
+			WHERE id = $5 AND ($6::bigint = 0 OR version = $6::bigint)
+			RETURNING id, author_id, status, publish_at, score, created_date, version
+			`,
+			blog.Title,
+			blog.Body,
+			blog.Excerpt,
+			blog.Category,
+			blogId,
+			version,
+		).Scan(&blog.ID, &blog.AuthorID, &blog.Status, &publishAt, &blog.Score, &blog.CreatedDate, &blog.Version)
+		if err != nil {
+			return fmt.Errorf("failed to update blog %d: %w", blogId, replaceNoRows(translateError(err), ErrPreconditionFailed))
+		}
+		blog.PublishAt = publishAt.Time
+
+		return s.tags.SetBlogTags(ctx, tx, blogId, blog.Tags)
+	})
+
+	if err != nil {
+		return models.Blog{}, fmt.Errorf(
+			"[in services.BlogsService.RestoreBlogRevision] %w",
+			err,
+		)
+	}
+
+	return blog, nil
+}
+
+// DeleteBlog attempts to delete the blog with the provided id and its
+// comments in a single transaction. They are deleted at the same time so that
+// RestoreBlog can bring them back, until they are purged. If version is not
+// zero the blog is only deleted if it is still the blog's version, and
+// ErrPreconditionFailed is returned otherwise. An error is returned if the
+// delete fails.
+func (s *BlogsService) DeleteBlog(ctx context.Context, id uint64, version uint) error {
+	s.logger.DebugContext(ctx, "Deleting blog", "id", id)
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		return s.deleteBlog(ctx, tx, id, version)
+	})
 
 	if err != nil {
 		return fmt.Errorf(
-			"[in services.BlogsService.DeleteBlog] failed to delete blog: %w",
+			"[in services.BlogsService.DeleteBlog] %w",
 			err,
 		)
 	}
 
-	//DELETE all comments with blog_id of deleted blog
-	_, err = s.db.ExecContext(
+	return nil
+}
+
+// deleteBlog performs DeleteBlog within tx.
+func (s *BlogsService) deleteBlog(ctx context.Context, tx *sql.Tx, id uint64, version uint) error {
+	var current uint
+	err := tx.QueryRowContext(
 		ctx,
 		`
-		DELETE FROM comments WHERE blog_id = $1::int
+		SELECT version FROM blogs WHERE id = $1::int AND deleted_at IS NULL FOR UPDATE
+		`,
+		id,
+	).Scan(&current)
+	if err != nil {
+		return fmt.Errorf("blog %d: %w", id, replaceNoRows(err, ErrNotFound))
+	}
+
+	if err = checkVersion(current, version); err != nil {
+		return fmt.Errorf("blog %d: %w", id, err)
+	}
+
+	// Delete all comments with blog_id of deleted blog. CURRENT_TIMESTAMP
+	// is the same for every statement in the transaction.
+	_, err = tx.ExecContext(
+		ctx,
+		`
+		UPDATE comments SET deleted_at = CURRENT_TIMESTAMP WHERE blog_id = $1::int AND deleted_at IS NULL
 		`,
 		id,
 	)
+	if err != nil {
+		return fmt.Errorf("failed to delete comments of deleted blog: %w", err)
+	}
+
+	// Delete the blog from blogs
+	result, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE blogs SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1::int AND deleted_at IS NULL
+		`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete blog: %w", err)
+	}
+
+	if err = expectAffected(result); err != nil {
+		return fmt.Errorf("blog %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// RestoreBlog attempts to restore the deleted blog with the provided id, along
+// with the comments that were deleted with it, in a single transaction.
+// Comments deleted on their own stay deleted. ErrNotFound is returned if there
+// is no such deleted blog, or if its author is deleted too and has to be
+// restored instead.
+func (s *BlogsService) RestoreBlog(ctx context.Context, id uint64) error {
+	s.logger.DebugContext(ctx, "Restoring blog", "id", id)
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var deletedAt time.Time
+		err := tx.QueryRowContext(
+			ctx,
+			`
+			SELECT b.deleted_at
+			FROM blogs b
+			JOIN users u ON u.id = b.author_id AND u.deleted_at IS NULL
+			WHERE b.id = $1::int AND b.deleted_at IS NOT NULL
+			FOR UPDATE OF b
+			`,
+			id,
+		).Scan(&deletedAt)
+		if err != nil {
+			return fmt.Errorf("deleted blog %d: %w", id, replaceNoRows(err, ErrNotFound))
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`
+			UPDATE comments SET deleted_at = NULL WHERE blog_id = $1::int AND deleted_at = $2
+			`,
+			id,
+			deletedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore comments: %w", err)
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`
+			UPDATE blogs SET deleted_at = NULL WHERE id = $1::int
+			`,
+			id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore blog: %w", err)
+		}
+
+		return nil
+	})
 
 	if err != nil {
 		return fmt.Errorf(
-			"[in services.BlogsService.DeleteBlog] failed to delete comments of deleted blog: %w",
+			"[in services.BlogsService.RestoreBlog] %w",
 			err,
 		)
 	}
@@ -211,52 +855,825 @@ func (s *BlogsService) DeleteBlog(ctx context.Context, id uint64) error {
 	return nil
 }
 
-// ListBlogs attempts to list all blogs in the database. A slice of models.Blog
-// or an error is returned.
-// WHY DO WE NEED ID?
-func (s *BlogsService) ListBlogs(ctx context.Context, title string) ([]models.Blog, error) {
+// Vote records the rating the user gives a blog, replacing any rating they
+// gave it before, and recomputes the blog's score in the same transaction.
+// Only published blogs can be voted on, and ErrNotFound is returned for any
+// other. The blog's new score or an error is returned.
+func (s *BlogsService) Vote(ctx context.Context, vote models.Vote) (float32, error) {
+	s.logger.DebugContext(ctx, "Voting on blog", "Blog Id", vote.BlogID, "User Id", vote.UserID)
+
+	var score float32
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Lock the blog so that concurrent votes are counted one at a time
+		var exists int
+
+		err := tx.QueryRowContext(
+			ctx,
+			`
+			SELECT 1 FROM blogs WHERE id = $1 AND status = 'published' AND deleted_at IS NULL FOR UPDATE
+			`,
+			vote.BlogID,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to read blog %d: %w", vote.BlogID, replaceNoRows(err, ErrNotFound))
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`
+			INSERT INTO votes (user_id, blog_id, rating) VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, blog_id) DO UPDATE SET rating = EXCLUDED.rating
+			`,
+			vote.UserID,
+			vote.BlogID,
+			vote.Rating,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record vote: %w", translateError(err))
+		}
+
+		score, err = rescore(ctx, tx, vote.BlogID)
+		return err
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[in services.BlogsService.Vote] %w",
+			err,
+		)
+	}
+
+	return score, nil
+}
+
+// DeleteVote removes the user's vote on a blog and recomputes the blog's
+// score in the same transaction. ErrNotFound is returned if the blog doesn't
+// exist or the user hasn't voted on it. The blog's new score or an error is
+// returned.
+func (s *BlogsService) DeleteVote(ctx context.Context, blogId uint, userId uint) (float32, error) {
+	s.logger.DebugContext(ctx, "Deleting vote", "Blog Id", blogId, "User Id", userId)
+
+	var score float32
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Lock the blog so that concurrent votes are counted one at a time
+		var exists int
+
+		err := tx.QueryRowContext(
+			ctx,
+			`
+			SELECT 1 FROM blogs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+			`,
+			blogId,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to read blog %d: %w", blogId, replaceNoRows(err, ErrNotFound))
+		}
+
+		result, err := tx.ExecContext(
+			ctx,
+			`
+			DELETE FROM votes WHERE user_id = $1 AND blog_id = $2
+			`,
+			userId,
+			blogId,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to delete vote: %w", err)
+		}
+
+		if err = expectAffected(result); err != nil {
+			return fmt.Errorf("vote by user %d on blog %d: %w", userId, blogId, err)
+		}
+
+		score, err = rescore(ctx, tx, blogId)
+		return err
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[in services.BlogsService.DeleteVote] %w",
+			err,
+		)
+	}
+
+	return score, nil
+}
+
+// rescore sets the score of the blog with blogId to the mean of its votes, or
+// zero if it has none, and returns it.
+func rescore(ctx context.Context, tx *sql.Tx, blogId uint) (float32, error) {
+	var score float32
+
+	err := tx.QueryRowContext(
+		ctx,
+		`
+		UPDATE blogs
+		SET score = COALESCE((SELECT AVG(rating) FROM votes WHERE blog_id = $1), 0)
+		WHERE id = $1
+		RETURNING score
+		`,
+		blogId,
+	).Scan(&score)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update score of blog %d: %w", blogId, err)
+	}
+
+	return score, nil
+}
+
+// blogSortColumns are the columns blogs can be sorted by, keyed by the field
+// name used in sort parameters.
+var blogSortColumns = map[string]sortColumn{
+	"id":           {column: "id", cast: "bigint"},
+	"author_id":    {column: "author_id", cast: "bigint"},
+	"title":        {column: "title", cast: "text"},
+	"score":        {column: "score", cast: "real"},
+	"created_date": {column: "created_date", cast: "timestamp"},
+}
+
+// ListBlogs attempts to list a page of the blogs in the database matching
+// filter, in the order given by sort and then by id. Blogs that aren't
+// published are left out unless the viewer wrote them or filter.AllBlogs is
+// set. Listed blogs carry
+// their excerpt but not their body, and the related records named by expand,
+// read in the same query. A page of models.Blog or an error is returned.
+// ErrInvalidSort is returned if sort names a field blogs can't be sorted by.
+func (s *BlogsService) ListBlogs(
+	ctx context.Context,
+	filter models.BlogFilter,
+	sort []models.SortField,
+	page models.PageRequest,
+	expand models.BlogExpand,
+) (models.Page[models.Blog], error) {
 	s.logger.DebugContext(ctx, "Listing blogs")
 
-	rows, err := s.db.QueryContext(
+	columns, err := sortColumns(sort, blogSortColumns, blogSortColumns["id"])
+	if err != nil {
+		return models.Page[models.Blog]{}, fmt.Errorf("[in services.BlogsService.ListBlogs] %w", err)
+	}
+
+	expandColumns, joins := blogExpansion(expand)
+
+	q := listQuery{
+		columns: "id, author_id, title, excerpt, status, publish_at, score, created_date, category, " + blogTagsColumn + expandColumns,
+		from:    "blogs" + joins,
+		sort:    columns,
+	}
+	switch {
+	case filter.AllBlogs:
+	case filter.ViewerID > 0:
+		q.where("(status = 'published' OR author_id = $%d)", filter.ViewerID)
+	default:
+		q.where("status = 'published'")
+	}
+	if filter.Status != "" {
+		q.where("status = $%d", filter.Status)
+	}
+	if filter.AuthorID > 0 {
+		q.where("author_id = $%d", filter.AuthorID)
+	}
+	if filter.Category != "" {
+		q.where("category = $%d", s.tags.Normalize(filter.Category))
+	}
+	if tags := s.tags.NormalizeAll(filter.Tags); len(tags) > 0 {
+		whereTagged(&q, tags, filter.TagMatch)
+	}
+	if filter.Title != "" {
+		q.where("title ILIKE $%d", "%"+escapeLike(filter.Title)+"%")
+	}
+	if filter.MinScore != nil {
+		q.where("score >= $%d", *filter.MinScore)
+	}
+	if filter.MaxScore != nil {
+		q.where("score <= $%d", *filter.MaxScore)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		q.where("created_date >= $%d", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		q.where("created_date < $%d", filter.CreatedBefore)
+	}
+	q.withDeleted(filter.IncludeDeleted)
+
+	blogs, err := listPage(
+		ctx,
+		s.db,
+		q,
+		page,
+		func(rows *sql.Rows) (models.Blog, error) {
+			var blog models.Blog
+			var publishAt, deletedAt sql.NullTime
+			var tags string
+			dest := []any{
+				&blog.ID,
+				&blog.AuthorID,
+				&blog.Title,
+				&blog.Excerpt,
+				&blog.Status,
+				&publishAt,
+				&blog.Score,
+				&blog.CreatedDate,
+				&blog.Category,
+				&tags,
+			}
+			if expand.Author {
+				dest = append(dest, &blog.Author.Name)
+			}
+			if filter.IncludeDeleted {
+				dest = append(dest, &deletedAt)
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return models.Blog{}, err
+			}
+			blog.PublishAt = publishAt.Time
+			blog.DeletedAt = deletedAt.Time
+			blog.Tags = splitTags(tags)
+			if expand.Author {
+				blog.Author.ID = blog.AuthorID
+			}
+			return blog, nil
+		},
+		func(blog models.Blog) []any {
+			return blogSortKey(blog, columns)
+		},
+	)
+	if err != nil {
+		return models.Page[models.Blog]{}, fmt.Errorf(
+			"[in services.BlogsService.ListBlogs] failed to list blogs: %w",
+			err,
+		)
+	}
+
+	return blogs, nil
+}
+
+// whereTagged restricts q to blogs carrying any of tags, or all of them when
+// match is models.TagMatchAll.
+func whereTagged(q *listQuery, tags []string, match models.TagMatch) {
+	args := make([]any, len(tags))
+	for i, tag := range tags {
+		args[i] = tag
+	}
+
+	condition := "id IN (SELECT bt.blog_id FROM blog_tags bt JOIN tags t ON t.id = bt.tag_id WHERE t.name IN (" +
+		strings.TrimSuffix(strings.Repeat("$%d, ", len(tags)), ", ") + ")"
+	if match == models.TagMatchAll {
+		condition += " GROUP BY bt.blog_id HAVING COUNT(*) = $%d"
+		args = append(args, len(tags))
+	}
+
+	q.where(condition+")", args...)
+}
+
+// ListUserBlogs attempts to list a page of the blogs written by the user with
+// userId, newest first. Blogs that aren't published are left out unless the
+// viewer wrote them or allBlogs is set. Listed blogs carry only their id,
+// title and created date. ErrNotFound is returned if the user does not exist.
+func (s *BlogsService) ListUserBlogs(ctx context.Context, userId uint, viewerId uint, allBlogs bool, page models.PageRequest) (models.Page[models.Blog], error) {
+	s.logger.DebugContext(ctx, "Listing user blogs", "User Id", userId)
+
+	//validate user exists with user_id
+	user := s.db.QueryRowContext(
 		ctx,
 		`
-		SELECT id, author_id, title, score, created_date
-		FROM blogs
+		SELECT 1
+		FROM users
+		WHERE id = $1::int AND deleted_at IS NULL
         `,
+		userId,
 	)
 
+	var userExists int
+
+	err := user.Scan(&userExists)
+
+	if err != nil {
+		return models.Page[models.Blog]{}, fmt.Errorf(
+			"[in services.BlogsService.ListUserBlogs] user %d: %w",
+			userId,
+			replaceNoRows(err, ErrNotFound),
+		)
+	}
+
+	q := listQuery{
+		columns: "id, title, created_date",
+		from:    "blogs",
+		sort: []sortColumn{
+			{column: "created_date", cast: "timestamp", desc: true},
+			{column: "id", cast: "bigint", desc: true},
+		},
+	}
+	q.where("author_id = $%d", userId)
+	q.where("deleted_at IS NULL")
+	if viewerId != userId && !allBlogs {
+		q.where("status = 'published'")
+	}
+
+	blogs, err := listPage(
+		ctx,
+		s.db,
+		q,
+		page,
+		func(rows *sql.Rows) (models.Blog, error) {
+			blog := models.Blog{AuthorID: userId}
+			err := rows.Scan(&blog.ID, &blog.Title, &blog.CreatedDate)
+			return blog, err
+		},
+		func(blog models.Blog) []any {
+			return []any{blog.CreatedDate, blog.ID}
+		},
+	)
 	if err != nil {
-		return []models.Blog{}, fmt.Errorf(
-			"[in services.CommentsService.ListComment] failed to read blogs: %w",
+		return models.Page[models.Blog]{}, fmt.Errorf(
+			"[in services.BlogsService.ListUserBlogs] failed to list blogs: %w",
 			err,
 		)
 	}
 
-	var blogs []models.Blog
+	return blogs, nil
+}
 
-	// error handling for error in for loop and then for empty error out of for loop?
-	// NEED TO HANDLE ERROR FOR EMPTY RESULTS
-	for rows.Next() {
-		var blog models.Blog
-		err := rows.Scan(&blog.ID, &blog.AuthorID, &blog.Title, &blog.Score, &blog.CreatedDate)
-		if err != nil {
-			return []models.Blog{}, fmt.Errorf(
-				"[in services.BlogsService.ListBlog] failed to read blogs: %w",
-				err,
-			)
-		}
-		if len(title) == 0 || blog.Title == title {
-			blogs = append(blogs, blog)
-		}
+// PublishDue publishes every scheduled blog whose publish time has come,
+// returning how many were published.
+func (s *BlogsService) PublishDue(ctx context.Context) (int64, error) {
+	s.logger.DebugContext(ctx, "Publishing due blogs")
 
+	result, err := s.db.ExecContext(
+		ctx,
+		`
+		UPDATE blogs SET status = 'published' WHERE status = 'scheduled' AND publish_at <= $1 AND deleted_at IS NULL
+		`,
+		s.now().UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[in services.BlogsService.PublishDue] failed to publish blogs: %w",
+			err,
+		)
 	}
 
-	if err = rows.Err(); err != nil {
-		return []models.Blog{}, fmt.Errorf(
-			"[in services.BlogsService.ListBlog] failed to read blogs: %w",
+	published, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[in services.BlogsService.PublishDue] failed to read affected rows: %w",
 			err,
 		)
 	}
 
-	return blogs, nil
+	return published, nil
+}
+
+// blogTransitions lists the statuses a blog may move to from each status. The
+// empty status stands for a blog that is being created.
+var blogTransitions = map[models.BlogStatus][]models.BlogStatus{
+	"": {
+		models.BlogStatusDraft,
+		models.BlogStatusScheduled,
+		models.BlogStatusPublished,
+	},
+	models.BlogStatusDraft: {
+		models.BlogStatusDraft,
+		models.BlogStatusScheduled,
+		models.BlogStatusPublished,
+	},
+	models.BlogStatusScheduled: {
+		models.BlogStatusDraft,
+		models.BlogStatusScheduled,
+		models.BlogStatusPublished,
+	},
+	models.BlogStatusPublished: {
+		models.BlogStatusPublished,
+		models.BlogStatusArchived,
+	},
+	models.BlogStatusArchived: {
+		models.BlogStatusDraft,
+		models.BlogStatusPublished,
+		models.BlogStatusArchived,
+	},
+}
+
+// applyStatus checks that blog may move from the status of current to its
+// own, and sets its publish time to match. An empty status keeps the current
+// one, or makes a new blog a draft. Times are stored in UTC.
+func applyStatus(blog *models.Blog, current models.Blog, now time.Time) error {
+	if blog.Status == "" {
+		blog.Status = current.Status
+	}
+	if blog.Status == "" {
+		blog.Status = models.BlogStatusDraft
+	}
+
+	if !slices.Contains(blogTransitions[current.Status], blog.Status) {
+		return fmt.Errorf("%w: %q to %q", ErrInvalidTransition, current.Status, blog.Status)
+	}
+
+	switch blog.Status {
+	case models.BlogStatusDraft:
+		blog.PublishAt = time.Time{}
+	case models.BlogStatusScheduled:
+		if blog.PublishAt.IsZero() {
+			blog.PublishAt = current.PublishAt
+		}
+		if !blog.PublishAt.After(now) {
+			return fmt.Errorf("%w: publish time %s has passed", ErrInvalidTransition, blog.PublishAt.Format(time.RFC3339))
+		}
+	case models.BlogStatusPublished:
+		// Blogs keep the time they were first published, and blogs published
+		// early are published now
+		switch {
+		case current.Status == models.BlogStatusPublished || current.Status == models.BlogStatusArchived:
+			blog.PublishAt = current.PublishAt
+		case blog.PublishAt.IsZero() || blog.PublishAt.After(now):
+			blog.PublishAt = now
+		}
+	case models.BlogStatusArchived:
+		blog.PublishAt = current.PublishAt
+	}
+	blog.PublishAt = blog.PublishAt.UTC()
+
+	return nil
+}
+
+// nullTime returns t as a nullable timestamp, which is NULL when t is zero.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// recordBlogRevision keeps the current content of the blog with blogId as a
+// revision made by the editor with editorId, before it is updated in tx.
+func recordBlogRevision(ctx context.Context, tx *sql.Tx, blogId uint, editorId uint) error {
+	_, err := tx.ExecContext(
+		ctx,
+		fmt.Sprintf(`
+		INSERT INTO blog_revisions (blog_id, editor_id, title, body, excerpt, category, tags)
+		SELECT id, $2, title, body, excerpt, category, %s
+		FROM blogs
+		WHERE id = $1
+		`, blogTagsColumn),
+		blogId,
+		nullID(editorId),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record revision of blog %d: %w", blogId, translateError(err))
+	}
+
+	return nil
+}
+
+// blogDocument lays out the content of a blog revision as text to be diffed:
+// a line for each of the title, excerpt, category and tags, then a blank line
+// and the body.
+func blogDocument(revision models.BlogRevision) string {
+	return fmt.Sprintf(
+		"Title: %s\nExcerpt: %s\nCategory: %s\nTags: %s\n\n%s",
+		revision.Title,
+		revision.Excerpt,
+		revision.Category,
+		strings.Join(revision.Tags, ", "),
+		revision.Body,
+	)
+}
+
+// excerptOf returns the excerpt of blog, generating one from its body when
+// none was provided.
+func excerptOf(blog models.Blog) (string, error) {
+	if blog.Excerpt != "" || blog.Body == "" {
+		return blog.Excerpt, nil
+	}
+
+	excerpt, err := markdown.Excerpt(blog.Body, generatedExcerptLength)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate excerpt: %w", err)
+	}
+
+	return excerpt, nil
+}
+
+// blogExpansion returns the columns to select after the blog columns, and the
+// joins that provide them, for the related records named by expand. The joined
+// tables only expose renamed columns, so the blogs columns can still be
+// referred to unqualified.
+func blogExpansion(expand models.BlogExpand) (columns string, joins string) {
+	if expand.Author {
+		columns += ", author_name"
+		joins += " JOIN (SELECT id AS author_key, name AS author_name FROM users) author ON author.author_key = blogs.author_id"
+	}
+
+	return columns, joins
+}
+
+// blogSortKey returns the values of the sort columns of blog.
+func blogSortKey(blog models.Blog, columns []sortColumn) []any {
+	keys := make([]any, len(columns))
+	for i, column := range columns {
+		switch column.column {
+		case "id":
+			keys[i] = blog.ID
+		case "author_id":
+			keys[i] = blog.AuthorID
+		case "title":
+			keys[i] = blog.Title
+		case "score":
+			keys[i] = blog.Score
+		case "created_date":
+			keys[i] = blog.CreatedDate
+		}
+	}
+
+	return keys
+}
+
+// prepareBlogs fills in the excerpt of each of blogs and normalizes its
+// category and tags, recording the error of each blog whose excerpt can't be
+// made in errs.
+func (s *BlogsService) prepareBlogs(blogs []models.Blog, errs []error) {
+	for i := range blogs {
+		if blogs[i].Excerpt, errs[i] = excerptOf(blogs[i]); errs[i] != nil {
+			continue
+		}
+
+		blogs[i].Category = s.tags.NormalizeCategory(blogs[i].Category)
+		blogs[i].Tags = s.tags.NormalizeAll(blogs[i].Tags)
+	}
+}
+
+// checkAuthors fails each blog whose author doesn't exist with
+// ErrInvalidReference, reading the authors of every blog with one query.
+func checkAuthors(ctx context.Context, tx *sql.Tx, blogs []models.Blog, errs []error) error {
+	ids := make([]uint, 0, len(blogs))
+	for _, i := range pendingItems(errs) {
+		ids = append(ids, blogs[i].AuthorID)
+	}
+
+	authors, err := liveIDs(ctx, tx, "users", ids)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range pendingItems(errs) {
+		if !authors[blogs[i].AuthorID] {
+			errs[i] = fmt.Errorf("author %d: %w", blogs[i].AuthorID, ErrInvalidReference)
+		}
+	}
+
+	return nil
+}
+
+// CreateBlogs attempts to create the provided blogs in a single transaction,
+// with a single multi-row INSERT and one query checking all of their authors.
+// Each blog is prepared as CreateBlog does. A models.BulkResult is returned
+// for each blog, in order, and mode decides whether the blogs that could be
+// created are kept when others fail. An error is only returned if the bulk
+// write as a whole fails.
+func (s *BlogsService) CreateBlogs(ctx context.Context, blogs []models.Blog, mode models.BulkMode) ([]models.BulkResult[models.Blog], error) {
+	s.logger.DebugContext(ctx, "Creating blogs", "count", len(blogs), "mode", mode)
+
+	blogs = slices.Clone(blogs)
+	errs := make([]error, len(blogs))
+	s.prepareBlogs(blogs, errs)
+
+	now := s.now()
+	for _, i := range pendingItems(errs) {
+		errs[i] = applyStatus(&blogs[i], models.Blog{}, now)
+	}
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		if err := checkAuthors(ctx, tx, blogs, errs); err != nil {
+			return err
+		}
+
+		return insertItems(ctx, tx, mode, errs, func(batch []int) error {
+			args := make([]any, 0, 7*len(batch))
+			for _, i := range batch {
+				blog := blogs[i]
+				args = append(args, blog.AuthorID, blog.Title, blog.Body, blog.Excerpt, blog.Category, blog.Status, nullTime(blog.PublishAt))
+			}
+
+			err := insertRows(
+				ctx,
+				tx,
+				batch,
+				fmt.Sprintf(`
+				INSERT INTO blogs (author_id, title, body, excerpt, category, status, publish_at) VALUES %s RETURNING id, score, created_date
+				`, valueRows(len(batch), 7)),
+				args,
+				func(i int, rows *sql.Rows) error {
+					return rows.Scan(&blogs[i].ID, &blogs[i].Score, &blogs[i].CreatedDate)
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create blogs: %w", err)
+			}
+
+			for _, i := range batch {
+				if err = s.tags.SetBlogTags(ctx, tx, blogs[i].ID, blogs[i].Tags); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.BlogsService.CreateBlogs] %w", err)
+	}
+
+	return bulkResults("services.BlogsService.CreateBlogs", blogs, errs), nil
+}
+
+// UpdateBlogs attempts to update each of the provided blogs, picked out by
+// their ID, in a single transaction, checking all of their authors with one
+// query. Each blog is updated as UpdateBlog does, including its revision,
+// status transition and version check. A models.BulkResult is returned for
+// each blog, in order, and mode decides whether the blogs that could be
+// updated are kept when others fail. An error is only returned if the bulk
+// write as a whole fails.
+func (s *BlogsService) UpdateBlogs(ctx context.Context, blogs []models.Blog, editorId uint, mode models.BulkMode) ([]models.BulkResult[models.Blog], error) {
+	s.logger.DebugContext(ctx, "Updating blogs", "count", len(blogs), "mode", mode)
+
+	blogs = slices.Clone(blogs)
+	errs := make([]error, len(blogs))
+	s.prepareBlogs(blogs, errs)
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		if err := checkAuthors(ctx, tx, blogs, errs); err != nil {
+			return err
+		}
+
+		return eachItem(ctx, tx, mode, errs, func(i int) error {
+			blog, err := s.updateBlog(ctx, tx, uint64(blogs[i].ID), blogs[i], editorId)
+			blogs[i] = blog
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.BlogsService.UpdateBlogs] %w", err)
+	}
+
+	return bulkResults("services.BlogsService.UpdateBlogs", blogs, errs), nil
+}
+
+// DeleteBlogs attempts to delete each of the provided blogs, picked out by
+// their ID, in a single transaction. Each blog is deleted with its comments
+// as DeleteBlog does, and only if it is still at its Version when that is
+// set. A models.BulkResult holding the blog as it was given is returned for
+// each blog, in order, and mode decides whether the blogs that could be
+// deleted stay deleted when others fail. An error is only returned if the
+// bulk write as a whole fails.
+func (s *BlogsService) DeleteBlogs(ctx context.Context, blogs []models.Blog, mode models.BulkMode) ([]models.BulkResult[models.Blog], error) {
+	s.logger.DebugContext(ctx, "Deleting blogs", "count", len(blogs), "mode", mode)
+
+	errs := make([]error, len(blogs))
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		return eachItem(ctx, tx, mode, errs, func(i int) error {
+			return s.deleteBlog(ctx, tx, uint64(blogs[i].ID), blogs[i].Version)
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.BlogsService.DeleteBlogs] %w", err)
+	}
+
+	return bulkResults("services.BlogsService.DeleteBlogs", blogs, errs), nil
+}
+
+// ExportBlogs calls fn with each blog that isn't deleted, in order of id,
+// together with its tags, reading them with a single query so that they are
+// streamed rather than held in memory. An error is returned if reading the
+// blogs fails or fn does.
+func (s *BlogsService) ExportBlogs(ctx context.Context, fn func(models.Blog) error) error {
+	s.logger.DebugContext(ctx, "Exporting blogs")
+
+	err := exportRows(
+		ctx,
+		s.db,
+		fmt.Sprintf(`
+		SELECT id,
+		       author_id,
+		       title,
+		       body,
+		       excerpt,
+		       category,
+		       status,
+		       publish_at,
+		       score,
+		       created_date,
+		       %s
+		FROM blogs
+		WHERE deleted_at IS NULL
+		ORDER BY id
+		`, blogTagsColumn),
+		func(rows *sql.Rows) error {
+			var blog models.Blog
+			var publishAt sql.NullTime
+			var tags string
+
+			err := rows.Scan(
+				&blog.ID,
+				&blog.AuthorID,
+				&blog.Title,
+				&blog.Body,
+				&blog.Excerpt,
+				&blog.Category,
+				&blog.Status,
+				&publishAt,
+				&blog.Score,
+				&blog.CreatedDate,
+				&tags,
+			)
+			if err != nil {
+				return err
+			}
+			blog.PublishAt = publishAt.Time
+			blog.Tags = splitTags(tags)
+
+			return fn(blog)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("[in services.BlogsService.ExportBlogs] failed to export blogs: %w", err)
+	}
+
+	return nil
+}
+
+// ImportBlogs attempts to create the provided blogs, as exported by
+// ExportBlogs, in a single transaction, checking all of their authors with one
+// query. Blogs are prepared as CreateBlogs prepares them, but keep their
+// status, publish time and created date, which defaults to now. Scores are
+// left to votes, which aren't imported. If keepIDs is set the blogs keep their
+// IDs. Blogs that can't be created are skipped, and a models.BulkResult is
+// returned for each blog, in order, carrying the ID it was created with. An
+// error is only returned if the import as a whole fails.
+func (s *BlogsService) ImportBlogs(ctx context.Context, blogs []models.Blog, keepIDs bool) ([]models.BulkResult[models.Blog], error) {
+	s.logger.DebugContext(ctx, "Importing blogs", "count", len(blogs), "keep_ids", keepIDs)
+
+	blogs = slices.Clone(blogs)
+	errs := make([]error, len(blogs))
+	s.prepareBlogs(blogs, errs)
+
+	now := s.now()
+	for i := range blogs {
+		if blogs[i].Status == "" {
+			blogs[i].Status = models.BlogStatusDraft
+		}
+		if blogs[i].CreatedDate.IsZero() {
+			blogs[i].CreatedDate = now
+		}
+	}
+
+	columns := importColumns(keepIDs, "author_id", "title", "body", "excerpt", "category", "status", "publish_at", "created_date")
+
+	err := bulkWrite(ctx, s.db, models.BulkBestEffort, errs, func(tx *sql.Tx) error {
+		if err := checkAuthors(ctx, tx, blogs, errs); err != nil {
+			return err
+		}
+
+		err := insertItems(ctx, tx, models.BulkBestEffort, errs, func(batch []int) error {
+			args := make([]any, 0, len(columns)*len(batch))
+			for _, i := range batch {
+				blog := blogs[i]
+				if keepIDs {
+					args = append(args, blog.ID)
+				}
+				args = append(args, blog.AuthorID, blog.Title, blog.Body, blog.Excerpt, blog.Category, blog.Status, nullTime(blog.PublishAt), blog.CreatedDate)
+			}
+
+			err := insertRows(
+				ctx,
+				tx,
+				batch,
+				fmt.Sprintf(`
+				INSERT INTO blogs (%s) VALUES %s RETURNING id, score
+				`, strings.Join(columns, ", "), valueRows(len(batch), len(columns))),
+				args,
+				func(i int, rows *sql.Rows) error {
+					return rows.Scan(&blogs[i].ID, &blogs[i].Score)
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to import blogs: %w", err)
+			}
+
+			for _, i := range batch {
+				if err = s.tags.SetBlogTags(ctx, tx, blogs[i].ID, blogs[i].Tags); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil || !keepIDs {
+			return err
+		}
+
+		return syncSequence(ctx, tx, "blogs")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.BlogsService.ImportBlogs] %w", err)
+	}
+
+	return bulkResults("services.BlogsService.ImportBlogs", blogs, errs), nil
 }
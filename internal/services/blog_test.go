@@ -4,44 +4,141 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/chickey/blog/internal/models"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 var testDate time.Time = time.Date(2025, 1, 21, 11, 12, 11, 11, time.UTC)
 
 func TestBlogsService_ReadBlog(t *testing.T) {
+	readQuery := `SELECT id, author_id, title, body, excerpt, status, publish_at, score, created_date, category, version, ` +
+		blogTagsColumn + ` FROM blogs WHERE id = $1::int AND deleted_at IS NULL`
+
 	testcases := map[string]struct {
 		mockCalled     bool
+		mockQuery      string
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
 		mockError      error
 		input          uint64
+		expand         models.BlogExpand
+		includeDeleted bool
 		expectedOutput models.Blog
 		expectedError  error
 	}{
 		"happy path": {
 			mockCalled:    true,
 			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"id", "author_id", "title", "score", "created_date"}).
-				AddRow(1, 1, "Book Title", 8.2, testDate),
+			mockOutput: sqlmock.NewRows([]string{"id", "author_id", "title", "body", "excerpt", "status", "publish_at", "score", "created_date", "category", "version", "tags"}).
+				AddRow(1, 1, "Book Title", "# Chapter one", "Chapter one", "published", testDate, 8.2, testDate, "go", 2, "go,postgres"),
+			mockError: nil,
+			input:     1,
+			expectedOutput: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Body:        "# Chapter one",
+				Excerpt:     "Chapter one",
+				Category:    "go",
+				Tags:        []string{"go", "postgres"},
+				Status:      models.BlogStatusPublished,
+				PublishAt:   testDate,
+				Score:       8.2,
+				CreatedDate: testDate,
+				Version:     2,
+			},
+			expectedError: nil,
+		},
+		"expanded author": {
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, body, excerpt, status, publish_at, score, created_date, category, version, ` +
+				blogTagsColumn + `, author_name FROM blogs JOIN (SELECT id AS author_key, name AS author_name FROM users) author ON author.author_key = blogs.author_id
+				WHERE id = $1::int AND deleted_at IS NULL`,
+			mockInputArgs: []driver.Value{1},
+			mockOutput: sqlmock.NewRows([]string{"id", "author_id", "title", "body", "excerpt", "status", "publish_at", "score", "created_date", "category", "version", "tags", "author_name"}).
+				AddRow(1, 1, "Book Title", "# Chapter one", "Chapter one", "published", testDate, 8.2, testDate, "go", 2, "", "John"),
+			mockError: nil,
+			input:     1,
+			expand:    models.BlogExpand{Author: true},
+			expectedOutput: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Author:      models.UserRef{ID: 1, Name: "John"},
+				Title:       "Book Title",
+				Body:        "# Chapter one",
+				Excerpt:     "Chapter one",
+				Category:    "go",
+				Status:      models.BlogStatusPublished,
+				PublishAt:   testDate,
+				Score:       8.2,
+				CreatedDate: testDate,
+				Version:     2,
+			},
+			expectedError: nil,
+		},
+		"draft": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockOutput: sqlmock.NewRows([]string{"id", "author_id", "title", "body", "excerpt", "status", "publish_at", "score", "created_date", "category", "version", "tags"}).
+				AddRow(1, 1, "Book Title", "", "", "draft", nil, 8.2, testDate, "go", 2, ""),
 			mockError: nil,
 			input:     1,
 			expectedOutput: models.Blog{
 				ID:          1,
 				AuthorID:    1,
 				Title:       "Book Title",
+				Category:    "go",
+				Status:      models.BlogStatusDraft,
+				Score:       8.2,
+				CreatedDate: testDate,
+				Version:     2,
+			},
+			expectedError: nil,
+		},
+		"deleted": {
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, body, excerpt, status, publish_at, score, created_date, category, version, ` +
+				blogTagsColumn + `, deleted_at FROM blogs WHERE id = $1::int
+			`,
+			mockInputArgs: []driver.Value{1},
+			mockOutput: sqlmock.NewRows([]string{"id", "author_id", "title", "body", "excerpt", "status", "publish_at", "score", "created_date", "category", "version", "tags", "deleted_at"}).
+				AddRow(1, 1, "Book Title", "", "", "published", testDate, 8.2, testDate, "go", 2, "", testDate),
+			mockError:      nil,
+			input:          1,
+			includeDeleted: true,
+			expectedOutput: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Category:    "go",
+				Status:      models.BlogStatusPublished,
+				PublishAt:   testDate,
 				Score:       8.2,
 				CreatedDate: testDate,
+				DeletedAt:   testDate,
+				Version:     2,
 			},
 			expectedError: nil,
 		},
+		"not found": {
+			mockCalled:     true,
+			mockInputArgs:  []driver.Value{2},
+			mockOutput:     sqlmock.NewRows([]string{"id", "author_id", "title", "body", "excerpt", "status", "publish_at", "score", "created_date", "category", "version", "tags"}),
+			mockError:      nil,
+			input:          2,
+			expectedOutput: models.Blog{},
+			expectedError:  ErrNotFound,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -53,29 +150,25 @@ func TestBlogsService_ReadBlog(t *testing.T) {
 
 			logger := slog.Default()
 
+			if tc.mockQuery == "" {
+				tc.mockQuery = readQuery
+			}
+
 			if tc.mockCalled {
 				mock.
-					ExpectQuery(regexp.QuoteMeta(`
-                        SELECT id,
-		       author_id,
-		       title,
-		       score,
-			   created_date
-		FROM blogs
-		WHERE id = $1::int
-                    `)).
+					ExpectQuery(regexp.QuoteMeta(tc.mockQuery)).
 					WithArgs(tc.mockInputArgs...).
 					WillReturnRows(tc.mockOutput).
 					WillReturnError(tc.mockError)
 			}
 
-			blogService := NewBlogsService(logger, db)
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
 
-			output, err := blogService.ReadBlog(context.TODO(), tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			output, err := blogService.ReadBlog(context.TODO(), tc.input, tc.expand, tc.includeDeleted)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
-			if output != tc.expectedOutput {
+			if !reflect.DeepEqual(output, tc.expectedOutput) {
 				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
 			}
 
@@ -88,73 +181,285 @@ func TestBlogsService_ReadBlog(t *testing.T) {
 	}
 }
 func TestBlogsService_ListBlogs(t *testing.T) {
+	columns := []string{"id", "author_id", "title", "excerpt", "status", "publish_at", "score", "created_date", "category", "tags"}
+	minScore, maxScore := float32(7.5), float32(9)
+	scoreThenDate := []models.SortField{
+		{Field: "score", Desc: true},
+		{Field: "created_date"},
+	}
+
 	testcases := map[string]struct {
 		mockCalled     bool
+		mockQuery      string
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
 		mockError      error
-		input          uint64
+		filter         models.BlogFilter
+		sort           []models.SortField
+		page           models.PageRequest
+		expand         models.BlogExpand
 		expectedOutput []models.Blog
+		expectedNext   bool
 		expectedError  error
 	}{
 		"happy path": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"id", "author_id", "title", "score", "created_date"}).
-				AddRow(1, 1, "Book Title", 8.2, testDate).
-				AddRow(2, 1, "New Book", 7.4, testDate),
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, excerpt, status, publish_at, score, created_date, category, ` + blogTagsColumn + ` FROM blogs
+				WHERE status = 'published' AND deleted_at IS NULL ORDER BY id ASC LIMIT 2`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(1, 1, "Book Title", "", "published", testDate, 8.2, testDate, "go", "").
+				AddRow(2, 1, "New Book", "", "published", testDate, 7.4, testDate, "go", ""),
 			mockError: nil,
-			input:     1,
+			page:      models.PageRequest{Limit: 1},
+			expectedOutput: []models.Blog{
+				{
+					ID:          1,
+					AuthorID:    1,
+					Title:       "Book Title",
+					Category:    "go",
+					Status:      models.BlogStatusPublished,
+					PublishAt:   testDate,
+					Score:       8.2,
+					CreatedDate: testDate,
+				},
+			},
+			expectedNext:  true,
+			expectedError: nil,
+		},
+		"filters": {
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, excerpt, status, publish_at, score, created_date, category, ` + blogTagsColumn + ` FROM blogs
+				WHERE (status = 'published' OR author_id = $1) AND status = $2
+				AND author_id = $3 AND title ILIKE $4 AND score >= $5 AND score <= $6
+				AND created_date >= $7 AND created_date < $8 AND deleted_at IS NULL
+				ORDER BY id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{
+				int64(1),
+				"draft",
+				int64(1),
+				`%100\%\_book%`,
+				float64(float32(7.5)),
+				float64(float32(9)),
+				testDate.Add(-time.Hour),
+				testDate.Add(time.Hour),
+			},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(1, 1, "Book Title", "", "draft", nil, 8.2, testDate, "go", ""),
+			mockError: nil,
+			filter: models.BlogFilter{
+				ViewerID:      1,
+				Status:        models.BlogStatusDraft,
+				AuthorID:      1,
+				Title:         "100%_book",
+				MinScore:      &minScore,
+				MaxScore:      &maxScore,
+				CreatedAfter:  testDate.Add(-time.Hour),
+				CreatedBefore: testDate.Add(time.Hour),
+			},
+			expectedOutput: []models.Blog{
+				{
+					ID:          1,
+					AuthorID:    1,
+					Title:       "Book Title",
+					Category:    "go",
+					Status:      models.BlogStatusDraft,
+					Score:       8.2,
+					CreatedDate: testDate,
+				},
+			},
+			expectedError: nil,
+		},
+		"admin": {
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, excerpt, status, publish_at, score, created_date, category, ` + blogTagsColumn + ` FROM blogs
+				WHERE status = $1 AND deleted_at IS NULL ORDER BY id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{"draft"},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(1, 2, "Book Title", "", "draft", nil, 8.2, testDate, "go", ""),
+			mockError: nil,
+			filter: models.BlogFilter{
+				ViewerID: 3,
+				AllBlogs: true,
+				Status:   models.BlogStatusDraft,
+			},
+			expectedOutput: []models.Blog{
+				{
+					ID:          1,
+					AuthorID:    2,
+					Title:       "Book Title",
+					Category:    "go",
+					Status:      models.BlogStatusDraft,
+					Score:       8.2,
+					CreatedDate: testDate,
+				},
+			},
+			expectedError: nil,
+		},
+		"any tag": {
+			mockCalled: true,
+			mockQuery: `FROM blogs WHERE status = 'published' AND category = $1
+				AND id IN (SELECT bt.blog_id FROM blog_tags bt JOIN tags t ON t.id = bt.tag_id WHERE t.name IN ($2, $3)) AND deleted_at IS NULL
+				ORDER BY id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{"web-dev", "go", "postgres"},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(1, 1, "Book Title", "", "published", testDate, 8.2, testDate, "web-dev", "go"),
+			mockError: nil,
+			filter: models.BlogFilter{
+				Category: "Web Dev",
+				Tags:     []string{"Postgres", "go"},
+			},
+			expectedOutput: []models.Blog{
+				{
+					ID:          1,
+					AuthorID:    1,
+					Title:       "Book Title",
+					Category:    "web-dev",
+					Tags:        []string{"go"},
+					Status:      models.BlogStatusPublished,
+					PublishAt:   testDate,
+					Score:       8.2,
+					CreatedDate: testDate,
+				},
+			},
+			expectedError: nil,
+		},
+		"all tags": {
+			mockCalled: true,
+			mockQuery: `FROM blogs WHERE status = 'published'
+				AND id IN (SELECT bt.blog_id FROM blog_tags bt JOIN tags t ON t.id = bt.tag_id WHERE t.name IN ($1, $2)
+				GROUP BY bt.blog_id HAVING COUNT(*) = $3) AND deleted_at IS NULL
+				ORDER BY id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{"go", "postgres", int64(2)},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(1, 1, "Book Title", "", "published", testDate, 8.2, testDate, "go", "go,postgres"),
+			mockError: nil,
+			filter: models.BlogFilter{
+				Tags:     []string{"go", "postgres", "Go"},
+				TagMatch: models.TagMatchAll,
+			},
+			expectedOutput: []models.Blog{
+				{
+					ID:          1,
+					AuthorID:    1,
+					Title:       "Book Title",
+					Category:    "go",
+					Tags:        []string{"go", "postgres"},
+					Status:      models.BlogStatusPublished,
+					PublishAt:   testDate,
+					Score:       8.2,
+					CreatedDate: testDate,
+				},
+			},
+			expectedError: nil,
+		},
+		"expanded author": {
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, excerpt, status, publish_at, score, created_date, category, ` + blogTagsColumn + `, author_name
+				FROM blogs JOIN (SELECT id AS author_key, name AS author_name FROM users) author ON author.author_key = blogs.author_id
+				WHERE status = 'published' AND deleted_at IS NULL ORDER BY id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(append(columns, "author_name")).
+				AddRow(1, 1, "Book Title", "", "published", testDate, 8.2, testDate, "go", "", "John"),
+			mockError: nil,
+			expand:    models.BlogExpand{Author: true},
 			expectedOutput: []models.Blog{
 				{
 					ID:          1,
 					AuthorID:    1,
+					Author:      models.UserRef{ID: 1, Name: "John"},
 					Title:       "Book Title",
+					Category:    "go",
+					Status:      models.BlogStatusPublished,
+					PublishAt:   testDate,
 					Score:       8.2,
 					CreatedDate: testDate,
 				},
+			},
+			expectedError: nil,
+		},
+		"sorted with cursor": {
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, excerpt, status, publish_at, score, created_date, category, ` + blogTagsColumn + ` FROM blogs
+				WHERE status = 'published' AND deleted_at IS NULL AND ((score < $1::text::real)
+				OR (score = $1::text::real AND created_date > $2::text::timestamp)
+				OR (score = $1::text::real AND created_date = $2::text::timestamp AND id > $3::text::bigint))
+				ORDER BY score DESC, created_date ASC, id ASC LIMIT 2`,
+			mockInputArgs: []driver.Value{"8.2", testDate.Format(time.RFC3339Nano), "1"},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(2, 1, "New Book", "", "published", testDate, 7.4, testDate, "go", "").
+				AddRow(3, 1, "Old Book", "", "published", testDate, 7.1, testDate, "go", ""),
+			mockError: nil,
+			sort:      scoreThenDate,
+			page: models.PageRequest{
+				Limit: 1,
+				Cursor: (&listQuery{sort: []sortColumn{
+					{column: "score", cast: "real", desc: true},
+					{column: "created_date", cast: "timestamp"},
+					{column: "id", cast: "bigint"},
+				}}).encodeCursor([]string{"8.2", testDate.Format(time.RFC3339Nano), "1"}, false),
+			},
+			expectedOutput: []models.Blog{
 				{
 					ID:          2,
 					AuthorID:    1,
 					Title:       "New Book",
+					Category:    "go",
+					Status:      models.BlogStatusPublished,
+					PublishAt:   testDate,
 					Score:       7.4,
 					CreatedDate: testDate,
 				},
 			},
+			expectedNext:  true,
 			expectedError: nil,
 		},
-		"title filter": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"id", "author_id", "title", "score", "created_date"}).
-				AddRow(1, 1, "Book Title", 8.2, testDate).
-				AddRow(2, 1, "New Book", 7.4, testDate),
+		"including deleted": {
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, excerpt, status, publish_at, score, created_date, category, ` + blogTagsColumn + `, deleted_at FROM blogs
+				WHERE status = 'published' ORDER BY id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(append(columns, "deleted_at")).
+				AddRow(1, 1, "Book Title", "", "published", testDate, 8.2, testDate, "go", "", testDate),
 			mockError: nil,
-			input:     1,
+			filter:    models.BlogFilter{IncludeDeleted: true},
 			expectedOutput: []models.Blog{
 				{
 					ID:          1,
 					AuthorID:    1,
 					Title:       "Book Title",
+					Category:    "go",
+					Status:      models.BlogStatusPublished,
+					PublishAt:   testDate,
 					Score:       8.2,
 					CreatedDate: testDate,
+					DeletedAt:   testDate,
 				},
 			},
 			expectedError: nil,
 		},
-		"no rows returned": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"id", "author_id", "title", "score", "created_date"}).
-				AddRow(1, 1, "Book Title", 8.2, testDate).
-				AddRow(2, 1, "New Book", 7.4, testDate),
-			mockError:      sql.ErrNoRows,
-			input:          1,
+		"cursor from another order": {
+			mockCalled:     false,
+			sort:           scoreThenDate,
+			page:           models.PageRequest{Cursor: testCursor("1", false)},
 			expectedOutput: []models.Blog{},
-			expectedError: fmt.Errorf(
-				"[in services.CommentsService.ListComment] failed to list blogs: %w",
-				sql.ErrNoRows,
-			),
+			expectedError:  ErrInvalidCursor,
+		},
+		"unknown sort field": {
+			mockCalled:     false,
+			sort:           []models.SortField{{Field: "password"}},
+			expectedOutput: []models.Blog{},
+			expectedError:  ErrInvalidSort,
+		},
+		"query error": {
+			mockCalled: true,
+			mockQuery: `SELECT id, author_id, title, excerpt, status, publish_at, score, created_date, category, ` + blogTagsColumn + ` FROM blogs
+				WHERE status = 'published' AND deleted_at IS NULL ORDER BY id ASC LIMIT 21`,
+			mockInputArgs:  []driver.Value{},
+			mockOutput:     sqlmock.NewRows(columns),
+			mockError:      sql.ErrConnDone,
+			expectedOutput: []models.Blog{},
+			expectedError:  sql.ErrConnDone,
 		},
 	}
 	for name, tc := range testcases {
@@ -169,25 +474,29 @@ func TestBlogsService_ListBlogs(t *testing.T) {
 
 			if tc.mockCalled {
 				mock.
-					ExpectQuery(regexp.QuoteMeta(`
-                        SELECT id, author_id, title, score, created_date
-					FROM blogs
-                    `)).
+					ExpectQuery(regexp.QuoteMeta(tc.mockQuery)).
+					WithArgs(tc.mockInputArgs...).
 					WillReturnRows(tc.mockOutput).
 					WillReturnError(tc.mockError)
 			}
 
-			blogService := NewBlogsService(logger, db)
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
 
-			outputs, err := blogService.ListBlogs(context.TODO(), "Book Title")
-			if err != tc.expectedError {
+			output, err := blogService.ListBlogs(context.TODO(), tc.filter, tc.sort, tc.page, tc.expand)
+			if !errors.Is(err, tc.expectedError) {
 				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
-			for i, output := range outputs {
-				if output != tc.expectedOutput[i] {
-					t.Errorf("expected %v, got %v", tc.expectedOutput[i], output)
+			if len(output.Items) != len(tc.expectedOutput) {
+				t.Fatalf("expected %v, got %v", tc.expectedOutput, output.Items)
+			}
+			for i, blog := range output.Items {
+				if !reflect.DeepEqual(blog, tc.expectedOutput[i]) {
+					t.Errorf("expected %v, got %v", tc.expectedOutput[i], blog)
 				}
 			}
+			if (output.NextCursor != "") != tc.expectedNext {
+				t.Errorf("expected next page %t, got cursor %q", tc.expectedNext, output.NextCursor)
+			}
 
 			if tc.mockCalled {
 				if err = mock.ExpectationsWereMet(); err != nil {
@@ -198,32 +507,213 @@ func TestBlogsService_ListBlogs(t *testing.T) {
 	}
 }
 
+func TestBlogsService_ListUserBlogs(t *testing.T) {
+	columns := []string{"id", "title", "created_date"}
+
+	testcases := map[string]struct {
+		mockUser       *sqlmock.Rows
+		mockCalled     bool
+		mockQuery      string
+		mockOutput     *sqlmock.Rows
+		viewerID       uint
+		allBlogs       bool
+		expectedOutput []models.Blog
+		expectedError  error
+	}{
+		"happy path": {
+			mockUser:   sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockCalled: true,
+			mockQuery: `SELECT id, title, created_date FROM blogs
+				WHERE author_id = $1 AND deleted_at IS NULL AND status = 'published'
+				ORDER BY created_date DESC, id DESC LIMIT 21`,
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(2, "New Book", testDate).
+				AddRow(1, "Book Title", testDate),
+			expectedOutput: []models.Blog{
+				{ID: 2, AuthorID: 1, Title: "New Book", CreatedDate: testDate},
+				{ID: 1, AuthorID: 1, Title: "Book Title", CreatedDate: testDate},
+			},
+			expectedError: nil,
+		},
+		"own drafts": {
+			mockUser:   sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockCalled: true,
+			mockQuery: `SELECT id, title, created_date FROM blogs
+				WHERE author_id = $1 AND deleted_at IS NULL
+				ORDER BY created_date DESC, id DESC LIMIT 21`,
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(3, "Draft", testDate),
+			viewerID: 1,
+			expectedOutput: []models.Blog{
+				{ID: 3, AuthorID: 1, Title: "Draft", CreatedDate: testDate},
+			},
+			expectedError: nil,
+		},
+		"admin": {
+			mockUser:   sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockCalled: true,
+			mockQuery: `SELECT id, title, created_date FROM blogs
+				WHERE author_id = $1 AND deleted_at IS NULL
+				ORDER BY created_date DESC, id DESC LIMIT 21`,
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(3, "Draft", testDate),
+			viewerID: 3,
+			allBlogs: true,
+			expectedOutput: []models.Blog{
+				{ID: 3, AuthorID: 1, Title: "Draft", CreatedDate: testDate},
+			},
+			expectedError: nil,
+		},
+		"user not found": {
+			mockUser:       sqlmock.NewRows([]string{"?column?"}),
+			expectedOutput: []models.Blog{},
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+					SELECT 1
+					FROM users
+					WHERE id = $1::int AND deleted_at IS NULL
+				`)).
+				WithArgs(1).
+				WillReturnRows(tc.mockUser)
+
+			if tc.mockCalled {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(tc.mockQuery)).
+					WithArgs(1).
+					WillReturnRows(tc.mockOutput)
+			}
+
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+
+			output, err := blogService.ListUserBlogs(context.TODO(), 1, tc.viewerID, tc.allBlogs, models.PageRequest{})
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if len(output.Items) != len(tc.expectedOutput) {
+				t.Fatalf("expected %v, got %v", tc.expectedOutput, output.Items)
+			}
+			for i, blog := range output.Items {
+				if !reflect.DeepEqual(blog, tc.expectedOutput[i]) {
+					t.Errorf("expected %v, got %v", tc.expectedOutput[i], blog)
+				}
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
 func TestBlogsService_CreateBlog(t *testing.T) {
 	testcases := map[string]struct {
 		mockCalled     bool
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
 		mockError      error
+		mockTags       []driver.Value
 		input          models.Blog
 		expectedOutput models.Blog
 		expectedError  error
 	}{
 		"happy path": {
 			mockCalled:    true,
-			mockInputArgs: []driver.Value{1, "Book Title", float32(8.2)},
-			mockOutput: sqlmock.NewRows([]string{"id", "created_date"}).
-				AddRow(1, testDate),
+			mockInputArgs: []driver.Value{1, "Book Title", "", "", "uncategorized", "draft", nil},
+			mockOutput: sqlmock.NewRows([]string{"id", "score", "created_date"}).
+				AddRow(1, 0, testDate),
 			mockError: nil,
 			input: models.Blog{
 				AuthorID: 1,
 				Title:    "Book Title",
-				Score:    8.2,
 			},
 			expectedOutput: models.Blog{
 				ID:          1,
 				AuthorID:    1,
 				Title:       "Book Title",
-				Score:       8.2,
+				Category:    "uncategorized",
+				Status:      models.BlogStatusDraft,
+				CreatedDate: testDate,
+			},
+			expectedError: nil,
+		},
+		"published": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1, "Book Title", "", "", "uncategorized", "published", testDate},
+			mockOutput: sqlmock.NewRows([]string{"id", "score", "created_date"}).
+				AddRow(1, 0, testDate),
+			mockError: nil,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Status:   models.BlogStatusPublished,
+			},
+			expectedOutput: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Category:    "uncategorized",
+				Status:      models.BlogStatusPublished,
+				PublishAt:   testDate,
+				CreatedDate: testDate,
+			},
+			expectedError: nil,
+		},
+		"excerpt generated from body": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1, "Book Title", "# Chapter *one*", "Chapter one", "uncategorized", "draft", nil},
+			mockOutput: sqlmock.NewRows([]string{"id", "score", "created_date"}).
+				AddRow(1, 0, testDate),
+			mockError: nil,
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Body:     "# Chapter *one*",
+			},
+			expectedOutput: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Body:        "# Chapter *one*",
+				Excerpt:     "Chapter one",
+				Category:    "uncategorized",
+				Status:      models.BlogStatusDraft,
+				CreatedDate: testDate,
+			},
+			expectedError: nil,
+		},
+		"tags and category normalized": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1, "Book Title", "", "", "web-dev", "draft", nil},
+			mockOutput: sqlmock.NewRows([]string{"id", "score", "created_date"}).
+				AddRow(1, 0, testDate),
+			mockError: nil,
+			mockTags:  []driver.Value{"go", "postgres"},
+			input: models.Blog{
+				AuthorID: 1,
+				Title:    "Book Title",
+				Category: " Web Dev ",
+				Tags:     []string{" Postgres", "Go", "go", "!!"},
+			},
+			expectedOutput: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Category:    "web-dev",
+				Tags:        []string{"go", "postgres"},
+				Status:      models.BlogStatusDraft,
 				CreatedDate: testDate,
 			},
 			expectedError: nil,
@@ -254,21 +744,25 @@ func TestBlogsService_CreateBlog(t *testing.T) {
 						AddRow(1, "john", "john@me.com", "password123!")).
 					WillReturnError(tc.mockError)
 
+				mock.ExpectBegin()
 				mock.
 					ExpectQuery(regexp.QuoteMeta(
-						`INSERT INTO blogs (author_id, title, score) VALUES ($1, $2, $3) RETURNING id, created_date`)).
+						`INSERT INTO blogs (author_id, title, body, excerpt, category, status, publish_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, score, created_date`)).
 					WithArgs(tc.mockInputArgs...).
 					WillReturnRows(tc.mockOutput).
 					WillReturnError(tc.mockError)
+				expectSetBlogTags(mock, 1, tc.mockTags...)
+				mock.ExpectCommit()
 			}
 
-			blogService := NewBlogsService(logger, db)
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+			blogService.now = func() time.Time { return testDate }
 
 			output, err := blogService.CreateBlog(context.TODO(), tc.input)
 			if err != tc.expectedError {
 				t.Errorf("expected no error, got %v", err)
 			}
-			if output != tc.expectedOutput {
+			if !reflect.DeepEqual(output, tc.expectedOutput) {
 				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
 			}
 
@@ -282,35 +776,109 @@ func TestBlogsService_CreateBlog(t *testing.T) {
 }
 
 func TestBlogsService_UpdateBlog(t *testing.T) {
+	publishedAt := testDate.Add(-24 * time.Hour)
+	tomorrow := testDate.Add(24 * time.Hour)
+
 	testcases := map[string]struct {
-		mockCalled     bool
+		mockCurrent    *sqlmock.Rows
+		mockUpdated    bool
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
 		mockError      error
+		mockTags       []driver.Value
 		input          models.Blog
 		expectedOutput models.Blog
 		expectedError  error
 	}{
 		"happy path": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{1, "Book Title", float32(8.2), 1},
-			mockOutput: sqlmock.NewRows([]string{"created_date"}).
-				AddRow(testDate),
+			mockCurrent: sqlmock.NewRows([]string{"status", "publish_at", "version", "tags"}).
+				AddRow("published", publishedAt, 3, "postgres"),
+			mockUpdated:   true,
+			mockInputArgs: []driver.Value{1, "Book Title", "Body text", "Summary", "databases", "published", publishedAt, false, 1},
+			mockOutput: sqlmock.NewRows([]string{"score", "created_date", "version"}).
+				AddRow(8.2, testDate, 4),
 			mockError: nil,
+			mockTags:  []driver.Value{"postgres"},
 			input: models.Blog{
 				AuthorID: 1,
 				Title:    "Book Title",
+				Body:     "Body text",
+				Excerpt:  "Summary",
+				Category: "Databases",
+				Tags:     []string{"Postgres"},
 				Score:    8.2,
 			},
 			expectedOutput: models.Blog{
 				ID:          1,
 				AuthorID:    1,
 				Title:       "Book Title",
+				Body:        "Body text",
+				Excerpt:     "Summary",
+				Category:    "databases",
+				Tags:        []string{"postgres"},
+				Status:      models.BlogStatusPublished,
+				PublishAt:   publishedAt,
+				Score:       8.2,
+				CreatedDate: testDate,
+				Version:     4,
+			},
+			expectedError: nil,
+		},
+		"schedule draft": {
+			mockCurrent: sqlmock.NewRows([]string{"status", "publish_at", "version", "tags"}).
+				AddRow("draft", nil, 3, ""),
+			mockUpdated:   true,
+			mockInputArgs: []driver.Value{1, "Book Title", "", "", "uncategorized", "scheduled", tomorrow, true, 1},
+			mockOutput: sqlmock.NewRows([]string{"score", "created_date", "version"}).
+				AddRow(8.2, testDate, 4),
+			mockError: nil,
+			input: models.Blog{
+				AuthorID:  1,
+				Title:     "Book Title",
+				Status:    models.BlogStatusScheduled,
+				PublishAt: tomorrow,
+			},
+			expectedOutput: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Book Title",
+				Category:    "uncategorized",
+				Status:      models.BlogStatusScheduled,
+				PublishAt:   tomorrow,
 				Score:       8.2,
 				CreatedDate: testDate,
+				Version:     4,
 			},
 			expectedError: nil,
 		},
+		"schedule published blog": {
+			mockCurrent: sqlmock.NewRows([]string{"status", "publish_at", "version", "tags"}).
+				AddRow("published", publishedAt, 3, ""),
+			mockUpdated: false,
+			input: models.Blog{
+				AuthorID:  1,
+				Title:     "Book Title",
+				Status:    models.BlogStatusScheduled,
+				PublishAt: tomorrow,
+			},
+			expectedOutput: models.Blog{},
+			expectedError:  ErrInvalidTransition,
+		},
+		"stale version": {
+			mockCurrent: sqlmock.NewRows([]string{"status", "publish_at", "version", "tags"}).
+				AddRow("published", publishedAt, 3, ""),
+			mockUpdated:    false,
+			input:          models.Blog{AuthorID: 1, Title: "Book Title", Version: 2},
+			expectedOutput: models.Blog{},
+			expectedError:  ErrPreconditionFailed,
+		},
+		"not found": {
+			mockCurrent:    sqlmock.NewRows([]string{"status", "publish_at", "version", "tags"}),
+			mockUpdated:    false,
+			input:          models.Blog{AuthorID: 1, Title: "Book Title"},
+			expectedOutput: models.Blog{},
+			expectedError:  ErrNotFound,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -322,43 +890,514 @@ func TestBlogsService_UpdateBlog(t *testing.T) {
 
 			logger := slog.Default()
 
-			if tc.mockCalled {
-				mock.
-					ExpectQuery(regexp.QuoteMeta(`
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
                        SELECT 1
                         FROM users
                         WHERE id = $1::int
                     `)).
-					WithArgs([]driver.Value{1}...).
-					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
-						AddRow(1)).
-					WillReturnError(tc.mockError)
+				WithArgs([]driver.Value{1}...).
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
+					AddRow(1))
 
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT status, publish_at, version, COALESCE((`)).
+				WithArgs(1).
+				WillReturnRows(tc.mockCurrent)
+
+			if tc.mockUpdated {
+				expectBlogRevision(mock, 1, 2)
 				mock.
 					ExpectQuery(regexp.QuoteMeta(
 						`UPDATE blogs
-		SET author_id = $1, title = $2, score = $3
-		WHERE id = $4
-		RETURNING created_date`)).
+			SET author_id = $1, title = $2, body = $3, excerpt = $4, category = $5, status = $6, publish_at = $7,
+			    version = CASE WHEN $8 THEN version + 1 ELSE version END
+			WHERE id = $9
+			RETURNING score, created_date, version`)).
 					WithArgs(tc.mockInputArgs...).
 					WillReturnRows(tc.mockOutput).
 					WillReturnError(tc.mockError)
+				expectSetBlogTags(mock, 1, tc.mockTags...)
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
 			}
 
-			blogService := NewBlogsService(logger, db)
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+			blogService.now = func() time.Time { return testDate }
 
-			output, err := blogService.UpdateBlog(context.TODO(), 1, tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			output, err := blogService.UpdateBlog(context.TODO(), 1, tc.input, 2)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if !reflect.DeepEqual(output, tc.expectedOutput) {
+				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBlogsService_PatchBlog(t *testing.T) {
+	publishedAt := testDate.Add(-24 * time.Hour)
+	title := "New Title"
+	sameTitle := "Book Title"
+	tags := []string{"Go", "Postgres", "go"}
+	published := models.BlogStatusPublished
+	scheduled := models.BlogStatusScheduled
+	jane := uint(2)
+
+	current := models.Blog{
+		ID:          1,
+		AuthorID:    1,
+		Title:       "Book Title",
+		Body:        "Body text",
+		Excerpt:     "Summary",
+		Category:    "databases",
+		Tags:        []string{"go"},
+		Status:      models.BlogStatusDraft,
+		Score:       8.2,
+		CreatedDate: testDate,
+		Version:     3,
+	}
+
+	testcases := map[string]struct {
+		input          models.BlogPatch
+		mockAuthor     bool
+		mockRevision   bool
+		mockUpdate     string
+		mockUpdateArgs []driver.Value
+		mockTags       []driver.Value
+		expectedOutput func(blog models.Blog) models.Blog
+		expectedError  error
+	}{
+		"title only": {
+			input:          models.BlogPatch{Title: &title, Version: 3},
+			mockRevision:   true,
+			mockUpdate:     `UPDATE blogs SET title = $1 WHERE id = $2 RETURNING version`,
+			mockUpdateArgs: []driver.Value{"New Title", 1},
+			expectedOutput: func(blog models.Blog) models.Blog {
+				blog.Title = "New Title"
+				blog.Version = 4
+				return blog
+			},
+		},
+		"tags only": {
+			input:          models.BlogPatch{Tags: &tags},
+			mockRevision:   true,
+			mockUpdate:     `UPDATE blogs SET version = $1 WHERE id = $2 RETURNING version`,
+			mockUpdateArgs: []driver.Value{4, 1},
+			mockTags:       []driver.Value{"go", "postgres"},
+			expectedOutput: func(blog models.Blog) models.Blog {
+				blog.Tags = []string{"go", "postgres"}
+				blog.Version = 4
+				return blog
+			},
+		},
+		"publish and hand over": {
+			input:          models.BlogPatch{AuthorID: &jane, Status: &published},
+			mockAuthor:     true,
+			mockUpdate:     `UPDATE blogs SET author_id = $1, status = $2, publish_at = $3, version = $4 WHERE id = $5 RETURNING version`,
+			mockUpdateArgs: []driver.Value{2, "published", testDate, 4, 1},
+			expectedOutput: func(blog models.Blog) models.Blog {
+				blog.AuthorID = 2
+				blog.Status = models.BlogStatusPublished
+				blog.PublishAt = testDate
+				blog.Version = 4
+				return blog
+			},
+		},
+		"nothing changed": {
+			input: models.BlogPatch{Title: &sameTitle},
+			expectedOutput: func(blog models.Blog) models.Blog {
+				return blog
+			},
+		},
+		"schedule in the past": {
+			input:          models.BlogPatch{Status: &scheduled, PublishAt: &publishedAt},
+			expectedOutput: func(models.Blog) models.Blog { return models.Blog{} },
+			expectedError:  ErrInvalidTransition,
+		},
+		"stale version": {
+			input:          models.BlogPatch{Title: &title, Version: 2},
+			expectedOutput: func(models.Blog) models.Blog { return models.Blog{} },
+			expectedError:  ErrPreconditionFailed,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			if tc.mockAuthor {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM users WHERE id = $1::int AND deleted_at IS NULL`)).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+			}
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT id, author_id, title, body, excerpt, status, publish_at, score, created_date, category, version, `)).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "author_id", "title", "body", "excerpt", "status", "publish_at", "score", "created_date", "category", "version", "tags"}).
+					AddRow(1, 1, "Book Title", "Body text", "Summary", "draft", nil, 8.2, testDate, "databases", 3, "go"))
+
+			if tc.mockRevision {
+				expectBlogRevision(mock, 1, 2)
+			}
+			if tc.mockUpdate != "" {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(tc.mockUpdate)).
+					WithArgs(tc.mockUpdateArgs...).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+			}
+			if tc.mockTags != nil {
+				expectSetBlogTags(mock, 1, tc.mockTags...)
+			}
+			if tc.expectedError != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+			blogService.now = func() time.Time { return testDate }
+
+			output, err := blogService.PatchBlog(context.TODO(), 1, tc.input, 2)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if expected := tc.expectedOutput(current); !reflect.DeepEqual(output, expected) {
+				t.Errorf("expected %v, got %v", expected, output)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBlogsService_Vote(t *testing.T) {
+	testcases := map[string]struct {
+		mockBlog       *sqlmock.Rows
+		mockVoted      bool
+		mockVoteError  error
+		expectedOutput float32
+		expectedError  error
+	}{
+		"happy path": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockVoted:      true,
+			expectedOutput: 7.5,
+			expectedError:  nil,
+		},
+		"blog not published": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}),
+			expectedOutput: 0,
+			expectedError:  ErrNotFound,
+		},
+		"vote fails": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockVoted:      true,
+			mockVoteError:  sql.ErrConnDone,
+			expectedOutput: 0,
+			expectedError:  sql.ErrConnDone,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM blogs WHERE id = $1 AND status = 'published' AND deleted_at IS NULL FOR UPDATE`)).
+				WithArgs(1).
+				WillReturnRows(tc.mockBlog)
+
+			if tc.mockVoted {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`INSERT INTO votes (user_id, blog_id, rating) VALUES ($1, $2, $3)
+						ON CONFLICT (user_id, blog_id) DO UPDATE SET rating = EXCLUDED.rating`)).
+					WithArgs(2, 1, 8).
+					WillReturnResult(sqlmock.NewResult(0, 1)).
+					WillReturnError(tc.mockVoteError)
+			}
+
+			if tc.mockVoted && tc.mockVoteError == nil {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`UPDATE blogs
+						SET score = COALESCE((SELECT AVG(rating) FROM votes WHERE blog_id = $1), 0)
+						WHERE id = $1
+						RETURNING score`)).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(7.5))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+
+			output, err := blogService.Vote(context.TODO(), models.Vote{UserID: 2, BlogID: 1, Rating: 8})
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
 			if output != tc.expectedOutput {
 				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
 			}
 
-			if tc.mockCalled {
-				if err = mock.ExpectationsWereMet(); err != nil {
-					t.Errorf("there were unfulfilled expectations: %s", err)
-				}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+// A vote only rescores the blog, which the version trigger ignores, so
+// nothing Vote sends may write the version itself.
+func TestBlogsService_VoteKeepsVersion(t *testing.T) {
+	matcher := sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		if strings.Contains(actualSQL, "version") {
+			return fmt.Errorf("vote changes the blog version: %s", actualSQL)
+		}
+		return sqlmock.QueryMatcherRegexp.Match(expectedSQL, actualSQL)
+	})
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	logger := slog.Default()
+
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM blogs WHERE id = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`INSERT INTO votes`)).
+		WithArgs(2, 1, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`UPDATE blogs`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(8))
+	mock.ExpectCommit()
+
+	blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+
+	if _, err = blogService.Vote(context.TODO(), models.Vote{UserID: 2, BlogID: 1, Rating: 8}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBlogsService_DeleteVote(t *testing.T) {
+	testcases := map[string]struct {
+		mockBlog       *sqlmock.Rows
+		mockDeleted    bool
+		mockAffected   int64
+		expectedOutput float32
+		expectedError  error
+	}{
+		"happy path": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockDeleted:    true,
+			mockAffected:   1,
+			expectedOutput: 0,
+			expectedError:  nil,
+		},
+		"not voted": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockDeleted:    true,
+			mockAffected:   0,
+			expectedOutput: 0,
+			expectedError:  ErrNotFound,
+		},
+		"blog not found": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}),
+			expectedOutput: 0,
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM blogs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`)).
+				WithArgs(1).
+				WillReturnRows(tc.mockBlog)
+
+			if tc.mockDeleted {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`DELETE FROM votes WHERE user_id = $1 AND blog_id = $2`)).
+					WithArgs(2, 1).
+					WillReturnResult(sqlmock.NewResult(0, tc.mockAffected))
+			}
+
+			if tc.mockAffected > 0 {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`UPDATE blogs
+						SET score = COALESCE((SELECT AVG(rating) FROM votes WHERE blog_id = $1), 0)`)).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"score"}).AddRow(0))
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+
+			output, err := blogService.DeleteVote(context.TODO(), 1, 2)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if output != tc.expectedOutput {
+				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBlogsService_PublishDue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.
+		ExpectExec(regexp.QuoteMeta(
+			`UPDATE blogs SET status = 'published' WHERE status = 'scheduled' AND publish_at <= $1`)).
+		WithArgs(testDate).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	blogService := NewBlogsService(slog.Default(), db, NewTagsService(slog.Default(), db))
+	blogService.now = func() time.Time { return testDate }
+
+	published, err := blogService.PublishDue(context.TODO())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if published != 2 {
+		t.Errorf("expected 2 blogs published, got %d", published)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestApplyStatus(t *testing.T) {
+	past := testDate.Add(-time.Hour)
+	future := testDate.Add(time.Hour)
+
+	testcases := map[string]struct {
+		input             models.Blog
+		current           models.Blog
+		expectedStatus    models.BlogStatus
+		expectedPublishAt time.Time
+		expectedError     error
+	}{
+		"new blogs are drafts": {
+			input:          models.Blog{},
+			expectedStatus: models.BlogStatusDraft,
+		},
+		"publish now": {
+			input:             models.Blog{Status: models.BlogStatusPublished},
+			current:           models.Blog{Status: models.BlogStatusDraft},
+			expectedStatus:    models.BlogStatusPublished,
+			expectedPublishAt: testDate,
+		},
+		"publish scheduled blog early": {
+			input:             models.Blog{Status: models.BlogStatusPublished, PublishAt: future},
+			current:           models.Blog{Status: models.BlogStatusScheduled, PublishAt: future},
+			expectedStatus:    models.BlogStatusPublished,
+			expectedPublishAt: testDate,
+		},
+		"keep scheduled time": {
+			input:             models.Blog{},
+			current:           models.Blog{Status: models.BlogStatusScheduled, PublishAt: future},
+			expectedStatus:    models.BlogStatusScheduled,
+			expectedPublishAt: future,
+		},
+		"schedule in the past": {
+			input:         models.Blog{Status: models.BlogStatusScheduled, PublishAt: past},
+			current:       models.Blog{Status: models.BlogStatusDraft},
+			expectedError: ErrInvalidTransition,
+		},
+		"archive keeps publish time": {
+			input:             models.Blog{Status: models.BlogStatusArchived},
+			current:           models.Blog{Status: models.BlogStatusPublished, PublishAt: past},
+			expectedStatus:    models.BlogStatusArchived,
+			expectedPublishAt: past,
+		},
+		"archive draft": {
+			input:         models.Blog{Status: models.BlogStatusArchived},
+			current:       models.Blog{Status: models.BlogStatusDraft},
+			expectedError: ErrInvalidTransition,
+		},
+		"unpublish to draft": {
+			input:         models.Blog{Status: models.BlogStatusDraft},
+			current:       models.Blog{Status: models.BlogStatusPublished, PublishAt: past},
+			expectedError: ErrInvalidTransition,
+		},
+		"create archived": {
+			input:         models.Blog{Status: models.BlogStatusArchived},
+			expectedError: ErrInvalidTransition,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			blog := tc.input
+
+			err := applyStatus(&blog, tc.current, testDate)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected %v, got %v", tc.expectedError, err)
+			}
+			if tc.expectedError != nil {
+				return
+			}
+
+			if blog.Status != tc.expectedStatus {
+				t.Errorf("expected status %q, got %q", tc.expectedStatus, blog.Status)
+			}
+			if !blog.PublishAt.Equal(tc.expectedPublishAt) {
+				t.Errorf("expected publish time %v, got %v", tc.expectedPublishAt, blog.PublishAt)
 			}
 		})
 	}
@@ -369,18 +1408,53 @@ func TestBlogsService_DeleteBlog(t *testing.T) {
 		mockCalled     bool
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
+		mockLock       *sqlmock.Rows
 		mockError      error
 		input          uint64
+		version        uint
 		expectedOutput models.Blog
 		expectedError  error
 	}{
 		"happy path": {
 			mockCalled:    true,
 			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}).AddRow(3),
+			mockError:     nil,
+			input:         1,
+			expectedError: nil,
+		},
+		"current version": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}).AddRow(3),
 			mockError:     nil,
 			input:         1,
+			version:       3,
 			expectedError: nil,
 		},
+		"stale version": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}).AddRow(4),
+			input:         1,
+			version:       3,
+			expectedError: ErrPreconditionFailed,
+		},
+		"not found": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}),
+			input:         1,
+			expectedError: ErrNotFound,
+		},
+		"rolls back when deleting comments fails": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}).AddRow(3),
+			mockError:     sql.ErrConnDone,
+			input:         1,
+			expectedError: sql.ErrConnDone,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -393,24 +1467,38 @@ func TestBlogsService_DeleteBlog(t *testing.T) {
 			logger := slog.Default()
 
 			if tc.mockCalled {
+				mock.ExpectBegin()
 				mock.
-					ExpectExec(regexp.QuoteMeta(`DELETE FROM blogs WHERE id = $1::int`)).
+					ExpectQuery(regexp.QuoteMeta(`SELECT version FROM blogs WHERE id = $1::int AND deleted_at IS NULL FOR UPDATE`)).
 					WithArgs(tc.mockInputArgs...).
-					WillReturnResult(sqlmock.NewResult(1, 1)).
-					WillReturnError(tc.mockError)
+					WillReturnRows(tc.mockLock)
+			}
 
+			if tc.mockCalled && (tc.expectedError == ErrPreconditionFailed || tc.expectedError == ErrNotFound) {
+				mock.ExpectRollback()
+			} else if tc.mockCalled {
 				mock.
-					ExpectExec(regexp.QuoteMeta(`DELETE FROM comments WHERE blog_id = $1::int`)).
+					ExpectExec(regexp.QuoteMeta(`UPDATE comments SET deleted_at = CURRENT_TIMESTAMP WHERE blog_id = $1::int AND deleted_at IS NULL`)).
 					WithArgs(tc.mockInputArgs...).
 					WillReturnResult(sqlmock.NewResult(1, 1)).
 					WillReturnError(tc.mockError)
+
+				if tc.mockError != nil {
+					mock.ExpectRollback()
+				} else {
+					mock.
+						ExpectExec(regexp.QuoteMeta(`UPDATE blogs SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1::int AND deleted_at IS NULL`)).
+						WithArgs(tc.mockInputArgs...).
+						WillReturnResult(sqlmock.NewResult(1, 1))
+					mock.ExpectCommit()
+				}
 			}
 
-			blogService := NewBlogsService(logger, db)
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
 
-			err = blogService.DeleteBlog(context.TODO(), tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			err = blogService.DeleteBlog(context.TODO(), tc.input, tc.version)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
 
 			if tc.mockCalled {
@@ -421,3 +1509,517 @@ func TestBlogsService_DeleteBlog(t *testing.T) {
 		})
 	}
 }
+
+// expectBlogRevision expects the statement that keeps the content of the blog
+// with blogId as a revision made by the editor with editorId.
+func TestBlogsService_RestoreBlog(t *testing.T) {
+	testcases := map[string]struct {
+		mockDeleted   *sqlmock.Rows
+		expectedError error
+	}{
+		"happy path": {
+			mockDeleted:   sqlmock.NewRows([]string{"deleted_at"}).AddRow(testDate),
+			expectedError: nil,
+		},
+		"not deleted": {
+			mockDeleted:   sqlmock.NewRows([]string{"deleted_at"}),
+			expectedError: ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+					SELECT b.deleted_at
+					FROM blogs b
+					JOIN users u ON u.id = b.author_id AND u.deleted_at IS NULL
+					WHERE b.id = $1::int AND b.deleted_at IS NOT NULL
+					FOR UPDATE OF b
+				`)).
+				WithArgs(1).
+				WillReturnRows(tc.mockDeleted)
+			if tc.expectedError != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`UPDATE comments SET deleted_at = NULL WHERE blog_id = $1::int AND deleted_at = $2`)).
+					WithArgs(1, testDate).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.
+					ExpectExec(regexp.QuoteMeta(`UPDATE blogs SET deleted_at = NULL WHERE id = $1::int`)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+
+			err = blogService.RestoreBlog(context.TODO(), 1)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func expectBlogRevision(mock sqlmock.Sqlmock, blogId int, editorId int) {
+	mock.
+		ExpectExec(regexp.QuoteMeta(`INSERT INTO blog_revisions (blog_id, editor_id, title, body, excerpt, category, tags)
+			SELECT id, $2, title, body, excerpt, category, `)).
+		WithArgs(blogId, editorId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestBlogsService_ListBlogRevisions(t *testing.T) {
+	currentQuery := `SELECT title, body, excerpt, category, ` + blogTagsColumn + ` FROM blogs WHERE id = $1`
+	columns := []string{
+		"id", "blog_id", "editor_id", "title", "body", "excerpt", "category", "tags", "created_date",
+		"next_title", "next_body", "next_excerpt", "next_category", "next_tags",
+	}
+
+	testcases := map[string]struct {
+		mockCurrent    *sqlmock.Rows
+		mockListed     bool
+		mockOutput     *sqlmock.Rows
+		page           models.PageRequest
+		expectedOutput []models.BlogRevision
+		expectedNext   bool
+		expectedError  error
+	}{
+		"happy path": {
+			mockCurrent: sqlmock.NewRows([]string{"title", "body", "excerpt", "category", "tags"}).
+				AddRow("Book Title", "one\ntwo\nthree", "Summary", "go", "go"),
+			mockListed: true,
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(2, 1, 1, "Book Title", "one\n2\nthree", "Summary", "go", "go", testDate, nil, nil, nil, nil, nil).
+				AddRow(1, 1, nil, "Draft Title", "one\n2", "Summary", "uncategorized", "", testDate,
+					"Book Title", "one\n2\nthree", "Summary", "go", "go"),
+			expectedOutput: []models.BlogRevision{
+				{
+					ID:          2,
+					BlogID:      1,
+					EditorID:    1,
+					Title:       "Book Title",
+					Body:        "one\n2\nthree",
+					Excerpt:     "Summary",
+					Category:    "go",
+					Tags:        []string{"go"},
+					Diff:        " Title: Book Title\n Excerpt: Summary\n Category: go\n Tags: go\n \n one\n-2\n+two\n three\n",
+					CreatedDate: testDate,
+				},
+				{
+					ID:          1,
+					BlogID:      1,
+					Title:       "Draft Title",
+					Body:        "one\n2",
+					Excerpt:     "Summary",
+					Category:    "uncategorized",
+					Diff:        "-Title: Draft Title\n+Title: Book Title\n Excerpt: Summary\n-Category: uncategorized\n-Tags: \n+Category: go\n+Tags: go\n \n one\n 2\n+three\n",
+					CreatedDate: testDate,
+				},
+			},
+		},
+		"not found": {
+			mockCurrent:    sqlmock.NewRows([]string{"title", "body", "excerpt", "category", "tags"}),
+			expectedOutput: []models.BlogRevision{},
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.
+				ExpectQuery(regexp.QuoteMeta(currentQuery)).
+				WithArgs(1).
+				WillReturnRows(tc.mockCurrent)
+
+			if tc.mockListed {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT id, blog_id, editor_id, title, body, excerpt, category, tags, created_date,
+						next_title, next_body, next_excerpt, next_category, next_tags FROM (`)).
+					WithArgs(1).
+					WillReturnRows(tc.mockOutput)
+			}
+
+			blogService := NewBlogsService(slog.Default(), db, NewTagsService(slog.Default(), db))
+
+			output, err := blogService.ListBlogRevisions(context.TODO(), 1, tc.page)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if len(output.Items) != len(tc.expectedOutput) {
+				t.Fatalf("expected %v, got %v", tc.expectedOutput, output.Items)
+			}
+			for i, revision := range output.Items {
+				if !reflect.DeepEqual(revision, tc.expectedOutput[i]) {
+					t.Errorf("expected %+v, got %+v", tc.expectedOutput[i], revision)
+				}
+			}
+			if (output.NextCursor != "") != tc.expectedNext {
+				t.Errorf("expected next page %t, got cursor %q", tc.expectedNext, output.NextCursor)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBlogsService_RestoreBlogRevision(t *testing.T) {
+	revisionQuery := `SELECT title, body, excerpt, category, tags FROM blog_revisions WHERE id = $1 AND blog_id = $2`
+	updatedColumns := []string{"id", "author_id", "status", "publish_at", "score", "created_date", "version"}
+
+	testcases := map[string]struct {
+		version        uint
+		mockBlog       *sqlmock.Rows
+		mockRevision   *sqlmock.Rows
+		mockUpdated    *sqlmock.Rows
+		expectedOutput models.Blog
+		expectedError  error
+	}{
+		"happy path": {
+			mockBlog: sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockRevision: sqlmock.NewRows([]string{"title", "body", "excerpt", "category", "tags"}).
+				AddRow("Draft Title", "one", "Summary", "uncategorized", "go,postgres"),
+			mockUpdated: sqlmock.NewRows(updatedColumns).
+				AddRow(1, 1, "published", testDate, 8.2, testDate, 4),
+			expectedOutput: models.Blog{
+				ID:          1,
+				AuthorID:    1,
+				Title:       "Draft Title",
+				Body:        "one",
+				Excerpt:     "Summary",
+				Category:    "uncategorized",
+				Tags:        []string{"go", "postgres"},
+				Status:      models.BlogStatusPublished,
+				PublishAt:   testDate,
+				Score:       8.2,
+				CreatedDate: testDate,
+				Version:     4,
+			},
+		},
+		"stale version": {
+			version:  2,
+			mockBlog: sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockRevision: sqlmock.NewRows([]string{"title", "body", "excerpt", "category", "tags"}).
+				AddRow("Draft Title", "one", "Summary", "uncategorized", "go,postgres"),
+			mockUpdated:    sqlmock.NewRows(updatedColumns),
+			expectedOutput: models.Blog{},
+			expectedError:  ErrPreconditionFailed,
+		},
+		"blog not found": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}),
+			expectedOutput: models.Blog{},
+			expectedError:  ErrNotFound,
+		},
+		"revision of another blog": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockRevision:   sqlmock.NewRows([]string{"title", "body", "excerpt", "category", "tags"}),
+			expectedOutput: models.Blog{},
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT 1 FROM blogs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`)).
+				WithArgs(1).
+				WillReturnRows(tc.mockBlog)
+			if tc.mockRevision != nil {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(revisionQuery)).
+					WithArgs(5, 1).
+					WillReturnRows(tc.mockRevision)
+			}
+			if tc.mockUpdated != nil {
+				expectBlogRevision(mock, 1, 2)
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`UPDATE blogs
+						SET title = $1, body = $2, excerpt = $3, category = $4, version = version + 1
+						WHERE id = $5 AND ($6::bigint = 0 OR version = $6::bigint)
+						RETURNING id, author_id, status, publish_at, score, created_date, version`)).
+					WithArgs("Draft Title", "one", "Summary", "uncategorized", 1, tc.version).
+					WillReturnRows(tc.mockUpdated)
+			}
+			if tc.expectedError == nil {
+				expectSetBlogTags(mock, 1, "go", "postgres")
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			blogService := NewBlogsService(slog.Default(), db, NewTagsService(slog.Default(), db))
+
+			output, err := blogService.RestoreBlogRevision(context.TODO(), 1, 5, 2, tc.version)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if !reflect.DeepEqual(output, tc.expectedOutput) {
+				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBlogsService_CreateBlogs(t *testing.T) {
+	blogColumns := []string{"id", "score", "created_date"}
+	insert := `INSERT INTO blogs (author_id, title, body, excerpt, category, status, publish_at) VALUES `
+
+	testcases := map[string]struct {
+		mode           models.BulkMode
+		input          []models.Blog
+		expect         func(mock sqlmock.Sqlmock)
+		expectedIDs    []uint
+		expectedErrors []error
+	}{
+		"inserted together": {
+			mode: models.BulkAllOrNothing,
+			input: []models.Blog{
+				{AuthorID: 1, Title: "First"},
+				{AuthorID: 1, Title: "Second", Tags: []string{"Go"}},
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT id FROM users WHERE id IN ($1) AND deleted_at IS NULL`)).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert+`($1, $2, $3, $4, $5, $6, $7), ($8, $9, $10, $11, $12, $13, $14) RETURNING id, score, created_date`)).
+					WithArgs(
+						1, "First", "", "", "uncategorized", "draft", nil,
+						1, "Second", "", "", "uncategorized", "draft", nil,
+					).
+					WillReturnRows(sqlmock.NewRows(blogColumns).AddRow(1, 0, testDate).AddRow(2, 0, testDate))
+				expectSetBlogTags(mock, 1)
+				expectSetBlogTags(mock, 2, "go")
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedIDs:    []uint{1, 2},
+			expectedErrors: []error{nil, nil},
+		},
+		"missing author aborts the rest": {
+			mode: models.BulkAllOrNothing,
+			input: []models.Blog{
+				{AuthorID: 1, Title: "First"},
+				{AuthorID: 2, Title: "Second"},
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT id FROM users WHERE id IN ($1, $2) AND deleted_at IS NULL`)).
+					WithArgs(1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			expectedIDs:    []uint{0, 0},
+			expectedErrors: []error{ErrBulkAborted, ErrInvalidReference},
+		},
+		"invalid transition fails before the transaction": {
+			mode: models.BulkAllOrNothing,
+			input: []models.Blog{
+				{AuthorID: 1, Title: "First"},
+				{AuthorID: 1, Title: "Second", Status: models.BlogStatusScheduled, PublishAt: testDate.Add(-time.Hour)},
+			},
+			expect:         func(mock sqlmock.Sqlmock) {},
+			expectedIDs:    []uint{0, 0},
+			expectedErrors: []error{ErrBulkAborted, ErrInvalidTransition},
+		},
+		"failed batch is retried one at a time": {
+			mode: models.BulkBestEffort,
+			input: []models.Blog{
+				{AuthorID: 1, Title: "First"},
+				{AuthorID: 1, Title: "Second"},
+			},
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT id FROM users WHERE id IN ($1) AND deleted_at IS NULL`)).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert + `($1, $2, $3, $4, $5, $6, $7), ($8, $9, $10, $11, $12, $13, $14)`)).
+					WillReturnError(&pgconn.PgError{Code: pgCheckViolation, ConstraintName: "blogs_title_check"})
+				mock.ExpectExec(regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert+`($1, $2, $3, $4, $5, $6, $7) RETURNING id, score, created_date`)).
+					WithArgs(1, "First", "", "", "uncategorized", "draft", nil).
+					WillReturnRows(sqlmock.NewRows(blogColumns).AddRow(1, 0, testDate))
+				expectSetBlogTags(mock, 1)
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert+`($1, $2, $3, $4, $5, $6, $7) RETURNING id, score, created_date`)).
+					WithArgs(1, "Second", "", "", "uncategorized", "draft", nil).
+					WillReturnError(&pgconn.PgError{Code: pgCheckViolation, ConstraintName: "blogs_title_check"})
+				mock.ExpectExec(regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedIDs:    []uint{1, 0},
+			expectedErrors: []error{nil, ErrConstraintViolation},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			tc.expect(mock)
+
+			blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+			blogService.now = func() time.Time { return testDate }
+
+			results, err := blogService.CreateBlogs(context.TODO(), tc.input, tc.mode)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for i, result := range results {
+				if !errors.Is(result.Err, tc.expectedErrors[i]) || (result.Err == nil) != (tc.expectedErrors[i] == nil) {
+					t.Errorf("item %d: expected %v, got %v", i, tc.expectedErrors[i], result.Err)
+				}
+				if result.Value.ID != tc.expectedIDs[i] {
+					t.Errorf("item %d: expected id %d, got %d", i, tc.expectedIDs[i], result.Value.ID)
+				}
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestBlogsService_DeleteBlogs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	logger := slog.Default()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT version FROM blogs WHERE id = $1::int AND deleted_at IS NULL FOR UPDATE`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`UPDATE comments SET deleted_at = CURRENT_TIMESTAMP WHERE blog_id = $1::int AND deleted_at IS NULL`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`UPDATE blogs SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1::int AND deleted_at IS NULL`)).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT version FROM blogs WHERE id = $1::int AND deleted_at IS NULL FOR UPDATE`)).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+	mock.ExpectExec(regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+
+	results, err := blogService.DeleteBlogs(context.TODO(), []models.Blog{{ID: 1}, {ID: 2, Version: 4}}, models.BulkAllOrNothing)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrBulkAborted) {
+		t.Errorf("expected the first blog to be rolled back, got %v", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, ErrPreconditionFailed) {
+		t.Errorf("expected %v, got %v", ErrPreconditionFailed, results[1].Err)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBlogsService_ExportBlogs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	logger := slog.Default()
+
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id, author_id, title, body, excerpt, category, status, publish_at, score, created_date, COALESCE((`)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "author_id", "title", "body", "excerpt", "category", "status", "publish_at", "score", "created_date", "tags"}).
+				AddRow(1, 1, "First", "Body", "Body", "uncategorized", "published", testDate, 2.5, testDate, "go,sql").
+				AddRow(3, 2, "Second", "", "", "news", "draft", nil, 0, testDate, ""),
+		)
+
+	blogService := NewBlogsService(logger, db, NewTagsService(logger, db))
+
+	var blogs []models.Blog
+	err = blogService.ExportBlogs(context.TODO(), func(blog models.Blog) error {
+		blogs = append(blogs, blog)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []models.Blog{
+		{ID: 1, AuthorID: 1, Title: "First", Body: "Body", Excerpt: "Body", Category: "uncategorized", Tags: []string{"go", "sql"}, Status: models.BlogStatusPublished, PublishAt: testDate, Score: 2.5, CreatedDate: testDate},
+		{ID: 3, AuthorID: 2, Title: "Second", Category: "news", Status: models.BlogStatusDraft, CreatedDate: testDate},
+	}
+	if !reflect.DeepEqual(blogs, expected) {
+		t.Errorf("expected %+v, got %+v", expected, blogs)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// errBulkRollback rolls back the transaction of an all or nothing bulk write
+// once one of its items has failed.
+var errBulkRollback = errors.New("bulk write rolled back")
+
+// bulkWrite runs write in a single transaction for the items of a bulk write.
+// errs holds the error of each item, already set for the items that failed
+// before anything was written, and write records the errors of the items it
+// fails. In any mode but models.BulkBestEffort nothing is kept if an item
+// fails, and the items that would have been written get ErrBulkAborted. An
+// error is only returned if the transaction itself fails.
+func bulkWrite(ctx context.Context, db *sql.DB, mode models.BulkMode, errs []error, write func(tx *sql.Tx) error) error {
+	if len(pendingItems(errs)) == 0 {
+		return nil
+	}
+	if rollbackItems(mode, errs) {
+		abortItems(errs)
+		return nil
+	}
+
+	err := withTx(ctx, db, func(tx *sql.Tx) error {
+		if err := write(tx); err != nil {
+			return err
+		}
+		if rollbackItems(mode, errs) {
+			return errBulkRollback
+		}
+
+		return nil
+	})
+	if err == errBulkRollback {
+		abortItems(errs)
+		return nil
+	}
+
+	return err
+}
+
+// rollbackItems reports whether a bulk write in mode has to be rolled back
+// because one of its items failed. eachItem and insertItems stop writing
+// items once it does.
+func rollbackItems(mode models.BulkMode, errs []error) bool {
+	return mode != models.BulkBestEffort && len(pendingItems(errs)) < len(errs)
+}
+
+// pendingItems returns the indices of the items that haven't failed.
+func pendingItems(errs []error) []int {
+	var pending []int
+	for i, err := range errs {
+		if err == nil {
+			pending = append(pending, i)
+		}
+	}
+
+	return pending
+}
+
+// abortItems fails the items that haven't failed with ErrBulkAborted.
+func abortItems(errs []error) {
+	for i, err := range errs {
+		if err == nil {
+			errs[i] = ErrBulkAborted
+		}
+	}
+}
+
+// eachItem calls apply for each of the items that haven't failed, each within
+// its own savepoint, so that an item that fails is undone without aborting the
+// transaction. The errors apply returns are recorded in errs.
+func eachItem(ctx context.Context, tx *sql.Tx, mode models.BulkMode, errs []error, apply func(i int) error) error {
+	for _, i := range pendingItems(errs) {
+		if rollbackItems(mode, errs) {
+			return errBulkRollback
+		}
+
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT bulk_item`); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		if errs[i] = apply(i); errs[i] != nil {
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT bulk_item`); err != nil {
+				return fmt.Errorf("failed to roll back item %d: %w", i, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT bulk_item`); err != nil {
+			return fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// insertItems calls insert with every item that hasn't failed, so that they
+// are inserted with a single multi-row statement. If that fails, the batch is
+// undone and the items are inserted one at a time, as eachItem does, to find
+// out which of them failed.
+func insertItems(ctx context.Context, tx *sql.Tx, mode models.BulkMode, errs []error, insert func(batch []int) error) error {
+	if rollbackItems(mode, errs) {
+		return errBulkRollback
+	}
+
+	batch := pendingItems(errs)
+	if len(batch) < 2 {
+		return eachItem(ctx, tx, mode, errs, func(i int) error { return insert([]int{i}) })
+	}
+
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT bulk_batch`); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	if insert(batch) != nil {
+		if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT bulk_batch`); err != nil {
+			return fmt.Errorf("failed to roll back batch: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT bulk_batch`); err != nil {
+			return fmt.Errorf("failed to release savepoint: %w", err)
+		}
+
+		return eachItem(ctx, tx, mode, errs, func(i int) error { return insert([]int{i}) })
+	}
+
+	if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT bulk_batch`); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+// insertRows runs a multi-row INSERT of the items in batch and calls scan
+// with the index of each item and the row RETURNING gave back for it.
+// Postgres returns the rows in the order of the VALUES list.
+func insertRows(ctx context.Context, tx *sql.Tx, batch []int, query string, args []any, scan func(i int, rows *sql.Rows) error) error {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		if n == len(batch) {
+			return fmt.Errorf("insert returned more rows than the %d items", len(batch))
+		}
+		if err = scan(batch[n], rows); err != nil {
+			return err
+		}
+		n++
+	}
+	if err = rows.Err(); err != nil {
+		return translateError(err)
+	}
+	if n != len(batch) {
+		return fmt.Errorf("insert returned %d rows for %d items", n, len(batch))
+	}
+
+	return nil
+}
+
+// valueRows returns the VALUES list of a multi-row INSERT of rows rows of
+// columns columns each, such as "($1, $2), ($3, $4)".
+func valueRows(rows, columns int) string {
+	list := make([]string, rows)
+	for i := range list {
+		list[i] = "(" + placeholders(i*columns+1, columns) + ")"
+	}
+
+	return strings.Join(list, ", ")
+}
+
+// liveIDs returns the set of ids that belong to rows of table that aren't
+// deleted, reading them all with a single query.
+func liveIDs(ctx context.Context, tx *sql.Tx, table string, ids []uint) (map[uint]bool, error) {
+	return matchingIDs(ctx, tx, table, ids, "deleted_at IS NULL")
+}
+
+// matchingIDs returns the set of ids that belong to rows of table meeting
+// condition, reading them all with a single query. condition numbers its
+// placeholders for args with %d verbs, as listQuery.where does.
+func matchingIDs(ctx context.Context, tx *sql.Tx, table string, ids []uint, condition string, args ...any) (map[uint]bool, error) {
+	live := make(map[uint]bool)
+
+	var idArgs []any
+	seen := make(map[uint]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			idArgs = append(idArgs, id)
+		}
+	}
+	if len(idArgs) == 0 {
+		return live, nil
+	}
+
+	numbers := make([]any, len(args))
+	for i := range args {
+		numbers[i] = len(idArgs) + i + 1
+	}
+
+	rows, err := tx.QueryContext(
+		ctx,
+		fmt.Sprintf(`
+		SELECT id FROM %s WHERE id IN (%s) AND %s
+		`, table, placeholders(1, len(idArgs)), fmt.Sprintf(condition, numbers...)),
+		append(idArgs, args...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", table, err)
+		}
+		live[id] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+
+	return live, nil
+}
+
+// bulkResults pairs each of values with the error of its item, wrapping the
+// errors with op and the index of the item.
+func bulkResults[T any](op string, values []T, errs []error) []models.BulkResult[T] {
+	results := make([]models.BulkResult[T], len(values))
+	for i := range values {
+		if errs[i] != nil {
+			results[i].Err = fmt.Errorf("[in %s] item %d: %w", op, i, errs[i])
+			continue
+		}
+		results[i].Value = values[i]
+	}
+
+	return results
+}
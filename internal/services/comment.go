@@ -5,11 +5,23 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/chickey/blog/internal/diff"
 	"github.com/chickey/blog/internal/models"
 )
 
+const (
+	// DefaultThreadDepth is the number of levels of replies read when a
+	// request for comment threads does not set a depth.
+	DefaultThreadDepth = 5
+
+	// MaxThreadDepth is the largest number of levels of replies read at once.
+	MaxThreadDepth = 20
+)
+
 // CommentsService is a service capable of performing CRUD operations for
 // models.Comment models.
 type CommentsService struct {
@@ -26,8 +38,10 @@ func NewCommentsService(logger *slog.Logger, db *sql.DB) *CommentsService {
 }
 
 // CreateComment attempts to create the provided comment, returning a fully hydrated
-// models.Comment or an error.
-func (s *CommentsService) CreateComment(ctx context.Context, comment models.Comment) (models.Comment, error) {
+// models.Comment or an error. A reply must be on the same blog as the comment
+// it replies to. The blog must be readable by viewerId, unless allBlogs is
+// set, as an unpublished blog is only readable by its author.
+func (s *CommentsService) CreateComment(ctx context.Context, comment models.Comment, viewerId uint, allBlogs bool) (models.Comment, error) {
 	s.logger.DebugContext(ctx, "Creating comment", "Blog ID", comment.BlogID, "UserId", comment.UserID)
 
 	//validate user_id exists in user table
@@ -36,7 +50,7 @@ func (s *CommentsService) CreateComment(ctx context.Context, comment models.Comm
 		`
 			SELECT 1
 			FROM users
-			WHERE id = $1::int
+			WHERE id = $1::int AND deleted_at IS NULL
 			`,
 		comment.UserID,
 	)
@@ -47,8 +61,9 @@ func (s *CommentsService) CreateComment(ctx context.Context, comment models.Comm
 
 	if err != nil {
 		return models.Comment{}, fmt.Errorf(
-			"[in services.CommentsService.UpdateComment] failed to create comment: %w",
-			err,
+			"[in services.CommentsService.CreateComment] user %d: %w",
+			comment.UserID,
+			replaceNoRows(err, ErrInvalidReference),
 		)
 	}
 
@@ -58,9 +73,12 @@ func (s *CommentsService) CreateComment(ctx context.Context, comment models.Comm
 		`
 			SELECT 1
 			FROM blogs
-			WHERE id = $1::int
+			WHERE id = $1::int AND deleted_at IS NULL
+			AND ($2 OR status = 'published' OR author_id = $3::bigint)
 			`,
 		comment.BlogID,
+		allBlogs,
+		viewerId,
 	)
 
 	var blogExists int
@@ -69,61 +87,122 @@ func (s *CommentsService) CreateComment(ctx context.Context, comment models.Comm
 
 	if err != nil {
 		return models.Comment{}, fmt.Errorf(
-			"[in services.CommentsService.UpdateComment] failed to create comment: %w",
-			err,
+			"[in services.CommentsService.CreateComment] blog %d: %w",
+			comment.BlogID,
+			replaceNoRows(err, ErrInvalidReference),
 		)
 	}
 
-	//validate comment does not already exist with blog_id and user_id
-	commentCheck := s.db.QueryRowContext(
-		ctx,
-		`
-			SELECT 1
+	//validate the comment being replied to is on the same blog
+	if comment.ParentID > 0 {
+		parent := s.db.QueryRowContext(
+			ctx,
+			`
+			SELECT blog_id
 			FROM comments
-			WHERE blog_id = $1::int AND user_id = $2::int
+			WHERE id = $1::int AND deleted_at IS NULL
 			`,
-		comment.BlogID,
-		comment.UserID,
-	)
+			comment.ParentID,
+		)
 
-	var commentExists int
+		var parentBlogID uint
 
-	err = commentCheck.Scan(&commentExists)
+		err = parent.Scan(&parentBlogID)
 
-	if err != nil {
-		return models.Comment{}, fmt.Errorf(
-			"[in services.CommentsService.UpdateComment] failed to create comment: %w",
-			err,
-		)
+		if err != nil {
+			return models.Comment{}, fmt.Errorf(
+				"[in services.CommentsService.CreateComment] parent comment %d: %w",
+				comment.ParentID,
+				replaceNoRows(err, ErrInvalidReference),
+			)
+		}
+		if parentBlogID != comment.BlogID {
+			return models.Comment{}, fmt.Errorf(
+				"[in services.CommentsService.CreateComment] parent comment %d is on blog %d: %w",
+				comment.ParentID,
+				parentBlogID,
+				ErrInvalidReference,
+			)
+		}
 	}
 
 	// Create new comment entry in comment table
 	result := s.db.QueryRowContext(
 		ctx,
 		`
-		INSERT INTO comments (user_id, blog_id, message) VALUES ($1, $2, $3) RETURNING created_date
+		INSERT INTO comments (user_id, blog_id, parent_id, message) VALUES ($1, $2, $3, $4) RETURNING id, created_date
 		`,
 		comment.UserID,
 		comment.BlogID,
+		nullID(comment.ParentID),
 		comment.Message,
 	)
 
-	err = result.Scan(&comment.CreatedDate)
+	err = result.Scan(&comment.ID, &comment.CreatedDate)
 
 	if err != nil {
 		return models.Comment{}, fmt.Errorf(
 			"[in services.CommentsService.CreateComment] failed to create comment: %w",
-			err,
+			translateError(err),
 		)
 	}
 
 	return comment, nil
 }
 
-// UpdateComment attempts to perform an update of the comment with the provided id,
-// updating, it to reflect the properties on the provided patch object. A
-// models.Comment or an error.
-func (s *CommentsService) UpdateComment(ctx context.Context, patch models.Comment) (models.Comment, error) {
+// ReadComment attempts to read the comment by the user with userId on the blog
+// with blogId. If id is set it picks out one of the user's comments on the
+// blog, otherwise their first comment is read, matching the comment
+// UpdateComment would update. A models.Comment or an error is returned.
+func (s *CommentsService) ReadComment(ctx context.Context, id uint, userId uint, blogId uint) (models.Comment, error) {
+	s.logger.DebugContext(ctx, "Reading comment", "Blog ID", blogId, "UserId", userId)
+
+	var comment models.Comment
+	var parentID sql.NullInt64
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`
+		SELECT id, parent_id, user_id, blog_id, message, created_date, version
+		FROM comments
+		WHERE user_id = $1::int AND blog_id = $2::int AND ($3::bigint = 0 OR id = $3::bigint) AND deleted_at IS NULL
+		ORDER BY id
+		LIMIT 1
+		`,
+		userId,
+		blogId,
+		id,
+	).Scan(
+		&comment.ID,
+		&parentID,
+		&comment.UserID,
+		&comment.BlogID,
+		&comment.Message,
+		&comment.CreatedDate,
+		&comment.Version,
+	)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf(
+			"[in services.CommentsService.ReadComment] comment by user %d on blog %d: %w",
+			userId,
+			blogId,
+			replaceNoRows(err, ErrNotFound),
+		)
+	}
+	comment.ParentID = uint(parentID.Int64)
+
+	return comment, nil
+}
+
+// UpdateComment attempts to perform an update of the comment by patch.UserID
+// on patch.BlogID, updating, it to reflect the properties on the provided
+// patch object. If patch.ID is set it picks out one of the user's comments on
+// the blog, otherwise their first comment is updated. The previous message is
+// kept as a revision made by the editor with editorId. If patch.Version is set
+// the update only goes ahead if it is still the comment's version, and
+// ErrPreconditionFailed is returned otherwise. A models.Comment carrying its
+// new version or an error.
+func (s *CommentsService) UpdateComment(ctx context.Context, patch models.Comment, editorId uint) (models.Comment, error) {
 	s.logger.DebugContext(ctx, "Updating comment", "Blog ID", patch.BlogID, "UserId", patch.UserID)
 
 	//validate user_id exists in user table
@@ -132,7 +211,7 @@ func (s *CommentsService) UpdateComment(ctx context.Context, patch models.Commen
 		`
 		SELECT 1
 		FROM users
-		WHERE id = $1::int
+		WHERE id = $1::int AND deleted_at IS NULL
         `,
 		patch.UserID,
 	)
@@ -143,8 +222,9 @@ func (s *CommentsService) UpdateComment(ctx context.Context, patch models.Commen
 
 	if err != nil {
 		return models.Comment{}, fmt.Errorf(
-			"[in services.CommentsService.UpdateComment] failed to update comment: %w",
-			err,
+			"[in services.CommentsService.UpdateComment] user %d: %w",
+			patch.UserID,
+			replaceNoRows(err, ErrInvalidReference),
 		)
 	}
 
@@ -154,7 +234,7 @@ func (s *CommentsService) UpdateComment(ctx context.Context, patch models.Commen
 		`
 		SELECT 1
 		FROM blogs
-		WHERE id = $1::int
+		WHERE id = $1::int AND deleted_at IS NULL
         `,
 		patch.BlogID,
 	)
@@ -165,54 +245,424 @@ func (s *CommentsService) UpdateComment(ctx context.Context, patch models.Commen
 
 	if err != nil {
 		return models.Comment{}, fmt.Errorf(
-			"[in services.CommentsService.UpdateComment] failed to update comment: %w",
+			"[in services.CommentsService.UpdateComment] blog %d: %w",
+			patch.BlogID,
+			replaceNoRows(err, ErrInvalidReference),
+		)
+	}
+
+	err = withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		patch, err = s.updateComment(ctx, tx, patch, editorId)
+		return err
+	})
+
+	if err != nil {
+		return models.Comment{}, fmt.Errorf(
+			"[in services.CommentsService.UpdateComment] %w",
 			err,
 		)
 	}
 
+	return patch, nil
+}
+
+// updateComment performs the writes of UpdateComment within tx.
+func (s *CommentsService) updateComment(ctx context.Context, tx *sql.Tx, patch models.Comment, editorId uint) (models.Comment, error) {
+	var parentID sql.NullInt64
+
+	// Lock the comment so that no other update slips in between checking
+	// its version and writing it
+	var version uint
+	err := tx.QueryRowContext(
+		ctx,
+		`
+		SELECT id, version
+		FROM comments
+		WHERE user_id = $1 AND blog_id = $2 AND ($3::bigint = 0 OR id = $3::bigint) AND deleted_at IS NULL
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE
+		`,
+		patch.UserID,
+		patch.BlogID,
+		patch.ID,
+	).Scan(&patch.ID, &version)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to read comment: %w", replaceNoRows(err, ErrNotFound))
+	}
+
+	if err = checkVersion(version, patch.Version); err != nil {
+		return models.Comment{}, fmt.Errorf("comment %d: %w", patch.ID, err)
+	}
+
 	// should we be able to update created date?
-	row := s.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		`
+		WITH revision AS (
+			INSERT INTO comment_revisions (comment_id, editor_id, message)
+			SELECT id, $3, message FROM comments WHERE id = $2
+		)
 		UPDATE comments
 		SET message = $1
-		WHERE user_id = $2 AND blog_id = $3
-		RETURNING created_date
+		WHERE id = $2
+		RETURNING parent_id, created_date, version
 		`,
 		patch.Message,
-		patch.UserID,
-		patch.BlogID,
+		patch.ID,
+		nullID(editorId),
+	).Scan(&parentID, &patch.CreatedDate, &patch.Version)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to update comment: %w", translateError(err))
+	}
+
+	patch.ParentID = uint(parentID.Int64)
+	return patch, nil
+}
+
+// ListCommentRevisions attempts to list a page of the revisions of the comment
+// with commentId, newest first. Each revision carries a diff to the message
+// the update it records left, which for the newest revision is the comment's
+// current message. Like the blog itself, the revisions of a comment on a blog
+// that isn't published are only listed to the blog's author, the viewer with
+// viewerId, unless allBlogs is set. A page of models.CommentRevision or an
+// error is returned. ErrNotFound is returned if the comment does not exist or
+// the viewer may not read its blog.
+func (s *CommentsService) ListCommentRevisions(ctx context.Context, commentId uint, viewerId uint, allBlogs bool, page models.PageRequest) (models.Page[models.CommentRevision], error) {
+	s.logger.DebugContext(ctx, "Listing comment revisions", "Comment Id", commentId)
+
+	var comment models.Comment
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`
+		SELECT c.user_id, c.blog_id, c.message
+		FROM comments c
+		JOIN blogs b ON b.id = c.blog_id
+		WHERE c.id = $1 AND c.deleted_at IS NULL AND b.deleted_at IS NULL
+		  AND ($2 OR b.status = 'published' OR b.author_id = $3::bigint)
+		`,
+		commentId,
+		allBlogs,
+		viewerId,
+	).Scan(&comment.UserID, &comment.BlogID, &comment.Message)
+	if err != nil {
+		return models.Page[models.CommentRevision]{}, fmt.Errorf(
+			"[in services.CommentsService.ListCommentRevisions] comment %d: %w",
+			commentId,
+			replaceNoRows(err, ErrNotFound),
+		)
+	}
+
+	// Each revision is diffed against the next one, read alongside it
+	q := listQuery{
+		columns: "id, comment_id, editor_id, message, created_date, next_message",
+		from: `(
+			SELECT r.*, LEAD(message) OVER (PARTITION BY comment_id ORDER BY id) AS next_message
+			FROM comment_revisions r
+		) revisions`,
+		sort: []sortColumn{
+			{column: "id", cast: "bigint", desc: true},
+		},
+	}
+	q.where("comment_id = $%d", commentId)
+
+	revisions, err := listPage(
+		ctx,
+		s.db,
+		q,
+		page,
+		func(rows *sql.Rows) (models.CommentRevision, error) {
+			revision := models.CommentRevision{UserID: comment.UserID, BlogID: comment.BlogID}
+			var editorID sql.NullInt64
+			var nextMessage sql.NullString
+
+			err := rows.Scan(
+				&revision.ID,
+				&revision.CommentID,
+				&editorID,
+				&revision.Message,
+				&revision.CreatedDate,
+				&nextMessage,
+			)
+			if err != nil {
+				return models.CommentRevision{}, err
+			}
+			revision.EditorID = uint(editorID.Int64)
+
+			next := comment.Message
+			if nextMessage.Valid {
+				next = nextMessage.String
+			}
+			revision.Diff = diff.Lines(revision.Message, next)
+
+			return revision, nil
+		},
+		func(revision models.CommentRevision) []any {
+			return []any{revision.ID}
+		},
 	)
-	err = row.Scan(&patch.CreatedDate)
+	if err != nil {
+		return models.Page[models.CommentRevision]{}, fmt.Errorf(
+			"[in services.CommentsService.ListCommentRevisions] failed to list revisions: %w",
+			err,
+		)
+	}
+
+	return revisions, nil
+}
+
+// ReadCommentRevision attempts to read the revision with id, along with the
+// author and blog of the comment it belongs to. A models.CommentRevision
+// without a diff or an error is returned.
+func (s *CommentsService) ReadCommentRevision(ctx context.Context, id uint) (models.CommentRevision, error) {
+	s.logger.DebugContext(ctx, "Reading comment revision", "id", id)
+
+	var revision models.CommentRevision
+	var editorID sql.NullInt64
+
+	err := s.db.QueryRowContext(
+		ctx,
+		`
+		SELECT r.id, r.comment_id, c.user_id, c.blog_id, r.editor_id, r.message, r.created_date
+		FROM comment_revisions r
+		JOIN comments c ON c.id = r.comment_id
+		WHERE r.id = $1 AND c.deleted_at IS NULL
+		`,
+		id,
+	).Scan(
+		&revision.ID,
+		&revision.CommentID,
+		&revision.UserID,
+		&revision.BlogID,
+		&editorID,
+		&revision.Message,
+		&revision.CreatedDate,
+	)
+	if err != nil {
+		return models.CommentRevision{}, fmt.Errorf(
+			"[in services.CommentsService.ReadCommentRevision] revision %d: %w",
+			id,
+			replaceNoRows(err, ErrNotFound),
+		)
+	}
+	revision.EditorID = uint(editorID.Int64)
+
+	return revision, nil
+}
+
+// RestoreCommentRevision attempts to put the message of the revision with id
+// back on its comment. Like any other update, the message it replaces is kept
+// as a new revision made by the editor with editorId. If version is not zero
+// the revision is only restored if it is still the comment's version, and
+// ErrPreconditionFailed is returned otherwise. The restored models.Comment or
+// an error is returned. ErrNotFound is returned if there is no such revision.
+func (s *CommentsService) RestoreCommentRevision(ctx context.Context, id uint, editorId uint, version uint) (models.Comment, error) {
+	s.logger.DebugContext(ctx, "Restoring comment revision", "id", id)
+
+	var comment models.Comment
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Lock the comment so that no other update slips in between keeping
+		// its message and replacing it
+		var commentId uint
+
+		err := tx.QueryRowContext(
+			ctx,
+			`
+			SELECT c.id
+			FROM comment_revisions r
+			JOIN comments c ON c.id = r.comment_id
+			WHERE r.id = $1 AND c.deleted_at IS NULL
+			FOR UPDATE OF c
+			`,
+			id,
+		).Scan(&commentId)
+		if err != nil {
+			return fmt.Errorf("revision %d: %w", id, replaceNoRows(err, ErrNotFound))
+		}
+
+		// The comment is locked, so if it isn't updated it has moved on from
+		// the version the restore was made against. Restoring records a
+		// revision even when the message is unchanged, so the version always
+		// goes up.
+		row := tx.QueryRowContext(
+			ctx,
+			`
+			WITH target AS (
+				SELECT c.id, c.message AS previous, r.message AS restored
+				FROM comment_revisions r
+				JOIN comments c ON c.id = r.comment_id
+				WHERE r.id = $1 AND ($3::bigint = 0 OR c.version = $3::bigint)
+			), revision AS (
+				INSERT INTO comment_revisions (comment_id, editor_id, message)
+				SELECT id, $2, previous FROM target
+			)
+			UPDATE comments
+			SET message = target.restored, version = comments.version + 1
+			FROM target
+			WHERE comments.id = target.id
+			RETURNING comments.id, comments.parent_id, comments.user_id, comments.blog_id, comments.message, comments.created_date, comments.version
+			`,
+			id,
+			nullID(editorId),
+			version,
+		)
+
+		var parentID sql.NullInt64
+
+		err = row.Scan(&comment.ID, &parentID, &comment.UserID, &comment.BlogID, &comment.Message, &comment.CreatedDate, &comment.Version)
+		if err != nil {
+			return fmt.Errorf("comment %d: %w", commentId, replaceNoRows(translateError(err), ErrPreconditionFailed))
+		}
+		comment.ParentID = uint(parentID.Int64)
+
+		return nil
+	})
 
 	if err != nil {
 		return models.Comment{}, fmt.Errorf(
-			"[in services.CommentsService.UpdateComment] failed to update blog: %w",
+			"[in services.CommentsService.RestoreCommentRevision] %w",
 			err,
 		)
 	}
 
-	return patch, nil
+	return comment, nil
 }
 
-// DeleteComment attempts to delete the comment with the provided id. An error is
-// returned if the delete fails.
-func (s *CommentsService) DeleteComment(ctx context.Context, userId uint, blogId uint) error {
-	s.logger.DebugContext(ctx, "Deleteing comment", "User Id", userId, "Blog Id", blogId)
+// DeleteComment attempts to delete the comment by the user with userId on the
+// blog with blogId, along with the replies to it. If id is not zero it picks
+// out one of the user's comments on the blog, otherwise their first comment is
+// deleted. The replies are deleted at the same time so that RestoreComment can
+// bring them back, until they are purged. If version is not zero the comment
+// is only deleted if it is still the comment's version, and
+// ErrPreconditionFailed is returned otherwise. An error is returned if the
+// delete fails.
+func (s *CommentsService) DeleteComment(ctx context.Context, id uint, userId uint, blogId uint, version uint) error {
+	s.logger.DebugContext(ctx, "Deleteing comment", "Id", id, "User Id", userId, "Blog Id", blogId)
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		return s.deleteComment(ctx, tx, id, userId, blogId, version)
+	})
+
+	if err != nil {
+		return fmt.Errorf(
+			"[in services.CommentsService.DeleteComment] %w",
+			err,
+		)
+	}
+
+	return nil
+}
+
+// deleteComment performs DeleteComment within tx.
+func (s *CommentsService) deleteComment(ctx context.Context, tx *sql.Tx, id uint, userId uint, blogId uint, version uint) error {
+	var current uint
+	err := tx.QueryRowContext(
+		ctx,
+		`
+		SELECT id, version
+		FROM comments
+		WHERE user_id = $1::int AND blog_id = $2::int AND ($3::bigint = 0 OR id = $3::bigint) AND deleted_at IS NULL
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE
+		`,
+		userId,
+		blogId,
+		id,
+	).Scan(&id, &current)
+	if err != nil {
+		return fmt.Errorf(
+			"comment by user %d on blog %d: %w",
+			userId,
+			blogId,
+			replaceNoRows(err, ErrNotFound),
+		)
+	}
+
+	if err = checkVersion(current, version); err != nil {
+		return fmt.Errorf("comment %d: %w", id, err)
+	}
 
 	//DELETE from comment from comments
-	_, err := s.db.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		`
-		DELETE FROM comments WHERE user_id = $1::int AND blog_id = $2::int
+		WITH RECURSIVE removed AS (
+			SELECT $1::bigint AS id
+			UNION ALL
+			SELECT c.id
+			FROM comments c
+			JOIN removed r ON c.parent_id = r.id
+			WHERE c.deleted_at IS NULL
+		)
+		UPDATE comments
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT id FROM removed)
+		`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreComment attempts to restore the deleted comment by the user with
+// userId on the blog with blogId, along with the replies that were deleted
+// with it. If id is not zero it picks out one of the user's deleted comments
+// on the blog, otherwise their first deleted comment is restored. ErrNotFound
+// is returned if there is no such deleted comment, or if its blog, author or
+// the comment it replies to is deleted too and has to be restored instead.
+func (s *CommentsService) RestoreComment(ctx context.Context, id uint, userId uint, blogId uint) error {
+	s.logger.DebugContext(ctx, "Restoring comment", "Id", id, "User Id", userId, "Blog Id", blogId)
+
+	result, err := s.db.ExecContext(
+		ctx,
+		`
+		WITH RECURSIVE target AS (
+			SELECT c.id, c.deleted_at
+			FROM comments c
+			JOIN users u ON u.id = c.user_id AND u.deleted_at IS NULL
+			JOIN blogs b ON b.id = c.blog_id AND b.deleted_at IS NULL
+			LEFT JOIN comments p ON p.id = c.parent_id
+			WHERE c.user_id = $1::int AND c.blog_id = $2::int AND ($3::bigint = 0 OR c.id = $3::bigint)
+			  AND c.deleted_at IS NOT NULL
+			  AND p.deleted_at IS NULL
+			ORDER BY c.id
+			LIMIT 1
+		), restored AS (
+			SELECT id FROM target
+			UNION ALL
+			SELECT c.id
+			FROM comments c
+			JOIN restored r ON c.parent_id = r.id
+			WHERE c.deleted_at = (SELECT deleted_at FROM target)
+		)
+		UPDATE comments
+		SET deleted_at = NULL
+		WHERE id IN (SELECT id FROM restored)
 		`,
 		userId,
 		blogId,
+		id,
 	)
 
 	if err != nil {
 		return fmt.Errorf(
-			"[in services.CommentsService.DeleteComment] failed to delete comment: %w",
+			"[in services.CommentsService.RestoreComment] failed to restore comment: %w",
+			err,
+		)
+	}
+
+	if err = expectAffected(result); err != nil {
+		return fmt.Errorf(
+			"[in services.CommentsService.RestoreComment] deleted comment by user %d on blog %d: %w",
+			userId,
+			blogId,
 			err,
 		)
 	}
@@ -220,68 +670,582 @@ func (s *CommentsService) DeleteComment(ctx context.Context, userId uint, blogId
 	return nil
 }
 
-// ListComments attempts to list all comments in the database. A slice of models.Comment
-// or an error is returned.
-// WHY DO WE NEED ID?
-func (s *CommentsService) ListComments(ctx context.Context, userId uint, blogId uint) ([]models.Comment, error) {
+// ListComments attempts to list a page of the comments in the database matching
+// filter, ordered by blog, then user and then id. Replies are listed alongside
+// the comments they reply to. The related records named by expand are read in
+// the same query. A page of models.Comment or an error is returned.
+func (s *CommentsService) ListComments(ctx context.Context, filter models.CommentFilter, page models.PageRequest, expand models.CommentExpand) (models.Page[models.Comment], error) {
 	s.logger.DebugContext(ctx, "Listing comments")
 
-	//Build query based on query params
-	baseQuery := "SELECT user_id, blog_id, message, created_date FROM comments"
-
-	conditions := []string{}
-	args := []any{}
-	i := 1
+	columns, joins := commentExpansion(expand)
 
-	if userId > 0 {
-		conditions = append(conditions, fmt.Sprintf("user_id = $%d", i))
-		args = append(args, userId)
-		i++
+	q := listQuery{
+		columns: "id, parent_id, user_id, blog_id, message, created_date" + columns,
+		from:    "comments" + joins,
+		sort: []sortColumn{
+			{column: "blog_id", cast: "bigint"},
+			{column: "user_id", cast: "bigint"},
+			{column: "id", cast: "bigint"},
+		},
+	}
+	if filter.UserID > 0 {
+		q.where("user_id = $%d", filter.UserID)
 	}
-	if blogId > 0 {
-		conditions = append(conditions, fmt.Sprintf("blog_id = $%d", i))
-		args = append(args, blogId)
-		i++
+	if filter.BlogID > 0 {
+		q.where("blog_id = $%d", filter.BlogID)
+	}
+	if !filter.AllBlogs {
+		// Comments, and the blog titles they expand to, are only listed to
+		// those who may read the blog
+		if filter.ViewerID > 0 {
+			q.where("blog_id IN (SELECT id FROM blogs WHERE (status = 'published' OR author_id = $%d) AND deleted_at IS NULL)", filter.ViewerID)
+		} else {
+			q.where("blog_id IN (SELECT id FROM blogs WHERE status = 'published' AND deleted_at IS NULL)")
+		}
 	}
+	q.withDeleted(filter.IncludeDeleted)
 
-	if len(conditions) > 0 {
-		baseQuery = fmt.Sprintf("%s WHERE %s", baseQuery, strings.Join(conditions, " AND "))
+	comments, err := listPage(
+		ctx,
+		s.db,
+		q,
+		page,
+		expandedCommentScanner(expand, filter.IncludeDeleted),
+		func(comment models.Comment) []any {
+			return []any{comment.BlogID, comment.UserID, comment.ID}
+		},
+	)
+	if err != nil {
+		return models.Page[models.Comment]{}, fmt.Errorf(
+			"[in services.CommentsService.ListComments] failed to list comments: %w",
+			err,
+		)
 	}
 
-	rows, err := s.db.QueryContext(
+	return comments, nil
+}
+
+// ListCommentThreads attempts to read a page of the comments on the blog with
+// blogId as threads of replies, nested at most depth levels deep. The page
+// counts the comments on the blog itself, and each comes with its replies.
+// Threads and replies are ordered oldest first and carry the name of their
+// commenter. Like the blog itself, the comments on a blog that isn't published
+// are only listed to its author, the viewer with viewerId, unless allBlogs is
+// set. ErrNotFound is returned if the blog does not exist or the viewer may
+// not read it.
+func (s *CommentsService) ListCommentThreads(ctx context.Context, blogId uint, viewerId uint, allBlogs bool, depth int, page models.PageRequest) (models.Page[models.CommentThread], error) {
+	s.logger.DebugContext(ctx, "Listing comment threads", "Blog Id", blogId, "Depth", depth)
+
+	//validate blog exists with blog_id and the viewer may read it
+	blog := s.db.QueryRowContext(
 		ctx,
-		baseQuery,
-		args...,
+		`
+		SELECT 1
+		FROM blogs
+		WHERE id = $1::int AND deleted_at IS NULL AND ($2 OR status = 'published' OR author_id = $3::bigint)
+        `,
+		blogId,
+		allBlogs,
+		viewerId,
 	)
 
+	var blogExists int
+
+	err := blog.Scan(&blogExists)
+
 	if err != nil {
-		return []models.Comment{}, fmt.Errorf(
-			"[in services.CommentsService.ListComment] failed to read comments: %w",
-			err,
+		return models.Page[models.CommentThread]{}, fmt.Errorf(
+			"[in services.CommentsService.ListCommentThreads] blog %d: %w",
+			blogId,
+			replaceNoRows(err, ErrNotFound),
 		)
 	}
 
-	var comments []models.Comment
+	expand := models.CommentExpand{User: true}
+	columns, joins := commentExpansion(expand)
 
-	// error handling for error in for loop and then for empty error out of for loop?
-	for rows.Next() {
-		var comment models.Comment
-		err := rows.Scan(&comment.UserID, &comment.BlogID, &comment.Message, &comment.CreatedDate)
+	q := listQuery{
+		columns: "id, parent_id, user_id, blog_id, message, created_date" + columns,
+		from:    "comments" + joins,
+		sort: []sortColumn{
+			{column: "id", cast: "bigint"},
+		},
+	}
+	q.where("blog_id = $%d", blogId)
+	q.where("parent_id IS NULL")
+	q.where("deleted_at IS NULL")
+
+	roots, err := listPage(
+		ctx,
+		s.db,
+		q,
+		page,
+		expandedCommentScanner(expand, false),
+		func(comment models.Comment) []any {
+			return []any{comment.ID}
+		},
+	)
+	if err != nil {
+		return models.Page[models.CommentThread]{}, fmt.Errorf(
+			"[in services.CommentsService.ListCommentThreads] failed to list comments: %w",
+			err,
+		)
+	}
+
+	comments := roots.Items
+	if len(roots.Items) > 0 && threadDepth(depth) > 1 {
+		replies, err := s.listReplies(ctx, roots.Items, threadDepth(depth))
 		if err != nil {
-			return []models.Comment{}, fmt.Errorf(
-				"[in services.CommentsService.ListComment] failed to read comments: %w",
+			return models.Page[models.CommentThread]{}, fmt.Errorf(
+				"[in services.CommentsService.ListCommentThreads] %w",
 				err,
 			)
 		}
-		comments = append(comments, comment)
+		comments = append(comments, replies...)
 	}
 
+	return models.Page[models.CommentThread]{
+		Items:      buildThreads(comments),
+		NextCursor: roots.NextCursor,
+		PrevCursor: roots.PrevCursor,
+		Total:      roots.Total,
+	}, nil
+}
+
+// listReplies reads the replies to parents, and the replies to those, down to
+// depth levels counting the parents, ordered by level and then oldest first.
+func (s *CommentsService) listReplies(ctx context.Context, parents []models.Comment, depth int) ([]models.Comment, error) {
+	args := []any{depth}
+	for _, parent := range parents {
+		args = append(args, parent.ID)
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		fmt.Sprintf(`
+		WITH RECURSIVE thread AS (
+			SELECT id, parent_id, user_id, blog_id, message, created_date, 2 AS depth
+			FROM comments
+			WHERE parent_id IN (%s) AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id, c.parent_id, c.user_id, c.blog_id, c.message, c.created_date, t.depth + 1
+			FROM comments c
+			JOIN thread t ON c.parent_id = t.id
+			WHERE t.depth < $1 AND c.deleted_at IS NULL
+		)
+		SELECT t.id, t.parent_id, t.user_id, t.blog_id, t.message, t.created_date, u.name
+		FROM thread t
+		JOIN users u ON u.id = t.user_id
+		ORDER BY t.depth, t.id
+		`, placeholders(2, len(parents))),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query replies: %w", err)
+	}
+	defer rows.Close()
+
+	scan := expandedCommentScanner(models.CommentExpand{User: true}, false)
+
+	var replies []models.Comment
+	for rows.Next() {
+		reply, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reply: %w", err)
+		}
+		replies = append(replies, reply)
+	}
 	if err = rows.Err(); err != nil {
-		return []models.Comment{}, fmt.Errorf(
-			"[in services.CommentsService.ListComment] failed to read comments: %w",
-			err,
+		return nil, fmt.Errorf("failed to read replies: %w", err)
+	}
+
+	return replies, nil
+}
+
+// commentExpansion returns the columns to select after the comment columns,
+// and the joins that provide them, for the related records named by expand.
+// The joined tables only expose renamed columns, so the comments columns can
+// still be referred to unqualified.
+func commentExpansion(expand models.CommentExpand) (columns string, joins string) {
+	if expand.User {
+		columns += ", user_name"
+		joins += " JOIN (SELECT id AS user_key, name AS user_name FROM users) commenter ON commenter.user_key = comments.user_id"
+	}
+	if expand.Blog {
+		columns += ", blog_title"
+		joins += " JOIN (SELECT id AS blog_key, title AS blog_title FROM blogs) blog ON blog.blog_key = comments.blog_id"
+	}
+
+	return columns, joins
+}
+
+// expandedCommentScanner returns a function reading a comment from the current
+// row, which holds the columns id, parent_id, user_id, blog_id, message and
+// created_date followed by those added by commentExpansion for expand, and
+// then deleted_at if includeDeleted is set.
+func expandedCommentScanner(expand models.CommentExpand, includeDeleted bool) func(*sql.Rows) (models.Comment, error) {
+	return func(rows *sql.Rows) (models.Comment, error) {
+		var comment models.Comment
+		var parentID sql.NullInt64
+		var deletedAt sql.NullTime
+
+		dest := []any{&comment.ID, &parentID, &comment.UserID, &comment.BlogID, &comment.Message, &comment.CreatedDate}
+		if expand.User {
+			dest = append(dest, &comment.User.Name)
+		}
+		if expand.Blog {
+			dest = append(dest, &comment.Blog.Title)
+		}
+		if includeDeleted {
+			dest = append(dest, &deletedAt)
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return models.Comment{}, err
+		}
+		comment.ParentID = uint(parentID.Int64)
+		comment.DeletedAt = deletedAt.Time
+		if expand.User {
+			comment.User.ID = comment.UserID
+		}
+		if expand.Blog {
+			comment.Blog.ID = comment.BlogID
+		}
+
+		return comment, nil
+	}
+}
+
+// buildThreads nests comments under the comments they reply to, keeping the
+// order of comments. Comments whose parent is not among them are dropped.
+func buildThreads(comments []models.Comment) []models.CommentThread {
+	replies := make(map[uint][]models.Comment)
+	for _, comment := range comments {
+		replies[comment.ParentID] = append(replies[comment.ParentID], comment)
+	}
+
+	var build func(parentID uint) []models.CommentThread
+	build = func(parentID uint) []models.CommentThread {
+		threads := make([]models.CommentThread, 0, len(replies[parentID]))
+		for _, comment := range replies[parentID] {
+			threads = append(threads, models.CommentThread{
+				Comment: comment,
+				Replies: build(comment.ID),
+			})
+		}
+		return threads
+	}
+
+	return build(0)
+}
+
+// threadDepth returns the number of levels of comment threads to read,
+// applying the default and maximum depths.
+func threadDepth(depth int) int {
+	switch {
+	case depth <= 0:
+		return DefaultThreadDepth
+	case depth > MaxThreadDepth:
+		return MaxThreadDepth
+	default:
+		return depth
+	}
+}
+
+// nullID returns id as a nullable reference, which is NULL when id is zero.
+func nullID(id uint) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+// checkCommentReferences fails each comment whose user or blog doesn't exist
+// with ErrInvalidReference, as does a reply to a comment that doesn't exist
+// or is on another blog. A blog must also be readable by viewerId, unless
+// allBlogs is set. The users, blogs and parents of every comment are read
+// with one query each.
+func checkCommentReferences(ctx context.Context, tx *sql.Tx, comments []models.Comment, viewerId uint, allBlogs bool, errs []error) error {
+	var userIds, blogIds, parentIds []uint
+	for _, i := range pendingItems(errs) {
+		userIds = append(userIds, comments[i].UserID)
+		blogIds = append(blogIds, comments[i].BlogID)
+		if comments[i].ParentID > 0 {
+			parentIds = append(parentIds, comments[i].ParentID)
+		}
+	}
+
+	users, err := liveIDs(ctx, tx, "users", userIds)
+	if err != nil {
+		return err
+	}
+	blogs, err := matchingIDs(
+		ctx,
+		tx,
+		"blogs",
+		blogIds,
+		"deleted_at IS NULL AND ($%d OR status = 'published' OR author_id = $%d::bigint)",
+		allBlogs,
+		viewerId,
+	)
+	if err != nil {
+		return err
+	}
+
+	parents := make(map[uint]uint)
+	if len(parentIds) > 0 {
+		args := make([]any, len(parentIds))
+		for i, id := range parentIds {
+			args[i] = id
+		}
+
+		rows, err := tx.QueryContext(
+			ctx,
+			fmt.Sprintf(`
+			SELECT id, blog_id FROM comments WHERE id IN (%s) AND deleted_at IS NULL
+			`, placeholders(1, len(args))),
+			args...,
 		)
+		if err != nil {
+			return fmt.Errorf("failed to read parent comments: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id, blogId uint
+			if err = rows.Scan(&id, &blogId); err != nil {
+				return fmt.Errorf("failed to scan parent comment: %w", err)
+			}
+			parents[id] = blogId
+		}
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("failed to read parent comments: %w", err)
+		}
 	}
 
-	return comments, nil
+	for _, i := range pendingItems(errs) {
+		comment := comments[i]
+		switch parentBlogID, ok := parents[comment.ParentID]; {
+		case !users[comment.UserID]:
+			errs[i] = fmt.Errorf("user %d: %w", comment.UserID, ErrInvalidReference)
+		case !blogs[comment.BlogID]:
+			errs[i] = fmt.Errorf("blog %d: %w", comment.BlogID, ErrInvalidReference)
+		case comment.ParentID > 0 && !ok:
+			errs[i] = fmt.Errorf("parent comment %d: %w", comment.ParentID, ErrInvalidReference)
+		case comment.ParentID > 0 && parentBlogID != comment.BlogID:
+			errs[i] = fmt.Errorf("parent comment %d is on blog %d: %w", comment.ParentID, parentBlogID, ErrInvalidReference)
+		}
+	}
+
+	return nil
+}
+
+// CreateComments attempts to create the provided comments in a single
+// transaction, with a single multi-row INSERT and one query each checking
+// their users, blogs and parents. Each blog must be readable by viewerId, as
+// CreateComment checks. A models.BulkResult is returned for each
+// comment, in order, and mode decides whether the comments that could be
+// created are kept when others fail. An error is only returned if the bulk
+// write as a whole fails.
+func (s *CommentsService) CreateComments(ctx context.Context, comments []models.Comment, viewerId uint, allBlogs bool, mode models.BulkMode) ([]models.BulkResult[models.Comment], error) {
+	s.logger.DebugContext(ctx, "Creating comments", "count", len(comments), "mode", mode)
+
+	comments = slices.Clone(comments)
+	errs := make([]error, len(comments))
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		if err := checkCommentReferences(ctx, tx, comments, viewerId, allBlogs, errs); err != nil {
+			return err
+		}
+
+		return insertItems(ctx, tx, mode, errs, func(batch []int) error {
+			args := make([]any, 0, 4*len(batch))
+			for _, i := range batch {
+				comment := comments[i]
+				args = append(args, comment.UserID, comment.BlogID, nullID(comment.ParentID), comment.Message)
+			}
+
+			err := insertRows(
+				ctx,
+				tx,
+				batch,
+				fmt.Sprintf(`
+				INSERT INTO comments (user_id, blog_id, parent_id, message) VALUES %s RETURNING id, created_date
+				`, valueRows(len(batch), 4)),
+				args,
+				func(i int, rows *sql.Rows) error {
+					return rows.Scan(&comments[i].ID, &comments[i].CreatedDate)
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create comments: %w", err)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.CommentsService.CreateComments] %w", err)
+	}
+
+	return bulkResults("services.CommentsService.CreateComments", comments, errs), nil
+}
+
+// UpdateComments attempts to update each of the provided comments in a single
+// transaction, checking all of their users and blogs with one query each.
+// Each comment is picked out and updated as UpdateComment does, including its
+// revision and version check, and its ParentID is ignored. A
+// models.BulkResult is returned for each comment, in order, and mode decides
+// whether the comments that could be updated are kept when others fail. An
+// error is only returned if the bulk write as a whole fails.
+func (s *CommentsService) UpdateComments(ctx context.Context, comments []models.Comment, editorId uint, mode models.BulkMode) ([]models.BulkResult[models.Comment], error) {
+	s.logger.DebugContext(ctx, "Updating comments", "count", len(comments), "mode", mode)
+
+	comments = slices.Clone(comments)
+	errs := make([]error, len(comments))
+	for i := range comments {
+		comments[i].ParentID = 0
+	}
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		if err := checkCommentReferences(ctx, tx, comments, 0, true, errs); err != nil {
+			return err
+		}
+
+		return eachItem(ctx, tx, mode, errs, func(i int) error {
+			comment, err := s.updateComment(ctx, tx, comments[i], editorId)
+			comments[i] = comment
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.CommentsService.UpdateComments] %w", err)
+	}
+
+	return bulkResults("services.CommentsService.UpdateComments", comments, errs), nil
+}
+
+// DeleteComments attempts to delete each of the provided comments in a single
+// transaction. Each comment is picked out by its ID, UserID and BlogID and
+// deleted with its replies as DeleteComment does, and only if it is still at
+// its Version when that is set. A models.BulkResult holding the comment as it
+// was given is returned for each comment, in order, and mode decides whether
+// the comments that could be deleted stay deleted when others fail. An error
+// is only returned if the bulk write as a whole fails.
+func (s *CommentsService) DeleteComments(ctx context.Context, comments []models.Comment, mode models.BulkMode) ([]models.BulkResult[models.Comment], error) {
+	s.logger.DebugContext(ctx, "Deleting comments", "count", len(comments), "mode", mode)
+
+	errs := make([]error, len(comments))
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		return eachItem(ctx, tx, mode, errs, func(i int) error {
+			comment := comments[i]
+			return s.deleteComment(ctx, tx, comment.ID, comment.UserID, comment.BlogID, comment.Version)
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.CommentsService.DeleteComments] %w", err)
+	}
+
+	return bulkResults("services.CommentsService.DeleteComments", comments, errs), nil
+}
+
+// ExportComments calls fn with each comment that isn't deleted, in order of
+// id, reading them with a single query so that they are streamed rather than
+// held in memory. Replies come after the comments they reply to. An error is
+// returned if reading the comments fails or fn does.
+func (s *CommentsService) ExportComments(ctx context.Context, fn func(models.Comment) error) error {
+	s.logger.DebugContext(ctx, "Exporting comments")
+
+	err := exportRows(
+		ctx,
+		s.db,
+		`
+		SELECT id, parent_id, user_id, blog_id, message, created_date
+		FROM comments
+		WHERE deleted_at IS NULL
+		ORDER BY id
+		`,
+		func(rows *sql.Rows) error {
+			var comment models.Comment
+			var parentID sql.NullInt64
+			if err := rows.Scan(&comment.ID, &parentID, &comment.UserID, &comment.BlogID, &comment.Message, &comment.CreatedDate); err != nil {
+				return err
+			}
+			comment.ParentID = uint(parentID.Int64)
+
+			return fn(comment)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("[in services.CommentsService.ExportComments] failed to export comments: %w", err)
+	}
+
+	return nil
+}
+
+// ImportComments attempts to create the provided comments, as exported by
+// ExportComments, in a single transaction, checking their users, blogs and
+// parents with one query each. Comments keep their created date, which
+// defaults to now, and if keepIDs is set they keep their IDs too. A reply
+// can't be imported together with the comment it replies to. Comments that
+// can't be created are skipped, and a models.BulkResult is returned for each
+// comment, in order, carrying the ID it was created with. An error is only
+// returned if the import as a whole fails.
+func (s *CommentsService) ImportComments(ctx context.Context, comments []models.Comment, keepIDs bool) ([]models.BulkResult[models.Comment], error) {
+	s.logger.DebugContext(ctx, "Importing comments", "count", len(comments), "keep_ids", keepIDs)
+
+	comments = slices.Clone(comments)
+	errs := make([]error, len(comments))
+
+	now := time.Now()
+	for i := range comments {
+		if comments[i].CreatedDate.IsZero() {
+			comments[i].CreatedDate = now
+		}
+	}
+
+	columns := importColumns(keepIDs, "user_id", "blog_id", "parent_id", "message", "created_date")
+
+	err := bulkWrite(ctx, s.db, models.BulkBestEffort, errs, func(tx *sql.Tx) error {
+		if err := checkCommentReferences(ctx, tx, comments, 0, true, errs); err != nil {
+			return err
+		}
+
+		err := insertItems(ctx, tx, models.BulkBestEffort, errs, func(batch []int) error {
+			args := make([]any, 0, len(columns)*len(batch))
+			for _, i := range batch {
+				comment := comments[i]
+				if keepIDs {
+					args = append(args, comment.ID)
+				}
+				args = append(args, comment.UserID, comment.BlogID, nullID(comment.ParentID), comment.Message, comment.CreatedDate)
+			}
+
+			err := insertRows(
+				ctx,
+				tx,
+				batch,
+				fmt.Sprintf(`
+				INSERT INTO comments (%s) VALUES %s RETURNING id
+				`, strings.Join(columns, ", "), valueRows(len(batch), len(columns))),
+				args,
+				func(i int, rows *sql.Rows) error {
+					return rows.Scan(&comments[i].ID)
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to import comments: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil || !keepIDs {
+			return err
+		}
+
+		return syncSequence(ctx, tx, "comments")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.CommentsService.ImportComments] %w", err)
+	}
+
+	return bulkResults("services.CommentsService.ImportComments", comments, errs), nil
 }
@@ -4,7 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
-	"fmt"
+	"errors"
 	"log/slog"
 	"regexp"
 	"testing"
@@ -15,31 +15,48 @@ import (
 )
 
 func TestCommentsService_ListComments(t *testing.T) {
+	columns := []string{"id", "parent_id", "user_id", "blog_id", "message", "created_date"}
+	cursor := listQuery{
+		sort: []sortColumn{
+			{column: "blog_id", cast: "bigint"},
+			{column: "user_id", cast: "bigint"},
+			{column: "id", cast: "bigint"},
+		},
+	}
+
 	testcases := map[string]struct {
 		mockCalled     bool
+		mockQuery      string
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
 		mockError      error
-		input          uint64
+		filter         models.CommentFilter
+		page           models.PageRequest
+		expand         models.CommentExpand
 		expectedOutput []models.Comment
 		expectedError  error
 	}{
 		"happy path": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"user_id", "blog_id", "message", "created_date"}).
-				AddRow(1, 1, "New Comment", testDate).
-				AddRow(1, 2, "Good blog", testDate),
+			mockCalled: true,
+			mockQuery: `SELECT id, parent_id, user_id, blog_id, message, created_date FROM comments
+				WHERE blog_id IN (SELECT id FROM blogs WHERE status = 'published' AND deleted_at IS NULL) AND deleted_at IS NULL
+				ORDER BY blog_id ASC, user_id ASC, id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(1, nil, 1, 1, "New Comment", testDate).
+				AddRow(2, 1, 1, 2, "Good blog", testDate),
 			mockError: nil,
-			input:     1,
 			expectedOutput: []models.Comment{
 				{
+					ID:          1,
 					BlogID:      1,
 					UserID:      1,
 					Message:     "New Comment",
 					CreatedDate: testDate,
 				},
 				{
+					ID:          2,
+					ParentID:    1,
 					BlogID:      2,
 					UserID:      1,
 					Message:     "Good blog",
@@ -48,17 +65,80 @@ func TestCommentsService_ListComments(t *testing.T) {
 			},
 			expectedError: nil,
 		},
-		"no rows returned": {
+		"filters and cursor": {
+			mockCalled: true,
+			mockQuery: `SELECT id, parent_id, user_id, blog_id, message, created_date FROM comments
+				WHERE user_id = $1 AND blog_id = $2
+				AND blog_id IN (SELECT id FROM blogs WHERE (status = 'published' OR author_id = $3) AND deleted_at IS NULL)
+				AND deleted_at IS NULL
+				AND ((blog_id > $4::text::bigint) OR (blog_id = $4::text::bigint AND user_id > $5::text::bigint)
+				OR (blog_id = $4::text::bigint AND user_id = $5::text::bigint AND id > $6::text::bigint))
+				ORDER BY blog_id ASC, user_id ASC, id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{int64(1), int64(2), int64(3), "2", "1", "7"},
+			mockOutput:    sqlmock.NewRows(columns),
+			mockError:     nil,
+			filter:        models.CommentFilter{UserID: 1, BlogID: 2, ViewerID: 3},
+			page: models.PageRequest{
+				Cursor: cursor.encodeCursor([]string{"2", "1", "7"}, false),
+			},
+			expectedOutput: []models.Comment{},
+			expectedError:  nil,
+		},
+		"expanded user and blog": {
+			mockCalled: true,
+			mockQuery: `SELECT id, parent_id, user_id, blog_id, message, created_date, user_name, blog_title
+				FROM comments
+				JOIN (SELECT id AS user_key, name AS user_name FROM users) commenter ON commenter.user_key = comments.user_id
+				JOIN (SELECT id AS blog_key, title AS blog_title FROM blogs) blog ON blog.blog_key = comments.blog_id
+				WHERE deleted_at IS NULL
+				ORDER BY blog_id ASC, user_id ASC, id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(append(columns, "user_name", "blog_title")).
+				AddRow(1, nil, 1, 1, "New Comment", testDate, "John", "Book Title"),
+			mockError: nil,
+			filter:    models.CommentFilter{ViewerID: 3, AllBlogs: true},
+			expand:    models.CommentExpand{User: true, Blog: true},
+			expectedOutput: []models.Comment{
+				{
+					ID:          1,
+					BlogID:      1,
+					Blog:        models.BlogRef{ID: 1, Title: "Book Title"},
+					UserID:      1,
+					User:        models.UserRef{ID: 1, Name: "John"},
+					Message:     "New Comment",
+					CreatedDate: testDate,
+				},
+			},
+			expectedError: nil,
+		},
+		"including deleted": {
+			mockCalled:    true,
+			mockQuery:     `SELECT id, parent_id, user_id, blog_id, message, created_date, deleted_at FROM comments ORDER BY blog_id ASC, user_id ASC, id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(append(columns, "deleted_at")).
+				AddRow(1, nil, 1, 1, "New Comment", testDate, testDate),
+			mockError: nil,
+			filter:    models.CommentFilter{ViewerID: 3, AllBlogs: true, IncludeDeleted: true},
+			expectedOutput: []models.Comment{
+				{
+					ID:          1,
+					BlogID:      1,
+					UserID:      1,
+					Message:     "New Comment",
+					CreatedDate: testDate,
+					DeletedAt:   testDate,
+				},
+			},
+			expectedError: nil,
+		},
+		"query error": {
 			mockCalled:     true,
-			mockInputArgs:  []driver.Value{1},
-			mockOutput:     sqlmock.NewRows([]string{}),
-			mockError:      sql.ErrNoRows,
-			input:          1,
+			mockQuery:      `SELECT id, parent_id, user_id, blog_id, message, created_date FROM comments`,
+			mockInputArgs:  []driver.Value{},
+			mockOutput:     sqlmock.NewRows(columns),
+			mockError:      sql.ErrConnDone,
 			expectedOutput: []models.Comment{},
-			expectedError: fmt.Errorf(
-				"[in services.CommentsService.ListComment] failed to list comments: %w",
-				sql.ErrNoRows,
-			),
+			expectedError:  sql.ErrConnDone,
 		},
 	}
 	for name, tc := range testcases {
@@ -73,22 +153,24 @@ func TestCommentsService_ListComments(t *testing.T) {
 
 			if tc.mockCalled {
 				mock.
-					ExpectQuery(regexp.QuoteMeta(`
-                        SELECT user_id, blog_id, message, created_date FROM comments
-                    `)).
+					ExpectQuery(regexp.QuoteMeta(tc.mockQuery)).
+					WithArgs(tc.mockInputArgs...).
 					WillReturnRows(tc.mockOutput).
 					WillReturnError(tc.mockError)
 			}
 
 			commentService := NewCommentsService(logger, db)
 
-			outputs, err := commentService.ListComments(context.TODO(), 0, 0)
+			output, err := commentService.ListComments(context.TODO(), tc.filter, tc.page, tc.expand)
 			if !assert.ErrorIs(t, err, tc.expectedError) {
 				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
-			for i, output := range outputs {
-				if output != tc.expectedOutput[i] {
-					t.Errorf("expected %v, got %v", tc.expectedOutput[i], output)
+			if len(output.Items) != len(tc.expectedOutput) {
+				t.Fatalf("expected %v, got %v", tc.expectedOutput, output.Items)
+			}
+			for i, comment := range output.Items {
+				if comment != tc.expectedOutput[i] {
+					t.Errorf("expected %v, got %v", tc.expectedOutput[i], comment)
 				}
 			}
 
@@ -103,26 +185,30 @@ func TestCommentsService_ListComments(t *testing.T) {
 
 func TestCommentsService_CreateComment(t *testing.T) {
 	testcases := map[string]struct {
-		mockCalled     bool
+		mockBlog       *sqlmock.Rows
+		mockParent     *sqlmock.Rows
+		mockInserted   bool
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
-		mockError      error
 		input          models.Comment
+		viewerID       uint
+		allBlogs       bool
 		expectedOutput models.Comment
 		expectedError  error
 	}{
 		"happy path": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{1, 1, "Good blog"},
-			mockOutput: sqlmock.NewRows([]string{"created_date"}).
-				AddRow(testDate),
-			mockError: nil,
+			mockInserted:  true,
+			mockInputArgs: []driver.Value{1, 1, nil, "Good blog"},
+			mockOutput: sqlmock.NewRows([]string{"id", "created_date"}).
+				AddRow(3, testDate),
 			input: models.Comment{
 				BlogID:  1,
 				UserID:  1,
 				Message: "Good blog",
 			},
+			viewerID: 1,
 			expectedOutput: models.Comment{
+				ID:          3,
 				BlogID:      1,
 				UserID:      1,
 				Message:     "Good blog",
@@ -130,6 +216,84 @@ func TestCommentsService_CreateComment(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		"admin": {
+			mockInserted:  true,
+			mockInputArgs: []driver.Value{1, 1, nil, "Good blog"},
+			mockOutput: sqlmock.NewRows([]string{"id", "created_date"}).
+				AddRow(3, testDate),
+			input: models.Comment{
+				BlogID:  1,
+				UserID:  1,
+				Message: "Good blog",
+			},
+			viewerID: 3,
+			allBlogs: true,
+			expectedOutput: models.Comment{
+				ID:          3,
+				BlogID:      1,
+				UserID:      1,
+				Message:     "Good blog",
+				CreatedDate: testDate,
+			},
+			expectedError: nil,
+		},
+		"blog the caller can't read": {
+			mockBlog: sqlmock.NewRows([]string{"?column?"}),
+			input: models.Comment{
+				BlogID:  1,
+				UserID:  1,
+				Message: "Good blog",
+			},
+			viewerID:       1,
+			expectedOutput: models.Comment{},
+			expectedError:  ErrInvalidReference,
+		},
+		"reply": {
+			mockParent: sqlmock.NewRows([]string{"blog_id"}).
+				AddRow(1),
+			mockInserted:  true,
+			mockInputArgs: []driver.Value{1, 1, int64(2), "Agreed"},
+			mockOutput: sqlmock.NewRows([]string{"id", "created_date"}).
+				AddRow(3, testDate),
+			input: models.Comment{
+				ParentID: 2,
+				BlogID:   1,
+				UserID:   1,
+				Message:  "Agreed",
+			},
+			expectedOutput: models.Comment{
+				ID:          3,
+				ParentID:    2,
+				BlogID:      1,
+				UserID:      1,
+				Message:     "Agreed",
+				CreatedDate: testDate,
+			},
+			expectedError: nil,
+		},
+		"reply to comment on another blog": {
+			mockParent: sqlmock.NewRows([]string{"blog_id"}).
+				AddRow(4),
+			input: models.Comment{
+				ParentID: 2,
+				BlogID:   1,
+				UserID:   1,
+				Message:  "Agreed",
+			},
+			expectedOutput: models.Comment{},
+			expectedError:  ErrInvalidReference,
+		},
+		"reply to missing comment": {
+			mockParent: sqlmock.NewRows([]string{"blog_id"}),
+			input: models.Comment{
+				ParentID: 2,
+				BlogID:   1,
+				UserID:   1,
+				Message:  "Agreed",
+			},
+			expectedOutput: models.Comment{},
+			expectedError:  ErrInvalidReference,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -141,94 +305,252 @@ func TestCommentsService_CreateComment(t *testing.T) {
 
 			logger := slog.Default()
 
-			if tc.mockCalled {
-				mock.
-					ExpectQuery(regexp.QuoteMeta(`
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
 				   SELECT 1
 					FROM users
 					WHERE id = $1::int
 				`)).
-					WithArgs([]driver.Value{1}...).
-					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
-						AddRow(1)).
-					WillReturnError(tc.mockError)
-				mock.
-					ExpectQuery(regexp.QuoteMeta(`
+				WithArgs([]driver.Value{1}...).
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
+					AddRow(1))
+			mockBlog := tc.mockBlog
+			if mockBlog == nil {
+				mockBlog = sqlmock.NewRows([]string{"?column?"}).
+					AddRow(1)
+			}
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
                        SELECT 1
 						FROM blogs
-						WHERE id = $1::int
+						WHERE id = $1::int AND deleted_at IS NULL
+						AND ($2 OR status = 'published' OR author_id = $3::bigint)
                     `)).
-					WithArgs([]driver.Value{1}...).
-					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
-						AddRow(1)).
-					WillReturnError(tc.mockError)
+				WithArgs(1, tc.allBlogs, tc.viewerID).
+				WillReturnRows(mockBlog)
+
+			if tc.mockParent != nil {
 				mock.
 					ExpectQuery(regexp.QuoteMeta(`
-                    	SELECT 1
+						SELECT blog_id
 						FROM comments
-						WHERE blog_id = $1::int AND user_id = $2::int
-                    `)).
-					WithArgs([]driver.Value{1, 1}...).
-					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
-						AddRow(1)).
-					WillReturnError(tc.mockError)
+						WHERE id = $1::int
+					`)).
+					WithArgs(tc.input.ParentID).
+					WillReturnRows(tc.mockParent)
+			}
 
+			if tc.mockInserted {
 				mock.
 					ExpectQuery(regexp.QuoteMeta(
-						`INSERT INTO comments (user_id, blog_id, message) VALUES ($1, $2, $3) RETURNING created_date`)).
+						`INSERT INTO comments (user_id, blog_id, parent_id, message) VALUES ($1, $2, $3, $4) RETURNING id, created_date`)).
 					WithArgs(tc.mockInputArgs...).
-					WillReturnRows(tc.mockOutput).
-					WillReturnError(tc.mockError)
+					WillReturnRows(tc.mockOutput)
 			}
 
 			commentService := NewCommentsService(logger, db)
 
-			output, err := commentService.CreateComment(context.TODO(), tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			output, err := commentService.CreateComment(context.TODO(), tc.input, tc.viewerID, tc.allBlogs)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
 			if output != tc.expectedOutput {
 				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
 			}
 
-			if tc.mockCalled {
-				if err = mock.ExpectationsWereMet(); err != nil {
-					t.Errorf("there were unfulfilled expectations: %s", err)
-				}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCommentsService_ReadComment(t *testing.T) {
+	columns := []string{"id", "parent_id", "user_id", "blog_id", "message", "created_date", "version"}
+
+	testcases := map[string]struct {
+		mockInputArgs  []driver.Value
+		mockOutput     *sqlmock.Rows
+		id             uint
+		expectedOutput models.Comment
+		expectedError  error
+	}{
+		"first comment": {
+			mockInputArgs: []driver.Value{1, 2, 0},
+			mockOutput:    sqlmock.NewRows(columns).AddRow(3, nil, 1, 2, "Good blog", testDate, 2),
+			expectedOutput: models.Comment{
+				ID:          3,
+				UserID:      1,
+				BlogID:      2,
+				Message:     "Good blog",
+				CreatedDate: testDate,
+				Version:     2,
+			},
+			expectedError: nil,
+		},
+		"by id": {
+			mockInputArgs: []driver.Value{1, 2, 4},
+			mockOutput:    sqlmock.NewRows(columns).AddRow(4, 3, 1, 2, "Agreed", testDate, 1),
+			id:            4,
+			expectedOutput: models.Comment{
+				ID:          4,
+				ParentID:    3,
+				UserID:      1,
+				BlogID:      2,
+				Message:     "Agreed",
+				CreatedDate: testDate,
+				Version:     1,
+			},
+			expectedError: nil,
+		},
+		"not found": {
+			mockInputArgs:  []driver.Value{1, 2, 4},
+			mockOutput:     sqlmock.NewRows(columns),
+			id:             4,
+			expectedOutput: models.Comment{},
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT id, parent_id, user_id, blog_id, message, created_date, version
+					FROM comments
+					WHERE user_id = $1::int AND blog_id = $2::int AND ($3::bigint = 0 OR id = $3::bigint) AND deleted_at IS NULL`)).
+				WithArgs(tc.mockInputArgs...).
+				WillReturnRows(tc.mockOutput)
+
+			commentService := NewCommentsService(logger, db)
+
+			output, err := commentService.ReadComment(context.TODO(), tc.id, 1, 2)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			assert.Equal(t, tc.expectedOutput, output)
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
 			}
 		})
 	}
 }
 
 func TestCommentsService_UpdateComment(t *testing.T) {
+	lockColumns := []string{"id", "version"}
+	columns := []string{"parent_id", "created_date", "version"}
+
 	testcases := map[string]struct {
-		mockCalled     bool
+		mockLockArgs   []driver.Value
+		mockLock       *sqlmock.Rows
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
-		mockError      error
 		input          models.Comment
+		editorId       uint
 		expectedOutput models.Comment
 		expectedError  error
 	}{
 		"happy path": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{"Good blog", 1, 1},
-			mockOutput: sqlmock.NewRows([]string{"created_date"}).
-				AddRow(testDate),
-			mockError: nil,
+			mockLockArgs:  []driver.Value{1, 1, 0},
+			mockLock:      sqlmock.NewRows(lockColumns).AddRow(3, 1),
+			mockInputArgs: []driver.Value{"Good blog", 3, 1},
+			mockOutput:    sqlmock.NewRows(columns).AddRow(nil, testDate, 2),
 			input: models.Comment{
 				BlogID:  1,
 				UserID:  1,
 				Message: "Good blog",
 			},
+			editorId: 1,
 			expectedOutput: models.Comment{
+				ID:          3,
 				BlogID:      1,
 				UserID:      1,
 				Message:     "Good blog",
 				CreatedDate: testDate,
+				Version:     2,
+			},
+			expectedError: nil,
+		},
+		"by id": {
+			mockLockArgs:  []driver.Value{1, 1, 4},
+			mockLock:      sqlmock.NewRows(lockColumns).AddRow(4, 1),
+			mockInputArgs: []driver.Value{"Agreed", 4, 3},
+			mockOutput:    sqlmock.NewRows(columns).AddRow(3, testDate, 2),
+			input: models.Comment{
+				ID:      4,
+				BlogID:  1,
+				UserID:  1,
+				Message: "Agreed",
+			},
+			editorId: 3,
+			expectedOutput: models.Comment{
+				ID:          4,
+				ParentID:    3,
+				BlogID:      1,
+				UserID:      1,
+				Message:     "Agreed",
+				CreatedDate: testDate,
+				Version:     2,
+			},
+			expectedError: nil,
+		},
+		"current version": {
+			mockLockArgs:  []driver.Value{1, 1, 4},
+			mockLock:      sqlmock.NewRows(lockColumns).AddRow(4, 5),
+			mockInputArgs: []driver.Value{"Agreed", 4, 1},
+			mockOutput:    sqlmock.NewRows(columns).AddRow(nil, testDate, 6),
+			input: models.Comment{
+				ID:      4,
+				BlogID:  1,
+				UserID:  1,
+				Message: "Agreed",
+				Version: 5,
+			},
+			editorId: 1,
+			expectedOutput: models.Comment{
+				ID:          4,
+				BlogID:      1,
+				UserID:      1,
+				Message:     "Agreed",
+				CreatedDate: testDate,
+				Version:     6,
 			},
 			expectedError: nil,
 		},
+		"stale version": {
+			mockLockArgs: []driver.Value{1, 1, 4},
+			mockLock:     sqlmock.NewRows(lockColumns).AddRow(4, 6),
+			input: models.Comment{
+				ID:      4,
+				BlogID:  1,
+				UserID:  1,
+				Message: "Agreed",
+				Version: 5,
+			},
+			editorId:       1,
+			expectedOutput: models.Comment{},
+			expectedError:  ErrPreconditionFailed,
+		},
+		"not found": {
+			mockLockArgs: []driver.Value{1, 1, 4},
+			mockLock:     sqlmock.NewRows(lockColumns),
+			input: models.Comment{
+				ID:      4,
+				BlogID:  1,
+				UserID:  1,
+				Message: "Agreed",
+			},
+			editorId:       1,
+			expectedOutput: models.Comment{},
+			expectedError:  ErrNotFound,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -240,75 +562,117 @@ func TestCommentsService_UpdateComment(t *testing.T) {
 
 			logger := slog.Default()
 
-			if tc.mockCalled {
-				mock.
-					ExpectQuery(regexp.QuoteMeta(`
-				   SELECT 1
-					FROM users
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+			   SELECT 1
+				FROM users
+				WHERE id = $1::int
+			`)).
+				WithArgs([]driver.Value{1}...).
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
+					AddRow(1))
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+                   SELECT 1
+					FROM blogs
 					WHERE id = $1::int
-				`)).
-					WithArgs([]driver.Value{1}...).
-					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
-						AddRow(1)).
-					WillReturnError(tc.mockError)
-				mock.
-					ExpectQuery(regexp.QuoteMeta(`
-                       SELECT 1
-						FROM blogs
-						WHERE id = $1::int
-                    `)).
-					WithArgs([]driver.Value{1}...).
-					WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
-						AddRow(1)).
-					WillReturnError(tc.mockError)
+                `)).
+				WithArgs([]driver.Value{1}...).
+				WillReturnRows(sqlmock.NewRows([]string{"?column?"}).
+					AddRow(1))
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+					SELECT id, version
+					FROM comments
+					WHERE user_id = $1 AND blog_id = $2 AND ($3::bigint = 0 OR id = $3::bigint) AND deleted_at IS NULL
+					ORDER BY id
+					LIMIT 1
+					FOR UPDATE`)).
+				WithArgs(tc.mockLockArgs...).
+				WillReturnRows(tc.mockLock)
 
+			if tc.mockOutput == nil {
+				mock.ExpectRollback()
+			} else {
 				mock.
-					ExpectQuery(regexp.QuoteMeta(
-						`UPDATE comments
+					ExpectQuery(regexp.QuoteMeta(`
+						WITH revision AS (
+							INSERT INTO comment_revisions (comment_id, editor_id, message)
+							SELECT id, $3, message FROM comments WHERE id = $2
+						)
+						UPDATE comments
 						SET message = $1
-						WHERE user_id = $2 AND blog_id = $3
-						RETURNING created_date`)).
+						WHERE id = $2
+						RETURNING parent_id, created_date, version`)).
 					WithArgs(tc.mockInputArgs...).
-					WillReturnRows(tc.mockOutput).
-					WillReturnError(tc.mockError)
+					WillReturnRows(tc.mockOutput)
+				mock.ExpectCommit()
 			}
 
 			commentService := NewCommentsService(logger, db)
 
-			output, err := commentService.UpdateComment(context.TODO(), tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			output, err := commentService.UpdateComment(context.TODO(), tc.input, tc.editorId)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
 			if output != tc.expectedOutput {
 				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
 			}
 
-			if tc.mockCalled {
-				if err = mock.ExpectationsWereMet(); err != nil {
-					t.Errorf("there were unfulfilled expectations: %s", err)
-				}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
 			}
 		})
 	}
 }
 
 func TestCommentsService_DeleteComment(t *testing.T) {
+	lockColumns := []string{"id", "version"}
+
 	testcases := map[string]struct {
-		mockCalled     bool
-		mockInputArgs  []driver.Value
-		mockOutput     *sqlmock.Rows
-		mockError      error
-		input          uint64
-		expectedOutput models.Comment
-		expectedError  error
+		mockLockArgs  []driver.Value
+		mockLock      *sqlmock.Rows
+		mockTarget    int
+		id            uint
+		version       uint
+		expectedError error
 	}{
 		"happy path": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{1, 1},
-			mockError:     nil,
-			input:         1,
+			mockLockArgs:  []driver.Value{1, 1, 0},
+			mockLock:      sqlmock.NewRows(lockColumns).AddRow(3, 1),
+			mockTarget:    3,
+			expectedError: nil,
+		},
+		"by id": {
+			mockLockArgs:  []driver.Value{1, 1, 4},
+			mockLock:      sqlmock.NewRows(lockColumns).AddRow(4, 1),
+			mockTarget:    4,
+			id:            4,
 			expectedError: nil,
 		},
+		"current version": {
+			mockLockArgs:  []driver.Value{1, 1, 4},
+			mockLock:      sqlmock.NewRows(lockColumns).AddRow(4, 2),
+			mockTarget:    4,
+			id:            4,
+			version:       2,
+			expectedError: nil,
+		},
+		"stale version": {
+			mockLockArgs:  []driver.Value{1, 1, 4},
+			mockLock:      sqlmock.NewRows(lockColumns).AddRow(4, 3),
+			id:            4,
+			version:       2,
+			expectedError: ErrPreconditionFailed,
+		},
+		"not found": {
+			mockLockArgs:  []driver.Value{1, 1, 4},
+			mockLock:      sqlmock.NewRows(lockColumns),
+			id:            4,
+			expectedError: ErrNotFound,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -320,27 +684,548 @@ func TestCommentsService_DeleteComment(t *testing.T) {
 
 			logger := slog.Default()
 
-			if tc.mockCalled {
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+					SELECT id, version
+					FROM comments
+					WHERE user_id = $1::int AND blog_id = $2::int AND ($3::bigint = 0 OR id = $3::bigint) AND deleted_at IS NULL
+					ORDER BY id
+					LIMIT 1
+					FOR UPDATE`)).
+				WithArgs(tc.mockLockArgs...).
+				WillReturnRows(tc.mockLock)
 
+			if tc.mockTarget == 0 {
+				mock.ExpectRollback()
+			} else {
 				mock.
-					ExpectExec(regexp.QuoteMeta(`DELETE FROM comments WHERE user_id = $1::int AND blog_id = $2::int`)).
-					WithArgs(tc.mockInputArgs...).
-					WillReturnResult(sqlmock.NewResult(1, 1)).
-					WillReturnError(tc.mockError)
+					ExpectExec(regexp.QuoteMeta(`
+						WITH RECURSIVE removed AS (
+							SELECT $1::bigint AS id
+							UNION ALL
+							SELECT c.id
+							FROM comments c
+							JOIN removed r ON c.parent_id = r.id
+							WHERE c.deleted_at IS NULL
+						)
+						UPDATE comments
+						SET deleted_at = CURRENT_TIMESTAMP
+						WHERE id IN (SELECT id FROM removed)`)).
+					WithArgs(tc.mockTarget).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
 			}
 
 			commentService := NewCommentsService(logger, db)
 
-			err = commentService.DeleteComment(context.TODO(), uint(tc.input), uint(tc.input))
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			err = commentService.DeleteComment(context.TODO(), tc.id, 1, 1, tc.version)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
 
-			if tc.mockCalled {
-				if err = mock.ExpectationsWereMet(); err != nil {
-					t.Errorf("there were unfulfilled expectations: %s", err)
-				}
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
 			}
 		})
 	}
 }
+
+func TestCommentsService_RestoreComment(t *testing.T) {
+	testcases := map[string]struct {
+		id            uint
+		mockAffected  int64
+		expectedError error
+	}{
+		"happy path": {
+			mockAffected:  2,
+			expectedError: nil,
+		},
+		"by id": {
+			id:            7,
+			mockAffected:  1,
+			expectedError: nil,
+		},
+		"not found": {
+			mockAffected:  0,
+			expectedError: ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.
+				ExpectExec(regexp.QuoteMeta(`
+					WHERE c.user_id = $1::int AND c.blog_id = $2::int AND ($3::bigint = 0 OR c.id = $3::bigint)
+					  AND c.deleted_at IS NOT NULL
+					  AND p.deleted_at IS NULL
+					ORDER BY c.id
+					LIMIT 1
+				`)).
+				WithArgs(1, 1, tc.id).
+				WillReturnResult(sqlmock.NewResult(0, tc.mockAffected))
+
+			commentService := NewCommentsService(slog.Default(), db)
+
+			err = commentService.RestoreComment(context.TODO(), tc.id, 1, 1)
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCommentsService_ListCommentThreads(t *testing.T) {
+	columns := []string{"id", "parent_id", "user_id", "blog_id", "message", "created_date", "user_name"}
+
+	testcases := map[string]struct {
+		viewerID         uint
+		allBlogs         bool
+		mockBlog         *sqlmock.Rows
+		mockRoots        *sqlmock.Rows
+		mockReplies      bool
+		mockRepliesArgs  []driver.Value
+		mockRepliesQuery string
+		mockReplyRows    *sqlmock.Rows
+		depth            int
+		page             models.PageRequest
+		expectedOutput   []models.CommentThread
+		expectedNext     bool
+		expectedError    error
+	}{
+		"happy path": {
+			mockBlog: sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockRoots: sqlmock.NewRows(columns).
+				AddRow(1, nil, 1, 1, "First", testDate, "John").
+				AddRow(4, nil, 2, 1, "Second", testDate, "Jane"),
+			mockReplies:      true,
+			mockRepliesArgs:  []driver.Value{DefaultThreadDepth, 1, 4},
+			mockRepliesQuery: `WHERE parent_id IN ($2, $3)`,
+			mockReplyRows: sqlmock.NewRows(columns).
+				AddRow(2, 1, 2, 1, "Reply", testDate, "Jane").
+				AddRow(3, 1, 1, 1, "Another reply", testDate, "John").
+				AddRow(5, 2, 1, 1, "Reply to reply", testDate, "John"),
+			expectedOutput: []models.CommentThread{
+				{
+					Comment: models.Comment{ID: 1, UserID: 1, User: models.UserRef{ID: 1, Name: "John"}, BlogID: 1, Message: "First", CreatedDate: testDate},
+					Replies: []models.CommentThread{
+						{
+							Comment: models.Comment{ID: 2, ParentID: 1, UserID: 2, User: models.UserRef{ID: 2, Name: "Jane"}, BlogID: 1, Message: "Reply", CreatedDate: testDate},
+							Replies: []models.CommentThread{
+								{
+									Comment: models.Comment{ID: 5, ParentID: 2, UserID: 1, User: models.UserRef{ID: 1, Name: "John"}, BlogID: 1, Message: "Reply to reply", CreatedDate: testDate},
+									Replies: []models.CommentThread{},
+								},
+							},
+						},
+						{
+							Comment: models.Comment{ID: 3, ParentID: 1, UserID: 1, User: models.UserRef{ID: 1, Name: "John"}, BlogID: 1, Message: "Another reply", CreatedDate: testDate},
+							Replies: []models.CommentThread{},
+						},
+					},
+				},
+				{
+					Comment: models.Comment{ID: 4, UserID: 2, User: models.UserRef{ID: 2, Name: "Jane"}, BlogID: 1, Message: "Second", CreatedDate: testDate},
+					Replies: []models.CommentThread{},
+				},
+			},
+			expectedError: nil,
+		},
+		"paged": {
+			mockBlog: sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockRoots: sqlmock.NewRows(columns).
+				AddRow(1, nil, 1, 1, "First", testDate, "John").
+				AddRow(4, nil, 2, 1, "Second", testDate, "Jane"),
+			mockReplies:      true,
+			mockRepliesArgs:  []driver.Value{MaxThreadDepth, 1},
+			mockRepliesQuery: `WHERE parent_id IN ($2)`,
+			mockReplyRows:    sqlmock.NewRows(columns),
+			depth:            MaxThreadDepth + 1,
+			page:             models.PageRequest{Limit: 1},
+			expectedOutput: []models.CommentThread{
+				{
+					Comment: models.Comment{ID: 1, UserID: 1, User: models.UserRef{ID: 1, Name: "John"}, BlogID: 1, Message: "First", CreatedDate: testDate},
+					Replies: []models.CommentThread{},
+				},
+			},
+			expectedNext:  true,
+			expectedError: nil,
+		},
+		"top level only": {
+			mockBlog: sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockRoots: sqlmock.NewRows(columns).
+				AddRow(1, nil, 1, 1, "First", testDate, "John"),
+			depth: 1,
+			expectedOutput: []models.CommentThread{
+				{
+					Comment: models.Comment{ID: 1, UserID: 1, User: models.UserRef{ID: 1, Name: "John"}, BlogID: 1, Message: "First", CreatedDate: testDate},
+					Replies: []models.CommentThread{},
+				},
+			},
+			expectedError: nil,
+		},
+		"no comments": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockRoots:      sqlmock.NewRows(columns),
+			expectedOutput: []models.CommentThread{},
+			expectedError:  nil,
+		},
+		"admin": {
+			viewerID:       3,
+			allBlogs:       true,
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}).AddRow(1),
+			mockRoots:      sqlmock.NewRows(columns),
+			expectedOutput: []models.CommentThread{},
+			expectedError:  nil,
+		},
+		"blog not found": {
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}),
+			expectedOutput: nil,
+			expectedError:  ErrNotFound,
+		},
+		"someone else's draft": {
+			viewerID:       2,
+			mockBlog:       sqlmock.NewRows([]string{"?column?"}),
+			expectedOutput: nil,
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+					SELECT 1
+					FROM blogs
+					WHERE id = $1::int AND deleted_at IS NULL AND ($2 OR status = 'published' OR author_id = $3::bigint)
+				`)).
+				WithArgs(1, tc.allBlogs, tc.viewerID).
+				WillReturnRows(tc.mockBlog)
+
+			if tc.mockRoots != nil {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT id, parent_id, user_id, blog_id, message, created_date, user_name
+						FROM comments
+						JOIN (SELECT id AS user_key, name AS user_name FROM users) commenter ON commenter.user_key = comments.user_id
+						WHERE blog_id = $1 AND parent_id IS NULL AND deleted_at IS NULL ORDER BY id ASC`)).
+					WithArgs(1).
+					WillReturnRows(tc.mockRoots)
+			}
+
+			if tc.mockReplies {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(tc.mockRepliesQuery)).
+					WithArgs(tc.mockRepliesArgs...).
+					WillReturnRows(tc.mockReplyRows)
+			}
+
+			commentService := NewCommentsService(logger, db)
+
+			output, err := commentService.ListCommentThreads(context.TODO(), 1, tc.viewerID, tc.allBlogs, tc.depth, tc.page)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			assert.Equal(t, tc.expectedOutput, output.Items)
+			if (output.NextCursor != "") != tc.expectedNext {
+				t.Errorf("expected next page %t, got cursor %q", tc.expectedNext, output.NextCursor)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCommentsService_ListCommentRevisions(t *testing.T) {
+	columns := []string{"id", "comment_id", "editor_id", "message", "created_date", "next_message"}
+
+	testcases := map[string]struct {
+		viewerID       uint
+		allBlogs       bool
+		mockComment    *sqlmock.Rows
+		mockRevisions  *sqlmock.Rows
+		expectedOutput []models.CommentRevision
+		expectedError  error
+	}{
+		"happy path": {
+			mockComment: sqlmock.NewRows([]string{"user_id", "blog_id", "message"}).
+				AddRow(1, 2, "Great blog"),
+			mockRevisions: sqlmock.NewRows(columns).
+				AddRow(2, 3, 1, "Good blog", testDate, nil).
+				AddRow(1, 3, nil, "Nice", testDate, "Good blog"),
+			expectedOutput: []models.CommentRevision{
+				{ID: 2, CommentID: 3, UserID: 1, BlogID: 2, EditorID: 1, Message: "Good blog", Diff: "-Good blog\n+Great blog\n", CreatedDate: testDate},
+				{ID: 1, CommentID: 3, UserID: 1, BlogID: 2, Message: "Nice", Diff: "-Nice\n+Good blog\n", CreatedDate: testDate},
+			},
+			expectedError: nil,
+		},
+		"never updated": {
+			mockComment: sqlmock.NewRows([]string{"user_id", "blog_id", "message"}).
+				AddRow(1, 2, "Great blog"),
+			mockRevisions:  sqlmock.NewRows(columns),
+			expectedOutput: []models.CommentRevision{},
+			expectedError:  nil,
+		},
+		"admin": {
+			viewerID: 3,
+			allBlogs: true,
+			mockComment: sqlmock.NewRows([]string{"user_id", "blog_id", "message"}).
+				AddRow(1, 2, "Great blog"),
+			mockRevisions:  sqlmock.NewRows(columns),
+			expectedOutput: []models.CommentRevision{},
+			expectedError:  nil,
+		},
+		"comment on a draft": {
+			mockComment:    sqlmock.NewRows([]string{"user_id", "blog_id", "message"}),
+			expectedOutput: nil,
+			expectedError:  ErrNotFound,
+		},
+		"comment not found": {
+			mockComment:    sqlmock.NewRows([]string{"user_id", "blog_id", "message"}),
+			expectedOutput: nil,
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT c.user_id, c.blog_id, c.message
+					FROM comments c
+					JOIN blogs b ON b.id = c.blog_id
+					WHERE c.id = $1 AND c.deleted_at IS NULL AND b.deleted_at IS NULL
+					AND ($2 OR b.status = 'published' OR b.author_id = $3::bigint)`)).
+				WithArgs(3, tc.allBlogs, tc.viewerID).
+				WillReturnRows(tc.mockComment)
+
+			if tc.mockRevisions != nil {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT id, comment_id, editor_id, message, created_date, next_message FROM (
+						SELECT r.*, LEAD(message) OVER (PARTITION BY comment_id ORDER BY id) AS next_message
+						FROM comment_revisions r
+					) revisions WHERE comment_id = $1 ORDER BY id DESC`)).
+					WithArgs(3).
+					WillReturnRows(tc.mockRevisions)
+			}
+
+			commentService := NewCommentsService(slog.Default(), db)
+
+			output, err := commentService.ListCommentRevisions(context.TODO(), 3, tc.viewerID, tc.allBlogs, models.PageRequest{})
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			assert.Equal(t, tc.expectedOutput, output.Items)
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCommentsService_RestoreCommentRevision(t *testing.T) {
+	lockQuery := `SELECT c.id
+		FROM comment_revisions r
+		JOIN comments c ON c.id = r.comment_id
+		WHERE r.id = $1 AND c.deleted_at IS NULL
+		FOR UPDATE OF c`
+	restoreQuery := `WITH target AS (
+			SELECT c.id, c.message AS previous, r.message AS restored
+			FROM comment_revisions r
+			JOIN comments c ON c.id = r.comment_id
+			WHERE r.id = $1 AND ($3::bigint = 0 OR c.version = $3::bigint)
+		), revision AS (
+			INSERT INTO comment_revisions (comment_id, editor_id, message)
+			SELECT id, $2, previous FROM target
+		)
+		UPDATE comments
+		SET message = target.restored, version = comments.version + 1
+		FROM target
+		WHERE comments.id = target.id`
+	columns := []string{"id", "parent_id", "user_id", "blog_id", "message", "created_date", "version"}
+
+	testcases := map[string]struct {
+		version        uint
+		mockLock       *sqlmock.Rows
+		mockOutput     *sqlmock.Rows
+		expectedOutput models.Comment
+		expectedError  error
+	}{
+		"happy path": {
+			mockLock: sqlmock.NewRows([]string{"id"}).AddRow(3),
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(3, nil, 1, 2, "Nice", testDate, 2),
+			expectedOutput: models.Comment{ID: 3, UserID: 1, BlogID: 2, Message: "Nice", CreatedDate: testDate, Version: 2},
+			expectedError:  nil,
+		},
+		"matching version": {
+			version:  1,
+			mockLock: sqlmock.NewRows([]string{"id"}).AddRow(3),
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(3, nil, 1, 2, "Nice", testDate, 2),
+			expectedOutput: models.Comment{ID: 3, UserID: 1, BlogID: 2, Message: "Nice", CreatedDate: testDate, Version: 2},
+			expectedError:  nil,
+		},
+		"stale version": {
+			version:        1,
+			mockLock:       sqlmock.NewRows([]string{"id"}).AddRow(3),
+			mockOutput:     sqlmock.NewRows(columns),
+			expectedOutput: models.Comment{},
+			expectedError:  ErrPreconditionFailed,
+		},
+		"revision not found": {
+			mockLock:       sqlmock.NewRows([]string{"id"}),
+			expectedOutput: models.Comment{},
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(lockQuery)).
+				WithArgs(1).
+				WillReturnRows(tc.mockLock)
+			if tc.mockOutput != nil {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(restoreQuery)).
+					WithArgs(1, 4, tc.version).
+					WillReturnRows(tc.mockOutput)
+			}
+			if tc.expectedError == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			commentService := NewCommentsService(slog.Default(), db)
+
+			output, err := commentService.RestoreCommentRevision(context.TODO(), 1, 4, tc.version)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			assert.Equal(t, tc.expectedOutput, output)
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestCommentsService_CreateComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM users WHERE id IN ($1) AND deleted_at IS NULL`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM blogs WHERE id IN ($1, $2, $3) AND deleted_at IS NULL AND ($4 OR status = 'published' OR author_id = $5::bigint)`)).
+		WithArgs(1, 2, 3, false, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id, blog_id FROM comments WHERE id IN ($1, $2) AND deleted_at IS NULL`)).
+		WithArgs(4, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "blog_id"}).AddRow(4, 1).AddRow(5, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`INSERT INTO comments (user_id, blog_id, parent_id, message) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8) RETURNING id, created_date`)).
+		WithArgs(1, 1, nil, "First", 1, 1, 4, "Reply").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_date"}).AddRow(6, testDate).AddRow(7, testDate))
+	mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	commentService := NewCommentsService(slog.Default(), db)
+
+	results, err := commentService.CreateComments(context.TODO(), []models.Comment{
+		{UserID: 1, BlogID: 1, Message: "First"},
+		{UserID: 1, BlogID: 1, ParentID: 4, Message: "Reply"},
+		{UserID: 1, BlogID: 2, ParentID: 5, Message: "Elsewhere"},
+		{UserID: 1, BlogID: 3, Message: "Draft"},
+	}, 1, false, models.BulkBestEffort)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, uint(6), results[0].Value.ID)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, uint(7), results[1].Value.ID)
+	assert.ErrorIs(t, results[2].Err, ErrInvalidReference)
+	assert.ErrorIs(t, results[3].Err, ErrInvalidReference)
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCommentsService_ImportComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM users WHERE id IN ($1, $2) AND deleted_at IS NULL`)).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id FROM blogs WHERE id IN ($1) AND deleted_at IS NULL AND ($2 OR status = 'published' OR author_id = $3::bigint)`)).
+		WithArgs(7, true, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`INSERT INTO comments (user_id, blog_id, parent_id, message, created_date) VALUES ($1, $2, $3, $4, $5) RETURNING id`)).
+		WithArgs(1, 7, nil, "First", testDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+	mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	commentService := NewCommentsService(slog.Default(), db)
+
+	results, err := commentService.ImportComments(context.TODO(), []models.Comment{
+		{ID: 3, UserID: 1, BlogID: 7, Message: "First", CreatedDate: testDate},
+		{ID: 4, UserID: 2, BlogID: 7, Message: "Second", CreatedDate: testDate},
+	}, false)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, uint(12), results[0].Value.ID)
+	assert.Equal(t, testDate, results[0].Value.CreatedDate)
+	assert.ErrorIs(t, results[1].Err, ErrInvalidReference)
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+// Users, blogs and comments are soft deleted: deleting one sets its
+// deleted_at, and reads leave it out until it is restored or purged. Rows
+// deleted together share the same deleted_at, so they can be restored together.
+
+// deletedColumns returns the columns to select after the others, and the
+// condition to add to the WHERE clause, when reading a single row of a soft
+// deleted table. Deleted rows are left out unless includeDeleted is set, in
+// which case deleted_at is read instead.
+func deletedColumns(includeDeleted bool) (columns string, condition string) {
+	if includeDeleted {
+		return ", deleted_at", ""
+	}
+
+	return "", " AND deleted_at IS NULL"
+}
+
+// withDeleted leaves the soft deleted rows out of q unless includeDeleted is
+// set, in which case deleted_at is selected after the other columns instead.
+func (q *listQuery) withDeleted(includeDeleted bool) {
+	if includeDeleted {
+		q.columns += ", deleted_at"
+		return
+	}
+
+	q.where("deleted_at IS NULL")
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	// ErrNotFound is returned when the requested resource does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when a write would duplicate a unique value,
+	// such as an existing email address.
+	ErrConflict = errors.New("conflict")
+
+	// ErrInvalidReference is returned when a write refers to a row that does
+	// not exist, such as a blog whose author has been deleted.
+	ErrInvalidReference = errors.New("invalid reference")
+
+	// ErrConstraintViolation is returned when a write is rejected by a check
+	// or not-null constraint.
+	ErrConstraintViolation = errors.New("constraint violation")
+
+	// ErrInvalidCredentials is returned when an email and password pair does
+	// not match a user. It deliberately does not say which half was wrong.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrInvalidToken is returned when an access or refresh token is
+	// malformed, expired, of the wrong kind or belongs to a revoked session.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrInvalidCursor is returned when a pagination cursor is malformed or
+	// was issued for a list in a different order.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrInvalidSort is returned when a list is asked to be sorted by a field
+	// that it can't be sorted by.
+	ErrInvalidSort = errors.New("invalid sort")
+
+	// ErrInvalidTransition is returned when a blog is asked to move to a
+	// status its current status can't move to, such as scheduling a blog
+	// that has already been published.
+	ErrInvalidTransition = errors.New("invalid status transition")
+
+	// ErrPreconditionFailed is returned when a write is made on condition
+	// that a row is still at a version it has since moved on from.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrBulkAborted is returned for an item of an all or nothing bulk write
+	// that was not written because another item failed.
+	ErrBulkAborted = errors.New("bulk write aborted")
+)
+
+// Postgres error codes for the constraint violations we translate. See
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgNotNullViolation    = "23502"
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+)
+
+// ConstraintError is returned when the database rejects a write because of a
+// constraint. It matches one of ErrConflict, ErrInvalidReference or
+// ErrConstraintViolation with errors.Is, and Constraint names the constraint
+// that failed.
+type ConstraintError struct {
+	Constraint string
+	kind       error
+	err        error
+}
+
+// Error implements the error interface.
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%s: %s", e.kind, e.Constraint)
+}
+
+// Unwrap allows errors.Is and errors.As to match both the sentinel kind and
+// the underlying driver error.
+func (e *ConstraintError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// translateError converts Postgres constraint violations reported by pgx into
+// a *ConstraintError. Any other error is returned unchanged.
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var kind error
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		kind = ErrConflict
+	case pgForeignKeyViolation:
+		kind = ErrInvalidReference
+	case pgCheckViolation, pgNotNullViolation:
+		kind = ErrConstraintViolation
+	default:
+		return err
+	}
+
+	return &ConstraintError{
+		Constraint: pgErr.ConstraintName,
+		kind:       kind,
+		err:        err,
+	}
+}
+
+// expectAffected returns ErrNotFound if the statement that produced result did
+// not touch any rows.
+func expectAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// checkVersion returns ErrPreconditionFailed if want is set and is not the
+// current version of a row. A zero want makes the write unconditional.
+func checkVersion(current uint, want uint) error {
+	if want != 0 && want != current {
+		return ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+// replaceNoRows returns kind in place of sql.ErrNoRows so that callers can
+// tell a missing row apart from a failed query. Other errors are returned
+// unchanged.
+func replaceNoRows(err error, kind error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return kind
+	}
+
+	return err
+}
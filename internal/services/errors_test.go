@@ -0,0 +1,76 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestTranslateError(t *testing.T) {
+	testcases := map[string]struct {
+		input              error
+		expectedError      error
+		expectedConstraint string
+	}{
+		"unique violation": {
+			input:              &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_email_key"},
+			expectedError:      ErrConflict,
+			expectedConstraint: "users_email_key",
+		},
+		"foreign key violation": {
+			input:              &pgconn.PgError{Code: pgForeignKeyViolation, ConstraintName: "blogs_author_id_fkey"},
+			expectedError:      ErrInvalidReference,
+			expectedConstraint: "blogs_author_id_fkey",
+		},
+		"check violation": {
+			input:              &pgconn.PgError{Code: pgCheckViolation, ConstraintName: "blogs_score_check"},
+			expectedError:      ErrConstraintViolation,
+			expectedConstraint: "blogs_score_check",
+		},
+		"wrapped driver error": {
+			input:              fmt.Errorf("insert: %w", &pgconn.PgError{Code: pgNotNullViolation}),
+			expectedError:      ErrConstraintViolation,
+			expectedConstraint: "",
+		},
+		"other postgres error": {
+			input:         &pgconn.PgError{Code: "42P01"},
+			expectedError: nil,
+		},
+		"non postgres error": {
+			input:         sql.ErrConnDone,
+			expectedError: nil,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			err := translateError(tc.input)
+
+			if tc.expectedError == nil {
+				if err != tc.input {
+					t.Errorf("expected %v to be returned unchanged, got %v", tc.input, err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+
+			var constraintErr *ConstraintError
+			if !errors.As(err, &constraintErr) {
+				t.Fatalf("expected a *ConstraintError, got %T", err)
+			}
+			if constraintErr.Constraint != tc.expectedConstraint {
+				t.Errorf("expected constraint %q, got %q", tc.expectedConstraint, constraintErr.Constraint)
+			}
+
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) {
+				t.Errorf("expected the driver error to remain in the chain")
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// IdempotencyService is a service capable of storing the idempotency keys
+// clients send with their requests, along with the responses to replay when
+// the requests are retried.
+type IdempotencyService struct {
+	logger *slog.Logger
+	db     *sql.DB
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewIdempotencyService creates a new IdempotencyService and returns a pointer
+// to it. Keys expire ttl after they were first used, after which they can be
+// used again.
+func NewIdempotencyService(logger *slog.Logger, db *sql.DB, ttl time.Duration) (*IdempotencyService, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("[in services.NewIdempotencyService] key lifetime must be positive")
+	}
+
+	return &IdempotencyService{
+		logger: logger,
+		db:     db,
+		ttl:    ttl,
+		now:    time.Now,
+	}, nil
+}
+
+// Reserve attempts to claim key within scope for the request with
+// fingerprint. If the key is new, or the key it replaces has expired, it is
+// reserved for the request and true is returned. Otherwise the key that is
+// already stored is returned with false, whether or not its fingerprint
+// matches, and without a response if its request is still being handled.
+func (s *IdempotencyService) Reserve(ctx context.Context, scope string, key string, fingerprint string) (models.IdempotencyKey, bool, error) {
+	s.logger.DebugContext(ctx, "Reserving idempotency key", "scope", scope, "key", key)
+
+	now := s.now().UTC()
+	reserved := models.IdempotencyKey{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+	}
+
+	// An expired key is taken over as if it had never been used
+	err := s.db.QueryRowContext(
+		ctx,
+		`
+		INSERT INTO idempotency_keys (scope, key, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+		    status_code = NULL,
+		    header = NULL,
+		    body = NULL,
+		    created_at = EXCLUDED.created_at,
+		    expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		RETURNING created_at, expires_at
+		`,
+		scope,
+		key,
+		fingerprint,
+		now,
+		now.Add(s.ttl),
+	).Scan(&reserved.CreatedAt, &reserved.ExpiresAt)
+	if err == nil {
+		return reserved, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.IdempotencyKey{}, false, fmt.Errorf(
+			"[in services.IdempotencyService.Reserve] failed to reserve key: %w",
+			err,
+		)
+	}
+
+	existing := models.IdempotencyKey{Scope: scope, Key: key}
+	var statusCode sql.NullInt32
+	var header []byte
+
+	err = s.db.QueryRowContext(
+		ctx,
+		`
+		SELECT fingerprint, status_code, header, body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+		`,
+		scope,
+		key,
+	).Scan(
+		&existing.Fingerprint,
+		&statusCode,
+		&header,
+		&existing.Body,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		return models.IdempotencyKey{}, false, fmt.Errorf(
+			"[in services.IdempotencyService.Reserve] key %q: %w",
+			key,
+			replaceNoRows(err, ErrNotFound),
+		)
+	}
+	existing.StatusCode = int(statusCode.Int32)
+
+	if header != nil {
+		if err = json.Unmarshal(header, &existing.Header); err != nil {
+			return models.IdempotencyKey{}, false, fmt.Errorf(
+				"[in services.IdempotencyService.Reserve] failed to decode header of key %q: %w",
+				key,
+				err,
+			)
+		}
+	}
+
+	return existing, false, nil
+}
+
+// Complete stores the response to the request that reserved key, so that it
+// is replayed when the request is retried. ErrNotFound is returned if the key
+// is not reserved.
+func (s *IdempotencyService) Complete(ctx context.Context, key models.IdempotencyKey) error {
+	s.logger.DebugContext(ctx, "Completing idempotency key", "scope", key.Scope, "key", key.Key)
+
+	header, err := json.Marshal(key.Header)
+	if err != nil {
+		return fmt.Errorf(
+			"[in services.IdempotencyService.Complete] failed to encode header: %w",
+			err,
+		)
+	}
+
+	result, err := s.db.ExecContext(
+		ctx,
+		`
+		UPDATE idempotency_keys
+		SET status_code = $1, header = $2, body = $3
+		WHERE scope = $4 AND key = $5 AND status_code IS NULL
+		`,
+		key.StatusCode,
+		header,
+		key.Body,
+		key.Scope,
+		key.Key,
+	)
+	if err == nil {
+		err = expectAffected(result)
+	}
+	if err != nil {
+		return fmt.Errorf(
+			"[in services.IdempotencyService.Complete] key %q: %w",
+			key.Key,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Release gives up the reservation of key within scope by a request that
+// produced no response worth replaying, so that it can be retried. Keys that
+// hold a response are kept.
+func (s *IdempotencyService) Release(ctx context.Context, scope string, key string) error {
+	s.logger.DebugContext(ctx, "Releasing idempotency key", "scope", scope, "key", key)
+
+	_, err := s.db.ExecContext(
+		ctx,
+		`
+		DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND status_code IS NULL
+		`,
+		scope,
+		key,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"[in services.IdempotencyService.Release] key %q: %w",
+			key,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// PurgeExpired deletes the keys that expired before the provided time,
+// returning the number of keys deleted.
+func (s *IdempotencyService) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	s.logger.DebugContext(ctx, "Purging expired idempotency keys", "before", before)
+
+	result, err := s.db.ExecContext(
+		ctx,
+		`
+		DELETE FROM idempotency_keys WHERE expires_at < $1
+		`,
+		before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[in services.IdempotencyService.PurgeExpired] failed to purge keys: %w",
+			err,
+		)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[in services.IdempotencyService.PurgeExpired] failed to count purged keys: %w",
+			err,
+		)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestIdempotencyService_Reserve(t *testing.T) {
+	columns := []string{"fingerprint", "status_code", "header", "body", "created_at", "expires_at"}
+	expiresAt := testDate.Add(24 * time.Hour)
+
+	testcases := map[string]struct {
+		mockReserved   bool
+		mockExisting   *sqlmock.Rows
+		expectedOutput models.IdempotencyKey
+		expectedOk     bool
+		expectedError  error
+	}{
+		"new key": {
+			mockReserved: true,
+			expectedOutput: models.IdempotencyKey{
+				Scope:       "user:1",
+				Key:         "abc",
+				Fingerprint: "f1",
+				CreatedAt:   testDate,
+				ExpiresAt:   expiresAt,
+			},
+			expectedOk:    true,
+			expectedError: nil,
+		},
+		"completed key": {
+			mockExisting: sqlmock.NewRows(columns).
+				AddRow("f1", 200, []byte(`{"Content-Type":["application/json"]}`), []byte(`{"ID":1}`), testDate, expiresAt),
+			expectedOutput: models.IdempotencyKey{
+				Scope:       "user:1",
+				Key:         "abc",
+				Fingerprint: "f1",
+				StatusCode:  200,
+				Header:      map[string][]string{"Content-Type": {"application/json"}},
+				Body:        []byte(`{"ID":1}`),
+				CreatedAt:   testDate,
+				ExpiresAt:   expiresAt,
+			},
+			expectedOk:    false,
+			expectedError: nil,
+		},
+		"key in flight": {
+			mockExisting: sqlmock.NewRows(columns).
+				AddRow("f1", nil, nil, nil, testDate, expiresAt),
+			expectedOutput: models.IdempotencyKey{
+				Scope:       "user:1",
+				Key:         "abc",
+				Fingerprint: "f1",
+				CreatedAt:   testDate,
+				ExpiresAt:   expiresAt,
+			},
+			expectedOk:    false,
+			expectedError: nil,
+		},
+		"key purged in between": {
+			mockExisting:   sqlmock.NewRows(columns),
+			expectedOutput: models.IdempotencyKey{},
+			expectedOk:     false,
+			expectedError:  ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			reserved := sqlmock.NewRows([]string{"created_at", "expires_at"})
+			if tc.mockReserved {
+				reserved.AddRow(testDate, expiresAt)
+			}
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`INSERT INTO idempotency_keys (scope, key, fingerprint, created_at, expires_at)`)).
+				WithArgs("user:1", "abc", "f1", testDate, expiresAt).
+				WillReturnRows(reserved)
+			if tc.mockExisting != nil {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT fingerprint, status_code, header, body, created_at, expires_at FROM idempotency_keys WHERE scope = $1 AND key = $2`)).
+					WithArgs("user:1", "abc").
+					WillReturnRows(tc.mockExisting)
+			}
+
+			idempotencyService, err := NewIdempotencyService(logger, db, 24*time.Hour)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			idempotencyService.now = func() time.Time { return testDate }
+
+			output, ok, err := idempotencyService.Reserve(context.TODO(), "user:1", "abc", "f1")
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if ok != tc.expectedOk {
+				t.Errorf("expected reserved %v, got %v", tc.expectedOk, ok)
+			}
+			if !reflect.DeepEqual(output, tc.expectedOutput) {
+				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_Complete(t *testing.T) {
+	testcases := map[string]struct {
+		mockAffected  int64
+		expectedError error
+	}{
+		"happy path": {
+			mockAffected:  1,
+			expectedError: nil,
+		},
+		"not reserved": {
+			mockAffected:  0,
+			expectedError: ErrNotFound,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.
+				ExpectExec(regexp.QuoteMeta(`UPDATE idempotency_keys SET status_code = $1, header = $2, body = $3 WHERE scope = $4 AND key = $5 AND status_code IS NULL`)).
+				WithArgs(201, []byte(`{"Location":["/api/blog/1"]}`), []byte(`1`), "user:1", "abc").
+				WillReturnResult(sqlmock.NewResult(0, tc.mockAffected))
+
+			idempotencyService, err := NewIdempotencyService(logger, db, time.Hour)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+
+			err = idempotencyService.Complete(context.TODO(), models.IdempotencyKey{
+				Scope:      "user:1",
+				Key:        "abc",
+				StatusCode: 201,
+				Header:     map[string][]string{"Location": {"/api/blog/1"}},
+				Body:       []byte(`1`),
+			})
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_PurgeExpired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.
+		ExpectExec(regexp.QuoteMeta(`DELETE FROM idempotency_keys WHERE expires_at < $1`)).
+		WithArgs(testDate).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	idempotencyService, err := NewIdempotencyService(slog.Default(), db, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+
+	purged, err := idempotencyService.PurgeExpired(context.TODO(), testDate)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if purged != 3 {
+		t.Errorf("expected 3 keys purged, got %d", purged)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
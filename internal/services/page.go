@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+const (
+	// DefaultPageLimit is the number of items returned when a page request
+	// does not set a limit.
+	DefaultPageLimit = 20
+
+	// MaxPageLimit is the largest number of items returned in one page.
+	MaxPageLimit = 100
+)
+
+// sortColumn is a column a list is ordered by. Cursor values are bound as
+// text and cast to the Postgres type cast before they are compared against
+// the column.
+type sortColumn struct {
+	column string
+	cast   string
+	desc   bool
+}
+
+// pageCursor is the decoded form of the opaque cursors handed to clients. It
+// holds the sort key of the item the page starts after, or before, as text.
+type pageCursor struct {
+	Before bool     `json:"b,omitempty"`
+	Sort   string   `json:"s"`
+	Keys   []string `json:"k"`
+}
+
+// listQuery builds a filtered, ordered and paged SELECT. The sort columns
+// must identify a row uniquely, so that the order is stable across pages.
+type listQuery struct {
+	columns    string
+	from       string
+	conditions []string
+	args       []any
+	sort       []sortColumn
+}
+
+// where adds a condition to the query. condition holds a %d verb for the
+// placeholder of each of args, such as "name = $%d".
+func (q *listQuery) where(condition string, args ...any) {
+	numbers := make([]any, len(args))
+	for i := range args {
+		numbers[i] = len(q.args) + i + 1
+	}
+
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, numbers...))
+	q.args = append(q.args, args...)
+}
+
+// sortKey describes the order of the query, such as "-score,id". It is stored
+// in cursors so that they can't be used with a different order.
+func (q *listQuery) sortKey() string {
+	columns := make([]string, len(q.sort))
+	for i, s := range q.sort {
+		columns[i] = s.column
+		if s.desc {
+			columns[i] = "-" + s.column
+		}
+	}
+
+	return strings.Join(columns, ",")
+}
+
+// whereClause returns the WHERE clause for conditions with a leading space, or
+// an empty string if there are none.
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// countSQL returns a query counting every row matching the conditions.
+func (q *listQuery) countSQL() (string, []any) {
+	return fmt.Sprintf("SELECT COUNT(*) FROM %s%s", q.from, whereClause(q.conditions)), q.args
+}
+
+// pageSQL returns a query for the requested page. It selects one row more
+// than the limit so callers can tell whether there is a further page. before
+// reports whether the rows are returned in reverse order, which is the case
+// when paging backwards from a cursor.
+func (q *listQuery) pageSQL(page models.PageRequest) (query string, args []any, before bool, err error) {
+	conditions := slices.Clone(q.conditions)
+	args = slices.Clone(q.args)
+
+	if page.Cursor != "" {
+		cursor, err := q.decodeCursor(page.Cursor)
+		if err != nil {
+			return "", nil, false, err
+		}
+
+		before = cursor.Before
+		conditions = append(conditions, q.keysetCondition(cursor, len(args)))
+		for _, key := range cursor.Keys {
+			args = append(args, key)
+		}
+	}
+
+	order := make([]string, len(q.sort))
+	for i, s := range q.sort {
+		direction := "ASC"
+		if s.desc != before {
+			direction = "DESC"
+		}
+		order[i] = s.column + " " + direction
+	}
+
+	query = fmt.Sprintf(
+		"SELECT %s FROM %s%s ORDER BY %s LIMIT %d",
+		q.columns,
+		q.from,
+		whereClause(conditions),
+		strings.Join(order, ", "),
+		pageLimit(page)+1,
+	)
+	if page.Cursor == "" && page.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", page.Offset)
+	}
+
+	return query, args, before, nil
+}
+
+// keysetCondition returns a condition matching the rows after, or before,
+// the cursor in the query order. Placeholders are numbered after the offset
+// arguments already bound.
+//
+// For a sort of a ASC, b DESC the rows after (x, y) are
+// (a > x) OR (a = x AND b < y).
+func (q *listQuery) keysetCondition(cursor pageCursor, offset int) string {
+	alternatives := make([]string, len(q.sort))
+	for i, s := range q.sort {
+		terms := make([]string, 0, i+1)
+		for j, prev := range q.sort[:i] {
+			terms = append(terms, fmt.Sprintf("%s = $%d::text::%s", prev.column, offset+j+1, prev.cast))
+		}
+
+		operator := ">"
+		if s.desc != cursor.Before {
+			operator = "<"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s $%d::text::%s", s.column, operator, offset+i+1, s.cast))
+
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// encodeCursor returns an opaque cursor for the item with the provided sort
+// key.
+func (q *listQuery) encodeCursor(keys []string, before bool) string {
+	b, _ := json.Marshal(pageCursor{
+		Before: before,
+		Sort:   q.sortKey(),
+		Keys:   keys,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor parses an opaque cursor, returning ErrInvalidCursor if it is
+// malformed or was issued for a different order.
+func (q *listQuery) decodeCursor(s string) (pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return pageCursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	var cursor pageCursor
+	if err = json.Unmarshal(b, &cursor); err != nil {
+		return pageCursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if cursor.Sort != q.sortKey() || len(cursor.Keys) != len(q.sort) {
+		return pageCursor{}, fmt.Errorf("%w: issued for order %q", ErrInvalidCursor, cursor.Sort)
+	}
+
+	return cursor, nil
+}
+
+// sortColumns translates a requested order into sort columns using the
+// allowed columns, keyed by field name. The unique column is appended as a
+// tiebreaker unless the order already includes it. ErrInvalidSort is
+// returned for fields that are not allowed or are repeated.
+func sortColumns(fields []models.SortField, allowed map[string]sortColumn, unique sortColumn) ([]sortColumn, error) {
+	columns := make([]sortColumn, 0, len(fields)+1)
+	seen := make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+		column, ok := allowed[field.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSort, field.Field)
+		}
+		if seen[column.column] {
+			return nil, fmt.Errorf("%w: field %q is repeated", ErrInvalidSort, field.Field)
+		}
+		seen[column.column] = true
+
+		column.desc = field.Desc
+		columns = append(columns, column)
+	}
+
+	if !seen[unique.column] {
+		columns = append(columns, unique)
+	}
+
+	return columns, nil
+}
+
+// pageLimit returns the number of items to return for page, applying the
+// default and maximum limits.
+func pageLimit(page models.PageRequest) int {
+	switch {
+	case page.Limit <= 0:
+		return DefaultPageLimit
+	case page.Limit > MaxPageLimit:
+		return MaxPageLimit
+	default:
+		return page.Limit
+	}
+}
+
+// listPage runs q and returns the requested page. scan reads an item from the
+// current row, and key returns the values of the sort columns of an item.
+func listPage[T any](
+	ctx context.Context,
+	db *sql.DB,
+	q listQuery,
+	page models.PageRequest,
+	scan func(*sql.Rows) (T, error),
+	key func(T) []any,
+) (models.Page[T], error) {
+	query, args, before, err := q.pageSQL(page)
+	if err != nil {
+		return models.Page[T]{}, err
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return models.Page[T]{}, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	items := []T{}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return models.Page[T]{}, fmt.Errorf("failed to scan: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return models.Page[T]{}, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	limit := pageLimit(page)
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+
+	// When paging backwards the rows come in reverse, and there is always a
+	// next page: the one the cursor was taken from.
+	hasNext, hasPrev := more, page.Cursor != "" || page.Offset > 0
+	if before {
+		slices.Reverse(items)
+		hasNext, hasPrev = true, more
+	}
+
+	result := models.Page[T]{Items: items}
+	if len(items) > 0 {
+		if hasNext {
+			result.NextCursor = q.encodeCursor(cursorKeys(key(items[len(items)-1])), false)
+		}
+		if hasPrev {
+			result.PrevCursor = q.encodeCursor(cursorKeys(key(items[0])), true)
+		}
+	}
+
+	if page.IncludeTotal {
+		query, args := q.countSQL()
+
+		var total int
+		if err = db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+			return models.Page[T]{}, fmt.Errorf("failed to count: %w", err)
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// cursorKeys formats sort key values as text that Postgres casts back to the
+// column types losslessly.
+func cursorKeys(values []any) []string {
+	keys := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case time.Time:
+			keys[i] = v.Format(time.RFC3339Nano)
+		default:
+			keys[i] = fmt.Sprint(v)
+		}
+	}
+
+	return keys
+}
+
+// likeEscaper escapes the LIKE wildcards and escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes s so that it matches literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// testCursor returns a cursor for a list ordered by id, positioned at id.
+func testCursor(id string, before bool) string {
+	q := listQuery{sort: []sortColumn{{column: "id", cast: "bigint"}}}
+	return q.encodeCursor([]string{id}, before)
+}
+
+func TestListQuery_PageSQL(t *testing.T) {
+	q := listQuery{
+		columns: "id, title",
+		from:    "blogs",
+		sort: []sortColumn{
+			{column: "score", cast: "real", desc: true},
+			{column: "id", cast: "bigint"},
+		},
+	}
+	q.where("author_id = $%d", 1)
+
+	testcases := map[string]struct {
+		input          models.PageRequest
+		expectedQuery  string
+		expectedArgs   []any
+		expectedBefore bool
+		expectedError  error
+	}{
+		"first page": {
+			input:         models.PageRequest{Limit: 10},
+			expectedQuery: "SELECT id, title FROM blogs WHERE author_id = $1 ORDER BY score DESC, id ASC LIMIT 11",
+			expectedArgs:  []any{1},
+		},
+		"offset": {
+			input:         models.PageRequest{Offset: 40},
+			expectedQuery: "SELECT id, title FROM blogs WHERE author_id = $1 ORDER BY score DESC, id ASC LIMIT 21 OFFSET 40",
+			expectedArgs:  []any{1},
+		},
+		"limit above maximum": {
+			input:         models.PageRequest{Limit: 1000},
+			expectedQuery: "SELECT id, title FROM blogs WHERE author_id = $1 ORDER BY score DESC, id ASC LIMIT 101",
+			expectedArgs:  []any{1},
+		},
+		"after cursor": {
+			input: models.PageRequest{
+				Limit:  10,
+				Cursor: q.encodeCursor([]string{"8.2", "7"}, false),
+			},
+			expectedQuery: "SELECT id, title FROM blogs WHERE author_id = $1 AND " +
+				"((score < $2::text::real) OR (score = $2::text::real AND id > $3::text::bigint)) " +
+				"ORDER BY score DESC, id ASC LIMIT 11",
+			expectedArgs: []any{1, "8.2", "7"},
+		},
+		"before cursor": {
+			input: models.PageRequest{
+				Limit:  10,
+				Offset: 40,
+				Cursor: q.encodeCursor([]string{"8.2", "7"}, true),
+			},
+			expectedQuery: "SELECT id, title FROM blogs WHERE author_id = $1 AND " +
+				"((score > $2::text::real) OR (score = $2::text::real AND id < $3::text::bigint)) " +
+				"ORDER BY score ASC, id DESC LIMIT 11",
+			expectedArgs:   []any{1, "8.2", "7"},
+			expectedBefore: true,
+		},
+		"cursor for another order": {
+			input:         models.PageRequest{Cursor: testCursor("7", false)},
+			expectedError: ErrInvalidCursor,
+		},
+		"malformed cursor": {
+			input:         models.PageRequest{Cursor: "not a cursor"},
+			expectedError: ErrInvalidCursor,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			query, args, before, err := q.pageSQL(tc.input)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected %v, got %v", tc.expectedError, err)
+			}
+			if tc.expectedError != nil {
+				return
+			}
+
+			if query != tc.expectedQuery {
+				t.Errorf("expected query\n%s\ngot\n%s", tc.expectedQuery, query)
+			}
+			if len(args) != len(tc.expectedArgs) {
+				t.Fatalf("expected args %v, got %v", tc.expectedArgs, args)
+			}
+			for i := range args {
+				if args[i] != tc.expectedArgs[i] {
+					t.Errorf("expected args %v, got %v", tc.expectedArgs, args)
+				}
+			}
+			if before != tc.expectedBefore {
+				t.Errorf("expected before %t, got %t", tc.expectedBefore, before)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// changeSet collects the columns a partial update writes, so that the UPDATE
+// it makes only names the columns that changed.
+type changeSet struct {
+	columns []string
+	args    []any
+}
+
+// set adds column to the columns written, with value as its new value.
+func (c *changeSet) set(column string, value any) {
+	c.args = append(c.args, value)
+	c.columns = append(c.columns, fmt.Sprintf("%s = $%d", column, len(c.args)))
+}
+
+// empty reports whether no column has changed.
+func (c *changeSet) empty() bool {
+	return len(c.columns) == 0
+}
+
+// update returns an UPDATE of the row of table whose id is bound after the
+// changed columns, returning the listed columns.
+func (c *changeSet) update(table string, returning string) string {
+	return fmt.Sprintf(
+		"UPDATE %s SET %s WHERE id = $%d RETURNING %s",
+		table,
+		strings.Join(c.columns, ", "),
+		len(c.args)+1,
+		returning,
+	)
+}
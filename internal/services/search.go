@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"log/slog"
+	"strings"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// Matched words are wrapped in these control characters by ts_headline so
+// that the rest of the headline can be escaped before they are replaced with
+// <mark> tags.
+const (
+	headlineStart = "\x01"
+	headlineStop  = "\x02"
+)
+
+// headlineMarker replaces the headline markers with <mark> tags.
+var headlineMarker = strings.NewReplacer(headlineStart, "<mark>", headlineStop, "</mark>")
+
+// searchSource matches published blogs, and the comments on them, against the
+// web search query in $1 and ranks them. ts_headline is applied to the page
+// only, as it is costly.
+const searchSource = `(
+	SELECT 'blog' AS type,
+	       id AS blog_id,
+	       author_id AS user_id,
+	       0::bigint AS comment_id,
+	       title AS content,
+	       ts_rank(search, websearch_to_tsquery('english', $1)) AS rank
+	FROM blogs
+	WHERE search @@ websearch_to_tsquery('english', $1)
+	  AND status = 'published'
+	  AND deleted_at IS NULL
+	UNION ALL
+	SELECT 'comment' AS type,
+	       blog_id,
+	       user_id,
+	       id AS comment_id,
+	       message AS content,
+	       ts_rank(search, websearch_to_tsquery('english', $1)) AS rank
+	FROM comments
+	WHERE search @@ websearch_to_tsquery('english', $1)
+	  AND deleted_at IS NULL
+	  AND blog_id IN (SELECT id FROM blogs WHERE status = 'published' AND deleted_at IS NULL)
+) AS results`
+
+// searchColumns are selected for each result on a page.
+const searchColumns = `type, blog_id, user_id, comment_id, ts_headline('english', content, websearch_to_tsquery('english', $1), ` +
+	`'StartSel=` + headlineStart + `, StopSel=` + headlineStop + `'), rank`
+
+// SearchService is a service capable of searching the content of blogs and
+// comments.
+type SearchService struct {
+	logger *slog.Logger
+	db     *sql.DB
+}
+
+// NewSearchService creates a new SearchService and returns a pointer to it.
+func NewSearchService(logger *slog.Logger, db *sql.DB) *SearchService {
+	return &SearchService{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Search attempts to find the blogs and comments matching query, which uses
+// web search syntax such as `"exact phrase" -excluded`. A page of results
+// ordered by rank, best first, or an error is returned.
+func (s *SearchService) Search(ctx context.Context, query string, page models.PageRequest) (models.Page[models.SearchResult], error) {
+	s.logger.DebugContext(ctx, "Searching", "query", query)
+
+	q := listQuery{
+		columns: searchColumns,
+		from:    searchSource,
+		args:    []any{query},
+		sort: []sortColumn{
+			{column: "rank", cast: "real", desc: true},
+			{column: "type", cast: "text"},
+			{column: "blog_id", cast: "bigint"},
+			{column: "user_id", cast: "bigint"},
+			{column: "comment_id", cast: "bigint"},
+		},
+	}
+
+	results, err := listPage(
+		ctx,
+		s.db,
+		q,
+		page,
+		func(rows *sql.Rows) (models.SearchResult, error) {
+			var result models.SearchResult
+			if err := rows.Scan(&result.Type, &result.BlogID, &result.UserID, &result.CommentID, &result.Headline, &result.Rank); err != nil {
+				return models.SearchResult{}, err
+			}
+			result.Headline = headlineMarker.Replace(html.EscapeString(result.Headline))
+			return result, nil
+		},
+		func(result models.SearchResult) []any {
+			return []any{result.Rank, result.Type, result.BlogID, result.UserID, result.CommentID}
+		},
+	)
+	if err != nil {
+		return models.Page[models.SearchResult]{}, fmt.Errorf(
+			"[in services.SearchService.Search] failed to search: %w",
+			err,
+		)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/chickey/blog/internal/models"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	columns := []string{"type", "blog_id", "user_id", "comment_id", "headline", "rank"}
+
+	testcases := map[string]struct {
+		mockCalled     bool
+		mockQuery      string
+		mockInputArgs  []driver.Value
+		mockOutput     *sqlmock.Rows
+		mockError      error
+		page           models.PageRequest
+		expectedOutput []models.SearchResult
+		expectedNext   bool
+		expectedError  error
+	}{
+		"happy path": {
+			mockCalled:    true,
+			mockQuery:     `ORDER BY rank DESC, type ASC, blog_id ASC, user_id ASC, comment_id ASC LIMIT 2`,
+			mockInputArgs: []driver.Value{"go <tips>"},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow("blog", 1, 1, 0, "\x01Go\x02 & <b>\x01tips\x02</b>", 0.6).
+				AddRow("comment", 1, 2, 3, "More \x01Go\x02", 0.3),
+			mockError: nil,
+			page:      models.PageRequest{Limit: 1},
+			expectedOutput: []models.SearchResult{
+				{
+					Type:     models.SearchResultBlog,
+					BlogID:   1,
+					UserID:   1,
+					Headline: "<mark>Go</mark> &amp; &lt;b&gt;<mark>tips</mark>&lt;/b&gt;",
+					Rank:     0.6,
+				},
+			},
+			expectedNext:  true,
+			expectedError: nil,
+		},
+		"after cursor": {
+			mockCalled: true,
+			mockQuery: `WHERE ((rank < $2::text::real)
+				OR (rank = $2::text::real AND type > $3::text::text)
+				OR (rank = $2::text::real AND type = $3::text::text AND blog_id > $4::text::bigint)
+				OR (rank = $2::text::real AND type = $3::text::text AND blog_id = $4::text::bigint AND user_id > $5::text::bigint)
+				OR (rank = $2::text::real AND type = $3::text::text AND blog_id = $4::text::bigint AND user_id = $5::text::bigint AND comment_id > $6::text::bigint))`,
+			mockInputArgs: []driver.Value{"go <tips>", "0.6", "blog", "1", "1", "0"},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow("comment", 1, 2, 3, "More \x01Go\x02", 0.3),
+			mockError: nil,
+			page: models.PageRequest{
+				Limit: 1,
+				Cursor: (&listQuery{sort: []sortColumn{
+					{column: "rank", cast: "real", desc: true},
+					{column: "type", cast: "text"},
+					{column: "blog_id", cast: "bigint"},
+					{column: "user_id", cast: "bigint"},
+					{column: "comment_id", cast: "bigint"},
+				}}).encodeCursor([]string{"0.6", "blog", "1", "1", "0"}, false),
+			},
+			expectedOutput: []models.SearchResult{
+				{
+					Type:      models.SearchResultComment,
+					BlogID:    1,
+					UserID:    2,
+					CommentID: 3,
+					Headline:  "More <mark>Go</mark>",
+					Rank:      0.3,
+				},
+			},
+			expectedError: nil,
+		},
+		"query error": {
+			mockCalled:     true,
+			mockQuery:      `FROM ( SELECT 'blog' AS type`,
+			mockInputArgs:  []driver.Value{"go <tips>"},
+			mockOutput:     sqlmock.NewRows(columns),
+			mockError:      sql.ErrConnDone,
+			expectedOutput: []models.SearchResult{},
+			expectedError:  sql.ErrConnDone,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			if tc.mockCalled {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(tc.mockQuery)).
+					WithArgs(tc.mockInputArgs...).
+					WillReturnRows(tc.mockOutput).
+					WillReturnError(tc.mockError)
+			}
+
+			searchService := NewSearchService(logger, db)
+
+			output, err := searchService.Search(context.TODO(), "go <tips>", tc.page)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if len(output.Items) != len(tc.expectedOutput) {
+				t.Fatalf("expected %v, got %v", tc.expectedOutput, output.Items)
+			}
+			for i, result := range output.Items {
+				if result != tc.expectedOutput[i] {
+					t.Errorf("expected %v, got %v", tc.expectedOutput[i], result)
+				}
+			}
+			if (output.NextCursor != "") != tc.expectedNext {
+				t.Errorf("expected next page %t, got cursor %q", tc.expectedNext, output.NextCursor)
+			}
+
+			if tc.mockCalled {
+				if err = mock.ExpectationsWereMet(); err != nil {
+					t.Errorf("there were unfulfilled expectations: %s", err)
+				}
+			}
+		})
+	}
+}
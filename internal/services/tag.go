@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/chickey/blog/internal/models"
+)
+
+// DefaultCategory is the category of blogs created without one.
+const DefaultCategory = "uncategorized"
+
+// TagsService is a service capable of normalizing the tags and categories of
+// blogs, assigning tags to blogs and listing them.
+type TagsService struct {
+	logger *slog.Logger
+	db     *sql.DB
+}
+
+// NewTagsService creates a new TagsService and returns a pointer to it.
+func NewTagsService(logger *slog.Logger, db *sql.DB) *TagsService {
+	return &TagsService{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Normalize returns tag as a slug: lower case letters and digits, with each
+// run of other characters replaced by a single hyphen and none at either end.
+// The slug is empty if tag has no letters or digits.
+func (s *TagsService) Normalize(tag string) string {
+	var b strings.Builder
+	hyphen := false
+
+	for _, r := range strings.ToLower(tag) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+
+	return b.String()
+}
+
+// NormalizeAll normalizes tags, dropping those that are empty once normalized
+// and duplicates, and sorts them. nil is returned if no tags are left.
+func (s *TagsService) NormalizeAll(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		if slug := s.Normalize(tag); slug != "" {
+			normalized = append(normalized, slug)
+		}
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// NormalizeCategory normalizes category like a tag, returning DefaultCategory
+// if nothing is left.
+func (s *TagsService) NormalizeCategory(category string) string {
+	if slug := s.Normalize(category); slug != "" {
+		return slug
+	}
+
+	return DefaultCategory
+}
+
+// SetBlogTags replaces the tags of the blog with blogId with tags, creating
+// those that don't exist yet. The tags must already be normalized. It runs in
+// tx so that the tags change together with the blog.
+func (s *TagsService) SetBlogTags(ctx context.Context, tx *sql.Tx, blogId uint, tags []string) error {
+	s.logger.DebugContext(ctx, "Setting blog tags", "Blog Id", blogId, "Tags", tags)
+
+	_, err := tx.ExecContext(
+		ctx,
+		`
+		DELETE FROM blog_tags WHERE blog_id = $1
+		`,
+		blogId,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clear tags of blog %d: %w", blogId, err)
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	names := make([]any, len(tags))
+	values := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag
+		values[i] = fmt.Sprintf("($%d)", i+1)
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		fmt.Sprintf(`
+		INSERT INTO tags (name) VALUES %s ON CONFLICT (name) DO NOTHING
+		`, strings.Join(values, ", ")),
+		names...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create tags: %w", translateError(err))
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		fmt.Sprintf(`
+		INSERT INTO blog_tags (blog_id, tag_id) SELECT $1, id FROM tags WHERE name IN (%s)
+		`, placeholders(2, len(tags))),
+		append([]any{blogId}, names...)...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to tag blog %d: %w", blogId, translateError(err))
+	}
+
+	return nil
+}
+
+// tagSortColumns are the columns tags can be sorted by, keyed by the field
+// name used in sort parameters.
+var tagSortColumns = map[string]sortColumn{
+	"name":       {column: "name", cast: "text"},
+	"blog_count": {column: "blog_count", cast: "bigint"},
+}
+
+// ListTags attempts to list a page of the tags carried by published blogs,
+// with the number of published blogs carrying each, in the order given by
+// sort and then by name. A page of models.Tag or an error is returned.
+// ErrInvalidSort is returned if sort names a field tags can't be sorted by.
+func (s *TagsService) ListTags(ctx context.Context, sort []models.SortField, page models.PageRequest) (models.Page[models.Tag], error) {
+	s.logger.DebugContext(ctx, "Listing tags")
+
+	columns, err := sortColumns(sort, tagSortColumns, tagSortColumns["name"])
+	if err != nil {
+		return models.Page[models.Tag]{}, fmt.Errorf("[in services.TagsService.ListTags] %w", err)
+	}
+
+	q := listQuery{
+		columns: "name, blog_count",
+		from: `(
+			SELECT t.name, COUNT(*) AS blog_count
+			FROM tags t
+			JOIN blog_tags bt ON bt.tag_id = t.id
+			JOIN blogs b ON b.id = bt.blog_id
+			WHERE b.status = 'published' AND b.deleted_at IS NULL
+			GROUP BY t.name
+		) tag_counts`,
+		sort: columns,
+	}
+
+	tags, err := listPage(
+		ctx,
+		s.db,
+		q,
+		page,
+		func(rows *sql.Rows) (models.Tag, error) {
+			var tag models.Tag
+			err := rows.Scan(&tag.Name, &tag.BlogCount)
+			return tag, err
+		},
+		func(tag models.Tag) []any {
+			return tagSortKey(tag, columns)
+		},
+	)
+	if err != nil {
+		return models.Page[models.Tag]{}, fmt.Errorf(
+			"[in services.TagsService.ListTags] failed to list tags: %w",
+			err,
+		)
+	}
+
+	return tags, nil
+}
+
+// tagSortKey returns the values of the sort columns of tag.
+func tagSortKey(tag models.Tag, columns []sortColumn) []any {
+	keys := make([]any, len(columns))
+	for i, column := range columns {
+		switch column.column {
+		case "name":
+			keys[i] = tag.Name
+		case "blog_count":
+			keys[i] = tag.BlogCount
+		}
+	}
+
+	return keys
+}
+
+// placeholders returns n comma separated placeholders numbered from first,
+// such as "$2, $3, $4".
+func placeholders(first, n int) string {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = fmt.Sprintf("$%d", first+i)
+	}
+
+	return strings.Join(list, ", ")
+}
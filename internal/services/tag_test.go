@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/chickey/blog/internal/models"
+)
+
+// expectSetBlogTags expects the statements TagsService.SetBlogTags runs to
+// give the blog with blogId tags.
+func expectSetBlogTags(mock sqlmock.Sqlmock, blogId int, tags ...driver.Value) {
+	mock.
+		ExpectExec(regexp.QuoteMeta(`DELETE FROM blog_tags WHERE blog_id = $1`)).
+		WithArgs(blogId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if len(tags) == 0 {
+		return
+	}
+
+	mock.
+		ExpectExec(regexp.QuoteMeta(`INSERT INTO tags (name) VALUES `)).
+		WithArgs(tags...).
+		WillReturnResult(sqlmock.NewResult(0, int64(len(tags))))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`INSERT INTO blog_tags (blog_id, tag_id) SELECT $1, id FROM tags WHERE name IN (`)).
+		WithArgs(append([]driver.Value{blogId}, tags...)...).
+		WillReturnResult(sqlmock.NewResult(0, int64(len(tags))))
+}
+
+func TestTagsService_NormalizeAll(t *testing.T) {
+	testcases := map[string]struct {
+		input          []string
+		expectedOutput []string
+	}{
+		"already normalized": {
+			input:          []string{"go", "postgres"},
+			expectedOutput: []string{"go", "postgres"},
+		},
+		"case and spacing": {
+			input:          []string{"  Web   Development ", "GO"},
+			expectedOutput: []string{"go", "web-development"},
+		},
+		"punctuation": {
+			input:          []string{"C++ & Rust!", "node.js", "--go--"},
+			expectedOutput: []string{"c-rust", "go", "node-js"},
+		},
+		"unicode letters": {
+			input:          []string{"Café Société"},
+			expectedOutput: []string{"café-société"},
+		},
+		"duplicates after normalizing": {
+			input:          []string{"Go", "go", " GO "},
+			expectedOutput: []string{"go"},
+		},
+		"nothing left": {
+			input:          []string{"", "!!", " - "},
+			expectedOutput: nil,
+		},
+		"no tags": {
+			input:          nil,
+			expectedOutput: nil,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			tagsService := NewTagsService(slog.Default(), nil)
+
+			output := tagsService.NormalizeAll(tc.input)
+			if !reflect.DeepEqual(output, tc.expectedOutput) {
+				t.Errorf("expected %q, got %q", tc.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestTagsService_NormalizeCategory(t *testing.T) {
+	tagsService := NewTagsService(slog.Default(), nil)
+
+	if got := tagsService.NormalizeCategory("Web Dev"); got != "web-dev" {
+		t.Errorf("expected %q, got %q", "web-dev", got)
+	}
+	if got := tagsService.NormalizeCategory(" ? "); got != DefaultCategory {
+		t.Errorf("expected %q, got %q", DefaultCategory, got)
+	}
+}
+
+func TestTagsService_ListTags(t *testing.T) {
+	columns := []string{"name", "blog_count"}
+
+	testcases := map[string]struct {
+		mockCalled     bool
+		mockQuery      string
+		mockOutput     *sqlmock.Rows
+		mockError      error
+		sort           []models.SortField
+		page           models.PageRequest
+		expectedOutput []models.Tag
+		expectedNext   bool
+		expectedError  error
+	}{
+		"happy path": {
+			mockCalled: true,
+			mockQuery: `SELECT name, blog_count FROM (
+				SELECT t.name, COUNT(*) AS blog_count
+				FROM tags t
+				JOIN blog_tags bt ON bt.tag_id = t.id
+				JOIN blogs b ON b.id = bt.blog_id
+				WHERE b.status = 'published' AND b.deleted_at IS NULL
+				GROUP BY t.name
+			) tag_counts ORDER BY name ASC LIMIT 2`,
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow("go", 3).
+				AddRow("postgres", 1),
+			page: models.PageRequest{Limit: 1},
+			expectedOutput: []models.Tag{
+				{Name: "go", BlogCount: 3},
+			},
+			expectedNext: true,
+		},
+		"most used first": {
+			mockCalled: true,
+			mockQuery:  `ORDER BY blog_count DESC, name ASC LIMIT 21`,
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow("go", 3).
+				AddRow("postgres", 1),
+			sort: []models.SortField{{Field: "blog_count", Desc: true}},
+			expectedOutput: []models.Tag{
+				{Name: "go", BlogCount: 3},
+				{Name: "postgres", BlogCount: 1},
+			},
+		},
+		"unknown sort field": {
+			sort:          []models.SortField{{Field: "id"}},
+			expectedError: ErrInvalidSort,
+		},
+		"query error": {
+			mockCalled:    true,
+			mockQuery:     `SELECT name, blog_count FROM`,
+			mockOutput:    sqlmock.NewRows(columns),
+			mockError:     sql.ErrConnDone,
+			expectedError: sql.ErrConnDone,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			if tc.mockCalled {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(tc.mockQuery)).
+					WillReturnRows(tc.mockOutput).
+					WillReturnError(tc.mockError)
+			}
+
+			tagsService := NewTagsService(slog.Default(), db)
+
+			output, err := tagsService.ListTags(context.TODO(), tc.sort, tc.page)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if len(output.Items) != len(tc.expectedOutput) {
+				t.Fatalf("expected %v, got %v", tc.expectedOutput, output.Items)
+			}
+			for i, tag := range output.Items {
+				if tag != tc.expectedOutput[i] {
+					t.Errorf("expected %v, got %v", tc.expectedOutput[i], tag)
+				}
+			}
+			if (output.NextCursor != "") != tc.expectedNext {
+				t.Errorf("expected next page %t, got cursor %q", tc.expectedNext, output.NextCursor)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// exportRows runs query and calls scan with each row it returns, so that a
+// whole table can be exported without holding it in memory. The rows are read
+// in a single query, and scan stops the export by returning an error.
+func exportRows(ctx context.Context, db *sql.DB, query string, scan func(rows *sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// importColumns returns the columns an import inserts, led by id when the
+// imported rows keep their IDs.
+func importColumns(keepIDs bool, columns ...string) []string {
+	if keepIDs {
+		return append([]string{"id"}, columns...)
+	}
+
+	return columns
+}
+
+// syncSequence moves the sequence that numbers the rows of table past the
+// largest id in it, so that rows imported with their IDs aren't handed out
+// again to rows created later.
+func syncSequence(ctx context.Context, tx *sql.Tx, table string) error {
+	_, err := tx.ExecContext(
+		ctx,
+		fmt.Sprintf(`
+		SELECT setval(pg_get_serial_sequence('%s', 'id'), MAX(id)) FROM %s
+		`, table, table),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to move %s sequence: %w", table, err)
+	}
+
+	return nil
+}
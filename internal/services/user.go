@@ -6,22 +6,29 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/password"
 )
 
 // UsersService is a service capable of performing CRUD operations for
-// models.User models.
+// models.User models. Passwords are hashed with hasher before they are
+// stored, so models.User.Password returned by the service always holds a hash.
 type UsersService struct {
 	logger *slog.Logger
 	db     *sql.DB
+	hasher *password.Hasher
 }
 
 // NewUsersService creates a new UsersService and returns a pointer to it.
-func NewUsersService(logger *slog.Logger, db *sql.DB) *UsersService {
+func NewUsersService(logger *slog.Logger, db *sql.DB, hasher *password.Hasher) *UsersService {
 	return &UsersService{
 		logger: logger,
 		db:     db,
+		hasher: hasher,
 	}
 }
 
@@ -30,6 +37,12 @@ func NewUsersService(logger *slog.Logger, db *sql.DB) *UsersService {
 func (s *UsersService) CreateUser(ctx context.Context, user models.User) (models.User, error) {
 	s.logger.DebugContext(ctx, "Creating user", "name", user.Name)
 
+	hash, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		return models.User{}, fmt.Errorf("[in services.UsersService.CreateUser] %w", err)
+	}
+	user.Password = hash
+
 	row := s.db.QueryRowContext(
 		ctx,
 		`
@@ -40,12 +53,12 @@ func (s *UsersService) CreateUser(ctx context.Context, user models.User) (models
 		user.Password,
 	)
 
-	err := row.Scan(&user.ID)
+	err = row.Scan(&user.ID)
 
 	if err != nil {
 		return models.User{}, fmt.Errorf(
 			"[in services.UsersService.CreateUser] failed to create user: %w",
-			err,
+			translateError(err),
 		)
 	}
 
@@ -53,30 +66,44 @@ func (s *UsersService) CreateUser(ctx context.Context, user models.User) (models
 }
 
 // ReadUser attempts to read a user from the database using the provided id. A
-// fully hydrated models.User or error is returned.
-func (s *UsersService) ReadUser(ctx context.Context, id uint64) (models.User, error) {
+// fully hydrated models.User or error is returned. Deleted users are only read
+// if includeDeleted is set.
+func (s *UsersService) ReadUser(ctx context.Context, id uint64, includeDeleted bool) (models.User, error) {
 	s.logger.DebugContext(ctx, "Reading user", "id", id)
 
+	columns, condition := deletedColumns(includeDeleted)
+
 	row := s.db.QueryRowContext(
 		ctx,
-		`
+		fmt.Sprintf(`
 		SELECT id,
 		       name,
 		       email,
-		       password
+		       password,
+		       version%s
 		FROM users
-		WHERE id = $1::int
-        `,
+		WHERE id = $1::int%s
+        `, columns, condition),
 		id,
 	)
 
 	var user models.User
+	var deletedAt sql.NullTime
 
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password)
+	dest := []any{&user.ID, &user.Name, &user.Email, &user.Password, &user.Version}
+	if includeDeleted {
+		dest = append(dest, &deletedAt)
+	}
+
+	err := row.Scan(dest...)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return models.User{}, nil
+			return models.User{}, fmt.Errorf(
+				"[in services.UsersService.ReadUser] user %d: %w",
+				id,
+				ErrNotFound,
+			)
 		default:
 			return models.User{}, fmt.Errorf(
 				"[in services.UsersService.ReadUser] failed to read user: %w",
@@ -84,89 +111,566 @@ func (s *UsersService) ReadUser(ctx context.Context, id uint64) (models.User, er
 			)
 		}
 	}
+	user.DeletedAt = deletedAt.Time
 
 	return user, nil
 }
 
 // UpdateUser attempts to perform an update of the user with the provided id,
 // updating, it to reflect the properties on the provided patch object. A
-// models.User or an error.
+// models.User or an error. Changing the password revokes every session of the
+// user, signing them out everywhere. If patch.Version is set the update only
+// goes ahead if it is still the user's version, and ErrPreconditionFailed is
+// returned otherwise. The returned user carries its new version.
 func (s *UsersService) UpdateUser(ctx context.Context, id uint64, patch models.User) (models.User, error) {
 	s.logger.DebugContext(ctx, "Updating user", "id", id)
 
-	_, err := s.db.ExecContext(
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		patch, err = s.updateUser(ctx, tx, id, patch)
+		return err
+	})
+
+	if err != nil {
+		return models.User{}, fmt.Errorf(
+			"[in services.UsersService.UpdateUser] %w",
+			err,
+		)
+	}
+
+	return patch, nil
+}
+
+// updateUser performs UpdateUser within tx.
+func (s *UsersService) updateUser(ctx context.Context, tx *sql.Tx, id uint64, patch models.User) (models.User, error) {
+	var current string
+	var version uint
+	err := tx.QueryRowContext(
+		ctx,
+		`
+		SELECT password, version FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		`,
+		id,
+	).Scan(&current, &version)
+	if err != nil {
+		return models.User{}, fmt.Errorf("user %d: %w", id, replaceNoRows(err, ErrNotFound))
+	}
+
+	if err = checkVersion(version, patch.Version); err != nil {
+		return models.User{}, fmt.Errorf("user %d: %w", id, err)
+	}
+
+	// Keep the stored hash when the password is unchanged so the user
+	// stays signed in
+	unchanged, _, err := s.hasher.Verify(current, patch.Password)
+	if err != nil && !errors.Is(err, password.ErrUnknownHash) {
+		return models.User{}, err
+	}
+
+	hash := current
+	if !unchanged {
+		if hash, err = s.hasher.Hash(patch.Password); err != nil {
+			return models.User{}, err
+		}
+	}
+
+	err = tx.QueryRowContext(
 		ctx,
 		`
 		UPDATE users 
 		SET name = $1, email = $2, password = $3
 		WHERE id = $4
+		RETURNING version
 		`,
 		patch.Name,
 		patch.Email,
-		patch.Password,
+		hash,
 		id,
-	)
+	).Scan(&patch.Version)
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to update user: %w", translateError(err))
+	}
+
+	if !unchanged {
+		_, err = tx.ExecContext(
+			ctx,
+			`
+			UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL
+			`,
+			id,
+		)
+		if err != nil {
+			return models.User{}, fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+	}
+
+	patch.Password = hash
+	patch.ID = uint(id)
+	return patch, nil
+}
+
+// PatchUser attempts to apply patch to the user with the provided id, writing
+// only the columns it changes. A patch that changes nothing leaves the user as
+// it is. Like UpdateUser, changing the password revokes every session of the
+// user, and a set patch.Version that is no longer the user's version returns
+// ErrPreconditionFailed. The patched models.User or an error is returned.
+func (s *UsersService) PatchUser(ctx context.Context, id uint64, patch models.UserPatch) (models.User, error) {
+	s.logger.DebugContext(ctx, "Patching user", "id", id)
+
+	var user models.User
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			`
+			SELECT id, name, email, password, version FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+			`,
+			id,
+		).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Version)
+		if err != nil {
+			return fmt.Errorf("user %d: %w", id, replaceNoRows(err, ErrNotFound))
+		}
+
+		if err = checkVersion(user.Version, patch.Version); err != nil {
+			return fmt.Errorf("user %d: %w", id, err)
+		}
+
+		var changes changeSet
+		if patch.Name != nil && *patch.Name != user.Name {
+			user.Name = *patch.Name
+			changes.set("name", user.Name)
+		}
+		if patch.Email != nil && *patch.Email != user.Email {
+			user.Email = *patch.Email
+			changes.set("email", user.Email)
+		}
+
+		passwordChanged := false
+		if patch.Password != nil {
+			unchanged, _, err := s.hasher.Verify(user.Password, *patch.Password)
+			if err != nil && !errors.Is(err, password.ErrUnknownHash) {
+				return err
+			}
+			if !unchanged {
+				if user.Password, err = s.hasher.Hash(*patch.Password); err != nil {
+					return err
+				}
+				changes.set("password", user.Password)
+				passwordChanged = true
+			}
+		}
+
+		if changes.empty() {
+			return nil
+		}
+
+		err = tx.QueryRowContext(ctx, changes.update("users", "version"), append(changes.args, id)...).Scan(&user.Version)
+		if err != nil {
+			return fmt.Errorf("failed to update user: %w", translateError(err))
+		}
+
+		if passwordChanged {
+			_, err = tx.ExecContext(
+				ctx,
+				`
+				UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL
+				`,
+				id,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to revoke sessions: %w", err)
+			}
+		}
+
+		return nil
+	})
 
 	if err != nil {
 		return models.User{}, fmt.Errorf(
-			"[in services.UsersService.UpdateUser] failed to update user: %w",
+			"[in services.UsersService.PatchUser] %w",
 			err,
 		)
 	}
-	patch.ID = uint(id)
-	return patch, nil
+
+	return user, nil
 }
 
-// DeleteUser attempts to delete the user with the provided id. An error is
-// returned if the delete fails.
-func (s *UsersService) DeleteUser(ctx context.Context, id uint64) error {
-	s.logger.DebugContext(ctx, "Deleting user", "id", id)
+// VerifyPassword checks plain against the stored password of the user with
+// the provided email, returning the user if it matches. ErrInvalidCredentials
+// is returned if there is no such user, the user is deleted or the password is
+// wrong. If the stored
+// hash was produced with outdated parameters it is transparently replaced with
+// a fresh one.
+func (s *UsersService) VerifyPassword(ctx context.Context, email, plain string) (models.User, error) {
+	s.logger.DebugContext(ctx, "Verifying password", "email", email)
 
-	// Delete user from user table
-	_, err := s.db.ExecContext(
+	row := s.db.QueryRowContext(
 		ctx,
 		`
-		DELETE FROM users WHERE id = $1::int
+		SELECT id,
+		       name,
+		       email,
+		       password
+		FROM users
+		WHERE LOWER(email) = LOWER($1) AND deleted_at IS NULL
 		`,
-		id,
+		email,
 	)
 
+	var user models.User
+
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password)
 	if err != nil {
-		return fmt.Errorf(
-			"[in services.UsersService.DeleteUser] failed to delete user: %w",
+		if errors.Is(err, sql.ErrNoRows) {
+			// Hash anyway so unknown emails take as long as wrong passwords
+			_, _ = s.hasher.Hash(plain)
+			return models.User{}, fmt.Errorf(
+				"[in services.UsersService.VerifyPassword] %w",
+				ErrInvalidCredentials,
+			)
+		}
+		return models.User{}, fmt.Errorf(
+			"[in services.UsersService.VerifyPassword] failed to read user: %w",
 			err,
 		)
 	}
 
-	// Delete blogs with authodId = id
+	match, needsRehash, err := s.hasher.Verify(user.Password, plain)
+	if err != nil {
+		return models.User{}, fmt.Errorf(
+			"[in services.UsersService.VerifyPassword] user %d: %w",
+			user.ID,
+			err,
+		)
+	}
+	if !match {
+		return models.User{}, fmt.Errorf(
+			"[in services.UsersService.VerifyPassword] %w",
+			ErrInvalidCredentials,
+		)
+	}
+
+	if needsRehash {
+		// A failed rehash shouldn't fail the login; the next one will retry
+		if err = s.rehash(ctx, &user, plain); err != nil {
+			s.logger.WarnContext(
+				ctx,
+				"failed to rehash password",
+				slog.Uint64("id", uint64(user.ID)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
+	return user, nil
+}
+
+// rehash replaces the stored hash of user with a fresh hash of plain. The
+// update only applies if the stored hash hasn't changed since it was read.
+func (s *UsersService) rehash(ctx context.Context, user *models.User, plain string) error {
+	hash, err := s.hasher.Hash(plain)
+	if err != nil {
+		return err
+	}
+
 	_, err = s.db.ExecContext(
 		ctx,
 		`
-		DELETE FROM blogs WHERE author_id = $1::int
+		UPDATE users
+		SET password = $1
+		WHERE id = $2 AND password = $3
 		`,
-		id,
+		hash,
+		user.ID,
+		user.Password,
 	)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	user.Password = hash
+	return nil
+}
+
+// HashPlaintextPasswords hashes every stored password that is not already a
+// hash, returning the number of users updated. It is intended to be run once
+// against databases created before passwords were hashed.
+func (s *UsersService) HashPlaintextPasswords(ctx context.Context) (int, error) {
+	s.logger.DebugContext(ctx, "Hashing plaintext passwords")
+
+	updated := 0
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(
+			ctx,
+			`
+			SELECT id, password FROM users ORDER BY id FOR UPDATE
+			`,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to read users: %w", err)
+		}
+
+		var plaintext []models.User
+		for rows.Next() {
+			var user models.User
+			if err = rows.Scan(&user.ID, &user.Password); err != nil {
+				_ = rows.Close()
+				return fmt.Errorf("failed to read users: %w", err)
+			}
+			if !password.IsHash(user.Password) {
+				plaintext = append(plaintext, user)
+			}
+		}
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("failed to read users: %w", err)
+		}
+
+		for _, user := range plaintext {
+			hash, err := s.hasher.Hash(user.Password)
+			if err != nil {
+				return fmt.Errorf("user %d: %w", user.ID, err)
+			}
+
+			_, err = tx.ExecContext(
+				ctx,
+				`
+				UPDATE users SET password = $1 WHERE id = $2
+				`,
+				hash,
+				user.ID,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to update user %d: %w", user.ID, err)
+			}
+			updated++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[in services.UsersService.HashPlaintextPasswords] %w",
+			err,
+		)
+	}
+
+	return updated, nil
+}
+
+// DeleteUser attempts to delete the user with the provided id, along with
+// their blogs, every comment written by or on behalf of them and the replies
+// to those, in a single transaction. Everything is deleted at the same time so
+// that RestoreUser can bring it back, until it is purged. The user's sessions
+// are revoked, and their votes are taken out of blog scores for good. If
+// version is not zero the user is only deleted if it is still their version,
+// and ErrPreconditionFailed is returned otherwise. An error is returned if the
+// delete fails.
+func (s *UsersService) DeleteUser(ctx context.Context, id uint64, version uint) error {
+	s.logger.DebugContext(ctx, "Deleting user", "id", id)
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		return s.deleteUser(ctx, tx, id, version)
+	})
 
 	if err != nil {
 		return fmt.Errorf(
-			"[in services.UsersService.DeleteUser] failed to delete blogs: %w",
+			"[in services.UsersService.DeleteUser] %w",
 			err,
 		)
 	}
 
-	//Delete comments where userId = id
+	return nil
+}
 
-	_, err = s.db.ExecContext(
+// deleteUser performs DeleteUser within tx.
+func (s *UsersService) deleteUser(ctx context.Context, tx *sql.Tx, id uint64, version uint) error {
+	var current uint
+	err := tx.QueryRowContext(
+		ctx,
+		`
+		SELECT version FROM users WHERE id = $1::int AND deleted_at IS NULL FOR UPDATE
+		`,
+		id,
+	).Scan(&current)
+	if err != nil {
+		return fmt.Errorf("user %d: %w", id, replaceNoRows(err, ErrNotFound))
+	}
+
+	if err = checkVersion(current, version); err != nil {
+		return fmt.Errorf("user %d: %w", id, err)
+	}
+
+	// Delete comments written by the user as well as comments other users
+	// left on the user's blogs, and the replies to them. CURRENT_TIMESTAMP
+	// is the same for every statement in the transaction.
+	_, err = tx.ExecContext(
 		ctx,
 		`
-		DELETE FROM comments WHERE user_id = $1::int
+		WITH RECURSIVE removed AS (
+			SELECT id
+			FROM comments
+			WHERE user_id = $1::int
+			   OR blog_id IN (SELECT id FROM blogs WHERE author_id = $1::int)
+			UNION
+			SELECT c.id
+			FROM comments c
+			JOIN removed r ON c.parent_id = r.id
+		)
+		UPDATE comments
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT id FROM removed) AND deleted_at IS NULL
 		`,
 		id,
 	)
+	if err != nil {
+		return fmt.Errorf("failed to delete comments: %w", err)
+	}
+
+	// Delete blogs with authorId = id
+	_, err = tx.ExecContext(
+		ctx,
+		`
+		UPDATE blogs SET deleted_at = CURRENT_TIMESTAMP WHERE author_id = $1::int AND deleted_at IS NULL
+		`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete blogs: %w", err)
+	}
+
+	// Take the user's votes out of the scores of the blogs they voted on.
+	// The subquery sees the votes as they were before the delete.
+	_, err = tx.ExecContext(
+		ctx,
+		`
+		WITH removed AS (
+			DELETE FROM votes WHERE user_id = $1::int RETURNING blog_id
+		)
+		UPDATE blogs
+		SET score = COALESCE(
+			(SELECT AVG(rating) FROM votes WHERE blog_id = blogs.id AND user_id <> $1::int),
+			0
+		)
+		WHERE id IN (SELECT blog_id FROM removed)
+		`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete votes: %w", err)
+	}
+
+	// Sign the user out everywhere
+	_, err = tx.ExecContext(
+		ctx,
+		`
+		UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1::int AND revoked_at IS NULL
+		`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	// Delete user from user table
+	result, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1::int AND deleted_at IS NULL
+		`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	if err = expectAffected(result); err != nil {
+		return fmt.Errorf("user %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// RestoreUser attempts to restore the deleted user with the provided id, along
+// with the blogs and comments that were deleted with them, in a single
+// transaction. Blogs and comments deleted on their own stay deleted, and
+// votes are not restored. ErrNotFound is returned if there is no such deleted
+// user, and ErrConflict if their email has since been taken.
+func (s *UsersService) RestoreUser(ctx context.Context, id uint64) error {
+	s.logger.DebugContext(ctx, "Restoring user", "id", id)
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var deletedAt time.Time
+		err := tx.QueryRowContext(
+			ctx,
+			`
+			SELECT deleted_at FROM users WHERE id = $1::int AND deleted_at IS NOT NULL FOR UPDATE
+			`,
+			id,
+		).Scan(&deletedAt)
+		if err != nil {
+			return fmt.Errorf("deleted user %d: %w", id, replaceNoRows(err, ErrNotFound))
+		}
+
+		// Restore the comments removed with the user, found the same way
+		// DeleteUser found them, unless their blog has since been deleted
+		_, err = tx.ExecContext(
+			ctx,
+			`
+			WITH RECURSIVE removed AS (
+				SELECT id
+				FROM comments
+				WHERE user_id = $1::int
+				   OR blog_id IN (SELECT id FROM blogs WHERE author_id = $1::int)
+				UNION
+				SELECT c.id
+				FROM comments c
+				JOIN removed r ON c.parent_id = r.id
+			)
+			UPDATE comments
+			SET deleted_at = NULL
+			WHERE id IN (SELECT id FROM removed)
+			  AND deleted_at = $2
+			  AND blog_id IN (
+				SELECT id FROM blogs WHERE deleted_at IS NULL OR (author_id = $1::int AND deleted_at = $2)
+			  )
+			`,
+			id,
+			deletedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore comments: %w", err)
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`
+			UPDATE blogs SET deleted_at = NULL WHERE author_id = $1::int AND deleted_at = $2
+			`,
+			id,
+			deletedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore blogs: %w", err)
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`
+			UPDATE users SET deleted_at = NULL WHERE id = $1::int
+			`,
+			id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore user: %w", translateError(err))
+		}
+
+		return nil
+	})
 
 	if err != nil {
 		return fmt.Errorf(
-			"[in services.UsersService.DeleteUser] failed to delete comments: %w",
+			"[in services.UsersService.RestoreUser] %w",
 			err,
 		)
 	}
@@ -174,55 +678,290 @@ func (s *UsersService) DeleteUser(ctx context.Context, id uint64) error {
 	return nil
 }
 
-// ListUsers attempts to list all users in the database. A slice of models.User
-// or an error is returned.
-// WHY DO WE NEED ID?
-func (s *UsersService) ListUsers(ctx context.Context, name string) ([]models.User, error) {
+// PurgeDeleted permanently deletes the users, blogs and comments that were
+// deleted before the provided time, in a single transaction, returning the
+// number of rows deleted. The records that belong to them, such as votes,
+// tags and revisions, go with them.
+func (s *UsersService) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	s.logger.DebugContext(ctx, "Purging deleted records", "before", before)
+
+	var purged int64
+
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Comments and blogs go first so that those deleted along with their
+		// blog or user are counted rather than removed by the cascade
+		for _, table := range []string{"comments", "blogs", "users"} {
+			result, err := tx.ExecContext(
+				ctx,
+				fmt.Sprintf(`
+				DELETE FROM %s WHERE deleted_at < $1
+				`, table),
+				before,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to purge %s: %w", table, err)
+			}
+
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to count purged %s: %w", table, err)
+			}
+			purged += affected
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf(
+			"[in services.UsersService.PurgeDeleted] %w",
+			err,
+		)
+	}
+
+	return purged, nil
+}
+
+// ListUsers attempts to list a page of users in the database, ordered by id
+// and optionally restricted to those with the provided name. Deleted users are
+// only listed if includeDeleted is set. A page of models.User or an error is
+// returned.
+func (s *UsersService) ListUsers(ctx context.Context, name string, page models.PageRequest, includeDeleted bool) (models.Page[models.User], error) {
 	s.logger.DebugContext(ctx, "Listing users")
 
-	rows, err := s.db.QueryContext(
+	q := listQuery{
+		columns: "id, name, email, role",
+		from:    "users",
+		sort:    []sortColumn{{column: "id", cast: "bigint"}},
+	}
+	if name != "" {
+		q.where("name = $%d", name)
+	}
+	q.withDeleted(includeDeleted)
+
+	users, err := listPage(
 		ctx,
-		`
-		SELECT id,
-		       name,
-		       email,
-		       password
-		FROM users
-        `,
+		s.db,
+		q,
+		page,
+		func(rows *sql.Rows) (models.User, error) {
+			var user models.User
+			var deletedAt sql.NullTime
+			dest := []any{&user.ID, &user.Name, &user.Email, &user.Role}
+			if includeDeleted {
+				dest = append(dest, &deletedAt)
+			}
+			if err := rows.Scan(dest...); err != nil {
+				return models.User{}, err
+			}
+			user.DeletedAt = deletedAt.Time
+			return user, nil
+		},
+		func(user models.User) []any {
+			return []any{user.ID}
+		},
 	)
-
 	if err != nil {
-		return []models.User{}, fmt.Errorf(
-			"[in services.UsersService.ListUser] failed to read users: %w",
+		return models.Page[models.User]{}, fmt.Errorf(
+			"[in services.UsersService.ListUsers] failed to list users: %w",
 			err,
 		)
 	}
 
-	var users []models.User
+	return users, nil
+}
 
-	// error handling for error in for loop and then for empty error out of for loop?
-	// NEED TO HANDLE ERROR FOR EMPTY RESULTS
-	for rows.Next() {
-		var user models.User
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Password)
-		if err != nil {
-			return []models.User{}, fmt.Errorf(
-				"[in services.UsersService.ListUser] failed to read users: %w",
-				err,
-			)
+// CreateUsers attempts to create the provided users in a single transaction,
+// with a single multi-row INSERT. Passwords are hashed as CreateUser does. A
+// models.BulkResult is returned for each user, in order, and mode decides
+// whether the users that could be created are kept when others fail. An error
+// is only returned if the bulk write as a whole fails.
+func (s *UsersService) CreateUsers(ctx context.Context, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error) {
+	s.logger.DebugContext(ctx, "Creating users", "count", len(users), "mode", mode)
+
+	users = slices.Clone(users)
+	errs := make([]error, len(users))
+	for i := range users {
+		if users[i].Password, errs[i] = s.hasher.Hash(users[i].Password); errs[i] != nil {
+			users[i].Password = ""
 		}
+	}
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		return insertItems(ctx, tx, mode, errs, func(batch []int) error {
+			args := make([]any, 0, 3*len(batch))
+			for _, i := range batch {
+				args = append(args, users[i].Name, users[i].Email, users[i].Password)
+			}
+
+			err := insertRows(
+				ctx,
+				tx,
+				batch,
+				fmt.Sprintf(`
+				INSERT INTO users (name, email, password) VALUES %s RETURNING id
+				`, valueRows(len(batch), 3)),
+				args,
+				func(i int, rows *sql.Rows) error {
+					return rows.Scan(&users[i].ID)
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create users: %w", err)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.UsersService.CreateUsers] %w", err)
+	}
+
+	return bulkResults("services.UsersService.CreateUsers", users, errs), nil
+}
+
+// UpdateUsers attempts to update each of the provided users, picked out by
+// their ID, in a single transaction. Each user is updated as UpdateUser does,
+// including its version check. A models.BulkResult is returned for each user,
+// in order, and mode decides whether the users that could be updated are kept
+// when others fail. An error is only returned if the bulk write as a whole
+// fails.
+func (s *UsersService) UpdateUsers(ctx context.Context, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error) {
+	s.logger.DebugContext(ctx, "Updating users", "count", len(users), "mode", mode)
+
+	users = slices.Clone(users)
+	errs := make([]error, len(users))
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		return eachItem(ctx, tx, mode, errs, func(i int) error {
+			user, err := s.updateUser(ctx, tx, uint64(users[i].ID), users[i])
+			users[i] = user
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.UsersService.UpdateUsers] %w", err)
+	}
+
+	return bulkResults("services.UsersService.UpdateUsers", users, errs), nil
+}
+
+// DeleteUsers attempts to delete each of the provided users, picked out by
+// their ID, in a single transaction. Each user is deleted as DeleteUser does,
+// and only if it is still at its Version when that is set. A
+// models.BulkResult holding the user as it was given is returned for each
+// user, in order, and mode decides whether the users that could be deleted
+// stay deleted when others fail. An error is only returned if the bulk write
+// as a whole fails.
+func (s *UsersService) DeleteUsers(ctx context.Context, users []models.User, mode models.BulkMode) ([]models.BulkResult[models.User], error) {
+	s.logger.DebugContext(ctx, "Deleting users", "count", len(users), "mode", mode)
+
+	errs := make([]error, len(users))
+
+	err := bulkWrite(ctx, s.db, mode, errs, func(tx *sql.Tx) error {
+		return eachItem(ctx, tx, mode, errs, func(i int) error {
+			return s.deleteUser(ctx, tx, uint64(users[i].ID), users[i].Version)
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.UsersService.DeleteUsers] %w", err)
+	}
+
+	return bulkResults("services.UsersService.DeleteUsers", users, errs), nil
+}
+
+// ExportUsers calls fn with each user that isn't deleted, in order of id,
+// reading them with a single query so that they are streamed rather than
+// held in memory. Users carry their password hash and role. An error is
+// returned if reading the users fails or fn does.
+func (s *UsersService) ExportUsers(ctx context.Context, fn func(models.User) error) error {
+	s.logger.DebugContext(ctx, "Exporting users")
+
+	err := exportRows(
+		ctx,
+		s.db,
+		`
+		SELECT id, name, email, password, role FROM users WHERE deleted_at IS NULL ORDER BY id
+		`,
+		func(rows *sql.Rows) error {
+			var user models.User
+			if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role); err != nil {
+				return err
+			}
+
+			return fn(user)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("[in services.UsersService.ExportUsers] failed to export users: %w", err)
+	}
 
-		if name == "" || user.Name == name {
-			users = append(users, user)
+	return nil
+}
+
+// ImportUsers attempts to create the provided users, as exported by
+// ExportUsers, in a single transaction. Passwords that are already hashes are
+// stored as they are and the rest are hashed, roles are kept and default to
+// models.RoleUser, and if keepIDs is set the users keep their IDs. Users that
+// can't be created are skipped, and a models.BulkResult is returned for each
+// user, in order, carrying the ID it was created with. An error is only
+// returned if the import as a whole fails.
+func (s *UsersService) ImportUsers(ctx context.Context, users []models.User, keepIDs bool) ([]models.BulkResult[models.User], error) {
+	s.logger.DebugContext(ctx, "Importing users", "count", len(users), "keep_ids", keepIDs)
+
+	users = slices.Clone(users)
+	errs := make([]error, len(users))
+	for i := range users {
+		if users[i].Role == "" {
+			users[i].Role = models.RoleUser
+		}
+		if password.IsHash(users[i].Password) {
+			continue
+		}
+		if users[i].Password, errs[i] = s.hasher.Hash(users[i].Password); errs[i] != nil {
+			users[i].Password = ""
 		}
 	}
 
-	if err = rows.Err(); err != nil {
-		return []models.User{}, fmt.Errorf(
-			"[in services.UsersService.ListUser] failed to read users: %w",
-			err,
-		)
+	columns := importColumns(keepIDs, "name", "email", "password", "role")
+
+	err := bulkWrite(ctx, s.db, models.BulkBestEffort, errs, func(tx *sql.Tx) error {
+		err := insertItems(ctx, tx, models.BulkBestEffort, errs, func(batch []int) error {
+			args := make([]any, 0, len(columns)*len(batch))
+			for _, i := range batch {
+				if keepIDs {
+					args = append(args, users[i].ID)
+				}
+				args = append(args, users[i].Name, users[i].Email, users[i].Password, users[i].Role)
+			}
+
+			err := insertRows(
+				ctx,
+				tx,
+				batch,
+				fmt.Sprintf(`
+				INSERT INTO users (%s) VALUES %s RETURNING id
+				`, strings.Join(columns, ", "), valueRows(len(batch), len(columns))),
+				args,
+				func(i int, rows *sql.Rows) error {
+					return rows.Scan(&users[i].ID)
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to import users: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil || !keepIDs {
+			return err
+		}
+
+		return syncSequence(ctx, tx, "users")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[in services.UsersService.ImportUsers] %w", err)
 	}
 
-	return users, nil
+	return bulkResults("services.UsersService.ImportUsers", users, errs), nil
 }
@@ -2,15 +2,53 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"log/slog"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/chickey/blog/internal/models"
+	"github.com/chickey/blog/internal/password"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// newTestHasher returns a hasher with the cheapest bcrypt cost so tests stay
+// fast.
+func newTestHasher(t *testing.T) *password.Hasher {
+	t.Helper()
+
+	hasher, err := password.NewHasher(password.Params{
+		Algorithm:  password.Bcrypt,
+		BcryptCost: 4,
+	})
+	if err != nil {
+		t.Fatalf("failed to create hasher: %v", err)
+	}
+	return hasher
+}
+
+// hashOf matches a driver argument that is a password hash of plain.
+type hashOf string
+
+// Match implements sqlmock.Argument.
+func (h hashOf) Match(v driver.Value) bool {
+	hash, ok := v.(string)
+	if !ok {
+		return false
+	}
+
+	hasher, err := password.NewHasher(password.Params{Algorithm: password.Bcrypt, BcryptCost: 4})
+	if err != nil {
+		return false
+	}
+	match, _, err := hasher.Verify(hash, string(h))
+	return err == nil && match
+}
+
 func TestUsersService_ReadUser(t *testing.T) {
 	testcases := map[string]struct {
 		mockCalled     bool
@@ -24,8 +62,8 @@ func TestUsersService_ReadUser(t *testing.T) {
 		"happy path": {
 			mockCalled:    true,
 			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
-				AddRow(1, "john", "john@me.com", "password123!"),
+			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password", "version"}).
+				AddRow(1, "john", "john@me.com", "password123!", 2),
 			mockError: nil,
 			input:     1,
 			expectedOutput: models.User{
@@ -33,9 +71,19 @@ func TestUsersService_ReadUser(t *testing.T) {
 				Name:     "john",
 				Email:    "john@me.com",
 				Password: "password123!",
+				Version:  2,
 			},
 			expectedError: nil,
 		},
+		"not found": {
+			mockCalled:     true,
+			mockInputArgs:  []driver.Value{2},
+			mockOutput:     sqlmock.NewRows([]string{"id", "name", "email", "password", "version"}),
+			mockError:      nil,
+			input:          2,
+			expectedOutput: models.User{},
+			expectedError:  ErrNotFound,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -53,7 +101,8 @@ func TestUsersService_ReadUser(t *testing.T) {
                         SELECT id,
                                name,
                                email,
-                               password
+                               password,
+                               version
                         FROM users
                         WHERE id = $1::int
                     `)).
@@ -62,11 +111,11 @@ func TestUsersService_ReadUser(t *testing.T) {
 					WillReturnError(tc.mockError)
 			}
 
-			userService := NewUsersService(logger, db)
+			userService := NewUsersService(logger, db, newTestHasher(t))
 
-			output, err := userService.ReadUser(context.TODO(), tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			output, err := userService.ReadUser(context.TODO(), tc.input, false)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
 			if output != tc.expectedOutput {
 				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
@@ -81,70 +130,130 @@ func TestUsersService_ReadUser(t *testing.T) {
 	}
 }
 func TestUsersService_ListUsers(t *testing.T) {
+	columns := []string{"id", "name", "email", "role"}
+
 	testcases := map[string]struct {
 		mockCalled     bool
+		mockQuery      string
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
 		mockError      error
+		mockTotal      *sqlmock.Rows
 		input          string
+		page           models.PageRequest
+		includeDeleted bool
 		expectedOutput []models.User
+		expectedNext   bool
+		expectedPrev   bool
+		expectedTotal  int
 		expectedError  error
 	}{
-		"happy path": {
+		"first page": {
 			mockCalled:    true,
-			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
-				AddRow(1, "john", "john@me.com", "password123!").
-				AddRow(2, "jane", "jane@me.com", "pwd5678!"),
-
+			mockQuery:     `SELECT id, name, email, role FROM users WHERE deleted_at IS NULL ORDER BY id ASC LIMIT 3`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(1, "john", "john@me.com", "admin").
+				AddRow(2, "jane", "jane@me.com", "user").
+				AddRow(3, "joe", "joe@me.com", "user"),
 			mockError: nil,
 			input:     "",
+			page:      models.PageRequest{Limit: 2},
 			expectedOutput: []models.User{
-				{
-					ID:       1,
-					Name:     "john",
-					Email:    "john@me.com",
-					Password: "password123!",
-				},
-				{
-					ID:       2,
-					Name:     "jane",
-					Email:    "jane@me.com",
-					Password: "pwd5678!",
-				},
+				{ID: 1, Name: "john", Email: "john@me.com", Role: models.RoleAdmin},
+				{ID: 2, Name: "jane", Email: "jane@me.com", Role: models.RoleUser},
 			},
+			expectedNext:  true,
 			expectedError: nil,
 		},
 		"filter by name": {
 			mockCalled:    true,
-			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
-				AddRow(1, "john", "john@me.com", "password123!").
-				AddRow(2, "jane", "jane@me.com", "pwd5678!"),
-
+			mockQuery:     `SELECT id, name, email, role FROM users WHERE name = $1 AND deleted_at IS NULL ORDER BY id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{"jane"},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(2, "jane", "jane@me.com", "user"),
 			mockError: nil,
 			input:     "jane",
 			expectedOutput: []models.User{
-				{
-					ID:       2,
-					Name:     "jane",
-					Email:    "jane@me.com",
-					Password: "pwd5678!",
-				},
+				{ID: 2, Name: "jane", Email: "jane@me.com", Role: models.RoleUser},
 			},
 			expectedError: nil,
 		},
-		"filter by name no results": {
+		"after cursor": {
 			mockCalled:    true,
-			mockInputArgs: []driver.Value{1},
-			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
-				AddRow(1, "john", "john@me.com", "password123!").
-				AddRow(2, "jane", "jane@me.com", "pwd5678!"),
-
+			mockQuery:     `SELECT id, name, email, role FROM users WHERE deleted_at IS NULL AND ((id > $1::text::bigint)) ORDER BY id ASC LIMIT 3`,
+			mockInputArgs: []driver.Value{"2"},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(3, "joe", "joe@me.com", "user"),
+			mockError: nil,
+			page:      models.PageRequest{Limit: 2, Cursor: testCursor("2", false)},
+			expectedOutput: []models.User{
+				{ID: 3, Name: "joe", Email: "joe@me.com", Role: models.RoleUser},
+			},
+			expectedPrev:  true,
+			expectedError: nil,
+		},
+		"before cursor": {
+			mockCalled:    true,
+			mockQuery:     `SELECT id, name, email, role FROM users WHERE deleted_at IS NULL AND ((id < $1::text::bigint)) ORDER BY id DESC LIMIT 3`,
+			mockInputArgs: []driver.Value{"3"},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(2, "jane", "jane@me.com", "user").
+				AddRow(1, "john", "john@me.com", "admin"),
+			mockError: nil,
+			page:      models.PageRequest{Limit: 2, Cursor: testCursor("3", true)},
+			expectedOutput: []models.User{
+				{ID: 1, Name: "john", Email: "john@me.com", Role: models.RoleAdmin},
+				{ID: 2, Name: "jane", Email: "jane@me.com", Role: models.RoleUser},
+			},
+			expectedNext:  true,
+			expectedError: nil,
+		},
+		"offset with total": {
+			mockCalled:    true,
+			mockQuery:     `SELECT id, name, email, role FROM users WHERE deleted_at IS NULL ORDER BY id ASC LIMIT 3 OFFSET 2`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(columns).
+				AddRow(3, "joe", "joe@me.com", "user"),
+			mockError: nil,
+			mockTotal: sqlmock.NewRows([]string{"count"}).AddRow(3),
+			page:      models.PageRequest{Limit: 2, Offset: 2, IncludeTotal: true},
+			expectedOutput: []models.User{
+				{ID: 3, Name: "joe", Email: "joe@me.com", Role: models.RoleUser},
+			},
+			expectedPrev:  true,
+			expectedTotal: 3,
+			expectedError: nil,
+		},
+		"including deleted": {
+			mockCalled:    true,
+			mockQuery:     `SELECT id, name, email, role, deleted_at FROM users ORDER BY id ASC LIMIT 21`,
+			mockInputArgs: []driver.Value{},
+			mockOutput: sqlmock.NewRows(append(columns, "deleted_at")).
+				AddRow(1, "john", "john@me.com", "admin", nil).
+				AddRow(2, "jane", "jane@me.com", "user", time.Date(2025, 1, 21, 0, 0, 0, 0, time.UTC)),
 			mockError:      nil,
-			input:          "joe",
+			includeDeleted: true,
+			expectedOutput: []models.User{
+				{ID: 1, Name: "john", Email: "john@me.com", Role: models.RoleAdmin},
+				{ID: 2, Name: "jane", Email: "jane@me.com", Role: models.RoleUser, DeletedAt: time.Date(2025, 1, 21, 0, 0, 0, 0, time.UTC)},
+			},
+			expectedError: nil,
+		},
+		"invalid cursor": {
+			mockCalled:     false,
+			page:           models.PageRequest{Cursor: "not a cursor"},
 			expectedOutput: []models.User{},
-			expectedError:  nil,
+			expectedError:  ErrInvalidCursor,
+		},
+		"query error": {
+			mockCalled:     true,
+			mockQuery:      `SELECT id, name, email, role FROM users WHERE deleted_at IS NULL ORDER BY id ASC LIMIT 21`,
+			mockInputArgs:  []driver.Value{},
+			mockOutput:     sqlmock.NewRows(columns),
+			mockError:      sql.ErrConnDone,
+			expectedOutput: []models.User{},
+			expectedError:  sql.ErrConnDone,
 		},
 	}
 	for name, tc := range testcases {
@@ -159,29 +268,41 @@ func TestUsersService_ListUsers(t *testing.T) {
 
 			if tc.mockCalled {
 				mock.
-					ExpectQuery(regexp.QuoteMeta(`
-                        SELECT id,
-							name,
-							email,
-							password
-						FROM users
-                    `)).
+					ExpectQuery(regexp.QuoteMeta(tc.mockQuery)).
+					WithArgs(tc.mockInputArgs...).
 					WillReturnRows(tc.mockOutput).
 					WillReturnError(tc.mockError)
 			}
+			if tc.mockTotal != nil {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM users WHERE deleted_at IS NULL`)).
+					WillReturnRows(tc.mockTotal)
+			}
 
-			userService := NewUsersService(logger, db)
+			userService := NewUsersService(logger, db, newTestHasher(t))
 
-			outputs, err := userService.ListUsers(context.TODO(), tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			output, err := userService.ListUsers(context.TODO(), tc.input, tc.page, tc.includeDeleted)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
 
-			for i, output := range outputs {
-				if output != tc.expectedOutput[i] {
-					t.Errorf("expected %v, got %v", tc.expectedOutput[i], output)
+			if len(output.Items) != len(tc.expectedOutput) {
+				t.Fatalf("expected %v, got %v", tc.expectedOutput, output.Items)
+			}
+			for i, user := range output.Items {
+				if user != tc.expectedOutput[i] {
+					t.Errorf("expected %v, got %v", tc.expectedOutput[i], user)
 				}
 			}
+			if (output.NextCursor != "") != tc.expectedNext {
+				t.Errorf("expected next page %t, got cursor %q", tc.expectedNext, output.NextCursor)
+			}
+			if (output.PrevCursor != "") != tc.expectedPrev {
+				t.Errorf("expected previous page %t, got cursor %q", tc.expectedPrev, output.PrevCursor)
+			}
+			if tc.mockTotal != nil && (output.Total == nil || *output.Total != tc.expectedTotal) {
+				t.Errorf("expected total %d, got %v", tc.expectedTotal, output.Total)
+			}
 
 			if tc.mockCalled {
 				if err = mock.ExpectationsWereMet(); err != nil {
@@ -197,18 +318,53 @@ func TestBlogsService_DeleteUser(t *testing.T) {
 		mockCalled     bool
 		mockInputArgs  []driver.Value
 		mockOutput     *sqlmock.Rows
+		mockLock       *sqlmock.Rows
 		mockError      error
 		input          uint64
+		version        uint
 		expectedOutput models.Blog
 		expectedError  error
 	}{
 		"happy path": {
 			mockCalled:    true,
 			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}).AddRow(3),
+			mockError:     nil,
+			input:         1,
+			expectedError: nil,
+		},
+		"current version": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}).AddRow(3),
 			mockError:     nil,
 			input:         1,
+			version:       3,
 			expectedError: nil,
 		},
+		"stale version": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}).AddRow(4),
+			input:         1,
+			version:       3,
+			expectedError: ErrPreconditionFailed,
+		},
+		"not found": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}),
+			input:         1,
+			expectedError: ErrNotFound,
+		},
+		"rolls back when deleting blogs fails": {
+			mockCalled:    true,
+			mockInputArgs: []driver.Value{1},
+			mockLock:      sqlmock.NewRows([]string{"version"}).AddRow(3),
+			mockError:     sql.ErrConnDone,
+			input:         1,
+			expectedError: sql.ErrConnDone,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -221,29 +377,58 @@ func TestBlogsService_DeleteUser(t *testing.T) {
 			logger := slog.Default()
 
 			if tc.mockCalled {
+				mock.ExpectBegin()
 				mock.
-					ExpectExec(regexp.QuoteMeta(`DELETE FROM users WHERE id = $1::int`)).
+					ExpectQuery(regexp.QuoteMeta(`SELECT version FROM users WHERE id = $1::int AND deleted_at IS NULL FOR UPDATE`)).
 					WithArgs(tc.mockInputArgs...).
-					WillReturnResult(sqlmock.NewResult(1, 1)).
-					WillReturnError(tc.mockError)
+					WillReturnRows(tc.mockLock)
+			}
+
+			if tc.mockCalled && (tc.expectedError == ErrPreconditionFailed || tc.expectedError == ErrNotFound) {
+				mock.ExpectRollback()
+			} else if tc.mockCalled {
 				mock.
-					ExpectExec(regexp.QuoteMeta(`DELETE FROM blogs WHERE author_id = $1::int`)).
+					ExpectExec(regexp.QuoteMeta(`
+						WHERE user_id = $1::int
+						   OR blog_id IN (SELECT id FROM blogs WHERE author_id = $1::int)
+						UNION
+						SELECT c.id
+						FROM comments c
+						JOIN removed r ON c.parent_id = r.id
+					) UPDATE comments SET deleted_at = CURRENT_TIMESTAMP WHERE id IN (SELECT id FROM removed) AND deleted_at IS NULL
+					`)).
 					WithArgs(tc.mockInputArgs...).
-					WillReturnResult(sqlmock.NewResult(1, 1)).
-					WillReturnError(tc.mockError)
-
+					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.
-					ExpectExec(regexp.QuoteMeta(`DELETE FROM comments WHERE user_id = $1::int`)).
+					ExpectExec(regexp.QuoteMeta(`UPDATE blogs SET deleted_at = CURRENT_TIMESTAMP WHERE author_id = $1::int AND deleted_at IS NULL`)).
 					WithArgs(tc.mockInputArgs...).
 					WillReturnResult(sqlmock.NewResult(1, 1)).
 					WillReturnError(tc.mockError)
+
+				if tc.mockError != nil {
+					mock.ExpectRollback()
+				} else {
+					mock.
+						ExpectExec(regexp.QuoteMeta(`DELETE FROM votes WHERE user_id = $1::int RETURNING blog_id`)).
+						WithArgs(tc.mockInputArgs...).
+						WillReturnResult(sqlmock.NewResult(0, 2))
+					mock.
+						ExpectExec(regexp.QuoteMeta(`UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1::int AND revoked_at IS NULL`)).
+						WithArgs(tc.mockInputArgs...).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+					mock.
+						ExpectExec(regexp.QuoteMeta(`UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1::int AND deleted_at IS NULL`)).
+						WithArgs(tc.mockInputArgs...).
+						WillReturnResult(sqlmock.NewResult(1, 1))
+					mock.ExpectCommit()
+				}
 			}
 
-			userService := NewUsersService(logger, db)
+			userService := NewUsersService(logger, db, newTestHasher(t))
 
-			err = userService.DeleteUser(context.TODO(), tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			err = userService.DeleteUser(context.TODO(), tc.input, tc.version)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
 
 			if tc.mockCalled {
@@ -255,6 +440,121 @@ func TestBlogsService_DeleteUser(t *testing.T) {
 	}
 }
 
+func TestUsersService_RestoreUser(t *testing.T) {
+	deletedAt := time.Date(2025, 1, 21, 0, 0, 0, 0, time.UTC)
+
+	testcases := map[string]struct {
+		mockDeleted   *sqlmock.Rows
+		mockError     error
+		input         uint64
+		expectedError error
+	}{
+		"happy path": {
+			mockDeleted:   sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt),
+			input:         1,
+			expectedError: nil,
+		},
+		"not deleted": {
+			mockDeleted:   sqlmock.NewRows([]string{"deleted_at"}),
+			input:         1,
+			expectedError: ErrNotFound,
+		},
+		"email taken": {
+			mockDeleted:   sqlmock.NewRows([]string{"deleted_at"}).AddRow(deletedAt),
+			mockError:     &pgconn.PgError{Code: pgUniqueViolation},
+			input:         1,
+			expectedError: ErrConflict,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT deleted_at FROM users WHERE id = $1::int AND deleted_at IS NOT NULL FOR UPDATE`)).
+				WithArgs(1).
+				WillReturnRows(tc.mockDeleted)
+			if tc.expectedError == ErrNotFound {
+				mock.ExpectRollback()
+			} else {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`UPDATE comments SET deleted_at = NULL WHERE id IN (SELECT id FROM removed) AND deleted_at = $2`)).
+					WithArgs(1, deletedAt).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.
+					ExpectExec(regexp.QuoteMeta(`UPDATE blogs SET deleted_at = NULL WHERE author_id = $1::int AND deleted_at = $2`)).
+					WithArgs(1, deletedAt).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.
+					ExpectExec(regexp.QuoteMeta(`UPDATE users SET deleted_at = NULL WHERE id = $1::int`)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1)).
+					WillReturnError(tc.mockError)
+				if tc.mockError != nil {
+					mock.ExpectRollback()
+				} else {
+					mock.ExpectCommit()
+				}
+			}
+
+			userService := NewUsersService(slog.Default(), db, newTestHasher(t))
+
+			err = userService.RestoreUser(context.TODO(), tc.input)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUsersService_PurgeDeleted(t *testing.T) {
+	before := time.Date(2025, 1, 21, 0, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.
+		ExpectExec(regexp.QuoteMeta(`DELETE FROM comments WHERE deleted_at < $1`)).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`DELETE FROM blogs WHERE deleted_at < $1`)).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`DELETE FROM users WHERE deleted_at < $1`)).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	userService := NewUsersService(slog.Default(), db, newTestHasher(t))
+
+	purged, err := userService.PurgeDeleted(context.TODO(), before)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if purged != 6 {
+		t.Errorf("expected 6 purged, got %d", purged)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestUsersService_CreateUser(t *testing.T) {
 	testcases := map[string]struct {
 		mockCalled     bool
@@ -267,7 +567,7 @@ func TestUsersService_CreateUser(t *testing.T) {
 	}{
 		"happy path": {
 			mockCalled:    true,
-			mockInputArgs: []driver.Value{"john", "john@me.com", "password123!"},
+			mockInputArgs: []driver.Value{"john", "john@me.com", hashOf("password123!")},
 			mockOutput: sqlmock.NewRows([]string{"id"}).
 				AddRow(1),
 			mockError: nil,
@@ -277,10 +577,9 @@ func TestUsersService_CreateUser(t *testing.T) {
 				Password: "password123!",
 			},
 			expectedOutput: models.User{
-				ID:       1,
-				Name:     "john",
-				Email:    "john@me.com",
-				Password: "password123!",
+				ID:    1,
+				Name:  "john",
+				Email: "john@me.com",
 			},
 			expectedError: nil,
 		},
@@ -305,12 +604,16 @@ func TestUsersService_CreateUser(t *testing.T) {
 					WillReturnError(tc.mockError)
 			}
 
-			userService := NewUsersService(logger, db)
+			userService := NewUsersService(logger, db, newTestHasher(t))
 
 			output, err := userService.CreateUser(context.TODO(), tc.input)
 			if err != tc.expectedError {
 				t.Errorf("expected no error, got %v", err)
 			}
+			if !hashOf(tc.input.Password).Match(output.Password) {
+				t.Errorf("expected a hash of %q, got %q", tc.input.Password, output.Password)
+			}
+			output.Password = ""
 			if output != tc.expectedOutput {
 				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
 			}
@@ -325,34 +628,104 @@ func TestUsersService_CreateUser(t *testing.T) {
 }
 
 func TestUsersService_UpdateUser(t *testing.T) {
+	hasher := newTestHasher(t)
+	currentHash, err := hasher.Hash("password123!")
+	if err != nil {
+		t.Fatalf("failed to hash: %v", err)
+	}
+
 	testcases := map[string]struct {
-		mockCalled     bool
-		mockInputArgs  []driver.Value
-		mockOutput     *sqlmock.Rows
-		mockError      error
-		input          models.User
-		expectedOutput models.User
-		expectedError  error
+		mockCurrent     *sqlmock.Rows
+		expectUpdate    bool
+		expectRevoke    bool
+		mockUpdateError error
+		input           models.User
+		expectedOutput  models.User
+		expectedError   error
 	}{
-		"happy path": {
-			mockCalled:    true,
-			mockInputArgs: []driver.Value{"john", "john@me.com", "password123!", 1},
-			mockOutput: sqlmock.NewRows([]string{"id"}).
-				AddRow(1),
-			mockError: nil,
+		"password changed": {
+			mockCurrent:  sqlmock.NewRows([]string{"password", "version"}).AddRow(currentHash, 3),
+			expectUpdate: true,
+			expectRevoke: true,
 			input: models.User{
 				Name:     "john",
 				Email:    "john@me.com",
+				Password: "password456!",
+			},
+			expectedOutput: models.User{
+				ID:      1,
+				Name:    "john",
+				Email:   "john@me.com",
+				Version: 4,
+			},
+			expectedError: nil,
+		},
+		"password unchanged": {
+			mockCurrent:  sqlmock.NewRows([]string{"password", "version"}).AddRow(currentHash, 3),
+			expectUpdate: true,
+			input: models.User{
+				Name:     "johnny",
+				Email:    "john@me.com",
 				Password: "password123!",
 			},
 			expectedOutput: models.User{
-				ID:       1,
-				Name:     "john",
+				ID:      1,
+				Name:    "johnny",
+				Email:   "john@me.com",
+				Version: 4,
+			},
+			expectedError: nil,
+		},
+		"current version": {
+			mockCurrent:  sqlmock.NewRows([]string{"password", "version"}).AddRow(currentHash, 3),
+			expectUpdate: true,
+			input: models.User{
+				Name:     "johnny",
 				Email:    "john@me.com",
 				Password: "password123!",
+				Version:  3,
+			},
+			expectedOutput: models.User{
+				ID:      1,
+				Name:    "johnny",
+				Email:   "john@me.com",
+				Version: 4,
 			},
 			expectedError: nil,
 		},
+		"stale version": {
+			mockCurrent: sqlmock.NewRows([]string{"password", "version"}).AddRow(currentHash, 3),
+			input: models.User{
+				Name:     "johnny",
+				Email:    "john@me.com",
+				Password: "password123!",
+				Version:  2,
+			},
+			expectedOutput: models.User{},
+			expectedError:  ErrPreconditionFailed,
+		},
+		"duplicate email": {
+			mockCurrent:     sqlmock.NewRows([]string{"password", "version"}).AddRow(currentHash, 3),
+			expectUpdate:    true,
+			mockUpdateError: &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_email_key"},
+			input: models.User{
+				Name:     "john",
+				Email:    "jane@me.com",
+				Password: "password123!",
+			},
+			expectedOutput: models.User{},
+			expectedError:  ErrConflict,
+		},
+		"not found": {
+			mockCurrent: sqlmock.NewRows([]string{"password", "version"}),
+			input: models.User{
+				Name:     "john",
+				Email:    "john@me.com",
+				Password: "password123!",
+			},
+			expectedOutput: models.User{},
+			expectedError:  ErrNotFound,
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -364,33 +737,479 @@ func TestUsersService_UpdateUser(t *testing.T) {
 
 			logger := slog.Default()
 
-			if tc.mockCalled {
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT password, version FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`)).
+				WithArgs(1).
+				WillReturnRows(tc.mockCurrent)
+			if tc.expectUpdate {
 				mock.
-					ExpectExec(regexp.QuoteMeta(`
+					ExpectQuery(regexp.QuoteMeta(`
                         UPDATE users 
 						SET name = $1, email = $2, password = $3
 						WHERE id = $4
+						RETURNING version
                     `)).
-					WithArgs(tc.mockInputArgs...).
-					WillReturnResult(sqlmock.NewResult(1, 1)).
-					WillReturnError(tc.mockError)
+					WithArgs(tc.input.Name, tc.input.Email, hashOf(tc.input.Password), 1).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4)).
+					WillReturnError(tc.mockUpdateError)
+			}
+			if tc.expectRevoke {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`
+						UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL
+					`)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			}
+			if tc.expectedError != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
 			}
 
-			userService := NewUsersService(logger, db)
+			userService := NewUsersService(logger, db, hasher)
 
 			output, err := userService.UpdateUser(context.TODO(), 1, tc.input)
-			if err != tc.expectedError {
-				t.Errorf("expected no error, got %v", err)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
 			}
+			if tc.expectedError == nil {
+				if !hashOf(tc.input.Password).Match(output.Password) {
+					t.Errorf("expected a hash of %q, got %q", tc.input.Password, output.Password)
+				}
+				if tc.input.Password == "password123!" && output.Password != currentHash {
+					t.Errorf("expected the stored hash to be kept")
+				}
+			}
+			output.Password = ""
 			if output != tc.expectedOutput {
 				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
 			}
 
-			if tc.mockCalled {
-				if err = mock.ExpectationsWereMet(); err != nil {
-					t.Errorf("there were unfulfilled expectations: %s", err)
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUsersService_PatchUser(t *testing.T) {
+	hasher := newTestHasher(t)
+	currentHash, err := hasher.Hash("password123!")
+	if err != nil {
+		t.Fatalf("failed to hash: %v", err)
+	}
+	name := "johnny"
+	email := "jane@me.com"
+	same := "password123!"
+	changed := "password456!"
+
+	testcases := map[string]struct {
+		input           models.UserPatch
+		expectUpdate    string
+		expectArgs      []driver.Value
+		expectRevoke    bool
+		mockUpdateError error
+		expectedOutput  models.User
+		expectedError   error
+	}{
+		"name only": {
+			input:          models.UserPatch{Name: &name},
+			expectUpdate:   `UPDATE users SET name = $1 WHERE id = $2 RETURNING version`,
+			expectArgs:     []driver.Value{"johnny", 1},
+			expectedOutput: models.User{ID: 1, Name: "johnny", Email: "john@me.com", Version: 4},
+		},
+		"password changed": {
+			input:          models.UserPatch{Email: &email, Password: &changed, Version: 3},
+			expectUpdate:   `UPDATE users SET email = $1, password = $2 WHERE id = $3 RETURNING version`,
+			expectArgs:     []driver.Value{"jane@me.com", hashOf(changed), 1},
+			expectRevoke:   true,
+			expectedOutput: models.User{ID: 1, Name: "john", Email: "jane@me.com", Version: 4},
+		},
+		"nothing changed": {
+			input:          models.UserPatch{Password: &same},
+			expectedOutput: models.User{ID: 1, Name: "john", Email: "john@me.com", Version: 3},
+		},
+		"stale version": {
+			input:          models.UserPatch{Name: &name, Version: 2},
+			expectedOutput: models.User{},
+			expectedError:  ErrPreconditionFailed,
+		},
+		"duplicate email": {
+			input:           models.UserPatch{Email: &email},
+			expectUpdate:    `UPDATE users SET email = $1 WHERE id = $2 RETURNING version`,
+			expectArgs:      []driver.Value{"jane@me.com", 1},
+			mockUpdateError: &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_email_key"},
+			expectedOutput:  models.User{},
+			expectedError:   ErrConflict,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.ExpectBegin()
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`SELECT id, name, email, password, version FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`)).
+				WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "version"}).AddRow(1, "john", "john@me.com", currentHash, 3))
+			if tc.expectUpdate != "" {
+				mock.
+					ExpectQuery(regexp.QuoteMeta(tc.expectUpdate)).
+					WithArgs(tc.expectArgs...).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4)).
+					WillReturnError(tc.mockUpdateError)
+			}
+			if tc.expectRevoke {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`UPDATE auth_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`)).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			}
+			if tc.expectedError != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			userService := NewUsersService(logger, db, hasher)
+
+			output, err := userService.PatchUser(context.TODO(), 1, tc.input)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			output.Password = ""
+			if output != tc.expectedOutput {
+				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUsersService_VerifyPassword(t *testing.T) {
+	current := newTestHasher(t)
+	currentHash, err := current.Hash("password123!")
+	if err != nil {
+		t.Fatalf("failed to hash: %v", err)
+	}
+
+	outdated, err := password.NewHasher(password.Params{Algorithm: password.Bcrypt, BcryptCost: 5})
+	if err != nil {
+		t.Fatalf("failed to create hasher: %v", err)
+	}
+	outdatedHash, err := outdated.Hash("password123!")
+	if err != nil {
+		t.Fatalf("failed to hash: %v", err)
+	}
+
+	testcases := map[string]struct {
+		mockOutput     *sqlmock.Rows
+		expectRehash   bool
+		input          string
+		expectedOutput models.User
+		expectedError  error
+	}{
+		"happy path": {
+			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
+				AddRow(1, "john", "john@me.com", currentHash),
+			input: "password123!",
+			expectedOutput: models.User{
+				ID:    1,
+				Name:  "john",
+				Email: "john@me.com",
+			},
+			expectedError: nil,
+		},
+		"rehash outdated parameters": {
+			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
+				AddRow(1, "john", "john@me.com", outdatedHash),
+			expectRehash: true,
+			input:        "password123!",
+			expectedOutput: models.User{
+				ID:    1,
+				Name:  "john",
+				Email: "john@me.com",
+			},
+			expectedError: nil,
+		},
+		"wrong password": {
+			mockOutput: sqlmock.NewRows([]string{"id", "name", "email", "password"}).
+				AddRow(1, "john", "john@me.com", currentHash),
+			input:          "password456!",
+			expectedOutput: models.User{},
+			expectedError:  ErrInvalidCredentials,
+		},
+		"unknown email": {
+			mockOutput:     sqlmock.NewRows([]string{"id", "name", "email", "password"}),
+			input:          "password123!",
+			expectedOutput: models.User{},
+			expectedError:  ErrInvalidCredentials,
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			logger := slog.Default()
+
+			mock.
+				ExpectQuery(regexp.QuoteMeta(`
+					SELECT id,
+					       name,
+					       email,
+					       password
+					FROM users
+					WHERE LOWER(email) = LOWER($1)
+				`)).
+				WithArgs("john@me.com").
+				WillReturnRows(tc.mockOutput)
+			if tc.expectRehash {
+				mock.
+					ExpectExec(regexp.QuoteMeta(`
+						UPDATE users
+						SET password = $1
+						WHERE id = $2 AND password = $3
+					`)).
+					WithArgs(hashOf(tc.input), 1, outdatedHash).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			}
+
+			userService := NewUsersService(logger, db, current)
+
+			output, err := userService.VerifyPassword(context.TODO(), "john@me.com", tc.input)
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("expected %v, got %v", tc.expectedError, err)
+			}
+			if tc.expectRehash && output.Password == outdatedHash {
+				t.Errorf("expected the outdated hash to be replaced")
+			}
+			output.Password = ""
+			if output != tc.expectedOutput {
+				t.Errorf("expected %v, got %v", tc.expectedOutput, output)
+			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUsersService_HashPlaintextPasswords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	logger := slog.Default()
+	hasher := newTestHasher(t)
+
+	hash, err := hasher.Hash("pwd5678!")
+	if err != nil {
+		t.Fatalf("failed to hash: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`SELECT id, password FROM users ORDER BY id FOR UPDATE`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password"}).
+			AddRow(1, "password123!").
+			AddRow(2, hash).
+			AddRow(3, "password456!"))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`UPDATE users SET password = $1 WHERE id = $2`)).
+		WithArgs(hashOf("password123!"), 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`UPDATE users SET password = $1 WHERE id = $2`)).
+		WithArgs(hashOf("password456!"), 3).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	userService := NewUsersService(logger, db, hasher)
+
+	updated, err := userService.HashPlaintextPasswords(context.TODO())
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("expected 2 users updated, got %d", updated)
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUsersService_CreateUsers(t *testing.T) {
+	insert := `INSERT INTO users (name, email, password) VALUES `
+	john := models.User{Name: "john", Email: "john@example.com", Password: "password123"}
+	jane := models.User{Name: "jane", Email: "jane@example.com", Password: "password456"}
+
+	testcases := map[string]struct {
+		mode           models.BulkMode
+		expect         func(mock sqlmock.Sqlmock)
+		expectedIDs    []uint
+		expectedErrors []error
+	}{
+		"inserted together": {
+			mode: models.BulkAllOrNothing,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert+`($1, $2, $3), ($4, $5, $6) RETURNING id`)).
+					WithArgs("john", "john@example.com", hashOf("password123"), "jane", "jane@example.com", hashOf("password456")).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedIDs:    []uint{1, 2},
+			expectedErrors: []error{nil, nil},
+		},
+		"duplicate email rolls back": {
+			mode: models.BulkAllOrNothing,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert + `($1, $2, $3), ($4, $5, $6) RETURNING id`)).
+					WillReturnError(&pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_email_key"})
+				mock.ExpectExec(regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert+`($1, $2, $3) RETURNING id`)).
+					WithArgs("john", "john@example.com", hashOf("password123")).
+					WillReturnError(&pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_email_key"})
+				mock.ExpectExec(regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedIDs:    []uint{0, 0},
+			expectedErrors: []error{ErrConflict, ErrBulkAborted},
+		},
+		"duplicate email with best effort": {
+			mode: models.BulkBestEffort,
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert + `($1, $2, $3), ($4, $5, $6) RETURNING id`)).
+					WillReturnError(&pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_email_key"})
+				mock.ExpectExec(regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert+`($1, $2, $3) RETURNING id`)).
+					WithArgs("john", "john@example.com", hashOf("password123")).
+					WillReturnError(&pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "users_email_key"})
+				mock.ExpectExec(regexp.QuoteMeta(`ROLLBACK TO SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.
+					ExpectQuery(regexp.QuoteMeta(insert+`($1, $2, $3) RETURNING id`)).
+					WithArgs("jane", "jane@example.com", hashOf("password456")).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_item`)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			expectedIDs:    []uint{0, 2},
+			expectedErrors: []error{ErrConflict, nil},
+		},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			tc.expect(mock)
+
+			userService := NewUsersService(slog.Default(), db, newTestHasher(t))
+
+			results, err := userService.CreateUsers(context.TODO(), []models.User{john, jane}, tc.mode)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			for i, result := range results {
+				if !errors.Is(result.Err, tc.expectedErrors[i]) || (result.Err == nil) != (tc.expectedErrors[i] == nil) {
+					t.Errorf("item %d: expected %v, got %v", i, tc.expectedErrors[i], result.Err)
+				}
+				if result.Value.ID != tc.expectedIDs[i] {
+					t.Errorf("item %d: expected id %d, got %d", i, tc.expectedIDs[i], result.Value.ID)
 				}
 			}
+
+			if err = mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
 		})
 	}
 }
+
+func TestUsersService_ImportUsers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	hasher := newTestHasher(t)
+	hash, err := hasher.Hash("password456")
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectQuery(regexp.QuoteMeta(`INSERT INTO users (id, name, email, password, role) VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10) RETURNING id`)).
+		WithArgs(4, "john", "john@example.com", hashOf("password123"), "user", 9, "jane", "jane@example.com", hash, "admin").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(9))
+	mock.ExpectExec(regexp.QuoteMeta(`RELEASE SAVEPOINT bulk_batch`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectExec(regexp.QuoteMeta(`SELECT setval(pg_get_serial_sequence('users', 'id'), MAX(id)) FROM users`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	userService := NewUsersService(slog.Default(), db, hasher)
+
+	results, err := userService.ImportUsers(context.TODO(), []models.User{
+		{ID: 4, Name: "john", Email: "john@example.com", Password: "password123"},
+		{ID: 9, Name: "jane", Email: "jane@example.com", Password: hash, Role: models.RoleAdmin},
+	}, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for i, id := range []uint{4, 9} {
+		if results[i].Err != nil {
+			t.Errorf("item %d: expected no error, got %v", i, results[i].Err)
+		}
+		if results[i].Value.ID != id {
+			t.Errorf("item %d: expected id %d, got %d", i, id, results[i].Value.ID)
+		}
+	}
+
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}